@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/config"
+	"moonshine/internal/repository"
+)
+
+// recalculate-stats recomputes every user's attack, defense and hp from the
+// base stats and equipment and lists the users whose stored stats were off.
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println(".env not loaded, relying on environment")
+	}
+
+	db, err := repository.New(config.Load())
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	service := services.NewStatsRepairService(db.DB(), repository.NewUserRepository(db.DB()))
+	changes, err := service.RecalculateAll(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to recalculate stats: %v", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("All user stats are consistent")
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "user_id\tusername\tattack\tdefense\thp")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%d -> %d\t%d -> %d\t%d -> %d\n", c.UserID, c.Username,
+			c.Before.Attack, c.After.Attack, c.Before.Defense, c.After.Defense, c.Before.Hp, c.After.Hp)
+	}
+	tw.Flush()
+
+	fmt.Printf("Fixed stats of %d users\n", len(changes))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/config"
+	"moonshine/internal/repository"
+)
+
+// reconcile checks that every user's gold equals the sum of their gold
+// ledger entries and exits with status 1 when any user is off.
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println(".env not loaded, relying on environment")
+	}
+
+	db, err := repository.New(config.Load())
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	service := services.NewGoldTransactionService(repository.NewGoldTransactionRepository(db.DB()))
+	mismatches, err := service.Reconcile(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to reconcile gold: %v", err)
+	}
+
+	if len(mismatches) == 0 {
+		fmt.Println("Gold ledger is consistent")
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "user_id\tusername\tgold\tledger_sum\tdifference")
+	for _, m := range mismatches {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", m.UserID, m.Username, m.Gold, m.LedgerSum, m.Gold-m.LedgerSum)
+	}
+	tw.Flush()
+
+	log.Fatalf("%d users do not match the gold ledger", len(mismatches))
+}
@@ -50,12 +50,27 @@ func main() {
 	if err := seedArtifactItems(db.DB()); err != nil {
 		log.Printf("Failed to seed artifact items: %v", err)
 	}
+	if err := seedItemSets(db.DB()); err != nil {
+		log.Printf("Failed to seed item sets: %v", err)
+	}
 	if err := seedLocations(db.DB()); err != nil {
 		log.Printf("Failed to seed locations: %v", err)
 	}
+	if err := seedConsumables(db.DB()); err != nil {
+		log.Printf("Failed to seed consumables: %v", err)
+	}
+	if err := seedShopStock(db.DB()); err != nil {
+		log.Printf("Failed to seed shop stock: %v", err)
+	}
 	if err := seedBots(db.DB()); err != nil {
 		log.Printf("Failed to seed bots: %v", err)
 	}
+	if err := seedQuests(db.DB()); err != nil {
+		log.Printf("Failed to seed quests: %v", err)
+	}
+	if err := seedAchievements(db.DB()); err != nil {
+		log.Printf("Failed to seed achievements: %v", err)
+	}
 	seedUsers(db.DB())
 
 	log.Println("Seed process completed!")
@@ -66,6 +81,16 @@ func truncateTables(db *sqlx.DB) error {
 
 	tables := []string{
 		"inventory",
+		"user_quests",
+		"quests",
+		"user_achievements",
+		"user_visited_cells",
+		"achievements",
+		"consumable_inventory",
+		"consumables",
+		"item_sets",
+		"shop_price_history",
+		"shop_stock",
 		"location_locations",
 		"equipment_items",
 		"equipment_categories",
@@ -202,7 +227,7 @@ func seedLocations(db *sqlx.DB) error {
 
 	moonshineLocation, err := locationRepo.FindStartLocation()
 	if err == nil && moonshineLocation != nil {
-		_, _ = db.Exec("UPDATE locations SET cell = false WHERE slug IN ('moonshine', 'shop_of_artifacts', 'weapon_shop')")
+		_, _ = db.Exec("UPDATE locations SET cell = false WHERE slug IN ('moonshine', 'shop_of_artifacts', 'weapon_shop', 'blacksmith', 'bank')")
 		_, _ = db.Exec("UPDATE locations SET cell = true WHERE slug LIKE '%cell'")
 		return nil
 	}
@@ -226,6 +251,8 @@ func seedLocations(db *sqlx.DB) error {
 	}{
 		{"weapon_shop", "Weapon shop"},
 		{"shop_of_artifacts", "Артефакты"},
+		{"blacksmith", "Кузница"},
+		{"bank", "Банк"},
 	}
 
 	shopLocations := make(map[string]uuid.UUID)
@@ -290,10 +317,12 @@ func seedLocations(db *sqlx.DB) error {
 		"moonshine":         moonshineLocation.ID,
 		"shop_of_artifacts": shopLocations["shop_of_artifacts"],
 		"weapon_shop":       shopLocations["weapon_shop"],
+		"blacksmith":        shopLocations["blacksmith"],
+		"bank":              shopLocations["bank"],
 		"wayward_pines":     waywardPinesLocation.ID,
 	}
 
-	locationNames := []string{"moonshine", "shop_of_artifacts", "weapon_shop", "wayward_pines"}
+	locationNames := []string{"moonshine", "shop_of_artifacts", "weapon_shop", "blacksmith", "bank", "wayward_pines"}
 
 	for i, loc1Name := range locationNames {
 		for j, loc2Name := range locationNames {
@@ -523,6 +552,219 @@ func seedBots(db *sqlx.DB) error {
 	return nil
 }
 
+func seedConsumables(db *sqlx.DB) error {
+	log.Println("Seeding consumables...")
+
+	consumableRepo := repository.NewConsumableRepository(db)
+
+	shield := domain.StatusEffectShield
+	attackUp := domain.StatusEffectAttackUp
+	consumables := []*domain.Consumable{
+		{Name: "Малое зелье лечения", Slug: "small-healing-potion", Price: 10, RequiredLevel: 1, EffectType: domain.ConsumableEffectHeal, EffectValue: 10},
+		{Name: "Зелье лечения", Slug: "healing-potion", Price: 30, RequiredLevel: 3, EffectType: domain.ConsumableEffectHeal, EffectValue: 35},
+		{Name: "Большое зелье лечения", Slug: "large-healing-potion", Price: 80, RequiredLevel: 6, EffectType: domain.ConsumableEffectHeal, EffectValue: 100},
+		{
+			Name: "Зелье каменной кожи", Slug: "stoneskin-potion", Price: 40, RequiredLevel: 3, EffectType: domain.ConsumableEffectStatusEffect,
+			StatusEffectSource: domain.StatusEffectSource{StatusEffectType: &shield, StatusEffectValue: 30, StatusEffectDuration: 3, StatusEffectChance: 100},
+		},
+		{
+			Name: "Зелье силы", Slug: "strength-potion", Price: 40, RequiredLevel: 3, EffectType: domain.ConsumableEffectStatusEffect,
+			StatusEffectSource: domain.StatusEffectSource{StatusEffectType: &attackUp, StatusEffectValue: 5, StatusEffectDuration: 3, StatusEffectChance: 100},
+		},
+		{Name: "Камень заточки", Slug: domain.EnhancementStoneSlug, Price: 50, RequiredLevel: 1, EffectType: domain.ConsumableEffectMaterial},
+	}
+
+	for _, consumable := range consumables {
+		if err := consumableRepo.Create(consumable); err != nil {
+			return fmt.Errorf("failed to create consumable %s: %w", consumable.Slug, err)
+		}
+		log.Printf("Created consumable: %s (ID: %s)", consumable.Name, consumable.ID.String())
+	}
+
+	log.Println("Consumables seeding completed!")
+	return nil
+}
+
+func seedQuests(db *sqlx.DB) error {
+	log.Println("Seeding quests...")
+
+	questRepo := repository.NewQuestRepository(db)
+
+	quests := []*domain.Quest{
+		{
+			Name: "Крысиная напасть", Slug: "daily-rats", Description: "Убейте 5 крыс в Wayward Pines.",
+			RequiredLevel: 1, ObjectiveType: domain.QuestObjectiveKillBot, TargetSlug: "rat", TargetCount: 5,
+			RewardGold: 50, RewardExp: 30, Daily: true,
+		},
+		{
+			Name: "Закупка зелий", Slug: "daily-potions", Description: "Купите 3 малых зелья лечения.",
+			RequiredLevel: 1, ObjectiveType: domain.QuestObjectiveBuyItem, TargetSlug: "small-healing-potion", TargetCount: 3,
+			RewardGold: 20, Daily: true,
+		},
+		{
+			Name: "Разведка леса", Slug: "explore-wayward-pines", Description: "Доберитесь до клетки 29 в Wayward Pines.",
+			RequiredLevel: 1, ObjectiveType: domain.QuestObjectiveVisitCell, TargetSlug: "29cell", TargetCount: 1,
+			RewardGold: 30, RewardExp: 20,
+		},
+	}
+
+	for _, quest := range quests {
+		if err := questRepo.Create(quest); err != nil {
+			return fmt.Errorf("failed to create quest %s: %w", quest.Slug, err)
+		}
+		log.Printf("Created quest: %s (ID: %s)", quest.Name, quest.ID.String())
+	}
+
+	log.Println("Quests seeding completed!")
+	return nil
+}
+
+func seedAchievements(db *sqlx.DB) error {
+	log.Println("Seeding achievements...")
+
+	achievementRepo := repository.NewAchievementRepository(db)
+
+	achievements := []*domain.Achievement{
+		{Name: "Первая кровь", Slug: "first-kill", Description: "Победите первого противника.", Type: domain.AchievementKillBots, Threshold: 1},
+		{Name: "Охотник", Slug: "hunter", Description: "Победите 100 противников.", Type: domain.AchievementKillBots, Threshold: 100, Title: strPtr("Охотник")},
+		{Name: "Ветеран", Slug: "level-10", Description: "Достигните 10 уровня.", Type: domain.AchievementReachLevel, Threshold: 10, Title: strPtr("Ветеран")},
+		{Name: "Коллекционер", Slug: "artifact-owner", Description: "Получите артефакт.", Type: domain.AchievementOwnArtifact, Threshold: 1},
+		{Name: "Следопыт", Slug: "wayward-pines-explorer", Description: "Посетите все 64 клетки Wayward Pines.", Type: domain.AchievementVisitCells, Threshold: 64, Title: strPtr("Следопыт")},
+	}
+
+	for _, achievement := range achievements {
+		if err := achievementRepo.Create(achievement); err != nil {
+			return fmt.Errorf("failed to create achievement %s: %w", achievement.Slug, err)
+		}
+		log.Printf("Created achievement: %s (ID: %s)", achievement.Name, achievement.ID.String())
+	}
+
+	log.Println("Achievements seeding completed!")
+	return nil
+}
+
+// seedItemSets makes a set of the artifacts of every required level, each
+// level's artifacts share a look.
+func seedItemSets(db *sqlx.DB) error {
+	log.Println("Seeding item sets...")
+
+	var items []struct {
+		ID            uuid.UUID `db:"id"`
+		RequiredLevel uint      `db:"required_level"`
+	}
+	query := `SELECT id, required_level FROM equipment_items WHERE artifact = true AND deleted_at IS NULL ORDER BY required_level`
+	if err := db.Select(&items, query); err != nil {
+		return fmt.Errorf("failed to load artifacts: %w", err)
+	}
+
+	byLevel := make(map[uint][]uuid.UUID)
+	var levels []uint
+	for _, item := range items {
+		if _, ok := byLevel[item.RequiredLevel]; !ok {
+			levels = append(levels, item.RequiredLevel)
+		}
+		byLevel[item.RequiredLevel] = append(byLevel[item.RequiredLevel], item.ID)
+	}
+
+	itemSetRepo := repository.NewItemSetRepository(db)
+	for _, level := range levels {
+		ids := byLevel[level]
+		if len(ids) < 2 {
+			continue
+		}
+
+		set := &domain.ItemSet{
+			Name: fmt.Sprintf("Комплект %d уровня", level),
+			Slug: fmt.Sprintf("artifact-set-%d", level),
+		}
+		if err := itemSetRepo.Create(set); err != nil {
+			return fmt.Errorf("failed to create item set %s: %w", set.Slug, err)
+		}
+		for _, id := range ids {
+			if err := itemSetRepo.AddItem(set.ID, id); err != nil {
+				return fmt.Errorf("failed to add item to set %s: %w", set.Slug, err)
+			}
+		}
+
+		bonuses := []*domain.ItemSetBonus{
+			{ItemSetID: set.ID, Pieces: 2, Attack: 5 + level},
+		}
+		if len(ids) >= 4 {
+			bonuses = append(bonuses, &domain.ItemSetBonus{ItemSetID: set.ID, Pieces: 4, Hp: 50 + 5*level})
+		}
+		for _, bonus := range bonuses {
+			if err := itemSetRepo.CreateBonus(bonus); err != nil {
+				return fmt.Errorf("failed to create bonus of set %s: %w", set.Slug, err)
+			}
+		}
+		log.Printf("Created item set: %s (%d items)", set.Name, len(ids))
+	}
+
+	log.Println("Item sets seeding completed!")
+	return nil
+}
+
+// seedShopStock stocks artifacts in the artifact shop and everything else,
+// consumables too, in the weapon shop, artifacts in fewer copies.
+func seedShopStock(db *sqlx.DB) error {
+	log.Println("Seeding shop stock...")
+
+	locationRepo := repository.NewLocationRepository(db)
+	shops := make(map[bool]*domain.Location)
+	for artifact, slug := range map[bool]string{false: "weapon_shop", true: "shop_of_artifacts"} {
+		shop, err := locationRepo.FindBySlug(slug)
+		if err != nil {
+			return fmt.Errorf("failed to find shop %s: %w", slug, err)
+		}
+		shops[artifact] = shop
+	}
+
+	items, err := repository.NewEquipmentItemRepository(db).FindAll()
+	if err != nil {
+		return fmt.Errorf("failed to load equipment items: %w", err)
+	}
+
+	stockRepo := repository.NewShopStockRepository(db)
+	for _, item := range items {
+		stock := &domain.ShopStock{
+			LocationID:      shops[item.Artifact].ID,
+			EquipmentItemID: item.ID,
+			Quantity:        10,
+			MaxQuantity:     10,
+			RestockAmount:   2,
+			Price:           max(item.Price, 1),
+		}
+		if item.Artifact {
+			stock.Quantity, stock.MaxQuantity, stock.RestockAmount = 3, 3, 1
+		}
+		if err := stockRepo.Create(stock); err != nil {
+			return fmt.Errorf("failed to stock %s: %w", item.Slug, err)
+		}
+	}
+
+	consumables, err := repository.NewConsumableRepository(db).FindAll()
+	if err != nil {
+		return fmt.Errorf("failed to load consumables: %w", err)
+	}
+
+	for _, consumable := range consumables {
+		stock := &domain.ShopStock{
+			LocationID:    shops[false].ID,
+			ConsumableID:  &consumable.ID,
+			Quantity:      50,
+			MaxQuantity:   50,
+			RestockAmount: 10,
+			Price:         max(consumable.Price, 1),
+		}
+		if err := stockRepo.Create(stock); err != nil {
+			return fmt.Errorf("failed to stock %s: %w", consumable.Slug, err)
+		}
+	}
+
+	log.Printf("Stocked %d items and %d consumables", len(items), len(consumables))
+	return nil
+}
+
 func seedEquipmentCategories(db *sqlx.DB) {
 	log.Println("Seeding equipment categories...")
 
@@ -18,7 +18,11 @@ import (
 
 	"moonshine/cmd/server/docs"
 	"moonshine/internal/api"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
 	"moonshine/internal/config"
+	"moonshine/internal/domain"
+	"moonshine/internal/events"
 	"moonshine/internal/metrics"
 	"moonshine/internal/repository"
 	"moonshine/internal/tracing"
@@ -56,6 +60,23 @@ func main() {
 		log.Fatalf("failed to initialize database: %v", err)
 	}
 
+	progression, err := services.LoadProgression(cfg.ProgressionPath, repository.NewProgressionRepository(db.DB()))
+	if err != nil {
+		db.Close()
+		log.Fatalf("failed to load progression config: %v", err)
+	}
+	if err := domain.SetProgression(progression); err != nil {
+		db.Close()
+		log.Fatalf("invalid progression config: %v", err)
+	}
+	log.Printf("progression config v%d loaded, max level %d", progression.Version, progression.MaxLevel())
+
+	shopSettings := domain.ShopSettings{SellBackPercent: cfg.Shop.SellBackPercent, PriceDrift: cfg.Shop.PriceDrift}
+	if err := domain.SetShopSettings(shopSettings); err != nil {
+		db.Close()
+		log.Fatalf("invalid shop config: %v", err)
+	}
+
 	rdb := redis.New(cfg)
 	if err := redis.Ping(ctx, rdb); err != nil {
 		db.Close()
@@ -88,6 +109,20 @@ func main() {
 		registerPprof(e)
 	}
 
+	achievementService := services.NewAchievementService(
+		repository.NewAchievementRepository(db.DB()),
+		repository.NewUserRepository(db.DB()),
+		ws.GetHub(),
+	)
+	events.GetBus().Subscribe(achievementService.HandleEvent)
+
+	leaderboardService := services.NewLeaderboardService(
+		repository.NewLeaderboardRepository(db.DB()),
+		repository.NewUserRepository(db.DB()),
+		redis.NewLeaderboard(rdb, "leaderboard"),
+	)
+	events.GetBus().Subscribe(leaderboardService.HandleEvent)
+
 	api.SetupRoutes(e, db.DB(), rdb, cfg)
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -101,6 +136,24 @@ func main() {
 	hpWorker := worker.NewHpWorker(db.DB(), rdb, 3*time.Second)
 	go hpWorker.StartWorker(ctx)
 
+	questResetWorker := worker.NewQuestResetWorker(db.DB(), time.Hour)
+	go questResetWorker.StartWorker(ctx)
+
+	leaderboardWorker := worker.NewLeaderboardWorker(db.DB(), rdb, 10*time.Minute)
+	go leaderboardWorker.StartWorker(ctx)
+
+	goldLedgerWorker := worker.NewGoldLedgerWorker(db.DB(), rdb, repository.DSN(cfg))
+	go goldLedgerWorker.StartWorker(ctx)
+
+	auctionWorker := worker.NewAuctionWorker(db.DB(), time.Minute)
+	go auctionWorker.StartWorker(ctx)
+
+	shopRestockWorker := worker.NewShopRestockWorker(db.DB(), cfg.Shop.RestockInterval)
+	go shopRestockWorker.StartWorker(ctx)
+
+	mailWorker := worker.NewMailWorker(db.DB(), 5*time.Minute)
+	go mailWorker.StartWorker(ctx)
+
 	<-ctx.Done()
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
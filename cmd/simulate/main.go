@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/config"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+type fixture struct {
+	Bots           []fixtureBot           `json:"bots"`
+	EquipmentItems []fixtureEquipmentItem `json:"equipment_items"`
+}
+
+type fixtureBot struct {
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Attack  uint   `json:"attack"`
+	Defense uint   `json:"defense"`
+	Hp      uint   `json:"hp"`
+	Level   uint   `json:"level"`
+}
+
+type fixtureEquipmentItem struct {
+	Name           string `json:"name"`
+	Slug           string `json:"slug"`
+	Attack         uint   `json:"attack"`
+	Defense        uint   `json:"defense"`
+	Hp             uint   `json:"hp"`
+	RequiredLevel  uint   `json:"required_level"`
+	Price          uint   `json:"price"`
+	CritChance     uint   `json:"crit_chance"`
+	CritMultiplier uint   `json:"crit_multiplier"`
+	DodgeChance    uint   `json:"dodge_chance"`
+	BlockChance    uint   `json:"block_chance"`
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println(".env not loaded, relying on environment")
+	}
+
+	fixturePath := flag.String("fixture", "", "JSON fixture with bots and equipment_items (loads from the database when empty)")
+	progressionPath := flag.String("progression", "", "Progression config file (uses the database config, or the default with -fixture, when empty)")
+	level := flag.Uint("level", 1, "Player level")
+	exp := flag.Int("exp", -1, "Player exp (defaults to the minimum exp of the level)")
+	attack := flag.Uint("attack", 1, "Player base attack")
+	defense := flag.Uint("defense", 1, "Player base defense")
+	hp := flag.Uint("hp", 20, "Player base hp")
+	agility := flag.Uint("agility", 0, "Player allocated agility")
+	luck := flag.Uint("luck", 0, "Player allocated luck")
+	itemSlugs := flag.String("items", "", "Comma-separated slugs of equipped items")
+	botSlugs := flag.String("bots", "", "Comma-separated slugs of bots to simulate (all bots when empty)")
+	fights := flag.Int("fights", 10000, "Number of fights per bot")
+	csvPath := flag.String("csv", "", "Write the report as CSV to this file (- for stdout)")
+	flag.Parse()
+
+	if *fights <= 0 {
+		log.Fatal("fights must be positive")
+	}
+
+	bots, items, progression, err := load(*fixturePath, *progressionPath)
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+	if err := domain.SetProgression(progression); err != nil {
+		log.Fatalf("Invalid progression config: %v", err)
+	}
+
+	bots = filterBots(bots, splitSlugs(*botSlugs))
+	if len(bots) == 0 {
+		log.Fatal("No bots to simulate")
+	}
+
+	build := services.SimulationBuild{
+		Level:   *level,
+		Attack:  *attack,
+		Defense: *defense,
+		Hp:      *hp,
+		Agility: *agility,
+		Luck:    *luck,
+	}
+	if *exp >= 0 {
+		build.Exp = uint(*exp)
+	} else {
+		build.Exp, _ = progression.RequiredExp(build.Level)
+	}
+
+	equipped, err := pickItems(items, splitSlugs(*itemSlugs))
+	if err != nil {
+		log.Fatalf("Failed to equip items: %v", err)
+	}
+	for _, item := range equipped {
+		if item.RequiredLevel > build.Level {
+			log.Printf("Warning: %s requires level %d", item.Slug, item.RequiredLevel)
+		}
+	}
+	build.Equip(equipped...)
+
+	results := make([]*services.SimulationResult, 0, len(bots))
+	for _, bot := range bots {
+		results = append(results, services.SimulateFights(build, bot, *fights))
+	}
+
+	stats := build.SecondaryStats()
+	fmt.Printf("Player: level %d, exp %d, attack %d, defense %d, hp %d, crit %d%% x%d%%, dodge %d%%, block %d%%, %d fights per bot\n\n",
+		build.Level, build.Exp, build.Attack, build.Defense, build.Hp,
+		stats.CritChance, stats.CritMultiplier, stats.DodgeChance, stats.BlockChance, *fights)
+	writeTable(os.Stdout, build, results)
+
+	if *csvPath == "" {
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if *csvPath != "-" {
+		f, err := os.Create(*csvPath)
+		if err != nil {
+			log.Fatalf("Failed to create CSV file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeCSV(out, build, results); err != nil {
+		log.Fatalf("Failed to write CSV: %v", err)
+	}
+}
+
+func load(fixturePath, progressionPath string) ([]*domain.Bot, []*domain.EquipmentItem, *domain.Progression, error) {
+	if fixturePath != "" {
+		bots, items, err := loadFixture(fixturePath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		progression := domain.DefaultProgression()
+		if progressionPath != "" {
+			progression, err = services.LoadProgression(progressionPath, nil)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		return bots, items, progression, nil
+	}
+
+	db, err := repository.New(config.Load())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("initialize database: %w", err)
+	}
+	defer db.Close()
+
+	bots, err := repository.NewBotRepository(db.DB()).FindAll()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load bots: %w", err)
+	}
+
+	items, err := repository.NewEquipmentItemRepository(db.DB()).FindAll()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load equipment items: %w", err)
+	}
+
+	progression, err := services.LoadProgression(progressionPath, repository.NewProgressionRepository(db.DB()))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return bots, items, progression, nil
+}
+
+func loadFixture(path string) ([]*domain.Bot, []*domain.EquipmentItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read fixture: %w", err)
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, nil, fmt.Errorf("parse fixture: %w", err)
+	}
+
+	bots := make([]*domain.Bot, 0, len(f.Bots))
+	for _, b := range f.Bots {
+		if b.Level == 0 {
+			return nil, nil, fmt.Errorf("bot %s: level must be positive", b.Slug)
+		}
+		bots = append(bots, &domain.Bot{
+			Name:    b.Name,
+			Slug:    b.Slug,
+			Attack:  b.Attack,
+			Defense: b.Defense,
+			Hp:      b.Hp,
+			Level:   b.Level,
+		})
+	}
+
+	items := make([]*domain.EquipmentItem, 0, len(f.EquipmentItems))
+	for _, it := range f.EquipmentItems {
+		items = append(items, &domain.EquipmentItem{
+			Name:          it.Name,
+			Slug:          it.Slug,
+			Attack:        it.Attack,
+			Defense:       it.Defense,
+			Hp:            it.Hp,
+			RequiredLevel: it.RequiredLevel,
+			Price:         it.Price,
+			SecondaryStats: domain.SecondaryStats{
+				CritChance:     it.CritChance,
+				CritMultiplier: it.CritMultiplier,
+				DodgeChance:    it.DodgeChance,
+				BlockChance:    it.BlockChance,
+			},
+		})
+	}
+
+	return bots, items, nil
+}
+
+func splitSlugs(value string) []string {
+	var slugs []string
+	for _, slug := range strings.Split(value, ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+func filterBots(bots []*domain.Bot, slugs []string) []*domain.Bot {
+	if len(slugs) == 0 {
+		return bots
+	}
+
+	wanted := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		wanted[slug] = true
+	}
+
+	var result []*domain.Bot
+	for _, bot := range bots {
+		if wanted[bot.Slug] {
+			result = append(result, bot)
+		}
+	}
+	return result
+}
+
+func pickItems(items []*domain.EquipmentItem, slugs []string) ([]*domain.EquipmentItem, error) {
+	bySlug := make(map[string]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		bySlug[item.Slug] = item
+	}
+
+	result := make([]*domain.EquipmentItem, 0, len(slugs))
+	for _, slug := range slugs {
+		item, ok := bySlug[slug]
+		if !ok {
+			return nil, fmt.Errorf("equipment item %s not found", slug)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+var reportHeader = []string{"bot", "level", "win_rate", "avg_rounds", "gold_per_fight", "exp_per_fight", "fights_to_level"}
+
+func reportRow(build services.SimulationBuild, r *services.SimulationResult) []string {
+	fightsToLevel := "inf"
+	if n := r.FightsToLevel(build); !math.IsInf(n, 1) {
+		fightsToLevel = strconv.FormatFloat(n, 'f', 0, 64)
+	}
+
+	return []string{
+		r.Bot.Slug,
+		strconv.FormatUint(uint64(r.Bot.Level), 10),
+		strconv.FormatFloat(r.WinRate()*100, 'f', 2, 64),
+		strconv.FormatFloat(r.AvgRounds(), 'f', 2, 64),
+		strconv.FormatFloat(r.ExpectedGold(), 'f', 2, 64),
+		strconv.FormatFloat(r.ExpectedExp(), 'f', 2, 64),
+		fightsToLevel,
+	}
+}
+
+func writeTable(w io.Writer, build services.SimulationBuild, results []*services.SimulationResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, strings.Join(reportHeader, "\t")+"\t")
+	for _, r := range results {
+		fmt.Fprintln(tw, strings.Join(reportRow(build, r), "\t")+"\t")
+	}
+	tw.Flush()
+}
+
+func writeCSV(w io.Writer, build services.SimulationBuild, results []*services.SimulationResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := cw.Write(reportRow(build, r)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type Achievement struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	Type        string     `json:"type"`
+	Threshold   int        `json:"threshold"`
+	Title       *string    `json:"title,omitempty"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+}
+
+type SelectTitleRequest struct {
+	AchievementSlug string `json:"achievementSlug"`
+}
+
+func AchievementFromDomain(progress *domain.AchievementProgress) *Achievement {
+	if progress == nil || progress.Achievement == nil {
+		return nil
+	}
+
+	achievement := progress.Achievement
+	return &Achievement{
+		ID:          achievement.ID.String(),
+		Name:        achievement.Name,
+		Slug:        achievement.Slug,
+		Description: achievement.Description,
+		Type:        string(achievement.Type),
+		Threshold:   int(achievement.Threshold),
+		Title:       achievement.Title,
+		Unlocked:    progress.UnlockedAt != nil,
+		UnlockedAt:  progress.UnlockedAt,
+	}
+}
+
+func AchievementsFromDomain(achievements []*domain.AchievementProgress) []*Achievement {
+	result := make([]*Achievement, len(achievements))
+	for i, achievement := range achievements {
+		result[i] = AchievementFromDomain(achievement)
+	}
+	return result
+}
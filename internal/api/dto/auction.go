@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type Auction struct {
+	ID          string         `json:"id"`
+	SellerID    string         `json:"sellerId"`
+	Item        *InventoryItem `json:"item"`
+	StartPrice  int            `json:"startPrice"`
+	BuyoutPrice *int           `json:"buyoutPrice,omitempty"`
+	CurrentBid  *int           `json:"currentBid,omitempty"`
+	BidderID    *string        `json:"bidderId,omitempty"`
+	MinBid      int            `json:"minBid"`
+	ListingFee  int            `json:"listingFee"`
+	Status      string         `json:"status"`
+	ExpiresAt   time.Time      `json:"expiresAt"`
+}
+
+type CreateAuctionRequest struct {
+	InstanceID    string `json:"instanceId"`
+	StartPrice    uint   `json:"startPrice"`
+	BuyoutPrice   *uint  `json:"buyoutPrice"`
+	DurationHours uint   `json:"durationHours"`
+}
+
+type BidRequest struct {
+	Amount uint `json:"amount"`
+}
+
+func AuctionFromDomain(auction *domain.Auction) *Auction {
+	if auction == nil {
+		return nil
+	}
+
+	result := &Auction{
+		ID:         auction.ID.String(),
+		SellerID:   auction.SellerID.String(),
+		StartPrice: int(auction.StartPrice),
+		MinBid:     int(auction.MinBid()),
+		ListingFee: int(auction.ListingFee),
+		Status:     string(auction.Status),
+		ExpiresAt:  auction.ExpiresAt,
+	}
+	if auction.Item != nil {
+		result.Item = InventoryItemFromDomain(auction.Item.EquipmentItem, auction.Item.Instance)
+	}
+	if auction.BuyoutPrice != nil {
+		price := int(*auction.BuyoutPrice)
+		result.BuyoutPrice = &price
+	}
+	if auction.CurrentBid != nil {
+		bid := int(*auction.CurrentBid)
+		result.CurrentBid = &bid
+	}
+	if auction.BidderID != nil {
+		id := auction.BidderID.String()
+		result.BidderID = &id
+	}
+
+	return result
+}
+
+func AuctionsFromDomain(auctions []*domain.Auction) []*Auction {
+	result := make([]*Auction, len(auctions))
+	for i, auction := range auctions {
+		result[i] = AuctionFromDomain(auction)
+	}
+	return result
+}
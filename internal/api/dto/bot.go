@@ -7,16 +7,17 @@ import (
 )
 
 type Bot struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	Attack    int       `json:"attack"`
-	Defense   int       `json:"defense"`
-	Hp        int       `json:"hp"`
-	CurrentHp int       `json:"currentHp"`
-	Level     int       `json:"level"`
-	Avatar    string    `json:"avatar"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Slug         string              `json:"slug"`
+	Attack       int                 `json:"attack"`
+	Defense      int                 `json:"defense"`
+	Hp           int                 `json:"hp"`
+	CurrentHp    int                 `json:"currentHp"`
+	Level        int                 `json:"level"`
+	Avatar       string              `json:"avatar"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	StatusEffect *StatusEffectSource `json:"statusEffect,omitempty"`
 }
 
 func BotFromDomain(bot *domain.Bot) *Bot {
@@ -25,16 +26,17 @@ func BotFromDomain(bot *domain.Bot) *Bot {
 	}
 
 	return &Bot{
-		ID:        bot.ID.String(),
-		Name:      bot.Name,
-		Slug:      bot.Slug,
-		Attack:    int(bot.Attack),
-		Defense:   int(bot.Defense),
-		Hp:        int(bot.Hp),
-		CurrentHp: int(bot.Hp),
-		Level:     int(bot.Level),
-		Avatar:    bot.Avatar,
-		CreatedAt: bot.CreatedAt,
+		ID:           bot.ID.String(),
+		Name:         bot.Name,
+		Slug:         bot.Slug,
+		Attack:       int(bot.Attack),
+		Defense:      int(bot.Defense),
+		Hp:           int(bot.Hp),
+		CurrentHp:    int(bot.Hp),
+		Level:        int(bot.Level),
+		Avatar:       bot.Avatar,
+		CreatedAt:    bot.CreatedAt,
+		StatusEffect: StatusEffectSourceFromDomain(bot.StatusEffectSource),
 	}
 }
 
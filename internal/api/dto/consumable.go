@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"moonshine/internal/domain"
+)
+
+type Consumable struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Slug          string              `json:"slug"`
+	Image         string              `json:"image"`
+	Price         int                 `json:"price"`
+	RequiredLevel int                 `json:"requiredLevel"`
+	EffectType    string              `json:"effectType"`
+	EffectValue   int                 `json:"effectValue"`
+	StatusEffect  *StatusEffectSource `json:"statusEffect,omitempty"`
+}
+
+type ConsumableStack struct {
+	Consumable
+	Quantity int `json:"quantity"`
+}
+
+func ConsumableFromDomain(consumable *domain.Consumable) *Consumable {
+	if consumable == nil {
+		return nil
+	}
+
+	return &Consumable{
+		ID:            consumable.ID.String(),
+		Name:          consumable.Name,
+		Slug:          consumable.Slug,
+		Image:         consumable.Image,
+		Price:         int(consumable.Price),
+		RequiredLevel: int(consumable.RequiredLevel),
+		EffectType:    string(consumable.EffectType),
+		EffectValue:   int(consumable.EffectValue),
+		StatusEffect:  StatusEffectSourceFromDomain(consumable.StatusEffectSource),
+	}
+}
+
+func ConsumablesFromDomain(consumables []*domain.Consumable) []*Consumable {
+	result := make([]*Consumable, len(consumables))
+	for i, consumable := range consumables {
+		result[i] = ConsumableFromDomain(consumable)
+	}
+	return result
+}
+
+func ConsumableStacksFromDomain(stacks []*domain.ConsumableStack) []*ConsumableStack {
+	result := make([]*ConsumableStack, len(stacks))
+	for i, stack := range stacks {
+		result[i] = &ConsumableStack{
+			Consumable: *ConsumableFromDomain(&stack.Consumable),
+			Quantity:   int(stack.Quantity),
+		}
+	}
+	return result
+}
@@ -7,18 +7,29 @@ import (
 )
 
 type EquipmentItem struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	Slug          string    `json:"slug"`
-	Attack        int       `json:"attack"`
-	Defense       int       `json:"defense"`
-	Hp            int       `json:"hp"`
-	RequiredLevel int       `json:"requiredLevel"`
-	Price         int       `json:"price"`
-	Artifact      bool      `json:"artifact"`
-	Image         string    `json:"image"`
-	EquipmentType string    `json:"equipment_type"`
-	CreatedAt     time.Time `json:"createdAt"`
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	DisplayName    string              `json:"displayName"`
+	Slug           string              `json:"slug"`
+	Attack         int                 `json:"attack"`
+	Defense        int                 `json:"defense"`
+	Hp             int                 `json:"hp"`
+	RequiredLevel  int                 `json:"requiredLevel"`
+	Price          int                 `json:"price"`
+	SellPrice      int                 `json:"sellPrice"`
+	Artifact       bool                `json:"artifact"`
+	Image          string              `json:"image"`
+	EquipmentType  string              `json:"equipment_type"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	CritChance     int                 `json:"critChance"`
+	CritMultiplier int                 `json:"critMultiplier"`
+	DodgeChance    int                 `json:"dodgeChance"`
+	BlockChance    int                 `json:"blockChance"`
+	StatusEffect   *StatusEffectSource `json:"statusEffect,omitempty"`
+	MaxStack       int                 `json:"maxStack"`
+	// EnhancementLevel is always 0 for shop items, inventory items set it
+	// from the instance.
+	EnhancementLevel int `json:"enhancementLevel"`
 }
 
 func EquipmentItemFromDomain(item *domain.EquipmentItem) *EquipmentItem {
@@ -27,18 +38,26 @@ func EquipmentItemFromDomain(item *domain.EquipmentItem) *EquipmentItem {
 	}
 
 	return &EquipmentItem{
-		ID:            item.ID.String(),
-		Name:          item.Name,
-		Slug:          item.Slug,
-		Attack:        int(item.Attack),
-		Defense:       int(item.Defense),
-		Hp:            int(item.Hp),
-		RequiredLevel: int(item.RequiredLevel),
-		Price:         int(item.Price),
-		Artifact:      item.Artifact,
-		Image:         item.Image,
-		CreatedAt:     item.CreatedAt,
-		EquipmentType: item.EquipmentType,
+		ID:             item.ID.String(),
+		Name:           item.Name,
+		DisplayName:    item.Name,
+		Slug:           item.Slug,
+		Attack:         int(item.Attack),
+		Defense:        int(item.Defense),
+		Hp:             int(item.Hp),
+		RequiredLevel:  int(item.RequiredLevel),
+		Price:          int(item.Price),
+		SellPrice:      int(domain.CurrentShopSettings().SellPrice(item.Price)),
+		Artifact:       item.Artifact,
+		Image:          item.Image,
+		CreatedAt:      item.CreatedAt,
+		EquipmentType:  item.EquipmentType,
+		CritChance:     int(item.CritChance),
+		CritMultiplier: int(item.CritMultiplier),
+		DodgeChance:    int(item.DodgeChance),
+		BlockChance:    int(item.BlockChance),
+		StatusEffect:   StatusEffectSourceFromDomain(item.StatusEffectSource),
+		MaxStack:       int(max(item.MaxStack, 1)),
 	}
 }
 
@@ -49,3 +68,28 @@ func EquipmentItemsFromDomain(items []*domain.EquipmentItem) []*EquipmentItem {
 	}
 	return result
 }
+
+type EquipmentSlot struct {
+	Name          string `json:"name"`
+	EquipmentType string `json:"equipmentType"`
+}
+
+func EquipmentSlotsFromDomain(slots []*domain.EquipmentSlot) []*EquipmentSlot {
+	result := make([]*EquipmentSlot, len(slots))
+	for i, slot := range slots {
+		result[i] = &EquipmentSlot{Name: slot.Name, EquipmentType: slot.EquipmentType}
+	}
+	return result
+}
+
+type CatalogPage struct {
+	Items      []*EquipmentItem `json:"items"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+func CatalogPageFromDomain(page *domain.CatalogPage) *CatalogPage {
+	return &CatalogPage{
+		Items:      EquipmentItemsFromDomain(page.Items),
+		NextCursor: page.NextCursor,
+	}
+}
@@ -7,30 +7,46 @@ import (
 )
 
 type Round struct {
-	ID                 string    `json:"id"`
-	FightID            string    `json:"fightId"`
-	PlayerDamage       int       `json:"playerDamage"`
-	BotDamage          int       `json:"botDamage"`
-	Status             string    `json:"status"`
-	PlayerHp           int       `json:"playerHp"`
-	BotHp              int       `json:"botHp"`
-	PlayerAttackPoint  *string   `json:"playerAttackPoint,omitempty"`
-	PlayerDefensePoint *string   `json:"playerDefensePoint,omitempty"`
-	BotAttackPoint     *string   `json:"botAttackPoint,omitempty"`
-	BotDefensePoint    *string   `json:"botDefensePoint,omitempty"`
-	CreatedAt          time.Time `json:"createdAt"`
+	ID                 string          `json:"id"`
+	FightID            string          `json:"fightId"`
+	PlayerDamage       int             `json:"playerDamage"`
+	BotDamage          int             `json:"botDamage"`
+	Status             string          `json:"status"`
+	PlayerHp           int             `json:"playerHp"`
+	BotHp              int             `json:"botHp"`
+	PlayerAttackPoint  *string         `json:"playerAttackPoint,omitempty"`
+	PlayerDefensePoint *string         `json:"playerDefensePoint,omitempty"`
+	BotAttackPoint     *string         `json:"botAttackPoint,omitempty"`
+	BotDefensePoint    *string         `json:"botDefensePoint,omitempty"`
+	PlayerHeal         int             `json:"playerHeal"`
+	PlayerConsumableID *string         `json:"playerConsumableId,omitempty"`
+	PlayerEffectDamage int             `json:"playerEffectDamage"`
+	BotEffectDamage    int             `json:"botEffectDamage"`
+	PlayerAbsorbed     int             `json:"playerAbsorbed"`
+	BotAbsorbed        int             `json:"botAbsorbed"`
+	PlayerStunned      bool            `json:"playerStunned"`
+	BotStunned         bool            `json:"botStunned"`
+	PlayerCrit         bool            `json:"playerCrit"`
+	BotCrit            bool            `json:"botCrit"`
+	PlayerDodged       bool            `json:"playerDodged"`
+	BotDodged          bool            `json:"botDodged"`
+	PlayerBlocked      bool            `json:"playerBlocked"`
+	BotBlocked         bool            `json:"botBlocked"`
+	Effects            []*StatusEffect `json:"effects"`
+	CreatedAt          time.Time       `json:"createdAt"`
 }
 
 type Fight struct {
-	ID            string    `json:"id"`
-	UserID        string    `json:"userId"`
-	BotID         string    `json:"botId"`
-	Status        string    `json:"status"`
-	DroppedGold   int       `json:"droppedGold"`
-	Exp           int       `json:"exp"`
-	DroppedItemID *string   `json:"droppedItemId,omitempty"`
-	Rounds        []*Round  `json:"rounds"`
-	CreatedAt     time.Time `json:"createdAt"`
+	ID            string          `json:"id"`
+	UserID        string          `json:"userId"`
+	BotID         string          `json:"botId"`
+	Status        string          `json:"status"`
+	DroppedGold   int             `json:"droppedGold"`
+	Exp           int             `json:"exp"`
+	DroppedItemID *string         `json:"droppedItemId,omitempty"`
+	Rounds        []*Round        `json:"rounds"`
+	Effects       []*StatusEffect `json:"effects"`
+	CreatedAt     time.Time       `json:"createdAt"`
 }
 
 func RoundFromDomain(round *domain.Round) *Round {
@@ -39,14 +55,28 @@ func RoundFromDomain(round *domain.Round) *Round {
 	}
 
 	result := &Round{
-		ID:           round.ID.String(),
-		FightID:      round.FightID.String(),
-		PlayerDamage: int(round.PlayerDamage),
-		BotDamage:    int(round.BotDamage),
-		Status:       string(round.Status),
-		PlayerHp:     round.PlayerHp,
-		BotHp:        round.BotHp,
-		CreatedAt:    round.CreatedAt,
+		ID:                 round.ID.String(),
+		FightID:            round.FightID.String(),
+		PlayerDamage:       int(round.PlayerDamage),
+		BotDamage:          int(round.BotDamage),
+		Status:             string(round.Status),
+		PlayerHp:           round.PlayerHp,
+		BotHp:              round.BotHp,
+		PlayerHeal:         int(round.PlayerHeal),
+		PlayerEffectDamage: int(round.PlayerEffectDamage),
+		BotEffectDamage:    int(round.BotEffectDamage),
+		PlayerAbsorbed:     int(round.PlayerAbsorbed),
+		BotAbsorbed:        int(round.BotAbsorbed),
+		PlayerStunned:      round.PlayerStunned,
+		BotStunned:         round.BotStunned,
+		PlayerCrit:         round.PlayerCrit,
+		BotCrit:            round.BotCrit,
+		PlayerDodged:       round.PlayerDodged,
+		BotDodged:          round.BotDodged,
+		PlayerBlocked:      round.PlayerBlocked,
+		BotBlocked:         round.BotBlocked,
+		Effects:            StatusEffectsFromDomain(round.Effects),
+		CreatedAt:          round.CreatedAt,
 	}
 
 	if round.PlayerAttackPoint != nil {
@@ -65,6 +95,10 @@ func RoundFromDomain(round *domain.Round) *Round {
 		part := string(*round.BotDefensePoint)
 		result.BotDefensePoint = &part
 	}
+	if round.PlayerConsumableID != nil {
+		id := round.PlayerConsumableID.String()
+		result.PlayerConsumableID = &id
+	}
 
 	return result
 }
@@ -91,6 +125,7 @@ func FightFromDomain(fight *domain.Fight) *Fight {
 		Exp:         int(fight.Exp),
 		CreatedAt:   fight.CreatedAt,
 		Rounds:      []*Round{},
+		Effects:     StatusEffectsFromDomain(fight.Effects),
 	}
 
 	if fight.Rounds != nil {
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type GoldTransaction struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Delta        int64     `json:"delta"`
+	Reason       string    `json:"reason"`
+	ReferenceID  *string   `json:"referenceId,omitempty"`
+	BalanceAfter int64     `json:"balanceAfter"`
+}
+
+func GoldTransactionFromDomain(transaction *domain.GoldTransaction) *GoldTransaction {
+	if transaction == nil {
+		return nil
+	}
+
+	result := &GoldTransaction{
+		ID:           transaction.ID.String(),
+		CreatedAt:    transaction.CreatedAt,
+		Delta:        transaction.Delta,
+		Reason:       string(transaction.Reason),
+		BalanceAfter: transaction.BalanceAfter,
+	}
+	if transaction.ReferenceID != nil {
+		id := transaction.ReferenceID.String()
+		result.ReferenceID = &id
+	}
+
+	return result
+}
+
+func GoldTransactionsFromDomain(transactions []*domain.GoldTransaction) []*GoldTransaction {
+	result := make([]*GoldTransaction, len(transactions))
+	for i, transaction := range transactions {
+		result[i] = GoldTransactionFromDomain(transaction)
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type Guild struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Tag       string         `json:"tag"`
+	Treasury  int            `json:"treasury"`
+	Members   []*GuildMember `json:"members"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type GuildMember struct {
+	UserID      string    `json:"userId"`
+	Username    string    `json:"username"`
+	Level       int       `json:"level"`
+	Rank        string    `json:"rank"`
+	Permissions []string  `json:"permissions"`
+	JoinedAt    time.Time `json:"joinedAt"`
+}
+
+type GuildInvite struct {
+	GuildID   string    `json:"guildId"`
+	Name      string    `json:"name"`
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type GuildTransaction struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	Delta        int64     `json:"delta"`
+	BalanceAfter int64     `json:"balanceAfter"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type GuildMessage struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId,omitempty"`
+	Username  string    `json:"username"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateGuildRequest struct {
+	Name string `json:"name"`
+	Tag  string `json:"tag"`
+}
+
+type GuildInviteRequest struct {
+	Username string `json:"username"`
+}
+
+type GuildRankRequest struct {
+	Rank string `json:"rank"`
+}
+
+type GuildTreasuryRequest struct {
+	Amount uint `json:"amount"`
+}
+
+func GuildFromDomain(guild *domain.Guild) *Guild {
+	result := &Guild{
+		ID:        guild.ID.String(),
+		Name:      guild.Name,
+		Tag:       guild.Tag,
+		Treasury:  int(guild.Treasury),
+		Members:   make([]*GuildMember, len(guild.Members)),
+		CreatedAt: guild.CreatedAt,
+	}
+	for i, member := range guild.Members {
+		result.Members[i] = GuildMemberFromDomain(member)
+	}
+	return result
+}
+
+func GuildMemberFromDomain(member *domain.GuildMember) *GuildMember {
+	permissions := member.Rank.Permissions()
+	result := &GuildMember{
+		UserID:      member.UserID.String(),
+		Username:    member.Username,
+		Level:       int(member.Level),
+		Rank:        string(member.Rank),
+		Permissions: make([]string, len(permissions)),
+		JoinedAt:    member.JoinedAt,
+	}
+	for i, permission := range permissions {
+		result.Permissions[i] = string(permission)
+	}
+	return result
+}
+
+func GuildInvitesFromDomain(invites []*domain.GuildInvite) []*GuildInvite {
+	result := make([]*GuildInvite, len(invites))
+	for i, invite := range invites {
+		result[i] = &GuildInvite{
+			GuildID:   invite.GuildID.String(),
+			Name:      invite.GuildName,
+			Tag:       invite.GuildTag,
+			CreatedAt: invite.CreatedAt,
+			ExpiresAt: invite.ExpiresAt,
+		}
+	}
+	return result
+}
+
+func GuildTransactionFromDomain(transaction *domain.GuildTransaction) *GuildTransaction {
+	return &GuildTransaction{
+		ID:           transaction.ID.String(),
+		Username:     transaction.Username,
+		Delta:        transaction.Delta,
+		BalanceAfter: transaction.BalanceAfter,
+		CreatedAt:    transaction.CreatedAt,
+	}
+}
+
+func GuildTransactionsFromDomain(transactions []*domain.GuildTransaction) []*GuildTransaction {
+	result := make([]*GuildTransaction, len(transactions))
+	for i, transaction := range transactions {
+		result[i] = GuildTransactionFromDomain(transaction)
+	}
+	return result
+}
+
+func GuildMessagesFromDomain(messages []*domain.GuildMessage) []*GuildMessage {
+	result := make([]*GuildMessage, len(messages))
+	for i, message := range messages {
+		result[i] = &GuildMessage{
+			ID:        message.ID.String(),
+			Username:  message.Username,
+			Text:      message.Text,
+			CreatedAt: message.CreatedAt,
+		}
+		if message.UserID != nil {
+			result[i].UserID = message.UserID.String()
+		}
+	}
+	return result
+}
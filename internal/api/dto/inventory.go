@@ -0,0 +1,94 @@
+package dto
+
+import "moonshine/internal/domain"
+
+// InventoryItem is an item instance, the item fields come first so clients
+// that only know EquipmentItem keep working.
+type InventoryItem struct {
+	*EquipmentItem
+	InstanceID    string `json:"instanceId,omitempty"`
+	Quantity      int    `json:"quantity"`
+	Durability    int    `json:"durability"`
+	MaxDurability int    `json:"maxDurability"`
+	BonusAttack   int    `json:"bonusAttack"`
+	BonusDefense  int    `json:"bonusDefense"`
+	BonusHp       int    `json:"bonusHp"`
+	Broken        bool   `json:"broken"`
+	RepairCost    int    `json:"repairCost"`
+	// Effective stats include the enhancement level and the bonus, broken
+	// instances still show them.
+	EffectiveAttack  int `json:"effectiveAttack"`
+	EffectiveDefense int `json:"effectiveDefense"`
+	EffectiveHp      int `json:"effectiveHp"`
+	// Set is only filled for equipped items.
+	Set *ItemSet `json:"set,omitempty"`
+}
+
+// EnhanceResult is the instance after an enhancement attempt, failed attempts
+// return it unchanged.
+type EnhanceResult struct {
+	Success bool           `json:"success"`
+	Item    *InventoryItem `json:"item"`
+}
+
+// Bank is what the user keeps in the bank.
+type Bank struct {
+	Items    []*InventoryItem `json:"items"`
+	Capacity int              `json:"capacity"`
+}
+
+// InventoryCapacity is the bag size after an upgrade. NextUpgradeCost is left
+// out once the bag can't grow any more.
+type InventoryCapacity struct {
+	Capacity        int  `json:"capacity"`
+	NextUpgradeCost *int `json:"nextUpgradeCost,omitempty"`
+}
+
+func InventoryItemFromDomain(item *domain.EquipmentItem, instance *domain.Inventory) *InventoryItem {
+	if item == nil {
+		return nil
+	}
+
+	result := &InventoryItem{EquipmentItem: EquipmentItemFromDomain(item), Quantity: 1}
+	if instance == nil {
+		result.EffectiveAttack = int(item.Attack)
+		result.EffectiveDefense = int(item.Defense)
+		result.EffectiveHp = int(item.Hp)
+		return result
+	}
+
+	result.InstanceID = instance.ID.String()
+	result.Quantity = int(max(instance.Quantity, 1))
+	result.Durability = int(instance.Durability)
+	result.MaxDurability = int(instance.MaxDurability)
+	result.BonusAttack = int(instance.BonusAttack)
+	result.BonusDefense = int(instance.BonusDefense)
+	result.BonusHp = int(instance.BonusHp)
+	result.Broken = instance.Broken()
+	result.RepairCost = int(instance.RepairCost(item))
+	result.EnhancementLevel = int(instance.EnhancementLevel)
+	result.DisplayName = domain.EnhancedName(item.Name, instance.EnhancementLevel)
+	result.EffectiveAttack, result.EffectiveDefense, result.EffectiveHp = instance.FullStats(item)
+	return result
+}
+
+func InventoryItemsFromDomain(items []*domain.InventoryItem) []*InventoryItem {
+	result := make([]*InventoryItem, len(items))
+	for i, item := range items {
+		result[i] = InventoryItemFromDomain(item.EquipmentItem, item.Instance)
+	}
+	return result
+}
+
+func BankFromDomain(items []*domain.InventoryItem) *Bank {
+	return &Bank{Items: InventoryItemsFromDomain(items), Capacity: domain.BankCapacity}
+}
+
+func InventoryCapacityFromDomain(capacity uint) *InventoryCapacity {
+	result := &InventoryCapacity{Capacity: int(capacity)}
+	if cost, ok := domain.InventoryUpgradeCost(capacity); ok {
+		next := int(cost)
+		result.NextUpgradeCost = &next
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "moonshine/internal/domain"
+
+type StatDelta struct {
+	Attack      int `json:"attack"`
+	Defense     int `json:"defense"`
+	Hp          int `json:"hp"`
+	CritChance  int `json:"critChance"`
+	DodgeChance int `json:"dodgeChance"`
+	BlockChance int `json:"blockChance"`
+}
+
+// ItemComparison is an item against what the user wears in Slot, Equipped is
+// left out for an empty slot.
+type ItemComparison struct {
+	Item       *EquipmentItem `json:"item"`
+	Slot       string         `json:"slot"`
+	Equipped   *InventoryItem `json:"equipped,omitempty"`
+	Delta      StatDelta      `json:"delta"`
+	Price      int            `json:"price"`
+	MeetsLevel bool           `json:"meetsLevel"`
+	CanAfford  bool           `json:"canAfford"`
+}
+
+func ItemComparisonFromDomain(comparison *domain.ItemComparison) *ItemComparison {
+	result := &ItemComparison{
+		Item: EquipmentItemFromDomain(comparison.Item),
+		Slot: comparison.Slot,
+		Delta: StatDelta{
+			Attack:      comparison.Delta.Attack,
+			Defense:     comparison.Delta.Defense,
+			Hp:          comparison.Delta.Hp,
+			CritChance:  comparison.Delta.CritChance,
+			DodgeChance: comparison.Delta.DodgeChance,
+			BlockChance: comparison.Delta.BlockChance,
+		},
+		Price:      int(comparison.Price),
+		MeetsLevel: comparison.MeetsLevel,
+		CanAfford:  comparison.CanAfford,
+	}
+	if comparison.Equipped != nil {
+		result.Equipped = InventoryItemFromDomain(comparison.Equipped.EquipmentItem, comparison.Equipped.Instance)
+	}
+	return result
+}
+
+func ItemComparisonsFromDomain(comparisons []*domain.ItemComparison) []*ItemComparison {
+	result := make([]*ItemComparison, len(comparisons))
+	for i, comparison := range comparisons {
+		result[i] = ItemComparisonFromDomain(comparison)
+	}
+	return result
+}
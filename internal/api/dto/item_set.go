@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+type ItemSetBonus struct {
+	Pieces  int  `json:"pieces"`
+	Attack  int  `json:"attack"`
+	Defense int  `json:"defense"`
+	Hp      int  `json:"hp"`
+	Active  bool `json:"active"`
+}
+
+type ItemSet struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Slug           string          `json:"slug"`
+	EquippedPieces int             `json:"equippedPieces"`
+	TotalPieces    int             `json:"totalPieces"`
+	Bonuses        []*ItemSetBonus `json:"bonuses"`
+}
+
+// ItemSetFromDomain shows the set as worn with the equipped items.
+func ItemSetFromDomain(set *domain.ItemSet, equipped []uuid.UUID) *ItemSet {
+	if set == nil {
+		return nil
+	}
+
+	pieces := set.EquippedPieces(equipped)
+	bonuses := make([]*ItemSetBonus, len(set.Bonuses))
+	for i, bonus := range set.Bonuses {
+		bonuses[i] = &ItemSetBonus{
+			Pieces:  int(bonus.Pieces),
+			Attack:  int(bonus.Attack),
+			Defense: int(bonus.Defense),
+			Hp:      int(bonus.Hp),
+			Active:  pieces >= bonus.Pieces,
+		}
+	}
+
+	return &ItemSet{
+		ID:             set.ID.String(),
+		Name:           set.Name,
+		Slug:           set.Slug,
+		EquippedPieces: int(pieces),
+		TotalPieces:    len(set.ItemIDs),
+		Bonuses:        bonuses,
+	}
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"moonshine/internal/api/services"
+)
+
+type LeaderboardEntry struct {
+	Rank     int64   `json:"rank"`
+	Score    float64 `json:"score"`
+	UserID   string  `json:"userId"`
+	Username string  `json:"username"`
+	Level    int     `json:"level"`
+	Title    *string `json:"title,omitempty"`
+	Avatar   string  `json:"avatar"`
+}
+
+type LeaderboardRank struct {
+	Rank  int64   `json:"rank"`
+	Score float64 `json:"score"`
+}
+
+type Leaderboard struct {
+	Kind    string              `json:"kind"`
+	Page    int                 `json:"page"`
+	Limit   int                 `json:"limit"`
+	Total   int64               `json:"total"`
+	Entries []*LeaderboardEntry `json:"entries"`
+	Me      *LeaderboardRank    `json:"me"`
+}
+
+func LeaderboardFromPage(page *services.LeaderboardPage) *Leaderboard {
+	if page == nil {
+		return nil
+	}
+
+	entries := make([]*LeaderboardEntry, len(page.Rows))
+	for i, row := range page.Rows {
+		entries[i] = &LeaderboardEntry{
+			Rank:     row.Rank,
+			Score:    row.Score,
+			UserID:   row.User.ID.String(),
+			Username: row.User.Username,
+			Level:    int(row.User.Level),
+			Title:    row.User.Title,
+			Avatar:   row.User.Avatar,
+		}
+	}
+
+	result := &Leaderboard{
+		Kind:    string(page.Kind),
+		Page:    page.Page,
+		Limit:   page.Limit,
+		Total:   page.Total,
+		Entries: entries,
+	}
+	if page.Me != nil {
+		result.Me = &LeaderboardRank{Rank: page.Me.Rank, Score: page.Me.Score}
+	}
+
+	return result
+}
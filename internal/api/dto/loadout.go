@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type Loadout struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Slots     []*LoadoutSlot `json:"slots"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+type LoadoutSlot struct {
+	Slot       string         `json:"slot"`
+	InstanceID *string        `json:"instanceId,omitempty"`
+	Item       *EquipmentItem `json:"item"`
+}
+
+// ApplyLoadoutResult lists the slots that stayed empty because the user no
+// longer has their item.
+type ApplyLoadoutResult struct {
+	Loadout *Loadout       `json:"loadout"`
+	Missing []*LoadoutSlot `json:"missing"`
+}
+
+type SaveLoadoutRequest struct {
+	Name string `json:"name"`
+}
+
+func LoadoutFromDomain(loadout *domain.Loadout) *Loadout {
+	if loadout == nil {
+		return nil
+	}
+
+	return &Loadout{
+		ID:        loadout.ID.String(),
+		Name:      loadout.Name,
+		Slots:     LoadoutSlotsFromDomain(loadout.Slots),
+		UpdatedAt: loadout.UpdatedAt,
+	}
+}
+
+func LoadoutsFromDomain(loadouts []*domain.Loadout) []*Loadout {
+	result := make([]*Loadout, len(loadouts))
+	for i, loadout := range loadouts {
+		result[i] = LoadoutFromDomain(loadout)
+	}
+	return result
+}
+
+func LoadoutSlotsFromDomain(slots []*domain.LoadoutSlot) []*LoadoutSlot {
+	result := make([]*LoadoutSlot, len(slots))
+	for i, slot := range slots {
+		result[i] = &LoadoutSlot{Slot: slot.Slot, Item: EquipmentItemFromDomain(slot.Item)}
+		if slot.InventoryID != nil {
+			id := slot.InventoryID.String()
+			result[i].InstanceID = &id
+		}
+	}
+	return result
+}
+
+func ApplyLoadoutResultFromDomain(loadout *domain.Loadout, missing []*domain.LoadoutSlot) *ApplyLoadoutResult {
+	return &ApplyLoadoutResult{
+		Loadout: LoadoutFromDomain(loadout),
+		Missing: LoadoutSlotsFromDomain(missing),
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type Mail struct {
+	ID        string           `json:"id"`
+	From      string           `json:"from"`
+	System    bool             `json:"system"`
+	Returned  bool             `json:"returned"`
+	Subject   string           `json:"subject"`
+	Body      string           `json:"body"`
+	Gold      int              `json:"gold"`
+	Items     []*InventoryItem `json:"items"`
+	Read      bool             `json:"read"`
+	Collected bool             `json:"collected"`
+	CreatedAt time.Time        `json:"createdAt"`
+	ExpiresAt time.Time        `json:"expiresAt"`
+}
+
+type SendMailRequest struct {
+	To          string   `json:"to"`
+	Subject     string   `json:"subject"`
+	Body        string   `json:"body"`
+	Gold        uint     `json:"gold"`
+	InstanceIDs []string `json:"instanceIds"`
+}
+
+type UnreadMailCount struct {
+	Unread int `json:"unread"`
+}
+
+func MailFromDomain(mail *domain.Mail) *Mail {
+	result := &Mail{
+		ID:        mail.ID.String(),
+		From:      mail.SenderName,
+		System:    mail.System(),
+		Returned:  mail.Returned,
+		Subject:   mail.Subject,
+		Body:      mail.Body,
+		Gold:      int(mail.Gold),
+		Items:     make([]*InventoryItem, len(mail.Items)),
+		Read:      mail.Read(),
+		Collected: mail.CollectedAt != nil,
+		CreatedAt: mail.CreatedAt,
+		ExpiresAt: mail.ExpiresAt,
+	}
+	for i, item := range mail.Items {
+		result.Items[i] = InventoryItemFromDomain(item.EquipmentItem, item.Instance)
+	}
+	return result
+}
+
+func MailsFromDomain(mails []*domain.Mail) []*Mail {
+	result := make([]*Mail, len(mails))
+	for i, mail := range mails {
+		result[i] = MailFromDomain(mail)
+	}
+	return result
+}
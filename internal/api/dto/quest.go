@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type Quest struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	Slug                  string     `json:"slug"`
+	Description           string     `json:"description"`
+	RequiredLevel         int        `json:"requiredLevel"`
+	ObjectiveType         string     `json:"objectiveType"`
+	TargetSlug            string     `json:"targetSlug"`
+	TargetCount           int        `json:"targetCount"`
+	RewardGold            int        `json:"rewardGold"`
+	RewardExp             int        `json:"rewardExp"`
+	RewardEquipmentItemID *string    `json:"rewardEquipmentItemId,omitempty"`
+	Daily                 bool       `json:"daily"`
+	Accepted              bool       `json:"accepted"`
+	Progress              int        `json:"progress"`
+	Status                string     `json:"status,omitempty"`
+	CompletedAt           *time.Time `json:"completedAt,omitempty"`
+	ClaimedAt             *time.Time `json:"claimedAt,omitempty"`
+}
+
+func QuestFromDomain(progress *domain.QuestProgress) *Quest {
+	if progress == nil || progress.Quest == nil {
+		return nil
+	}
+
+	quest := progress.Quest
+	result := &Quest{
+		ID:            quest.ID.String(),
+		Name:          quest.Name,
+		Slug:          quest.Slug,
+		Description:   quest.Description,
+		RequiredLevel: int(quest.RequiredLevel),
+		ObjectiveType: string(quest.ObjectiveType),
+		TargetSlug:    quest.TargetSlug,
+		TargetCount:   int(quest.TargetCount),
+		RewardGold:    int(quest.RewardGold),
+		RewardExp:     int(quest.RewardExp),
+		Daily:         quest.Daily,
+	}
+
+	if quest.RewardEquipmentItemID != nil {
+		id := quest.RewardEquipmentItemID.String()
+		result.RewardEquipmentItemID = &id
+	}
+
+	if userQuest := progress.UserQuest; userQuest != nil {
+		result.Accepted = true
+		result.Progress = int(userQuest.Progress)
+		result.Status = string(userQuest.Status)
+		result.CompletedAt = userQuest.CompletedAt
+		result.ClaimedAt = userQuest.ClaimedAt
+	}
+
+	return result
+}
+
+func QuestsFromDomain(quests []*domain.QuestProgress) []*Quest {
+	result := make([]*Quest, len(quests))
+	for i, quest := range quests {
+		result[i] = QuestFromDomain(quest)
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "moonshine/internal/domain"
+
+// ShopStockItem is an item or a consumable a shop has in stock, Price is
+// what the shop asks now and may differ from the base price.
+type ShopStockItem struct {
+	Item        *EquipmentItem `json:"item,omitempty"`
+	Consumable  *Consumable    `json:"consumable,omitempty"`
+	Quantity    int            `json:"quantity"`
+	MaxQuantity int            `json:"maxQuantity"`
+	Price       int            `json:"price"`
+}
+
+func ShopStockFromDomain(stocks []*domain.ShopStock) []*ShopStockItem {
+	result := make([]*ShopStockItem, 0, len(stocks))
+	for _, stock := range stocks {
+		entry := &ShopStockItem{
+			Quantity:    int(stock.Quantity),
+			MaxQuantity: int(stock.MaxQuantity),
+			Price:       int(stock.Price),
+		}
+		switch {
+		case stock.Item != nil:
+			entry.Item = EquipmentItemFromDomain(stock.Item)
+		case stock.Consumable != nil:
+			entry.Consumable = ConsumableFromDomain(stock.Consumable)
+		default:
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"moonshine/internal/domain"
+)
+
+type StatusEffect struct {
+	ID         string `json:"id"`
+	RoundID    string `json:"roundId"`
+	Target     string `json:"target"`
+	Type       string `json:"type"`
+	Value      int    `json:"value"`
+	RoundsLeft int    `json:"roundsLeft"`
+}
+
+type StatusEffectSource struct {
+	Type     string `json:"type"`
+	Value    int    `json:"value"`
+	Duration int    `json:"duration"`
+	Chance   int    `json:"chance"`
+}
+
+func StatusEffectFromDomain(effect *domain.StatusEffect) *StatusEffect {
+	if effect == nil {
+		return nil
+	}
+
+	return &StatusEffect{
+		ID:         effect.ID.String(),
+		RoundID:    effect.RoundID.String(),
+		Target:     string(effect.Target),
+		Type:       string(effect.EffectType),
+		Value:      int(effect.Value),
+		RoundsLeft: int(effect.RoundsLeft),
+	}
+}
+
+func StatusEffectsFromDomain(effects []*domain.StatusEffect) []*StatusEffect {
+	result := make([]*StatusEffect, len(effects))
+	for i, effect := range effects {
+		result[i] = StatusEffectFromDomain(effect)
+	}
+	return result
+}
+
+func StatusEffectSourceFromDomain(source domain.StatusEffectSource) *StatusEffectSource {
+	if !source.HasStatusEffect() {
+		return nil
+	}
+
+	return &StatusEffectSource{
+		Type:     string(*source.StatusEffectType),
+		Value:    int(source.StatusEffectValue),
+		Duration: int(source.StatusEffectDuration),
+		Chance:   int(source.StatusEffectChance),
+	}
+}
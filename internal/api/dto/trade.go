@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+// TradeSide is what one player offers.
+type TradeSide struct {
+	UserID    string           `json:"userId"`
+	Gold      int              `json:"gold"`
+	Confirmed bool             `json:"confirmed"`
+	Items     []*InventoryItem `json:"items"`
+}
+
+type Trade struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	LocationID  string     `json:"locationId"`
+	Initiator   *TradeSide `json:"initiator"`
+	Partner     *TradeSide `json:"partner"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+type StartTradeRequest struct {
+	PartnerID string `json:"partnerId"`
+}
+
+type TradeOfferRequest struct {
+	Items []string `json:"items"`
+	Gold  uint     `json:"gold"`
+}
+
+func TradeFromDomain(trade *domain.Trade) *Trade {
+	if trade == nil {
+		return nil
+	}
+
+	return &Trade{
+		ID:          trade.ID.String(),
+		Status:      string(trade.Status),
+		LocationID:  trade.LocationID.String(),
+		Initiator:   tradeSideFromDomain(trade, trade.InitiatorID),
+		Partner:     tradeSideFromDomain(trade, trade.PartnerID),
+		CreatedAt:   trade.CreatedAt,
+		CompletedAt: trade.CompletedAt,
+	}
+}
+
+func tradeSideFromDomain(trade *domain.Trade, userID uuid.UUID) *TradeSide {
+	return &TradeSide{
+		UserID:    userID.String(),
+		Gold:      int(trade.Gold(userID)),
+		Confirmed: trade.Confirmed(userID),
+		Items:     InventoryItemsFromDomain(trade.ItemsOf(userID)),
+	}
+}
@@ -3,42 +3,44 @@ package dto
 import (
 	"time"
 
-	"github.com/google/uuid"
-
 	"moonshine/internal/domain"
 )
 
 type User struct {
-	ID                    string    `json:"id"`
-	Username              string    `json:"username"`
-	Email                 string    `json:"email"`
-	Hp                    int       `json:"hp"`
-	CurrentHp             int       `json:"currentHp"`
-	Attack                int       `json:"attack"`
-	Defense               int       `json:"defense"`
-	Level                 int       `json:"level"`
-	Gold                  int       `json:"gold"`
-	Exp                   int       `json:"exp"`
-	FreeStats             int       `json:"freeStats"`
-	CreatedAt             time.Time `json:"createdAt"`
-	Avatar                string    `json:"avatar"`
-	ChestEquipmentItemID  *string   `json:"chestEquipmentItemId,omitempty"`
-	BeltEquipmentItemID   *string   `json:"beltEquipmentItemId,omitempty"`
-	HeadEquipmentItemID   *string   `json:"headEquipmentItemId,omitempty"`
-	NeckEquipmentItemID   *string   `json:"neckEquipmentItemId,omitempty"`
-	WeaponEquipmentItemID *string   `json:"weaponEquipmentItemId,omitempty"`
-	ShieldEquipmentItemID *string   `json:"shieldEquipmentItemId,omitempty"`
-	LegsEquipmentItemID   *string   `json:"legsEquipmentItemId,omitempty"`
-	FeetEquipmentItemID   *string   `json:"feetEquipmentItemId,omitempty"`
-	ArmsEquipmentItemID   *string   `json:"armsEquipmentItemId,omitempty"`
-	HandsEquipmentItemID  *string   `json:"handsEquipmentItemId,omitempty"`
-	Ring1EquipmentItemID  *string   `json:"ring1EquipmentItemId,omitempty"`
-	Ring2EquipmentItemID  *string   `json:"ring2EquipmentItemId,omitempty"`
-	Ring3EquipmentItemID  *string   `json:"ring3EquipmentItemId,omitempty"`
-	Ring4EquipmentItemID  *string   `json:"ring4EquipmentItemId,omitempty"`
-	LocationSlug          *string   `json:"locationSlug,omitempty"`
-	Location              *Location `json:"location,omitempty"`
-	InFight               bool      `json:"inFight"`
+	ID                string            `json:"id"`
+	Username          string            `json:"username"`
+	Email             string            `json:"email"`
+	Hp                int               `json:"hp"`
+	CurrentHp         int               `json:"currentHp"`
+	Attack            int               `json:"attack"`
+	Defense           int               `json:"defense"`
+	BaseHp            int               `json:"baseHp"`
+	BaseAttack        int               `json:"baseAttack"`
+	BaseDefense       int               `json:"baseDefense"`
+	Level             int               `json:"level"`
+	Gold              int               `json:"gold"`
+	Exp               int               `json:"exp"`
+	FreeStats         int               `json:"freeStats"`
+	Agility           int               `json:"agility"`
+	Luck              int               `json:"luck"`
+	Title             *string           `json:"title,omitempty"`
+	GuildTag          *string           `json:"guildTag,omitempty"`
+	InventoryCapacity int               `json:"inventoryCapacity"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	Avatar            string            `json:"avatar"`
+	Equipment         map[string]string `json:"equipment"`
+	LocationSlug      *string           `json:"locationSlug,omitempty"`
+	Location          *Location         `json:"location,omitempty"`
+	InFight           bool              `json:"inFight"`
+}
+
+type OnlinePlayer struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Level    int     `json:"level"`
+	Avatar   string  `json:"avatar"`
+	Title    *string `json:"title,omitempty"`
+	GuildTag *string `json:"guildTag,omitempty"`
 }
 
 type Location struct {
@@ -60,47 +62,33 @@ func UserFromDomain(user *domain.User, location *domain.Location, bots []*domain
 	}
 
 	result := &User{
-		ID:        user.ID.String(),
-		Username:  user.Username,
-		Email:     user.Email,
-		Hp:        int(user.Hp),
-		CurrentHp: user.CurrentHp,
-		Attack:    int(user.Attack),
-		Defense:   int(user.Defense),
-		Level:     int(user.Level),
-		Gold:      int(user.Gold),
-		Exp:       int(user.Exp),
-		FreeStats: int(user.FreeStats),
-		CreatedAt: user.CreatedAt,
-		InFight:   inFight,
-		Avatar:    user.Avatar,
+		ID:          user.ID.String(),
+		Username:    user.Username,
+		Email:       user.Email,
+		Hp:          int(user.Hp),
+		CurrentHp:   user.CurrentHp,
+		Attack:      int(user.Attack),
+		Defense:     int(user.Defense),
+		BaseHp:      int(user.BaseHp),
+		BaseAttack:  int(user.BaseAttack),
+		BaseDefense: int(user.BaseDefense),
+		Level:       int(user.Level),
+		Gold:        int(user.Gold),
+		Exp:         int(user.Exp),
+		FreeStats:   int(user.FreeStats),
+		Agility:     int(user.Agility),
+		Luck:        int(user.Luck),
+		Title:       user.Title,
+		GuildTag:    user.GuildTag,
+		CreatedAt:   user.CreatedAt,
+		InFight:     inFight,
+		Avatar:      user.Avatar,
 	}
+	result.InventoryCapacity = int(user.InventoryCapacity)
 
-	equipmentItemFields := []struct {
-		source *uuid.UUID
-		target **string
-	}{
-		{user.ChestEquipmentItemID, &result.ChestEquipmentItemID},
-		{user.BeltEquipmentItemID, &result.BeltEquipmentItemID},
-		{user.HeadEquipmentItemID, &result.HeadEquipmentItemID},
-		{user.NeckEquipmentItemID, &result.NeckEquipmentItemID},
-		{user.WeaponEquipmentItemID, &result.WeaponEquipmentItemID},
-		{user.ShieldEquipmentItemID, &result.ShieldEquipmentItemID},
-		{user.LegsEquipmentItemID, &result.LegsEquipmentItemID},
-		{user.FeetEquipmentItemID, &result.FeetEquipmentItemID},
-		{user.ArmsEquipmentItemID, &result.ArmsEquipmentItemID},
-		{user.HandsEquipmentItemID, &result.HandsEquipmentItemID},
-		{user.Ring1EquipmentItemID, &result.Ring1EquipmentItemID},
-		{user.Ring2EquipmentItemID, &result.Ring2EquipmentItemID},
-		{user.Ring3EquipmentItemID, &result.Ring3EquipmentItemID},
-		{user.Ring4EquipmentItemID, &result.Ring4EquipmentItemID},
-	}
-
-	for _, field := range equipmentItemFields {
-		if field.source != nil {
-			id := field.source.String()
-			*field.target = &id
-		}
+	result.Equipment = make(map[string]string, len(user.Equipment))
+	for slot, id := range user.Equipment {
+		result.Equipment[slot] = id.String()
 	}
 
 	if location != nil && location.Slug != "" {
@@ -120,6 +108,11 @@ type UpdateUserRequest struct {
 	AvatarID *string `json:"avatarId,omitempty"`
 }
 
+type AllocateStatsRequest struct {
+	Agility uint `json:"agility"`
+	Luck    uint `json:"luck"`
+}
+
 func AvatarFromDomain(avatar *domain.Avatar) *Avatar {
 	if avatar == nil {
 		return nil
@@ -138,3 +131,18 @@ func AvatarsFromDomain(avatars []*domain.Avatar) []*Avatar {
 	}
 	return result
 }
+
+func OnlinePlayersFromDomain(users []*domain.User) []*OnlinePlayer {
+	result := make([]*OnlinePlayer, len(users))
+	for i, user := range users {
+		result[i] = &OnlinePlayer{
+			ID:       user.ID.String(),
+			Username: user.Username,
+			Level:    int(user.Level),
+			Avatar:   user.Avatar,
+			Title:    user.Title,
+			GuildTag: user.GuildTag,
+		}
+	}
+	return result
+}
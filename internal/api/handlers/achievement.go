@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type AchievementHandler struct {
+	achievementService *services.AchievementService
+	userCache          r.Cache[domain.User]
+}
+
+func NewAchievementHandler(db *sqlx.DB, rdb *redis.Client) *AchievementHandler {
+	achievementService := services.NewAchievementService(
+		repository.NewAchievementRepository(db),
+		repository.NewUserRepository(db),
+		ws.GetHub(),
+	)
+
+	return &AchievementHandler{
+		achievementService: achievementService,
+		userCache:          r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+func (h *AchievementHandler) GetAchievements(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	achievements, err := h.achievementService.GetAchievements(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.AchievementsFromDomain(achievements))
+}
+
+func (h *AchievementHandler) SelectTitle(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.SelectTitleRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	err = h.achievementService.SelectTitle(c.Request().Context(), userID, req.AchievementSlug)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAchievementNotFound):
+			return ErrNotFound(c, "achievement not found")
+		case errors.Is(err, services.ErrAchievementLocked):
+			return ErrBadRequest(c, "achievement locked")
+		case errors.Is(err, services.ErrAchievementNoTitle):
+			return ErrBadRequest(c, "achievement grants no title")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	_ = h.userCache.Delete(c.Request().Context(), userID.String())
+	return SuccessResponse(c, "title updated successfully")
+}
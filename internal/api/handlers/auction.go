@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type AuctionHandler struct {
+	auctionService *services.AuctionService
+	userRepo       *repository.UserRepository
+	userCache      r.Cache[domain.User]
+}
+
+func NewAuctionHandler(db *sqlx.DB, rdb *redis.Client) *AuctionHandler {
+	return &AuctionHandler{
+		auctionService: services.NewAuctionService(db, ws.GetHub()),
+		userRepo:       repository.NewUserRepository(db),
+		userCache:      r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+// invalidateUserCache drops the bidder and the seller, whose gold a buyout
+// changes. The outbid user's entry runs out on its own.
+func (h *AuctionHandler) invalidateUserCache(ctx context.Context, userID uuid.UUID, auction *domain.Auction) {
+	_ = h.userCache.Delete(ctx, userID.String())
+	_ = h.userCache.Delete(ctx, auction.SellerID.String())
+}
+
+func handleAuctionError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrAuctionNotFound):
+		return ErrNotFound(c, "auction not found")
+	case errors.Is(err, services.ErrInstanceNotFound):
+		return ErrNotFound(c, "item not found")
+	case errors.Is(err, services.ErrAuctionEnded):
+		return ErrBadRequest(c, "auction has ended")
+	case errors.Is(err, services.ErrInvalidAuctionDuration):
+		return ErrBadRequest(c, "invalid auction duration")
+	case errors.Is(err, services.ErrInvalidAuctionPrice):
+		return ErrBadRequest(c, "invalid auction price")
+	case errors.Is(err, services.ErrOwnAuction):
+		return ErrBadRequest(c, "can't bid on your own auction")
+	case errors.Is(err, services.ErrAlreadyTopBidder):
+		return ErrBadRequest(c, "already the top bidder")
+	case errors.Is(err, services.ErrBidTooLow):
+		return ErrBadRequest(c, "bid too low")
+	case errors.Is(err, services.ErrNoBuyout):
+		return ErrBadRequest(c, "auction has no buyout price")
+	case errors.Is(err, services.ErrItemNotInInventory):
+		return ErrBadRequest(c, "item not in inventory")
+	case errors.Is(err, services.ErrInsufficientGold):
+		return ErrBadRequest(c, "insufficient gold")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *AuctionHandler) SearchAuctions(c echo.Context) error {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	minLevel, _ := strconv.ParseUint(c.QueryParam("minLevel"), 10, 32)
+	maxLevel, _ := strconv.ParseUint(c.QueryParam("maxLevel"), 10, 32)
+	minPrice, _ := strconv.ParseUint(c.QueryParam("minPrice"), 10, 32)
+	maxPrice, _ := strconv.ParseUint(c.QueryParam("maxPrice"), 10, 32)
+
+	filter := domain.AuctionFilter{
+		CategoryType: c.QueryParam("categoryType"),
+		MinLevel:     uint(minLevel),
+		MaxLevel:     uint(maxLevel),
+		MinPrice:     uint(minPrice),
+		MaxPrice:     uint(maxPrice),
+	}
+
+	auctions, err := h.auctionService.Search(c.Request().Context(), filter, page, limit)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.AuctionsFromDomain(auctions))
+}
+
+func (h *AuctionHandler) CreateAuction(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.CreateAuctionRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	instanceID, err := uuid.Parse(req.InstanceID)
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	duration := time.Duration(req.DurationHours) * time.Hour
+	auction, err := h.auctionService.CreateAuction(c.Request().Context(), userID, instanceID, req.StartPrice, req.BuyoutPrice, duration)
+	if err != nil {
+		return handleAuctionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.AuctionFromDomain(auction))
+}
+
+func (h *AuctionHandler) Bid(c echo.Context) error {
+	auctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid auction id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.BidRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	auction, err := h.auctionService.Bid(c.Request().Context(), userID, auctionID, req.Amount)
+	if err != nil {
+		return handleAuctionError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID, auction)
+	return c.JSON(http.StatusOK, dto.AuctionFromDomain(auction))
+}
+
+func (h *AuctionHandler) Buyout(c echo.Context) error {
+	auctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid auction id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	auction, err := h.auctionService.Buyout(c.Request().Context(), userID, auctionID)
+	if err != nil {
+		return handleAuctionError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID, auction)
+	return c.JSON(http.StatusOK, dto.AuctionFromDomain(auction))
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type ConsumableHandler struct {
+	consumableService *services.ConsumableService
+	fightService      *services.FightService
+	userRepo          *repository.UserRepository
+	locationRepo      *repository.LocationRepository
+	userCache         r.Cache[domain.User]
+}
+
+func NewConsumableHandler(db *sqlx.DB, rdb *redis.Client) *ConsumableHandler {
+	userRepo := repository.NewUserRepository(db)
+	consumableService := services.NewConsumableService(db, repository.NewConsumableRepository(db), userRepo)
+
+	return &ConsumableHandler{
+		consumableService: consumableService,
+		fightService:      newFightService(db),
+		userRepo:          userRepo,
+		locationRepo:      repository.NewLocationRepository(db),
+		userCache:         r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+func (h *ConsumableHandler) invalidateUserCache(ctx context.Context, userID string) {
+	_ = h.userCache.Delete(ctx, userID)
+}
+
+func handleConsumableError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrConsumableNotFound):
+		return ErrNotFound(c, "consumable not found")
+	case errors.Is(err, services.ErrConsumableNotOwned):
+		return ErrBadRequest(c, "consumable not owned")
+	case errors.Is(err, services.ErrHpAlreadyFull):
+		return ErrBadRequest(c, "hp already full")
+	case errors.Is(err, services.ErrInsufficientLevel):
+		return ErrBadRequest(c, "insufficient level")
+	case errors.Is(err, services.ErrInsufficientGold):
+		return ErrBadRequest(c, "insufficient gold")
+	case errors.Is(err, services.ErrOutOfStock):
+		return ErrBadRequest(c, "item is out of stock")
+	case errors.Is(err, services.ErrItemNotSoldHere):
+		return ErrBadRequest(c, "item is not sold here")
+	case errors.Is(err, services.ErrInvalidQuantity):
+		return ErrBadRequest(c, "invalid quantity")
+	case errors.Is(err, services.ErrConsumableFightOnly):
+		return ErrBadRequest(c, "consumable can only be used in a fight")
+	case errors.Is(err, services.ErrConsumableNotUsable):
+		return ErrBadRequest(c, "consumable can't be used")
+	case errors.Is(err, services.ErrPlayerStunned):
+		return ErrBadRequest(c, "player is stunned")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return handleFightError(c, err)
+	}
+}
+
+func (h *ConsumableHandler) GetConsumables(c echo.Context) error {
+	consumables, err := h.consumableService.GetConsumables(c.Request().Context())
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.ConsumablesFromDomain(consumables))
+}
+
+func (h *ConsumableHandler) GetUserConsumables(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	stacks, err := h.consumableService.GetUserConsumables(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.ConsumableStacksFromDomain(stacks))
+}
+
+type BuyConsumableRequest struct {
+	Quantity uint `json:"quantity"`
+}
+
+func (h *ConsumableHandler) BuyConsumable(c echo.Context) error {
+	slug := c.Param("slug")
+	if slug == "" {
+		return ErrBadRequest(c, "consumable slug is required")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	req := BuyConsumableRequest{Quantity: 1}
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	err = h.consumableService.BuyConsumable(c.Request().Context(), userID, slug, req.Quantity)
+	if err != nil {
+		return handleConsumableError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID.String())
+	return SuccessResponse(c, "consumable purchased successfully")
+}
+
+type UseConsumableRequest struct {
+	Defense string `json:"defense"`
+}
+
+// UseConsumable drinks a potion. During a fight it takes the player's turn and
+// responds like a hit, otherwise it responds with the updated user.
+func (h *ConsumableHandler) UseConsumable(c echo.Context) error {
+	slug := c.Param("slug")
+	if slug == "" {
+		return ErrBadRequest(c, "consumable slug is required")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req UseConsumableRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	inFight, err := h.userRepo.InFight(userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	if inFight {
+		result, err := h.fightService.UseConsumable(c.Request().Context(), userID, slug, req.Defense)
+		if err != nil {
+			return handleConsumableError(c, err)
+		}
+
+		h.invalidateUserCache(c.Request().Context(), userID.String())
+		return fightResponse(c, h.locationRepo, result)
+	}
+
+	user, err := h.consumableService.UseConsumable(c.Request().Context(), userID, slug)
+	if err != nil {
+		return handleConsumableError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID.String())
+	return c.JSON(http.StatusOK, dto.UserFromDomain(user, resolveUserLocation(user, h.locationRepo), nil, false))
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/redis/go-redis/v9"
@@ -24,7 +27,12 @@ type EquipmentItemHandler struct {
 	equipmentItemSellService    *services.EquipmentItemSellService
 	equipmentItemTakeOnService  *services.EquipmentItemTakeOnService
 	equipmentItemTakeOffService *services.EquipmentItemTakeOffService
+	repairService               *services.RepairService
+	enhancementService          *services.EnhancementService
+	itemComparisonService       *services.ItemComparisonService
+	catalogService              *services.CatalogService
 	equipmentItemRepo           *repository.EquipmentItemRepository
+	equipmentSlotRepo           *repository.EquipmentSlotRepository
 	userRepo                    *repository.UserRepository
 	userCache                   r.Cache[domain.User]
 }
@@ -39,6 +47,7 @@ func NewEquipmentItemHandler(db *sqlx.DB, rdb *redis.Client) *EquipmentItemHandl
 	equipmentItemSellService := services.NewEquipmentItemSellService(db, equipmentItemRepo, inventoryRepo, userRepo)
 	equipmentItemTakeOnService := services.NewEquipmentItemTakeOnService(db, equipmentItemRepo, inventoryRepo, userRepo)
 	equipmentItemTakeOffService := services.NewEquipmentItemTakeOffService(db, equipmentItemRepo, inventoryRepo, userRepo)
+	locationRepo := repository.NewLocationRepository(db)
 
 	return &EquipmentItemHandler{
 		equipmentItemService:        equipmentItemService,
@@ -46,7 +55,12 @@ func NewEquipmentItemHandler(db *sqlx.DB, rdb *redis.Client) *EquipmentItemHandl
 		equipmentItemSellService:    equipmentItemSellService,
 		equipmentItemTakeOnService:  equipmentItemTakeOnService,
 		equipmentItemTakeOffService: equipmentItemTakeOffService,
+		repairService:               services.NewRepairService(db, locationRepo),
+		enhancementService:          services.NewEnhancementService(db, locationRepo, repository.NewConsumableRepository(db)),
+		itemComparisonService:       services.NewItemComparisonService(db),
+		catalogService:              services.NewCatalogService(equipmentItemRepo, rdb),
 		equipmentItemRepo:           equipmentItemRepo,
+		equipmentSlotRepo:           repository.NewEquipmentSlotRepository(db),
 		userRepo:                    userRepo,
 		userCache:                   r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
 	}
@@ -81,6 +95,151 @@ func (h *EquipmentItemHandler) GetEquipmentItems(c echo.Context) error {
 	return c.JSON(http.StatusOK, dto.EquipmentItemsFromDomain(items))
 }
 
+// SearchCatalog searches the shop catalog. Filters left out match everything,
+// cursor is the nextCursor of the previous page.
+func (h *EquipmentItemHandler) SearchCatalog(c echo.Context) error {
+	query := domain.CatalogQuery{
+		Search: c.QueryParam("q"),
+		Sort:   domain.CatalogSort(c.QueryParam("sort")),
+		Desc:   c.QueryParam("order") == "desc",
+	}
+	for _, param := range []struct {
+		name  string
+		value *uint
+	}{
+		{"minLevel", &query.MinLevel},
+		{"maxLevel", &query.MaxLevel},
+		{"minPrice", &query.MinPrice},
+		{"maxPrice", &query.MaxPrice},
+		{"minAttack", &query.MinAttack},
+		{"minDefense", &query.MinDefense},
+		{"minHp", &query.MinHp},
+	} {
+		parsed, ok := queryUint(c, param.name)
+		if !ok {
+			return ErrBadRequest(c, "invalid "+param.name)
+		}
+		*param.value = parsed
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			return ErrBadRequest(c, "invalid limit")
+		}
+		query.Limit = parsed
+	}
+	for _, categoryType := range strings.Split(c.QueryParam("categoryType"), ",") {
+		if categoryType = strings.TrimSpace(categoryType); categoryType != "" {
+			query.CategoryTypes = append(query.CategoryTypes, categoryType)
+		}
+	}
+	if artifact := c.QueryParam("artifact"); artifact != "" {
+		parsed, err := strconv.ParseBool(artifact)
+		if err != nil {
+			return ErrBadRequest(c, "invalid artifact")
+		}
+		query.Artifact = &parsed
+	}
+	if cursor := c.QueryParam("cursor"); cursor != "" {
+		after, err := domain.ParseCatalogCursor(cursor)
+		if err != nil {
+			return ErrBadRequest(c, "invalid cursor")
+		}
+		query.After = after
+	}
+
+	page, err := h.catalogService.Search(c.Request().Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCatalogSort):
+			return ErrBadRequest(c, "invalid sort")
+		case errors.Is(err, services.ErrInvalidCatalogRange):
+			return ErrBadRequest(c, "minimum is above maximum")
+		case errors.Is(err, domain.ErrInvalidCatalogCursor):
+			return ErrBadRequest(c, "invalid cursor")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	return c.JSON(http.StatusOK, dto.CatalogPageFromDomain(page))
+}
+
+// queryUint parses the query param as an unsigned number. An absent param is
+// 0, a malformed or negative one is not ok.
+func queryUint(c echo.Context, name string) (uint, bool) {
+	param := c.QueryParam(name)
+	if param == "" {
+		return 0, true
+	}
+	value, err := strconv.ParseUint(param, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(value), true
+}
+
+// GetEquipmentSlots lists the slot registry in display order.
+func (h *EquipmentItemHandler) GetEquipmentSlots(c echo.Context) error {
+	slots, err := h.equipmentSlotRepo.FindAll()
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.EquipmentSlotsFromDomain(slots))
+}
+
+// CompareEquipmentItems compares the items named in the comma separated slugs
+// parameter with what the user wears.
+func (h *EquipmentItemHandler) CompareEquipmentItems(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var slugs []string
+	for _, slug := range strings.Split(c.QueryParam("slugs"), ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+
+	comparisons, err := h.itemComparisonService.Compare(c.Request().Context(), userID, slugs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNoItemsToCompare):
+			return ErrBadRequest(c, "slugs parameter is required")
+		case errors.Is(err, services.ErrTooManyItemsToCompare):
+			return ErrBadRequest(c, "too many items to compare")
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrNotFound(c, "user not found")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	return c.JSON(http.StatusOK, dto.ItemComparisonsFromDomain(comparisons))
+}
+
+// GetUpgradeSuggestions returns the best upgrade per slot the user can buy
+// where they are.
+func (h *EquipmentItemHandler) GetUpgradeSuggestions(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	upgrades, err := h.itemComparisonService.SuggestUpgrades(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrNotFound(c, "user not found")
+		}
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.ItemComparisonsFromDomain(upgrades))
+}
+
 func (h *EquipmentItemHandler) BuyEquipmentItem(c echo.Context) error {
 	itemSlug := c.Param("slug")
 	if itemSlug == "" {
@@ -103,6 +262,12 @@ func (h *EquipmentItemHandler) BuyEquipmentItem(c echo.Context) error {
 			return ErrNotFound(c, "equipment item not found")
 		case errors.Is(err, services.ErrInsufficientGold):
 			return ErrBadRequest(c, "insufficient gold")
+		case errors.Is(err, services.ErrOutOfStock):
+			return ErrBadRequest(c, "item is out of stock")
+		case errors.Is(err, services.ErrItemNotSoldHere):
+			return ErrBadRequest(c, "item is not sold here")
+		case errors.Is(err, services.ErrInventoryFull):
+			return ErrBadRequest(c, "inventory is full")
 		case errors.Is(err, repository.ErrUserNotFound):
 			return ErrNotFound(c, "user not found")
 		default:
@@ -134,28 +299,67 @@ func (h *EquipmentItemHandler) TakeOnEquipmentItem(c echo.Context) error {
 		return ErrNotFound(c, "equipment item not found")
 	}
 
-	err = h.equipmentItemTakeOnService.TakeOnEquipmentItem(c.Request().Context(), userID, item.ID)
+	// The slot is optional, rings go to the first free ring slot without it.
+	slot := c.QueryParam("slot")
+
+	err = h.equipmentItemTakeOnService.TakeOnEquipmentItemInSlot(c.Request().Context(), userID, item.ID, slot)
 	if err != nil {
-		switch {
-		case errors.Is(err, services.ErrEquipmentItemNotFound):
-			return ErrNotFound(c, "equipment item not found")
-		case errors.Is(err, services.ErrItemNotInInventory):
-			return ErrBadRequest(c, "item not in inventory")
-		case errors.Is(err, services.ErrInsufficientLevel):
-			return ErrBadRequest(c, "insufficient level")
-		case errors.Is(err, services.ErrInvalidEquipmentType):
-			return ErrBadRequest(c, "invalid equipment type")
-		case errors.Is(err, repository.ErrUserNotFound):
-			return ErrNotFound(c, "user not found")
-		default:
-			return ErrInternalServerError(c)
-		}
+		return handleTakeOnError(c, err)
 	}
 
 	h.invalidateUserCache(c.Request().Context(), userID.String())
 	return SuccessResponse(c, "item equipped successfully")
 }
 
+// TakeOnInstance equips one particular copy from the bag, e.g. the enhanced
+// one among several of the same item.
+func (h *EquipmentItemHandler) TakeOnInstance(c echo.Context) error {
+	instanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	err = h.equipmentItemTakeOnService.TakeOnInstance(c.Request().Context(), userID, instanceID, c.QueryParam("slot"))
+	if err != nil {
+		return handleTakeOnError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID.String())
+	return SuccessResponse(c, "item equipped successfully")
+}
+
+func handleTakeOnError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrEquipmentItemNotFound):
+		return ErrNotFound(c, "equipment item not found")
+	case errors.Is(err, services.ErrItemNotInInventory):
+		return ErrBadRequest(c, "item not in inventory")
+	case errors.Is(err, services.ErrInsufficientLevel):
+		return ErrBadRequest(c, "insufficient level")
+	case errors.Is(err, services.ErrInvalidEquipmentType):
+		return ErrBadRequest(c, "invalid equipment type")
+	case errors.Is(err, services.ErrInvalidSlot):
+		return ErrBadRequest(c, "slot doesn't take this item")
+	case errors.Is(err, services.ErrSlotRequired):
+		return ErrBadRequest(c, "every slot for this item is taken, pick one")
+	case errors.Is(err, services.ErrInventoryFull):
+		return ErrBadRequest(c, "inventory is full")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
 func (h *EquipmentItemHandler) TakeOffEquipmentItem(c echo.Context) error {
 	slotName := c.Param("slot")
 	if slotName == "" {
@@ -178,6 +382,8 @@ func (h *EquipmentItemHandler) TakeOffEquipmentItem(c echo.Context) error {
 			return ErrBadRequest(c, "no item equipped in this slot")
 		case errors.Is(err, services.ErrInvalidEquipmentType):
 			return ErrBadRequest(c, "invalid slot name")
+		case errors.Is(err, services.ErrInventoryFull):
+			return ErrBadRequest(c, "inventory is full")
 		case errors.Is(err, repository.ErrUserNotFound):
 			return ErrNotFound(c, "user not found")
 		default:
@@ -189,6 +395,12 @@ func (h *EquipmentItemHandler) TakeOffEquipmentItem(c echo.Context) error {
 	return SuccessResponse(c, "item removed successfully")
 }
 
+type SellEquipmentItemRequest struct {
+	Quantity uint `json:"quantity"`
+}
+
+// SellEquipmentItem sells the number of copies the request names, there is no
+// default so a missing quantity is rejected.
 func (h *EquipmentItemHandler) SellEquipmentItem(c echo.Context) error {
 	itemSlug := c.Param("slug")
 	if itemSlug == "" {
@@ -204,9 +416,16 @@ func (h *EquipmentItemHandler) SellEquipmentItem(c echo.Context) error {
 		return err
 	}
 
-	err = h.equipmentItemSellService.SellEquipmentItem(c.Request().Context(), userID, itemSlug)
+	var req SellEquipmentItemRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	err = h.equipmentItemSellService.SellEquipmentItem(c.Request().Context(), userID, itemSlug, req.Quantity)
 	if err != nil {
 		switch {
+		case errors.Is(err, services.ErrInvalidQuantity):
+			return ErrBadRequest(c, "invalid quantity")
 		case errors.Is(err, services.ErrItemNotOwned):
 			return ErrBadRequest(c, "item not owned")
 		case errors.Is(err, services.ErrEquipmentItemNotFound):
@@ -221,3 +440,123 @@ func (h *EquipmentItemHandler) SellEquipmentItem(c echo.Context) error {
 	h.invalidateUserCache(c.Request().Context(), userID.String())
 	return SuccessResponse(c, "item sold successfully")
 }
+
+// SellInstance sells one copy of the instance, the way to sell enhanced,
+// bonus-rolled and worn copies.
+func (h *EquipmentItemHandler) SellInstance(c echo.Context) error {
+	instanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	err = h.equipmentItemSellService.SellInstance(c.Request().Context(), userID, instanceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrItemNotOwned):
+			return ErrBadRequest(c, "item not owned")
+		case errors.Is(err, services.ErrEquipmentItemNotFound):
+			return ErrNotFound(c, "equipment item not found")
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrNotFound(c, "user not found")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID.String())
+	return SuccessResponse(c, "item sold successfully")
+}
+
+func (h *EquipmentItemHandler) RepairItem(c echo.Context) error {
+	instanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	item, err := h.repairService.RepairItem(c.Request().Context(), userID, instanceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInstanceNotFound):
+			return ErrNotFound(c, "item not found")
+		case errors.Is(err, services.ErrEquipmentItemNotFound):
+			return ErrNotFound(c, "equipment item not found")
+		case errors.Is(err, services.ErrItemNotDamaged):
+			return ErrBadRequest(c, "item is not damaged")
+		case errors.Is(err, services.ErrNotInWeaponShop):
+			return ErrBadRequest(c, "repairs are only done in the weapon shop")
+		case errors.Is(err, services.ErrInsufficientGold):
+			return ErrBadRequest(c, "insufficient gold")
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrNotFound(c, "user not found")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID.String())
+	return c.JSON(http.StatusOK, dto.InventoryItemFromDomain(item.EquipmentItem, item.Instance))
+}
+
+func (h *EquipmentItemHandler) EnhanceItem(c echo.Context) error {
+	instanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	item, success, err := h.enhancementService.EnhanceItem(c.Request().Context(), userID, instanceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInstanceNotFound):
+			return ErrNotFound(c, "item not found")
+		case errors.Is(err, services.ErrEquipmentItemNotFound):
+			return ErrNotFound(c, "equipment item not found")
+		case errors.Is(err, services.ErrMaxEnhancementLevel):
+			return ErrBadRequest(c, "item is already at max enhancement level")
+		case errors.Is(err, services.ErrNotAtBlacksmith):
+			return ErrBadRequest(c, "items are only enhanced at the blacksmith")
+		case errors.Is(err, services.ErrInsufficientGold):
+			return ErrBadRequest(c, "insufficient gold")
+		case errors.Is(err, services.ErrInsufficientMaterials):
+			return ErrBadRequest(c, "insufficient materials")
+		case errors.Is(err, services.ErrInventoryFull):
+			return ErrBadRequest(c, "inventory is full")
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrNotFound(c, "user not found")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID.String())
+	return c.JSON(http.StatusOK, &dto.EnhanceResult{
+		Success: success,
+		Item:    dto.InventoryItemFromDomain(item.EquipmentItem, item.Instance),
+	})
+}
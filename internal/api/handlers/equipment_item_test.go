@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -48,7 +49,7 @@ func setupEquipmentItemHandlerTest(t *testing.T) (*EquipmentItemHandler, *sqlx.D
 	require.NoError(t, userRepo.Create(user))
 
 	var categoryID uuid.UUID
-	err := db.QueryRow(`INSERT INTO equipment_categories (name, type) VALUES ($1, $2::equipment_category_type) RETURNING id`, "Weapon", "weapon").Scan(&categoryID)
+	err := db.QueryRow(`INSERT INTO equipment_categories (name, type) VALUES ($1, $2) RETURNING id`, "Weapon", "weapon").Scan(&categoryID)
 	require.NoError(t, err)
 
 	item := &domain.EquipmentItem{
@@ -112,6 +113,56 @@ func TestEquipmentItemHandler_GetEquipmentItems(t *testing.T) {
 	})
 }
 
+func TestEquipmentItemHandler_SearchCatalog(t *testing.T) {
+	handler, _, _, _, e := setupEquipmentItemHandlerTest(t)
+
+	for _, query := range []string{"minLevel=abc", "maxPrice=-5", "limit=ten", "artifact=maybe"} {
+		t.Run(query+" returns 400", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/equipment_items/catalog?"+query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.SearchCatalog(c)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+
+	t.Run("cursor for another order returns 400", func(t *testing.T) {
+		cursor := domain.CatalogCursor{Sort: domain.CatalogSortPrice, Value: "100", ID: uuid.New()}.Encode()
+		for _, query := range []string{"sort=name", "sort=price&order=desc"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/equipment_items/catalog?"+query+"&cursor="+cursor, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.SearchCatalog(c)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("edited cursor value returns 400", func(t *testing.T) {
+		cursor := domain.CatalogCursor{Sort: domain.CatalogSortPrice, Value: "cheap", ID: uuid.New()}.Encode()
+		req := httptest.NewRequest(http.MethodGet, "/api/equipment_items/catalog?sort=price&cursor="+cursor, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SearchCatalog(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("success returns 200", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/equipment_items/catalog?minLevel=1&maxPrice=500", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SearchCatalog(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
 func TestEquipmentItemHandler_BuyEquipmentItem(t *testing.T) {
 	handler, db, user, item, e := setupEquipmentItemHandlerTest(t)
 
@@ -293,7 +344,7 @@ func TestEquipmentItemHandler_SellEquipmentItem(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 	})
 
-	t.Run("success sell returns 200", func(t *testing.T) {
+	t.Run("missing quantity returns 400", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/equipment_items/"+item.Slug+"/sell", nil)
 		req = req.WithContext(eqCtx(user.ID))
 		rec := httptest.NewRecorder()
@@ -302,6 +353,21 @@ func TestEquipmentItemHandler_SellEquipmentItem(t *testing.T) {
 		c.SetParamNames("slug")
 		c.SetParamValues(item.Slug)
 
+		err := handler.SellEquipmentItem(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("success sell returns 200", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/equipment_items/"+item.Slug+"/sell", bytes.NewBufferString(`{"quantity":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(eqCtx(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/equipment_items/:slug/sell")
+		c.SetParamNames("slug")
+		c.SetParamValues(item.Slug)
+
 		err := handler.SellEquipmentItem(c)
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusOK, rec.Code)
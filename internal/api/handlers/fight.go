@@ -19,19 +19,20 @@ type FightHandler struct {
 }
 
 func NewFightHandler(db *sqlx.DB) *FightHandler {
-	locationRepo := repository.NewLocationRepository(db)
-	fightService := services.NewFightService(
+	return &FightHandler{
+		fightService: newFightService(db),
+		locationRepo: repository.NewLocationRepository(db),
+	}
+}
+
+func newFightService(db *sqlx.DB) *services.FightService {
+	return services.NewFightService(
 		db,
 		repository.NewFightRepository(db),
 		repository.NewBotRepository(db),
 		repository.NewUserRepository(db),
 		repository.NewRoundRepository(db),
 	)
-
-	return &FightHandler{
-		fightService: fightService,
-		locationRepo: locationRepo,
-	}
 }
 
 func handleFightError(c echo.Context, err error) error {
@@ -55,12 +56,12 @@ type GetCurrentFightResponse struct {
 	Fight dto.Fight `json:"fight"`
 }
 
-func (h *FightHandler) fightResponse(c echo.Context, result *services.GetCurrentFightResult) error {
+func fightResponse(c echo.Context, locationRepo *repository.LocationRepository, result *services.GetCurrentFightResult) error {
 	if result == nil {
 		return ErrInternalServerError(c)
 	}
 
-	location := resolveUserLocation(result.User, h.locationRepo)
+	location := resolveUserLocation(result.User, locationRepo)
 	userDTO := dto.UserFromDomain(result.User, location, nil, true)
 	botDTO := dto.BotFromDomain(result.Bot)
 	fightDTO := dto.FightFromDomain(result.Fight)
@@ -87,7 +88,7 @@ func (h *FightHandler) GetCurrentFight(c echo.Context) error {
 		return handleFightError(c, err)
 	}
 
-	return h.fightResponse(c, result)
+	return fightResponse(c, h.locationRepo, result)
 }
 
 type HitRequest struct {
@@ -114,5 +115,5 @@ func (h *FightHandler) Hit(c echo.Context) error {
 		return handleFightError(c, err)
 	}
 
-	return h.fightResponse(c, result)
+	return fightResponse(c, h.locationRepo, result)
 }
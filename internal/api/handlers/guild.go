@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type GuildHandler struct {
+	guildService *services.GuildService
+	userRepo     *repository.UserRepository
+	userCache    r.Cache[domain.User]
+}
+
+func NewGuildHandler(db *sqlx.DB, rdb *redis.Client) *GuildHandler {
+	return &GuildHandler{
+		guildService: services.NewGuildService(db, ws.GetHub()),
+		userRepo:     repository.NewUserRepository(db),
+		userCache:    r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+// invalidateUserCache drops the cached users, their gold or guild tag
+// changed.
+func (h *GuildHandler) invalidateUserCache(ctx context.Context, userIDs ...uuid.UUID) {
+	for _, userID := range userIDs {
+		_ = h.userCache.Delete(ctx, userID.String())
+	}
+}
+
+func handleGuildError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotInGuild):
+		return ErrNotFound(c, "not in a guild")
+	case errors.Is(err, services.ErrGuildMemberNotFound):
+		return ErrNotFound(c, "guild member not found")
+	case errors.Is(err, services.ErrGuildInviteNotFound):
+		return ErrNotFound(c, "guild invite not found")
+	case errors.Is(err, services.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	case errors.Is(err, services.ErrInvalidGuildName):
+		return ErrBadRequest(c, "invalid guild name")
+	case errors.Is(err, services.ErrInvalidGuildTag):
+		return ErrBadRequest(c, "invalid guild tag")
+	case errors.Is(err, services.ErrGuildNameTaken):
+		return ErrBadRequest(c, "guild name or tag already taken")
+	case errors.Is(err, services.ErrAlreadyInGuild):
+		return ErrBadRequest(c, "already in a guild")
+	case errors.Is(err, services.ErrGuildFull):
+		return ErrBadRequest(c, "guild is full")
+	case errors.Is(err, services.ErrGuildPermission):
+		return ErrBadRequest(c, "your guild rank doesn't allow this")
+	case errors.Is(err, services.ErrInvalidGuildRank):
+		return ErrBadRequest(c, "invalid rank")
+	case errors.Is(err, services.ErrGuildLeaderCantLeave):
+		return ErrBadRequest(c, "pass on leadership before leaving")
+	case errors.Is(err, services.ErrInvalidGuildAmount):
+		return ErrBadRequest(c, "invalid amount")
+	case errors.Is(err, services.ErrInsufficientTreasury):
+		return ErrBadRequest(c, "insufficient guild treasury")
+	case errors.Is(err, services.ErrInsufficientGold):
+		return ErrBadRequest(c, "insufficient gold")
+	case errors.Is(err, services.ErrInvalidGuildMessage):
+		return ErrBadRequest(c, "invalid message")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *GuildHandler) GetGuild(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	guild, err := h.guildService.Get(c.Request().Context(), userID)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GuildFromDomain(guild))
+}
+
+func (h *GuildHandler) CreateGuild(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.CreateGuildRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	guild, err := h.guildService.Create(c.Request().Context(), userID, req.Name, req.Tag)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, dto.GuildFromDomain(guild))
+}
+
+func (h *GuildHandler) Invite(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.GuildInviteRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	invite, err := h.guildService.Invite(c.Request().Context(), userID, req.Username)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GuildInvitesFromDomain([]*domain.GuildInvite{invite})[0])
+}
+
+func (h *GuildHandler) GetInvites(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	invites, err := h.guildService.Invites(c.Request().Context(), userID)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GuildInvitesFromDomain(invites))
+}
+
+func (h *GuildHandler) AcceptInvite(c echo.Context) error {
+	guildID, err := uuid.Parse(c.Param("guildId"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid guild id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	guild, err := h.guildService.AcceptInvite(c.Request().Context(), userID, guildID)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, dto.GuildFromDomain(guild))
+}
+
+func (h *GuildHandler) DeclineInvite(c echo.Context) error {
+	guildID, err := uuid.Parse(c.Param("guildId"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid guild id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.guildService.DeclineInvite(c.Request().Context(), userID, guildID); err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, nil)
+}
+
+func (h *GuildHandler) Leave(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.guildService.Leave(c.Request().Context(), userID); err != nil {
+		return handleGuildError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, nil)
+}
+
+func (h *GuildHandler) Kick(c echo.Context) error {
+	targetID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid user id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.guildService.Kick(c.Request().Context(), userID, targetID); err != nil {
+		return handleGuildError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), targetID)
+	return c.JSON(http.StatusOK, nil)
+}
+
+func (h *GuildHandler) SetRank(c echo.Context) error {
+	targetID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid user id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.GuildRankRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	if err := h.guildService.SetRank(c.Request().Context(), userID, targetID, domain.GuildRank(req.Rank)); err != nil {
+		return handleGuildError(c, err)
+	}
+
+	guild, err := h.guildService.Get(c.Request().Context(), userID)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GuildFromDomain(guild))
+}
+
+func (h *GuildHandler) Deposit(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.GuildTreasuryRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	transaction, err := h.guildService.Deposit(c.Request().Context(), userID, req.Amount)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, dto.GuildTransactionFromDomain(transaction))
+}
+
+func (h *GuildHandler) Withdraw(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.GuildTreasuryRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	transaction, err := h.guildService.Withdraw(c.Request().Context(), userID, req.Amount)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, dto.GuildTransactionFromDomain(transaction))
+}
+
+func (h *GuildHandler) GetTransactions(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	transactions, err := h.guildService.Transactions(c.Request().Context(), userID, page, limit)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GuildTransactionsFromDomain(transactions))
+}
+
+func (h *GuildHandler) GetChat(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	messages, err := h.guildService.ChatHistory(c.Request().Context(), userID)
+	if err != nil {
+		return handleGuildError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GuildMessagesFromDomain(messages))
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type InventoryHandler struct {
+	bankService             *services.BankService
+	inventoryUpgradeService *services.InventoryUpgradeService
+	userRepo                *repository.UserRepository
+	userCache               r.Cache[domain.User]
+}
+
+func NewInventoryHandler(db *sqlx.DB, rdb *redis.Client) *InventoryHandler {
+	return &InventoryHandler{
+		bankService:             services.NewBankService(db, repository.NewLocationRepository(db)),
+		inventoryUpgradeService: services.NewInventoryUpgradeService(db),
+		userRepo:                repository.NewUserRepository(db),
+		userCache:               r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+func handleBankError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotInBank):
+		return ErrBadRequest(c, "items are only deposited and withdrawn in the bank")
+	case errors.Is(err, services.ErrBankFull):
+		return ErrBadRequest(c, "bank is full")
+	case errors.Is(err, services.ErrInventoryFull):
+		return ErrBadRequest(c, "inventory is full")
+	case errors.Is(err, services.ErrInstanceNotFound):
+		return ErrNotFound(c, "item not found")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *InventoryHandler) GetBank(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	items, err := h.bankService.GetBank(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.BankFromDomain(items))
+}
+
+func (h *InventoryHandler) Deposit(c echo.Context) error {
+	instanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	if err := h.bankService.Deposit(c.Request().Context(), userID, instanceID); err != nil {
+		return handleBankError(c, err)
+	}
+
+	return SuccessResponse(c, "item deposited successfully")
+}
+
+func (h *InventoryHandler) Withdraw(c echo.Context) error {
+	instanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	if err := h.bankService.Withdraw(c.Request().Context(), userID, instanceID); err != nil {
+		return handleBankError(c, err)
+	}
+
+	return SuccessResponse(c, "item withdrawn successfully")
+}
+
+func (h *InventoryHandler) UpgradeCapacity(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	capacity, err := h.inventoryUpgradeService.UpgradeCapacity(c.Request().Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMaxInventoryCapacity):
+			return ErrBadRequest(c, "inventory is at max capacity")
+		case errors.Is(err, services.ErrInsufficientGold):
+			return ErrBadRequest(c, "insufficient gold")
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrNotFound(c, "user not found")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	_ = h.userCache.Delete(c.Request().Context(), userID.String())
+	return c.JSON(http.StatusOK, dto.InventoryCapacityFromDomain(capacity))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *services.LeaderboardService
+}
+
+func NewLeaderboardHandler(db *sqlx.DB, rdb *redis.Client) *LeaderboardHandler {
+	leaderboardService := services.NewLeaderboardService(
+		repository.NewLeaderboardRepository(db),
+		repository.NewUserRepository(db),
+		r.NewLeaderboard(rdb, "leaderboard"),
+	)
+
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+func (h *LeaderboardHandler) GetLeaderboard(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.leaderboardService.GetLeaderboard(c.Request().Context(), userID, c.Param("kind"), page, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownLeaderboard) {
+			return ErrNotFound(c, "leaderboard not found")
+		}
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.LeaderboardFromPage(result))
+}
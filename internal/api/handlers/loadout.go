@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type LoadoutHandler struct {
+	loadoutService *services.LoadoutService
+	userRepo       *repository.UserRepository
+	userCache      r.Cache[domain.User]
+}
+
+func NewLoadoutHandler(db *sqlx.DB, rdb *redis.Client) *LoadoutHandler {
+	return &LoadoutHandler{
+		loadoutService: services.NewLoadoutService(db),
+		userRepo:       repository.NewUserRepository(db),
+		userCache:      r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+func handleLoadoutError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrLoadoutNotFound):
+		return ErrNotFound(c, "loadout not found")
+	case errors.Is(err, services.ErrInvalidLoadoutName):
+		return ErrBadRequest(c, "invalid loadout name")
+	case errors.Is(err, services.ErrLoadoutNameTaken):
+		return ErrBadRequest(c, "loadout name already taken")
+	case errors.Is(err, services.ErrTooManyLoadouts):
+		return ErrBadRequest(c, "too many loadouts")
+	case errors.Is(err, services.ErrInventoryFull):
+		return ErrBadRequest(c, "inventory is full")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *LoadoutHandler) GetLoadouts(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	loadouts, err := h.loadoutService.GetLoadouts(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.LoadoutsFromDomain(loadouts))
+}
+
+// SaveLoadout saves what the user has equipped as a new loadout.
+func (h *LoadoutHandler) SaveLoadout(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.SaveLoadoutRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	loadout, err := h.loadoutService.Save(c.Request().Context(), userID, req.Name)
+	if err != nil {
+		return handleLoadoutError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.LoadoutFromDomain(loadout))
+}
+
+// OverwriteLoadout saves what the user has equipped into an existing loadout.
+func (h *LoadoutHandler) OverwriteLoadout(c echo.Context) error {
+	loadoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid loadout id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	loadout, err := h.loadoutService.Overwrite(c.Request().Context(), userID, loadoutID)
+	if err != nil {
+		return handleLoadoutError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.LoadoutFromDomain(loadout))
+}
+
+func (h *LoadoutHandler) DeleteLoadout(c echo.Context) error {
+	loadoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid loadout id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.loadoutService.Delete(c.Request().Context(), userID, loadoutID); err != nil {
+		return handleLoadoutError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, nil)
+}
+
+func (h *LoadoutHandler) ApplyLoadout(c echo.Context) error {
+	loadoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid loadout id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	loadout, missing, err := h.loadoutService.Apply(c.Request().Context(), userID, loadoutID)
+	if err != nil {
+		return handleLoadoutError(c, err)
+	}
+
+	_ = h.userCache.Delete(c.Request().Context(), userID.String())
+	return c.JSON(http.StatusOK, dto.ApplyLoadoutResultFromDomain(loadout, missing))
+}
@@ -38,7 +38,7 @@ type MoveToCellResponse struct {
 func NewLocationHandler(db *sqlx.DB, rdb *redis.Client) *LocationHandler {
 	locationRepo := repository.NewLocationRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	movingWorker := worker.NewCellsMovingWorker(locationRepo, userRepo, rdb, 5*time.Second)
+	movingWorker := worker.NewCellsMovingWorker(locationRepo, userRepo, repository.NewQuestRepository(db), rdb, 5*time.Second)
 	locationService, err := services.NewLocationService(db, rdb, locationRepo, userRepo, movingWorker)
 	if err != nil {
 		log.Fatalf("Failed to create LocationService: %v", err)
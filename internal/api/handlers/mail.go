@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type MailHandler struct {
+	mailService *services.MailService
+	userRepo    *repository.UserRepository
+	userCache   r.Cache[domain.User]
+}
+
+func NewMailHandler(db *sqlx.DB, rdb *redis.Client) *MailHandler {
+	return &MailHandler{
+		mailService: services.NewMailService(db, ws.GetHub()),
+		userRepo:    repository.NewUserRepository(db),
+		userCache:   r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+func (h *MailHandler) invalidateUserCache(ctx context.Context, userID uuid.UUID) {
+	_ = h.userCache.Delete(ctx, userID.String())
+}
+
+func handleMailError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrMailNotFound):
+		return ErrNotFound(c, "mail not found")
+	case errors.Is(err, services.ErrRecipientNotFound):
+		return ErrNotFound(c, "recipient not found")
+	case errors.Is(err, services.ErrMailToSelf):
+		return ErrBadRequest(c, "can't send mail to yourself")
+	case errors.Is(err, services.ErrInvalidMailSubject):
+		return ErrBadRequest(c, "invalid subject")
+	case errors.Is(err, services.ErrMailBodyTooLong):
+		return ErrBadRequest(c, "mail body too long")
+	case errors.Is(err, services.ErrTooManyAttachments):
+		return ErrBadRequest(c, "too many attachments")
+	case errors.Is(err, services.ErrNothingToCollect):
+		return ErrBadRequest(c, "mail has nothing to collect")
+	case errors.Is(err, services.ErrMailHasAttachments):
+		return ErrBadRequest(c, "collect the attachments first")
+	case errors.Is(err, services.ErrItemNotInInventory):
+		return ErrBadRequest(c, "item not in inventory")
+	case errors.Is(err, services.ErrInsufficientGold):
+		return ErrBadRequest(c, "insufficient gold")
+	case errors.Is(err, services.ErrInventoryFull):
+		return ErrBadRequest(c, "inventory is full")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *MailHandler) GetMailbox(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	mails, err := h.mailService.List(c.Request().Context(), userID)
+	if err != nil {
+		return handleMailError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.MailsFromDomain(mails))
+}
+
+func (h *MailHandler) GetUnreadCount(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	count, err := h.mailService.CountUnread(c.Request().Context(), userID)
+	if err != nil {
+		return handleMailError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.UnreadMailCount{Unread: count})
+}
+
+func (h *MailHandler) SendMail(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.SendMailRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	instanceIDs := make([]uuid.UUID, len(req.InstanceIDs))
+	for i, id := range req.InstanceIDs {
+		if instanceIDs[i], err = uuid.Parse(id); err != nil {
+			return ErrBadRequest(c, "invalid item id")
+		}
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	mail, err := h.mailService.Send(c.Request().Context(), userID, req.To, req.Subject, req.Body, req.Gold, instanceIDs)
+	if err != nil {
+		return handleMailError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, dto.MailFromDomain(mail))
+}
+
+func (h *MailHandler) ReadMail(c echo.Context) error {
+	mailID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid mail id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	mail, err := h.mailService.Read(c.Request().Context(), userID, mailID)
+	if err != nil {
+		return handleMailError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.MailFromDomain(mail))
+}
+
+func (h *MailHandler) CollectMail(c echo.Context) error {
+	mailID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid mail id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	mail, err := h.mailService.Collect(c.Request().Context(), userID, mailID)
+	if err != nil {
+		return handleMailError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), userID)
+	return c.JSON(http.StatusOK, dto.MailFromDomain(mail))
+}
+
+func (h *MailHandler) DeleteMail(c echo.Context) error {
+	mailID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid mail id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.mailService.Delete(c.Request().Context(), userID, mailID); err != nil {
+		return handleMailError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type QuestHandler struct {
+	questService *services.QuestService
+	userRepo     *repository.UserRepository
+	locationRepo *repository.LocationRepository
+	userCache    r.Cache[domain.User]
+}
+
+func NewQuestHandler(db *sqlx.DB, rdb *redis.Client) *QuestHandler {
+	userRepo := repository.NewUserRepository(db)
+	questService := services.NewQuestService(db, repository.NewQuestRepository(db), userRepo, ws.GetHub())
+
+	return &QuestHandler{
+		questService: questService,
+		userRepo:     userRepo,
+		locationRepo: repository.NewLocationRepository(db),
+		userCache:    r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+func handleQuestError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrQuestNotFound):
+		return ErrNotFound(c, "quest not found")
+	case errors.Is(err, services.ErrQuestAlreadyAccepted):
+		return ErrBadRequest(c, "quest already accepted")
+	case errors.Is(err, services.ErrQuestNotCompleted):
+		return ErrBadRequest(c, "quest not completed")
+	case errors.Is(err, services.ErrQuestNotAvailable):
+		return ErrBadRequest(c, "quest not available")
+	case errors.Is(err, services.ErrInsufficientLevel):
+		return ErrBadRequest(c, "insufficient level")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *QuestHandler) GetQuests(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	quests, err := h.questService.GetQuests(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.QuestsFromDomain(quests))
+}
+
+func (h *QuestHandler) AcceptQuest(c echo.Context) error {
+	slug := c.Param("slug")
+	if slug == "" {
+		return ErrBadRequest(c, "quest slug is required")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	quest, err := h.questService.AcceptQuest(c.Request().Context(), userID, slug)
+	if err != nil {
+		return handleQuestError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.QuestFromDomain(quest))
+}
+
+func (h *QuestHandler) ClaimQuest(c echo.Context) error {
+	slug := c.Param("slug")
+	if slug == "" {
+		return ErrBadRequest(c, "quest slug is required")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	user, err := h.questService.ClaimQuest(c.Request().Context(), userID, slug)
+	if err != nil {
+		return handleQuestError(c, err)
+	}
+
+	_ = h.userCache.Delete(c.Request().Context(), userID.String())
+	return c.JSON(http.StatusOK, dto.UserFromDomain(user, resolveUserLocation(user, h.locationRepo), nil, false))
+}
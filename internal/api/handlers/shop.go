@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/services"
+)
+
+type ShopHandler struct {
+	shopService *services.ShopService
+}
+
+func NewShopHandler(db *sqlx.DB) *ShopHandler {
+	return &ShopHandler{
+		shopService: services.NewShopService(db),
+	}
+}
+
+func (h *ShopHandler) GetStock(c echo.Context) error {
+	slug := c.Param("slug")
+	if slug == "" {
+		return ErrBadRequest(c, "shop slug is required")
+	}
+
+	stocks, err := h.shopService.GetStock(c.Request().Context(), slug)
+	if err != nil {
+		if errors.Is(err, services.ErrShopNotFound) {
+			return ErrNotFound(c, "shop not found")
+		}
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.ShopStockFromDomain(stocks))
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+type TradeHandler struct {
+	tradeService *services.TradeService
+	userRepo     *repository.UserRepository
+	userCache    r.Cache[domain.User]
+}
+
+func NewTradeHandler(db *sqlx.DB, rdb *redis.Client) *TradeHandler {
+	return &TradeHandler{
+		tradeService: services.NewTradeService(db, ws.GetHub()),
+		userRepo:     repository.NewUserRepository(db),
+		userCache:    r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
+	}
+}
+
+// invalidateUserCache drops both sides, a completed trade moves gold of each.
+func (h *TradeHandler) invalidateUserCache(ctx context.Context, trade *domain.Trade) {
+	_ = h.userCache.Delete(ctx, trade.InitiatorID.String())
+	_ = h.userCache.Delete(ctx, trade.PartnerID.String())
+}
+
+func handleTradeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTradeNotFound):
+		return ErrNotFound(c, "trade not found")
+	case errors.Is(err, services.ErrTradeWithSelf):
+		return ErrBadRequest(c, "can't trade with yourself")
+	case errors.Is(err, services.ErrNotInSameLocation):
+		return ErrBadRequest(c, "players are not in the same location")
+	case errors.Is(err, services.ErrAlreadyTrading):
+		return ErrBadRequest(c, "player already has an open trade")
+	case errors.Is(err, services.ErrTradeClosed):
+		return ErrBadRequest(c, "trade is already closed")
+	case errors.Is(err, services.ErrTradePartnerInFight):
+		return ErrBadRequest(c, "trade partner is in fight")
+	case errors.Is(err, services.ErrItemNotInInventory):
+		return ErrBadRequest(c, "item not in inventory")
+	case errors.Is(err, services.ErrInsufficientGold):
+		return ErrBadRequest(c, "insufficient gold")
+	case errors.Is(err, services.ErrInventoryFull):
+		return ErrBadRequest(c, "inventory is full")
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrNotFound(c, "user not found")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+func (h *TradeHandler) GetCurrentTrade(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	trade, err := h.tradeService.GetCurrent(c.Request().Context(), userID)
+	if err != nil {
+		return handleTradeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.TradeFromDomain(trade))
+}
+
+func (h *TradeHandler) StartTrade(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.StartTradeRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	partnerID, err := uuid.Parse(req.PartnerID)
+	if err != nil {
+		return ErrBadRequest(c, "invalid partner id")
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	trade, err := h.tradeService.Start(c.Request().Context(), userID, partnerID)
+	if err != nil {
+		return handleTradeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.TradeFromDomain(trade))
+}
+
+func (h *TradeHandler) UpdateOffer(c echo.Context) error {
+	tradeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid trade id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.TradeOfferRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	instanceIDs := make([]uuid.UUID, len(req.Items))
+	for i, id := range req.Items {
+		instanceIDs[i], err = uuid.Parse(id)
+		if err != nil {
+			return ErrBadRequest(c, "invalid item id")
+		}
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	trade, err := h.tradeService.UpdateOffer(c.Request().Context(), userID, tradeID, instanceIDs, req.Gold)
+	if err != nil {
+		return handleTradeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.TradeFromDomain(trade))
+}
+
+func (h *TradeHandler) ConfirmTrade(c echo.Context) error {
+	tradeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid trade id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	trade, err := h.tradeService.Confirm(c.Request().Context(), userID, tradeID)
+	if err != nil {
+		return handleTradeError(c, err)
+	}
+
+	h.invalidateUserCache(c.Request().Context(), trade)
+	return c.JSON(http.StatusOK, dto.TradeFromDomain(trade))
+}
+
+func (h *TradeHandler) CancelTrade(c echo.Context) error {
+	tradeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid trade id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.tradeService.Cancel(c.Request().Context(), userID, tradeID); err != nil {
+		return handleTradeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, nil)
+}
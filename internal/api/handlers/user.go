@@ -3,6 +3,8 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -12,14 +14,17 @@ import (
 	"moonshine/internal/api/dto"
 	"moonshine/internal/api/middleware"
 	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
 	"moonshine/internal/repository"
 )
 
 type UserHandler struct {
-	userService       *services.UserService
-	inventoryService  *services.InventoryService
-	userRepo          *repository.UserRepository
-	equipmentItemRepo *repository.EquipmentItemRepository
+	userService            *services.UserService
+	inventoryService       *services.InventoryService
+	goldTransactionService *services.GoldTransactionService
+	userRepo               *repository.UserRepository
+	equipmentItemRepo      *repository.EquipmentItemRepository
 }
 
 func NewUserHandler(db *sqlx.DB, rdb *redis.Client) *UserHandler {
@@ -29,13 +34,14 @@ func NewUserHandler(db *sqlx.DB, rdb *redis.Client) *UserHandler {
 	userService := services.NewUserService(userRepo, avatarRepo, locationRepo, rdb)
 
 	inventoryRepo := repository.NewInventoryRepository(db)
-	inventoryService := services.NewInventoryService(inventoryRepo)
+	inventoryService := services.NewInventoryService(inventoryRepo, repository.NewEquipmentItemRepository(db), repository.NewItemSetRepository(db))
 
 	return &UserHandler{
-		userService:       userService,
-		inventoryService:  inventoryService,
-		userRepo:          userRepo,
-		equipmentItemRepo: repository.NewEquipmentItemRepository(db),
+		userService:            userService,
+		inventoryService:       inventoryService,
+		goldTransactionService: services.NewGoldTransactionService(repository.NewGoldTransactionRepository(db)),
+		userRepo:               userRepo,
+		equipmentItemRepo:      repository.NewEquipmentItemRepository(db),
 	}
 }
 
@@ -67,7 +73,7 @@ func (h *UserHandler) GetUserInventory(c echo.Context) error {
 		return ErrInternalServerError(c)
 	}
 
-	return c.JSON(http.StatusOK, dto.EquipmentItemsFromDomain(items))
+	return c.JSON(http.StatusOK, dto.InventoryItemsFromDomain(items))
 }
 
 func (h *UserHandler) GetUserEquippedItems(c echo.Context) error {
@@ -81,50 +87,42 @@ func (h *UserHandler) GetUserEquippedItems(c echo.Context) error {
 		return ErrNotFound(c, "user not found")
 	}
 
-	slots := []struct {
-		name string
-		id   *uuid.UUID
-	}{
-		{"chest", user.ChestEquipmentItemID},
-		{"belt", user.BeltEquipmentItemID},
-		{"head", user.HeadEquipmentItemID},
-		{"neck", user.NeckEquipmentItemID},
-		{"weapon", user.WeaponEquipmentItemID},
-		{"shield", user.ShieldEquipmentItemID},
-		{"legs", user.LegsEquipmentItemID},
-		{"feet", user.FeetEquipmentItemID},
-		{"arms", user.ArmsEquipmentItemID},
-		{"hands", user.HandsEquipmentItemID},
-		{"ring1", user.Ring1EquipmentItemID},
-		{"ring2", user.Ring2EquipmentItemID},
-		{"ring3", user.Ring3EquipmentItemID},
-		{"ring4", user.Ring4EquipmentItemID},
-	}
-	var ids []uuid.UUID
-	for _, s := range slots {
-		if s.id != nil {
-			ids = append(ids, *s.id)
-		}
-	}
+	ids := user.EquippedItemIDs()
 	if len(ids) == 0 {
-		return c.JSON(http.StatusOK, map[string]*dto.EquipmentItem{})
+		return c.JSON(http.StatusOK, map[string]*dto.InventoryItem{})
 	}
 
 	list, err := h.equipmentItemRepo.FindByIDs(ids)
 	if err != nil {
 		return ErrInternalServerError(c)
 	}
-	idToItem := make(map[uuid.UUID]*dto.EquipmentItem)
+	idToItem := make(map[uuid.UUID]*domain.EquipmentItem)
 	for _, it := range list {
-		idToItem[it.ID] = dto.EquipmentItemFromDomain(it)
+		idToItem[it.ID] = it
+	}
+
+	instances, err := h.inventoryService.GetEquippedInstances(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	sets, err := h.inventoryService.GetItemSets(c.Request().Context(), ids)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+	itemToSet := make(map[uuid.UUID]*dto.ItemSet)
+	for _, set := range sets {
+		setDto := dto.ItemSetFromDomain(set, ids)
+		for _, id := range set.ItemIDs {
+			itemToSet[id] = setDto
+		}
 	}
 
-	equipmentItems := map[string]*dto.EquipmentItem{}
-	for _, s := range slots {
-		if s.id != nil {
-			if d, ok := idToItem[*s.id]; ok {
-				equipmentItems[s.name] = d
-			}
+	equipmentItems := map[string]*dto.InventoryItem{}
+	for slot, id := range user.Equipment {
+		if it, ok := idToItem[id]; ok {
+			equipmentItems[slot] = dto.InventoryItemFromDomain(it, instances[slot])
+			equipmentItems[slot].Set = itemToSet[id]
 		}
 	}
 	return c.JSON(http.StatusOK, equipmentItems)
@@ -173,3 +171,71 @@ func (h *UserHandler) UpdateCurrentUser(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, dto.UserFromDomain(user, location, nil, inFight))
 }
+
+func (h *UserHandler) AllocateStats(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	var req dto.AllocateStatsRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	_, err = h.userService.AllocateStats(c.Request().Context(), userID, req.Agility, req.Luck)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNoStatsToAllocate):
+			return ErrBadRequest(c, "no stats to allocate")
+		case errors.Is(err, services.ErrInsufficientFreeStats):
+			return ErrBadRequest(c, "insufficient free stats")
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrNotFound(c, "user not found")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	user, location, inFight, err := h.userService.GetCurrentUserWithRelations(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.UserFromDomain(user, location, nil, inFight))
+}
+
+func (h *UserHandler) GetGoldTransactions(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	transactions, err := h.goldTransactionService.GetTransactions(c.Request().Context(), userID, page, limit)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.GoldTransactionsFromDomain(transactions))
+}
+
+// GetOnlinePlayers lists the players connected to the websocket by username.
+func (h *UserHandler) GetOnlinePlayers(c echo.Context) error {
+	users, err := h.userRepo.FindByIDs(ws.GetHub().GetConnectedUserIDs())
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	return c.JSON(http.StatusOK, dto.OnlinePlayersFromDomain(users))
+}
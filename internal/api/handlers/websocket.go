@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -8,8 +10,10 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 
+	"moonshine/internal/api/services"
 	"moonshine/internal/api/ws"
 	"moonshine/internal/config"
 )
@@ -22,15 +26,29 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// incomingMessage is what clients send over the socket, Data depends on
+// Type.
+type incomingMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type guildChatMessage struct {
+	Text string `json:"text"`
+}
+
 type WebSocketHandler struct {
-	hub    *ws.Hub
-	config *config.Config
+	hub          *ws.Hub
+	config       *config.Config
+	guildService *services.GuildService
 }
 
-func NewWebSocketHandler(cfg *config.Config) *WebSocketHandler {
+func NewWebSocketHandler(cfg *config.Config, db *sqlx.DB) *WebSocketHandler {
+	hub := ws.GetHub()
 	return &WebSocketHandler{
-		hub:    ws.GetHub(),
-		config: cfg,
+		hub:          hub,
+		config:       cfg,
+		guildService: services.NewGuildService(db, hub),
 	}
 }
 
@@ -81,10 +99,11 @@ func (h *WebSocketHandler) handleConnection(userID uuid.UUID, conn *websocket.Co
 	go func() {
 		defer close(done)
 		for {
-			_, _, err := conn.ReadMessage()
+			_, data, err := conn.ReadMessage()
 			if err != nil {
 				return
 			}
+			h.handleMessage(userID, data)
 		}
 	}()
 
@@ -101,6 +120,25 @@ func (h *WebSocketHandler) handleConnection(userID uuid.UUID, conn *websocket.Co
 	}
 }
 
+// handleMessage dispatches a client message, unknown types are ignored.
+func (h *WebSocketHandler) handleMessage(userID uuid.UUID, data []byte) {
+	var msg incomingMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	switch msg.Type {
+	case "guild_chat":
+		var chat guildChatMessage
+		if err := json.Unmarshal(msg.Data, &chat); err != nil {
+			return
+		}
+		if _, err := h.guildService.Chat(context.Background(), userID, chat.Text); err != nil {
+			fmt.Printf("[WS] Guild chat from user %s failed: %v\n", userID, err)
+		}
+	}
+}
+
 func (h *WebSocketHandler) validateToken(tokenString string) (uuid.UUID, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
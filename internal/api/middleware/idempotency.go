@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	r "moonshine/internal/redis"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header. Keys are scoped to the user and route, requests
+// without the header run as usual. Server errors are not stored so the client
+// can retry them.
+func Idempotency(store *r.IdempotencyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "idempotency key is too long"})
+			}
+
+			userID, err := GetUserIDFromContext(c.Request().Context())
+			if err != nil {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			storeKey := userID.String() + ":" + c.Request().Method + ":" + c.Request().URL.Path + ":" + key
+
+			stored, err := store.Begin(ctx, storeKey)
+			if err != nil {
+				if errors.Is(err, r.ErrIdempotencyInProgress) {
+					return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+				}
+				log.Printf("[Idempotency] begin %s: %v", key, err)
+				return next(c)
+			}
+			if stored != nil {
+				c.Response().Header().Set(IdempotentReplayedHeader, "true")
+				return c.Blob(stored.Status, stored.ContentType, stored.Body)
+			}
+
+			body := &bytes.Buffer{}
+			original := c.Response().Writer
+			c.Response().Writer = &teeWriter{ResponseWriter: original, body: body}
+			err = next(c)
+			c.Response().Writer = original
+
+			status := c.Response().Status
+			if err != nil || status >= http.StatusInternalServerError {
+				if releaseErr := store.Release(ctx, storeKey); releaseErr != nil {
+					log.Printf("[Idempotency] release %s: %v", key, releaseErr)
+				}
+				return err
+			}
+
+			response := &r.IdempotentResponse{
+				Status:      status,
+				ContentType: c.Response().Header().Get(echo.HeaderContentType),
+				Body:        body.Bytes(),
+			}
+			if err := store.Complete(ctx, storeKey, response); err != nil {
+				log.Printf("[Idempotency] complete %s: %v", key, err)
+			}
+
+			return nil
+		}
+	}
+}
+
+type teeWriter struct {
+	http.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	r "moonshine/internal/redis"
+)
+
+func setupIdempotencyStore(t *testing.T) *r.IdempotencyStore {
+	t.Helper()
+	s := miniredis.RunT(t)
+	return r.NewIdempotencyStore(goredis.NewClient(&goredis.Options{Addr: s.Addr()}), "idempotency")
+}
+
+func serveIdempotent(e *echo.Echo, handler echo.HandlerFunc, userID uuid.UUID, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/equipment_items/sword/buy", nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	req = req.WithContext(ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	_ = handler(c)
+	return rec
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("retry replays the first response", func(t *testing.T) {
+		e := echo.New()
+		var calls atomic.Int32
+		handler := Idempotency(setupIdempotencyStore(t))(func(c echo.Context) error {
+			calls.Add(1)
+			return c.JSON(http.StatusOK, map[string]string{"message": "bought"})
+		})
+		userID := uuid.New()
+
+		first := serveIdempotent(e, handler, userID, "key-1")
+		second := serveIdempotent(e, handler, userID, "key-1")
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.JSONEq(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("concurrent requests run the handler once", func(t *testing.T) {
+		e := echo.New()
+		var calls atomic.Int32
+		entered := make(chan struct{}, 1)
+		release := make(chan struct{})
+		handler := Idempotency(setupIdempotencyStore(t))(func(c echo.Context) error {
+			calls.Add(1)
+			entered <- struct{}{}
+			<-release
+			return c.JSON(http.StatusOK, map[string]string{"message": "bought"})
+		})
+		userID := uuid.New()
+
+		const workers = 10
+		codes := make([]int, workers)
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func(i int) {
+				defer wg.Done()
+				codes[i] = serveIdempotent(e, handler, userID, "key-1").Code
+			}(i)
+		}
+		// Requests arriving while the first one runs get 409, later ones a replay.
+		<-entered
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		ok, conflicts := 0, 0
+		for _, code := range codes {
+			switch code {
+			case http.StatusOK:
+				ok++
+			case http.StatusConflict:
+				conflicts++
+			}
+		}
+		assert.Equal(t, workers, ok+conflicts)
+		assert.GreaterOrEqual(t, ok, 1)
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		e := echo.New()
+		var calls atomic.Int32
+		handler := Idempotency(setupIdempotencyStore(t))(func(c echo.Context) error {
+			if calls.Add(1) == 1 {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+			}
+			return c.JSON(http.StatusOK, map[string]string{"message": "bought"})
+		})
+		userID := uuid.New()
+
+		assert.Equal(t, http.StatusInternalServerError, serveIdempotent(e, handler, userID, "key-1").Code)
+		assert.Equal(t, http.StatusOK, serveIdempotent(e, handler, userID, "key-1").Code)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("keys are scoped per user", func(t *testing.T) {
+		e := echo.New()
+		var calls atomic.Int32
+		handler := Idempotency(setupIdempotencyStore(t))(func(c echo.Context) error {
+			calls.Add(1)
+			return c.NoContent(http.StatusOK)
+		})
+
+		serveIdempotent(e, handler, uuid.New(), "key-1")
+		serveIdempotent(e, handler, uuid.New(), "key-1")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("no header passes through", func(t *testing.T) {
+		e := echo.New()
+		var calls atomic.Int32
+		handler := Idempotency(setupIdempotencyStore(t))(func(c echo.Context) error {
+			calls.Add(1)
+			return c.NoContent(http.StatusOK)
+		})
+		userID := uuid.New()
+
+		serveIdempotent(e, handler, userID, "")
+		rec := serveIdempotent(e, handler, userID, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
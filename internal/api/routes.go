@@ -14,12 +14,13 @@ import (
 	"moonshine/internal/api/handlers"
 	jwtMiddleware "moonshine/internal/api/middleware"
 	"moonshine/internal/config"
+	r "moonshine/internal/redis"
 )
 
 func SetupRoutes(e *echo.Echo, db *sqlx.DB, rdb *redis.Client, cfg *config.Config) {
 	e.GET("/health", healthCheck)
 
-	wsHandler := handlers.NewWebSocketHandler(cfg)
+	wsHandler := handlers.NewWebSocketHandler(cfg, db)
 	e.GET("/api/ws", wsHandler.HandleConnection)
 
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -94,11 +95,35 @@ func SetupRoutes(e *echo.Echo, db *sqlx.DB, rdb *redis.Client, cfg *config.Confi
 	apiGroup.Use(echojwt.WithConfig(jwtConfig))
 	apiGroup.Use(jwtMiddleware.ExtractUserIDFromJWT())
 
+	// Purchases and sales can be retried safely with an Idempotency-Key header.
+	idempotent := jwtMiddleware.Idempotency(r.NewIdempotencyStore(rdb, "idempotency"))
+
 	userHandler := handlers.NewUserHandler(db, rdb)
 	apiGroup.GET("/user/me", userHandler.GetCurrentUser)
 	apiGroup.PUT("/user/me", userHandler.UpdateCurrentUser)
+	apiGroup.POST("/user/me/stats", userHandler.AllocateStats)
+	apiGroup.GET("/user/me/transactions", userHandler.GetGoldTransactions)
 	apiGroup.GET("/users/me/inventory", userHandler.GetUserInventory)
 	apiGroup.GET("/users/me/equipped", userHandler.GetUserEquippedItems)
+	apiGroup.GET("/players/online", userHandler.GetOnlinePlayers)
+
+	consumableHandler := handlers.NewConsumableHandler(db, rdb)
+	apiGroup.GET("/users/me/consumables", consumableHandler.GetUserConsumables)
+	apiGroup.GET("/consumables", consumableHandler.GetConsumables)
+	apiGroup.POST("/consumables/:slug/buy", consumableHandler.BuyConsumable, idempotent)
+	apiGroup.POST("/consumables/:slug/use", consumableHandler.UseConsumable)
+
+	questHandler := handlers.NewQuestHandler(db, rdb)
+	apiGroup.GET("/quests", questHandler.GetQuests)
+	apiGroup.POST("/quests/:slug/accept", questHandler.AcceptQuest)
+	apiGroup.POST("/quests/:slug/claim", questHandler.ClaimQuest)
+
+	achievementHandler := handlers.NewAchievementHandler(db, rdb)
+	apiGroup.GET("/achievements", achievementHandler.GetAchievements)
+	apiGroup.PUT("/user/me/title", achievementHandler.SelectTitle)
+
+	leaderboardHandler := handlers.NewLeaderboardHandler(db, rdb)
+	apiGroup.GET("/leaderboards/:kind", leaderboardHandler.GetLeaderboard)
 
 	avatarHandler := handlers.NewAvatarHandler(db)
 	apiGroup.GET("/avatars", avatarHandler.GetAllAvatars)
@@ -110,10 +135,70 @@ func SetupRoutes(e *echo.Echo, db *sqlx.DB, rdb *redis.Client, cfg *config.Confi
 
 	equipmentItemHandler := handlers.NewEquipmentItemHandler(db, rdb)
 	apiGroup.GET("/equipment_items", equipmentItemHandler.GetEquipmentItems)
+	apiGroup.GET("/equipment_items/catalog", equipmentItemHandler.SearchCatalog)
+	apiGroup.GET("/equipment_items/compare", equipmentItemHandler.CompareEquipmentItems)
+	apiGroup.GET("/equipment_items/upgrades", equipmentItemHandler.GetUpgradeSuggestions)
+	apiGroup.GET("/equipment_slots", equipmentItemHandler.GetEquipmentSlots)
 	apiGroup.POST("/equipment_items/take_off/:slot", equipmentItemHandler.TakeOffEquipmentItem)
-	apiGroup.POST("/equipment_items/:slug/buy", equipmentItemHandler.BuyEquipmentItem)
-	apiGroup.POST("/equipment_items/:slug/sell", equipmentItemHandler.SellEquipmentItem)
+	apiGroup.POST("/equipment_items/:slug/buy", equipmentItemHandler.BuyEquipmentItem, idempotent)
+	apiGroup.POST("/equipment_items/:slug/sell", equipmentItemHandler.SellEquipmentItem, idempotent)
 	apiGroup.POST("/equipment_items/:slug/take_on", equipmentItemHandler.TakeOnEquipmentItem)
+	apiGroup.POST("/inventory/:id/take_on", equipmentItemHandler.TakeOnInstance)
+	apiGroup.POST("/inventory/:id/sell", equipmentItemHandler.SellInstance, idempotent)
+	apiGroup.POST("/inventory/:id/repair", equipmentItemHandler.RepairItem, idempotent)
+	apiGroup.POST("/inventory/:id/enhance", equipmentItemHandler.EnhanceItem, idempotent)
+
+	inventoryHandler := handlers.NewInventoryHandler(db, rdb)
+	apiGroup.POST("/users/me/inventory/upgrade", inventoryHandler.UpgradeCapacity, idempotent)
+	apiGroup.GET("/users/me/bank", inventoryHandler.GetBank)
+	apiGroup.POST("/inventory/:id/deposit", inventoryHandler.Deposit)
+	apiGroup.POST("/bank/:id/withdraw", inventoryHandler.Withdraw)
+
+	loadoutHandler := handlers.NewLoadoutHandler(db, rdb)
+	apiGroup.GET("/loadouts", loadoutHandler.GetLoadouts)
+	apiGroup.POST("/loadouts", loadoutHandler.SaveLoadout)
+	apiGroup.PUT("/loadouts/:id", loadoutHandler.OverwriteLoadout)
+	apiGroup.DELETE("/loadouts/:id", loadoutHandler.DeleteLoadout)
+	apiGroup.POST("/loadouts/:id/apply", loadoutHandler.ApplyLoadout)
+
+	tradeHandler := handlers.NewTradeHandler(db, rdb)
+	apiGroup.POST("/trades", tradeHandler.StartTrade)
+	apiGroup.GET("/trades/current", tradeHandler.GetCurrentTrade)
+	apiGroup.PUT("/trades/:id/offer", tradeHandler.UpdateOffer)
+	apiGroup.POST("/trades/:id/confirm", tradeHandler.ConfirmTrade, idempotent)
+	apiGroup.POST("/trades/:id/cancel", tradeHandler.CancelTrade)
+
+	shopHandler := handlers.NewShopHandler(db)
+	apiGroup.GET("/shops/:slug/stock", shopHandler.GetStock)
+
+	auctionHandler := handlers.NewAuctionHandler(db, rdb)
+	apiGroup.GET("/auctions", auctionHandler.SearchAuctions)
+	apiGroup.POST("/auctions", auctionHandler.CreateAuction, idempotent)
+	apiGroup.POST("/auctions/:id/bid", auctionHandler.Bid, idempotent)
+	apiGroup.POST("/auctions/:id/buyout", auctionHandler.Buyout, idempotent)
+
+	mailHandler := handlers.NewMailHandler(db, rdb)
+	apiGroup.GET("/mail", mailHandler.GetMailbox)
+	apiGroup.GET("/mail/unread", mailHandler.GetUnreadCount)
+	apiGroup.POST("/mail", mailHandler.SendMail, idempotent)
+	apiGroup.POST("/mail/:id/read", mailHandler.ReadMail)
+	apiGroup.POST("/mail/:id/collect", mailHandler.CollectMail, idempotent)
+	apiGroup.DELETE("/mail/:id", mailHandler.DeleteMail)
+
+	guildHandler := handlers.NewGuildHandler(db, rdb)
+	apiGroup.GET("/guild", guildHandler.GetGuild)
+	apiGroup.POST("/guilds", guildHandler.CreateGuild, idempotent)
+	apiGroup.GET("/guild/invites", guildHandler.GetInvites)
+	apiGroup.POST("/guild/invites", guildHandler.Invite)
+	apiGroup.POST("/guild/invites/:guildId/accept", guildHandler.AcceptInvite)
+	apiGroup.POST("/guild/invites/:guildId/decline", guildHandler.DeclineInvite)
+	apiGroup.POST("/guild/leave", guildHandler.Leave)
+	apiGroup.POST("/guild/members/:userId/kick", guildHandler.Kick)
+	apiGroup.PUT("/guild/members/:userId/rank", guildHandler.SetRank)
+	apiGroup.POST("/guild/treasury/deposit", guildHandler.Deposit, idempotent)
+	apiGroup.POST("/guild/treasury/withdraw", guildHandler.Withdraw, idempotent)
+	apiGroup.GET("/guild/treasury/transactions", guildHandler.GetTransactions)
+	apiGroup.GET("/guild/chat", guildHandler.GetChat)
 
 	botHandler := handlers.NewBotHandler(db)
 	apiGroup.GET("/bots/:location_slug", botHandler.GetBots)
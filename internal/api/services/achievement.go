@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrAchievementNotFound = errors.New("achievement not found")
+	ErrAchievementLocked   = errors.New("achievement locked")
+	ErrAchievementNoTitle  = errors.New("achievement grants no title")
+)
+
+// AchievementService evaluates achievements on game events published by the
+// fight, movement and shop code and keeps the user's title.
+type AchievementService struct {
+	achievementRepo *repository.AchievementRepository
+	userRepo        *repository.UserRepository
+	hub             *ws.Hub
+}
+
+func NewAchievementService(
+	achievementRepo *repository.AchievementRepository,
+	userRepo *repository.UserRepository,
+	hub *ws.Hub,
+) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		userRepo:        userRepo,
+		hub:             hub,
+	}
+}
+
+func (s *AchievementService) GetAchievements(ctx context.Context, userID uuid.UUID) ([]*domain.AchievementProgress, error) {
+	achievements, err := s.achievementRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	userAchievements, err := s.achievementRepo.FindUserAchievements(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlockedAt := make(map[uuid.UUID]time.Time, len(userAchievements))
+	for _, userAchievement := range userAchievements {
+		unlockedAt[userAchievement.AchievementID] = userAchievement.UnlockedAt
+	}
+
+	result := make([]*domain.AchievementProgress, len(achievements))
+	for i, achievement := range achievements {
+		progress := &domain.AchievementProgress{Achievement: achievement}
+		if at, ok := unlockedAt[achievement.ID]; ok {
+			progress.UnlockedAt = &at
+		}
+		result[i] = progress
+	}
+
+	return result, nil
+}
+
+// HandleEvent unlocks every achievement the event completes and announces it
+// to the user. It is subscribed to the event bus at startup.
+func (s *AchievementService) HandleEvent(ctx context.Context, event domain.GameEvent) error {
+	if event.Type == domain.EventCellVisited {
+		if err := s.achievementRepo.RecordVisitedCell(event.UserID, event.Slug); err != nil {
+			return fmt.Errorf("record visited cell: %w", err)
+		}
+	}
+
+	types := domain.AchievementTypesFor(event.Type)
+	if len(types) == 0 {
+		return nil
+	}
+
+	achievements, err := s.achievementRepo.FindLockedByTypes(event.UserID, types)
+	if err != nil {
+		return fmt.Errorf("find locked achievements: %w", err)
+	}
+	if len(achievements) == 0 {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, achievement := range achievements {
+		reached, err := s.reached(user, achievement)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", achievement.Slug, err)
+		}
+		if !reached {
+			continue
+		}
+
+		if err := s.unlock(user, achievement); err != nil {
+			return fmt.Errorf("unlock %s: %w", achievement.Slug, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *AchievementService) reached(user *domain.User, achievement *domain.Achievement) (bool, error) {
+	switch achievement.Type {
+	case domain.AchievementKillBots:
+		kills, err := s.achievementRepo.CountKilledBots(user.ID)
+		return kills >= achievement.Threshold, err
+	case domain.AchievementReachLevel:
+		return user.Level >= achievement.Threshold, nil
+	case domain.AchievementOwnArtifact:
+		return s.achievementRepo.OwnsArtifact(user.ID, user.EquippedItemIDs())
+	case domain.AchievementVisitCells:
+		cells, err := s.achievementRepo.CountVisitedCells(user.ID)
+		return cells >= achievement.Threshold, err
+	default:
+		return false, nil
+	}
+}
+
+// unlock stores the achievement, gives its title to users without one and
+// sends the achievement_unlocked message.
+func (s *AchievementService) unlock(user *domain.User, achievement *domain.Achievement) error {
+	unlocked, err := s.achievementRepo.Unlock(user.ID, achievement.ID)
+	if err != nil || !unlocked {
+		return err
+	}
+
+	if achievement.Title != nil && user.Title == nil {
+		if err := s.userRepo.UpdateTitle(user.ID, achievement.Title); err != nil {
+			return err
+		}
+		user.Title = achievement.Title
+	}
+
+	return s.hub.SendAchievementUnlocked(user.ID, ws.AchievementUnlockedData{
+		Slug:        achievement.Slug,
+		Name:        achievement.Name,
+		Description: achievement.Description,
+		Title:       achievement.Title,
+	})
+}
+
+// SelectTitle shows the title of an unlocked achievement on the user. An
+// empty slug clears the title.
+func (s *AchievementService) SelectTitle(ctx context.Context, userID uuid.UUID, slug string) error {
+	if slug == "" {
+		return s.userRepo.UpdateTitle(userID, nil)
+	}
+
+	achievements, err := s.GetAchievements(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, progress := range achievements {
+		if progress.Achievement.Slug != slug {
+			continue
+		}
+		if progress.UnlockedAt == nil {
+			return ErrAchievementLocked
+		}
+		if progress.Achievement.Title == nil {
+			return ErrAchievementNoTitle
+		}
+		return s.userRepo.UpdateTitle(userID, progress.Achievement.Title)
+	}
+
+	return ErrAchievementNotFound
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func createTestAchievement(t *testing.T, achievementType domain.AchievementType, threshold uint, title *string) *domain.Achievement {
+	t.Helper()
+	ts := time.Now().UnixNano()
+
+	achievement := &domain.Achievement{
+		Name:      fmt.Sprintf("Achievement %d", ts),
+		Slug:      fmt.Sprintf("achievement-%d", ts),
+		Type:      achievementType,
+		Threshold: threshold,
+		Title:     title,
+	}
+	require.NoError(t, repository.NewAchievementRepository(testDB).Create(achievement))
+	return achievement
+}
+
+func newTestAchievementService() *AchievementService {
+	return NewAchievementService(repository.NewAchievementRepository(testDB), repository.NewUserRepository(testDB), ws.GetHub())
+}
+
+func TestAchievementService_HandleEvent(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestAchievementService()
+
+	t.Run("level reached unlocks once and grants the title", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		title := "Novice"
+		reached := createTestAchievement(t, domain.AchievementReachLevel, 1, &title)
+		locked := createTestAchievement(t, domain.AchievementReachLevel, 50, nil)
+
+		event := domain.GameEvent{Type: domain.EventLevelReached, UserID: user.ID}
+		require.NoError(t, service.HandleEvent(ctx, event))
+		require.NoError(t, service.HandleEvent(ctx, event))
+
+		achievements, err := service.GetAchievements(ctx, user.ID)
+		require.NoError(t, err)
+		for _, progress := range achievements {
+			switch progress.Achievement.ID {
+			case reached.ID:
+				assert.NotNil(t, progress.UnlockedAt)
+			case locked.ID:
+				assert.Nil(t, progress.UnlockedAt)
+			}
+		}
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, userAfter.Title)
+		assert.Equal(t, title, *userAfter.Title)
+	})
+
+	t.Run("only Wayward Pines cells count as visited", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		achievement := createTestAchievement(t, domain.AchievementVisitCells, 1, nil)
+		ts := time.Now().UnixNano()
+		otherImage := "elsewhere/cells/1cell.png"
+		other := &domain.Location{Name: "Elsewhere", Slug: fmt.Sprintf("elsewhere-%dcell", ts), Cell: true, Image: &otherImage}
+		require.NoError(t, repository.NewLocationRepository(testDB).Create(other))
+		image := domain.WaywardPinesSlug + "/cells/1cell.png"
+		cell := &domain.Location{Name: "Cell", Slug: fmt.Sprintf("%dcell", ts), Cell: true, Image: &image}
+		require.NoError(t, repository.NewLocationRepository(testDB).Create(cell))
+
+		unlocked := func() bool {
+			achievements, err := service.GetAchievements(ctx, user.ID)
+			require.NoError(t, err)
+			for _, progress := range achievements {
+				if progress.Achievement.ID == achievement.ID {
+					return progress.UnlockedAt != nil
+				}
+			}
+			return false
+		}
+
+		require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventCellVisited, UserID: user.ID, Slug: other.Slug}))
+		assert.False(t, unlocked())
+
+		require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventCellVisited, UserID: user.ID, Slug: cell.Slug}))
+		assert.True(t, unlocked())
+	})
+
+	t.Run("unrelated event does not unlock", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		achievement := createTestAchievement(t, domain.AchievementReachLevel, 1, nil)
+
+		require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventBotKilled, UserID: user.ID, Slug: "rat"}))
+
+		locked, err := repository.NewAchievementRepository(testDB).FindLockedByTypes(user.ID, []domain.AchievementType{domain.AchievementReachLevel})
+		require.NoError(t, err)
+		ids := make([]string, len(locked))
+		for i, a := range locked {
+			ids[i] = a.ID.String()
+		}
+		assert.Contains(t, ids, achievement.ID.String())
+	})
+}
+
+func TestAchievementService_SelectTitle(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestAchievementService()
+
+	user, _ := setupBuyTestData(t)
+	title := "Wanderer"
+	unlocked := createTestAchievement(t, domain.AchievementReachLevel, 1, &title)
+	noTitle := createTestAchievement(t, domain.AchievementReachLevel, 1, nil)
+	locked := createTestAchievement(t, domain.AchievementReachLevel, 50, &title)
+	require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventLevelReached, UserID: user.ID}))
+
+	assert.NoError(t, service.SelectTitle(ctx, user.ID, unlocked.Slug))
+	assert.ErrorIs(t, service.SelectTitle(ctx, user.ID, noTitle.Slug), ErrAchievementNoTitle)
+	assert.ErrorIs(t, service.SelectTitle(ctx, user.ID, locked.Slug), ErrAchievementLocked)
+	assert.ErrorIs(t, service.SelectTitle(ctx, user.ID, "nonexistent"), ErrAchievementNotFound)
+	assert.NoError(t, service.SelectTitle(ctx, user.ID, ""))
+}
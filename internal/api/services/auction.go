@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/events"
+	"moonshine/internal/repository"
+)
+
+const (
+	DefaultAuctionsLimit = 20
+	MaxAuctionsLimit     = 100
+	// auctionSettleBatch is how many expired auctions one worker run settles.
+	auctionSettleBatch = 100
+)
+
+var (
+	ErrAuctionNotFound        = errors.New("auction not found")
+	ErrAuctionEnded           = errors.New("auction has ended")
+	ErrInvalidAuctionDuration = errors.New("invalid auction duration")
+	ErrInvalidAuctionPrice    = errors.New("invalid auction price")
+	ErrOwnAuction             = errors.New("can't bid on your own auction")
+	ErrAlreadyTopBidder       = errors.New("already the top bidder")
+	ErrBidTooLow              = errors.New("bid too low")
+	ErrNoBuyout               = errors.New("auction has no buyout price")
+)
+
+// AuctionService runs the player market. Listed instances are held in escrow
+// and the top bid's gold is taken from the bidder right away, so settling an
+// auction never fails for lack of gold. Expired auctions are settled by the
+// auction worker, buyouts right away. The seller gets a system mail with the
+// result.
+type AuctionService struct {
+	uow   *repository.UnitOfWork
+	repos *repository.Repositories
+	hub   *ws.Hub
+}
+
+func NewAuctionService(db *sqlx.DB, hub *ws.Hub) *AuctionService {
+	return &AuctionService{
+		uow:   repository.NewUnitOfWork(db),
+		repos: repository.NewRepositories(db),
+		hub:   hub,
+	}
+}
+
+// Search returns one page of active auctions with their items. Pages start
+// at 1.
+func (s *AuctionService) Search(ctx context.Context, filter domain.AuctionFilter, page, limit int) ([]*domain.Auction, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultAuctionsLimit
+	}
+	if limit > MaxAuctionsLimit {
+		limit = MaxAuctionsLimit
+	}
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
+
+	auctions, err := s.repos.Auctions.Search(filter, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadAuctionItems(s.repos, auctions); err != nil {
+		return nil, err
+	}
+
+	return auctions, nil
+}
+
+// CreateAuction lists the user's unequipped instance. The seller needs the
+// gold for the listing fee, it is charged when the auction is settled.
+func (s *AuctionService) CreateAuction(ctx context.Context, userID, instanceID uuid.UUID, startPrice uint, buyoutPrice *uint, duration time.Duration) (*domain.Auction, error) {
+	if !domain.ValidAuctionDuration(duration) {
+		return nil, ErrInvalidAuctionDuration
+	}
+	if startPrice == 0 || (buyoutPrice != nil && *buyoutPrice < startPrice) {
+		return nil, ErrInvalidAuctionPrice
+	}
+
+	auction := &domain.Auction{
+		SellerID:    userID,
+		StartPrice:  startPrice,
+		BuyoutPrice: buyoutPrice,
+		ListingFee:  domain.AuctionListingFee(startPrice),
+	}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+		if user.Gold < auction.ListingFee {
+			return ErrInsufficientGold
+		}
+
+		instance, err := repos.Inventory.FindByIDForUpdate(userID, instanceID)
+		if err != nil {
+			if errors.Is(err, repository.ErrInventoryNotFound) {
+				return ErrInstanceNotFound
+			}
+			return err
+		}
+
+		auction.InventoryID = instance.ID
+		auction.EquipmentItemID = instance.EquipmentItemID
+		auction.ExpiresAt = time.Now().UTC().Add(duration)
+		if err := repos.Auctions.Create(auction); err != nil {
+			return err
+		}
+
+		if err := repos.Inventory.EscrowForAuction(auction.ID, userID, instance.ID); err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrItemNotInInventory
+			}
+			return err
+		}
+
+		return loadAuctionItems(repos, []*domain.Auction{auction})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// Bid takes the amount from the user and gives the previous top bidder their
+// gold back. A bid reaching the buyout price buys the item out.
+func (s *AuctionService) Bid(ctx context.Context, userID, auctionID uuid.UUID, amount uint) (*domain.Auction, error) {
+	var auction *domain.Auction
+	var mails []*domain.Mail
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		mails = nil
+		auction, err = findActiveAuctionForUpdate(repos, auctionID, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if auction.SellerID == userID {
+			return ErrOwnAuction
+		}
+
+		if auction.BuyoutPrice != nil && amount >= *auction.BuyoutPrice {
+			mails, err = buyoutAuction(repos, auction, userID)
+			return err
+		}
+
+		if auction.BidderID != nil && *auction.BidderID == userID {
+			return ErrAlreadyTopBidder
+		}
+		if amount < auction.MinBid() {
+			return ErrBidTooLow
+		}
+
+		return placeBid(repos, auction, userID, amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if mails != nil {
+		s.publishSold(ctx, auction, mails)
+		s.notifyMails(mails)
+	}
+	return auction, nil
+}
+
+// Buyout buys the item for the buyout price and settles the auction.
+func (s *AuctionService) Buyout(ctx context.Context, userID, auctionID uuid.UUID) (*domain.Auction, error) {
+	var auction *domain.Auction
+	var mails []*domain.Mail
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		auction, err = findActiveAuctionForUpdate(repos, auctionID, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if auction.SellerID == userID {
+			return ErrOwnAuction
+		}
+		if auction.BuyoutPrice == nil {
+			return ErrNoBuyout
+		}
+
+		mails, err = buyoutAuction(repos, auction, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishSold(ctx, auction, mails)
+	s.notifyMails(mails)
+	return auction, nil
+}
+
+// SettleExpired settles the auctions that ended before now and returns how
+// many were settled. One failing auction doesn't stop the others.
+func (s *AuctionService) SettleExpired(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.repos.Auctions.FindExpiredIDs(now, auctionSettleBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, id := range ids {
+		var auction *domain.Auction
+		var mails []*domain.Mail
+		err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+			auction = nil
+			locked, err := repos.Auctions.FindByIDForUpdate(id)
+			if err != nil {
+				return err
+			}
+			// A buyout may have settled it since it was picked.
+			if locked.Status != domain.AuctionStatusActive || !locked.Expired(now) {
+				return nil
+			}
+
+			auction = locked
+			mails, err = settleAuction(repos, auction)
+			return err
+		})
+		if err != nil {
+			log.Printf("[AuctionService] failed to settle auction %s: %v", id, err)
+			continue
+		}
+		if auction == nil {
+			continue
+		}
+
+		settled++
+		if auction.Status == domain.AuctionStatusSold {
+			s.publishSold(ctx, auction, mails)
+		}
+		s.notifyMails(mails)
+	}
+
+	return settled, nil
+}
+
+func placeBid(repos *repository.Repositories, auction *domain.Auction, userID uuid.UUID, amount uint) error {
+	if err := refundTopBid(repos, auction); err != nil {
+		return err
+	}
+
+	if _, err := repos.GoldTransactions.Apply(userID, -int64(amount), domain.GoldReasonAuctionBid, &auction.ID); err != nil {
+		if errors.Is(err, repository.ErrInsufficientGold) {
+			return ErrInsufficientGold
+		}
+		return err
+	}
+
+	return repos.Auctions.UpdateBid(auction, userID, amount)
+}
+
+func buyoutAuction(repos *repository.Repositories, auction *domain.Auction, userID uuid.UUID) ([]*domain.Mail, error) {
+	if err := placeBid(repos, auction, userID, *auction.BuyoutPrice); err != nil {
+		return nil, err
+	}
+	return settleAuction(repos, auction)
+}
+
+func refundTopBid(repos *repository.Repositories, auction *domain.Auction) error {
+	if auction.BidderID == nil || auction.CurrentBid == nil {
+		return nil
+	}
+
+	_, err := repos.GoldTransactions.Apply(*auction.BidderID, int64(*auction.CurrentBid), domain.GoldReasonAuctionRefund, &auction.ID)
+	return err
+}
+
+// settleAuction gives the item to the top bidder and the bid to the seller,
+// or the item back to the seller when nobody bid. The seller pays the listing
+// fee either way, an unsold auction as far as their gold goes, and is mailed
+// the result. An item that doesn't fit into the receiver's bag is mailed to
+// them. The returned mails are to be announced after commit.
+func settleAuction(repos *repository.Repositories, auction *domain.Auction) ([]*domain.Mail, error) {
+	item, err := repos.EquipmentItems.FindByID(auction.EquipmentItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.BidderID == nil {
+		seller, err := repos.Users.FindByIDForUpdate(auction.SellerID)
+		if err != nil {
+			return nil, repository.ErrUserNotFound
+		}
+		inBag, err := releaseAuctionItem(repos, auction, seller)
+		if err != nil {
+			return nil, err
+		}
+		fee := min(auction.ListingFee, seller.Gold)
+		if _, err := repos.GoldTransactions.Apply(auction.SellerID, -int64(fee), domain.GoldReasonAuctionFee, &auction.ID); err != nil {
+			return nil, err
+		}
+		if err := repos.Auctions.Settle(auction, domain.AuctionStatusExpired); err != nil {
+			return nil, err
+		}
+
+		where := "it is back in your bag"
+		if !inBag {
+			where = "your bag was full, so it is attached"
+		}
+		body := fmt.Sprintf("Nobody bought your %s, %s. The listing fee was %d gold.", item.Name, where, fee)
+		mail, err := SendSystemMail(repos, auction.SellerID, "Auction expired: "+item.Name, body, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !inBag {
+			if err := repos.Inventory.MailAuctionEscrow(auction.ID, mail.ID, auction.SellerID); err != nil {
+				return nil, err
+			}
+		}
+		return []*domain.Mail{mail}, nil
+	}
+
+	bidder, err := repos.Users.FindByIDForUpdate(*auction.BidderID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+	inBag, err := releaseAuctionItem(repos, auction, bidder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := repos.GoldTransactions.Apply(auction.SellerID, int64(*auction.CurrentBid), domain.GoldReasonAuctionSale, &auction.ID); err != nil {
+		return nil, err
+	}
+	if _, err := repos.GoldTransactions.Apply(auction.SellerID, -int64(auction.ListingFee), domain.GoldReasonAuctionFee, &auction.ID); err != nil {
+		return nil, err
+	}
+	if err := repos.Auctions.Settle(auction, domain.AuctionStatusSold); err != nil {
+		return nil, err
+	}
+
+	var mails []*domain.Mail
+	if !inBag {
+		body := fmt.Sprintf("You won the auction for %s. Your bag was full, so it is attached.", item.Name)
+		mail, err := SendSystemMail(repos, bidder.ID, "Auction won: "+item.Name, body, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := repos.Inventory.MailAuctionEscrow(auction.ID, mail.ID, bidder.ID); err != nil {
+			return nil, err
+		}
+		mails = append(mails, mail)
+	}
+
+	body := fmt.Sprintf("Your %s sold for %d gold. The listing fee was %d gold.", item.Name, *auction.CurrentBid, auction.ListingFee)
+	mail, err := SendSystemMail(repos, auction.SellerID, "Auction sold: "+item.Name, body, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(mails, mail), nil
+}
+
+// releaseAuctionItem puts the auctioned instance into the user's bag when
+// there is room and reports whether it did. Otherwise it stays in escrow
+// for the caller to mail.
+func releaseAuctionItem(repos *repository.Repositories, auction *domain.Auction, user *domain.User) (bool, error) {
+	if err := ensureBagSpace(repos, user); err != nil {
+		if errors.Is(err, ErrInventoryFull) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := repos.Inventory.ReleaseAuctionEscrow(auction.ID, user.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func findActiveAuctionForUpdate(repos *repository.Repositories, auctionID uuid.UUID, now time.Time) (*domain.Auction, error) {
+	auction, err := repos.Auctions.FindByIDForUpdate(auctionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuctionNotFound) {
+			return nil, ErrAuctionNotFound
+		}
+		return nil, err
+	}
+	if auction.Status != domain.AuctionStatusActive || auction.Expired(now) {
+		return nil, ErrAuctionEnded
+	}
+
+	return auction, nil
+}
+
+// loadAuctionItems fills the listed instance of every auction.
+func loadAuctionItems(repos *repository.Repositories, auctions []*domain.Auction) error {
+	if len(auctions) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(auctions))
+	for i, auction := range auctions {
+		ids[i] = auction.InventoryID
+	}
+
+	instances, err := repos.Inventory.FindByIDs(ids)
+	if err != nil {
+		return err
+	}
+	items, err := findInstanceItems(repos, instances)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Inventory, len(instances))
+	for _, instance := range instances {
+		byID[instance.ID] = instance
+	}
+	for _, auction := range auctions {
+		if instance, ok := byID[auction.InventoryID]; ok {
+			auction.Item = &domain.InventoryItem{EquipmentItem: items[instance.EquipmentItemID], Instance: instance}
+		}
+	}
+	return nil
+}
+
+// publishSold announces the item the winner got. An item mailed to the
+// winner is announced when the mail is collected.
+func (s *AuctionService) publishSold(ctx context.Context, auction *domain.Auction, mails []*domain.Mail) {
+	for _, mail := range mails {
+		if mail.RecipientID == *auction.BidderID {
+			return
+		}
+	}
+
+	item, err := s.repos.EquipmentItems.FindByID(auction.EquipmentItemID)
+	if err != nil {
+		return
+	}
+	events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventItemAcquired, UserID: *auction.BidderID, Slug: item.Slug})
+}
+
+func (s *AuctionService) notifyMails(mails []*domain.Mail) {
+	for _, mail := range mails {
+		NotifyNewMail(s.hub, mail)
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestAuctionService(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewAuctionService(testDB, ws.GetHub())
+	userRepo := repository.NewUserRepository(testDB)
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+
+	// list puts a new instance of a new item up for auction.
+	list := func(t *testing.T, buyout *uint) (*domain.User, *domain.Auction) {
+		seller, item := setupBuyTestData(t)
+		instance := &domain.Inventory{UserID: seller.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+
+		auction, err := service.CreateAuction(ctx, seller.ID, instance.ID, 100, buyout, 24*time.Hour)
+		require.NoError(t, err)
+		return seller, auction
+	}
+
+	gold := func(t *testing.T, user *domain.User) uint {
+		found, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		return found.Gold
+	}
+
+	t.Run("listed item leaves the bag", func(t *testing.T) {
+		seller, auction := list(t, nil)
+		assert.Equal(t, uint(5), auction.ListingFee)
+		require.NotNil(t, auction.Item)
+
+		bag, err := inventoryRepo.FindByUserID(seller.ID)
+		require.NoError(t, err)
+		assert.Empty(t, bag)
+
+		_, err = service.CreateAuction(ctx, seller.ID, auction.InventoryID, 100, nil, 24*time.Hour)
+		assert.ErrorIs(t, err, ErrInstanceNotFound)
+	})
+
+	t.Run("outbid bidder gets the gold back", func(t *testing.T) {
+		_, auction := list(t, nil)
+		first, _ := setupBuyTestData(t)
+		second, _ := setupBuyTestData(t)
+
+		_, err := service.Bid(ctx, first.ID, auction.ID, 99)
+		assert.ErrorIs(t, err, ErrBidTooLow)
+
+		_, err = service.Bid(ctx, first.ID, auction.ID, 100)
+		require.NoError(t, err)
+		assert.Equal(t, first.Gold-100, gold(t, first))
+
+		_, err = service.Bid(ctx, second.ID, auction.ID, 104)
+		assert.ErrorIs(t, err, ErrBidTooLow)
+		auction, err = service.Bid(ctx, second.ID, auction.ID, 105)
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Gold, gold(t, first))
+		assert.Equal(t, second.Gold-105, gold(t, second))
+		assert.Equal(t, second.ID, *auction.BidderID)
+	})
+
+	t.Run("buyout settles right away", func(t *testing.T) {
+		buyout := uint(300)
+		seller, auction := list(t, &buyout)
+		buyer, _ := setupBuyTestData(t)
+
+		auction, err := service.Buyout(ctx, buyer.ID, auction.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.AuctionStatusSold, auction.Status)
+
+		assert.Equal(t, buyer.Gold-300, gold(t, buyer))
+		assert.Equal(t, seller.Gold+300-auction.ListingFee, gold(t, seller))
+		_, err = inventoryRepo.FindByIDForUpdate(buyer.ID, auction.InventoryID)
+		assert.NoError(t, err)
+
+		_, err = service.Bid(ctx, buyer.ID, auction.ID, 400)
+		assert.ErrorIs(t, err, ErrAuctionEnded)
+	})
+
+	t.Run("buyout into a full bag is mailed", func(t *testing.T) {
+		buyout := uint(300)
+		_, auction := list(t, &buyout)
+		buyer, _ := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE users SET inventory_capacity = 0 WHERE id = $1`, buyer.ID)
+		require.NoError(t, err)
+
+		_, err = service.Buyout(ctx, buyer.ID, auction.ID)
+		require.NoError(t, err)
+
+		_, err = inventoryRepo.FindByIDForUpdate(buyer.ID, auction.InventoryID)
+		assert.ErrorIs(t, err, repository.ErrInventoryNotFound)
+		mails, err := repository.NewMailRepository(testDB).FindByRecipientID(buyer.ID, time.Now().UTC())
+		require.NoError(t, err)
+		require.Len(t, mails, 1)
+		var mailID string
+		require.NoError(t, testDB.Get(&mailID, `SELECT escrow_mail_id FROM inventory WHERE id = $1`, auction.InventoryID))
+		assert.Equal(t, mails[0].ID.String(), mailID)
+	})
+
+	t.Run("bid checks", func(t *testing.T) {
+		seller, auction := list(t, nil)
+		bidder, _ := setupBuyTestData(t)
+
+		_, err := service.Bid(ctx, seller.ID, auction.ID, 100)
+		assert.ErrorIs(t, err, ErrOwnAuction)
+		_, err = service.Buyout(ctx, bidder.ID, auction.ID)
+		assert.ErrorIs(t, err, ErrNoBuyout)
+		_, err = service.Bid(ctx, bidder.ID, auction.ID, bidder.Gold+1)
+		assert.ErrorIs(t, err, ErrInsufficientGold)
+
+		_, err = service.Bid(ctx, bidder.ID, auction.ID, 100)
+		require.NoError(t, err)
+		_, err = service.Bid(ctx, bidder.ID, auction.ID, 200)
+		assert.ErrorIs(t, err, ErrAlreadyTopBidder)
+	})
+
+	t.Run("expired auctions are settled", func(t *testing.T) {
+		seller, unsold := list(t, nil)
+		winnerSeller, sold := list(t, nil)
+		winner, _ := setupBuyTestData(t)
+		_, err := service.Bid(ctx, winner.ID, sold.ID, 150)
+		require.NoError(t, err)
+
+		_, err = service.SettleExpired(ctx, time.Now().UTC().Add(25*time.Hour))
+		require.NoError(t, err)
+
+		bag, err := inventoryRepo.FindByUserID(seller.ID)
+		require.NoError(t, err)
+		require.Len(t, bag, 1)
+		assert.Equal(t, unsold.InventoryID, bag[0].ID)
+		assert.Equal(t, seller.Gold-unsold.ListingFee, gold(t, seller))
+
+		assert.Equal(t, winnerSeller.Gold+150-sold.ListingFee, gold(t, winnerSeller))
+		_, err = inventoryRepo.FindByIDForUpdate(winner.ID, sold.InventoryID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid listings", func(t *testing.T) {
+		seller, item := setupBuyTestData(t)
+		instance := &domain.Inventory{UserID: seller.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+		low := uint(50)
+
+		_, err := service.CreateAuction(ctx, seller.ID, instance.ID, 100, nil, time.Hour)
+		assert.ErrorIs(t, err, ErrInvalidAuctionDuration)
+		_, err = service.CreateAuction(ctx, seller.ID, instance.ID, 0, nil, 24*time.Hour)
+		assert.ErrorIs(t, err, ErrInvalidAuctionPrice)
+		_, err = service.CreateAuction(ctx, seller.ID, instance.ID, 100, &low, 24*time.Hour)
+		assert.ErrorIs(t, err, ErrInvalidAuctionPrice)
+	})
+}
+
+func TestAuctionService_Search(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewAuctionService(testDB, ws.GetHub())
+
+	seller, item := setupBuyTestData(t)
+	instance := &domain.Inventory{UserID: seller.ID, EquipmentItemID: item.ID}
+	require.NoError(t, repository.NewInventoryRepository(testDB).Create(instance))
+	auction, err := service.CreateAuction(ctx, seller.ID, instance.ID, 12345, nil, 12*time.Hour)
+	require.NoError(t, err)
+
+	found, err := service.Search(ctx, domain.AuctionFilter{CategoryType: "weapon", MinPrice: 12345, MaxPrice: 12345}, 1, MaxAuctionsLimit)
+	require.NoError(t, err)
+	var ids []string
+	for _, a := range found {
+		ids = append(ids, a.ID.String())
+	}
+	assert.Contains(t, ids, auction.ID.String())
+
+	found, err = service.Search(ctx, domain.AuctionFilter{MinLevel: item.RequiredLevel + 1, MinPrice: 12345, MaxPrice: 12345}, 1, MaxAuctionsLimit)
+	require.NoError(t, err)
+	for _, a := range found {
+		assert.NotEqual(t, auction.ID, a.ID)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// BankLocationSlug is where items are deposited and withdrawn.
+const BankLocationSlug = "bank"
+
+var (
+	ErrNotInBank = errors.New("items are only deposited and withdrawn in the bank")
+	ErrBankFull  = errors.New("bank is full")
+)
+
+// BankService keeps items out of the bag. Banked instances can't be equipped,
+// sold, traded or auctioned until they are withdrawn.
+type BankService struct {
+	uow          *repository.UnitOfWork
+	repos        *repository.Repositories
+	locationRepo *repository.LocationRepository
+}
+
+func NewBankService(db *sqlx.DB, locationRepo *repository.LocationRepository) *BankService {
+	return &BankService{
+		uow:          repository.NewUnitOfWork(db),
+		repos:        repository.NewRepositories(db),
+		locationRepo: locationRepo,
+	}
+}
+
+// GetBank returns the instances the user keeps in the bank, it can be viewed
+// from anywhere.
+func (s *BankService) GetBank(ctx context.Context, userID uuid.UUID) ([]*domain.InventoryItem, error) {
+	instances, err := s.repos.Inventory.FindBanked(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := findInstanceItems(s.repos, instances)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.InventoryItem, 0, len(instances))
+	for _, instance := range instances {
+		if item, ok := items[instance.EquipmentItemID]; ok {
+			result = append(result, &domain.InventoryItem{EquipmentItem: item, Instance: instance})
+		}
+	}
+	return result, nil
+}
+
+// Deposit moves an unequipped instance, a whole stack for stackable items,
+// from the bag into the bank.
+func (s *BankService) Deposit(ctx context.Context, userID, instanceID uuid.UUID) error {
+	return s.move(ctx, userID, instanceID, true)
+}
+
+// Withdraw moves a banked instance back into the bag.
+func (s *BankService) Withdraw(ctx context.Context, userID, instanceID uuid.UUID) error {
+	return s.move(ctx, userID, instanceID, false)
+}
+
+func (s *BankService) move(ctx context.Context, userID, instanceID uuid.UUID, banked bool) error {
+	bank, err := s.locationRepo.FindBySlug(BankLocationSlug)
+	if err != nil {
+		return ErrNotInBank
+	}
+
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		if user.LocationID != bank.ID {
+			return ErrNotInBank
+		}
+
+		if banked {
+			used, err := repos.Inventory.CountBanked(userID)
+			if err != nil {
+				return err
+			}
+			if used >= domain.BankCapacity {
+				return ErrBankFull
+			}
+		} else if err := ensureBagSpace(repos, user); err != nil {
+			return err
+		}
+
+		if err := repos.Inventory.SetBanked(userID, instanceID, banked); err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrInstanceNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func bank(t *testing.T) *domain.Location {
+	t.Helper()
+	locationRepo := repository.NewLocationRepository(testDB)
+	if bank, err := locationRepo.FindBySlug(BankLocationSlug); err == nil {
+		return bank
+	}
+
+	bank := &domain.Location{Name: "Bank", Slug: BankLocationSlug}
+	require.NoError(t, locationRepo.Create(bank))
+	return bank
+}
+
+func TestBankService(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewBankService(testDB, repository.NewLocationRepository(testDB))
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+	userRepo := repository.NewUserRepository(testDB)
+
+	setup := func(t *testing.T) (*domain.User, *domain.Inventory) {
+		user, item := setupBuyTestData(t)
+		instance := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+		require.NoError(t, userRepo.UpdateLocationID(user.ID, bank(t).ID))
+		return user, instance
+	}
+
+	t.Run("deposit and withdraw", func(t *testing.T) {
+		user, instance := setup(t)
+
+		require.NoError(t, service.Deposit(ctx, user.ID, instance.ID))
+
+		bag, err := inventoryRepo.FindByUserID(user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, bag)
+
+		banked, err := service.GetBank(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, banked, 1)
+		assert.Equal(t, instance.ID, banked[0].Instance.ID)
+
+		assert.ErrorIs(t, service.Deposit(ctx, user.ID, instance.ID), ErrInstanceNotFound)
+
+		require.NoError(t, service.Withdraw(ctx, user.ID, instance.ID))
+		bag, err = inventoryRepo.FindByUserID(user.ID)
+		require.NoError(t, err)
+		assert.Len(t, bag, 1)
+	})
+
+	t.Run("withdraw into a full bag", func(t *testing.T) {
+		user, instance := setup(t)
+		require.NoError(t, service.Deposit(ctx, user.ID, instance.ID))
+		require.NoError(t, inventoryRepo.Create(&domain.Inventory{UserID: user.ID, EquipmentItemID: instance.EquipmentItemID}))
+		_, err := testDB.Exec(`UPDATE users SET inventory_capacity = 1 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, service.Withdraw(ctx, user.ID, instance.ID), ErrInventoryFull)
+	})
+
+	t.Run("outside the bank", func(t *testing.T) {
+		user, instance := setup(t)
+		require.NoError(t, userRepo.UpdateLocationID(user.ID, weaponShop(t).ID))
+
+		assert.ErrorIs(t, service.Deposit(ctx, user.ID, instance.ID), ErrNotInBank)
+	})
+}
+
+func TestInventoryUpgradeService_UpgradeCapacity(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewInventoryUpgradeService(testDB)
+
+	t.Run("success", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+
+		capacity, err := service.UpgradeCapacity(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(domain.DefaultInventoryCapacity+domain.InventoryCapacityStep), capacity)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, capacity, userAfter.InventoryCapacity)
+		assert.Equal(t, user.Gold-domain.InventoryUpgradeBaseCost, userAfter.Gold)
+	})
+
+	t.Run("max capacity", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE users SET inventory_capacity = $1 WHERE id = $2`, domain.MaxInventoryCapacity, user.ID)
+		require.NoError(t, err)
+
+		_, err = service.UpgradeCapacity(ctx, user.ID)
+		assert.ErrorIs(t, err, ErrMaxInventoryCapacity)
+	})
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"moonshine/internal/domain"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+// CatalogCacheTTL is how long a catalog page is served from the cache. Items
+// only change with migrations, so pages are not invalidated.
+const CatalogCacheTTL = 5 * time.Minute
+
+var (
+	ErrInvalidCatalogSort  = errors.New("invalid catalog sort")
+	ErrInvalidCatalogRange = errors.New("invalid catalog range")
+)
+
+type CatalogService struct {
+	equipmentItemRepo *repository.EquipmentItemRepository
+	cache             r.Cache[domain.CatalogPage]
+}
+
+func NewCatalogService(equipmentItemRepo *repository.EquipmentItemRepository, rdb *goredis.Client) *CatalogService {
+	return &CatalogService{
+		equipmentItemRepo: equipmentItemRepo,
+		cache:             r.NewJSONCache[domain.CatalogPage](rdb, "catalog", CatalogCacheTTL),
+	}
+}
+
+// Search returns a page of the catalog. The sort defaults to the level, the
+// limit to CatalogDefaultLimit and is capped at CatalogMaxLimit. A cursor made
+// for another sort or direction is domain.ErrInvalidCatalogCursor.
+func (s *CatalogService) Search(ctx context.Context, query domain.CatalogQuery) (*domain.CatalogPage, error) {
+	if query.Sort == "" {
+		query.Sort = domain.CatalogSortLevel
+	}
+	if !query.Sort.Valid() {
+		return nil, ErrInvalidCatalogSort
+	}
+	if query.After != nil && !query.After.Matches(query.Sort, query.Desc) {
+		return nil, domain.ErrInvalidCatalogCursor
+	}
+	if (query.MaxLevel > 0 && query.MinLevel > query.MaxLevel) || (query.MaxPrice > 0 && query.MinPrice > query.MaxPrice) {
+		return nil, ErrInvalidCatalogRange
+	}
+	if query.Limit <= 0 {
+		query.Limit = domain.CatalogDefaultLimit
+	}
+	query.Limit = min(query.Limit, domain.CatalogMaxLimit)
+	query.Search = strings.Join(domain.SearchTerms(query.Search), " ")
+	query.CategoryTypes = slices.Clone(query.CategoryTypes)
+	slices.Sort(query.CategoryTypes)
+	query.CategoryTypes = slices.Compact(query.CategoryTypes)
+
+	key := catalogCacheKey(query)
+	if cached, err := s.cache.Get(ctx, key); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	// One item more than the page tells whether there is a next page.
+	pageQuery := query
+	pageQuery.Limit++
+	items, err := s.equipmentItemRepo.Search(pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.CatalogPage{Items: items}
+	if len(items) > query.Limit {
+		page.Items = items[:query.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = domain.CatalogCursor{Sort: query.Sort, Desc: query.Desc, Value: query.Sort.Value(last), ID: last.ID}.Encode()
+	}
+
+	_ = s.cache.Set(ctx, key, page)
+	return page, nil
+}
+
+// catalogCacheKey hashes the normalized query, equal queries share a page.
+func catalogCacheKey(query domain.CatalogQuery) string {
+	data, _ := json.Marshal(query)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestCatalogService_Search(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	equipmentItemRepo := repository.NewEquipmentItemRepository(testDB)
+	service := NewCatalogService(equipmentItemRepo, nil)
+
+	_, item := setupBuyTestData(t)
+	ts := time.Now().UnixNano()
+	prefix := fmt.Sprintf("Catalog%d", ts)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, equipmentItemRepo.Create(&domain.EquipmentItem{
+			Name:                fmt.Sprintf("%s Blade %d", prefix, i),
+			Slug:                fmt.Sprintf("catalog-blade-%d-%d", ts, i),
+			Attack:              uint(i * 10),
+			Price:               uint(i * 100),
+			RequiredLevel:       1,
+			EquipmentCategoryID: item.EquipmentCategoryID,
+		}))
+	}
+
+	t.Run("pages by cursor", func(t *testing.T) {
+		query := domain.CatalogQuery{Search: prefix + " bla", Sort: domain.CatalogSortPrice, Desc: true, Limit: 2}
+		page, err := service.Search(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, uint(300), page.Items[0].Price)
+		assert.Equal(t, uint(200), page.Items[1].Price)
+		require.NotEmpty(t, page.NextCursor)
+
+		query.After, err = domain.ParseCatalogCursor(page.NextCursor)
+		require.NoError(t, err)
+		page, err = service.Search(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, uint(100), page.Items[0].Price)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		page, err := service.Search(ctx, domain.CatalogQuery{
+			Search:        prefix,
+			CategoryTypes: []string{"weapon"},
+			MinAttack:     20,
+			MaxPrice:      200,
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, uint(20), page.Items[0].Attack)
+		assert.Equal(t, "weapon", page.Items[0].EquipmentType)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, err := service.Search(ctx, domain.CatalogQuery{Sort: "created_at"})
+		assert.ErrorIs(t, err, ErrInvalidCatalogSort)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := service.Search(ctx, domain.CatalogQuery{MinLevel: 5, MaxLevel: 2})
+		assert.ErrorIs(t, err, ErrInvalidCatalogRange)
+	})
+}
+
+func TestCatalogService_SearchCache(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	equipmentItemRepo := repository.NewEquipmentItemRepository(testDB)
+	service := NewCatalogService(equipmentItemRepo, goredis.NewClient(&goredis.Options{Addr: s.Addr()}))
+
+	_, item := setupBuyTestData(t)
+	ts := time.Now().UnixNano()
+	prefix := fmt.Sprintf("Cached%d", ts)
+	newItem := func(i int) {
+		require.NoError(t, equipmentItemRepo.Create(&domain.EquipmentItem{
+			Name:                fmt.Sprintf("%s Blade %d", prefix, i),
+			Slug:                fmt.Sprintf("cached-blade-%d-%d", ts, i),
+			RequiredLevel:       1,
+			EquipmentCategoryID: item.EquipmentCategoryID,
+		}))
+	}
+	newItem(1)
+
+	query := domain.CatalogQuery{Search: prefix}
+	page, err := service.Search(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Len(t, s.Keys(), 1)
+
+	// The second item is only in the database, the identical query is
+	// answered from the cache.
+	newItem(2)
+	page, err = service.Search(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	s.FlushAll()
+	page, err = service.Search(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrConsumableNotFound  = errors.New("consumable not found")
+	ErrConsumableNotOwned  = errors.New("consumable not owned")
+	ErrHpAlreadyFull       = errors.New("hp already full")
+	ErrInvalidQuantity     = errors.New("invalid quantity")
+	ErrConsumableFightOnly = errors.New("consumable can only be used in a fight")
+	ErrConsumableNotUsable = errors.New("consumable can't be used")
+)
+
+type ConsumableService struct {
+	uow            *repository.UnitOfWork
+	consumableRepo *repository.ConsumableRepository
+	userRepo       *repository.UserRepository
+}
+
+func NewConsumableService(
+	db *sqlx.DB,
+	consumableRepo *repository.ConsumableRepository,
+	userRepo *repository.UserRepository,
+) *ConsumableService {
+	return &ConsumableService{
+		uow:            repository.NewUnitOfWork(db),
+		consumableRepo: consumableRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *ConsumableService) GetConsumables(ctx context.Context) ([]*domain.Consumable, error) {
+	return s.consumableRepo.FindAll()
+}
+
+func (s *ConsumableService) GetUserConsumables(ctx context.Context, userID uuid.UUID) ([]*domain.ConsumableStack, error) {
+	return s.consumableRepo.FindByUserID(userID)
+}
+
+func (s *ConsumableService) BuyConsumable(ctx context.Context, userID uuid.UUID, slug string, quantity uint) error {
+	if quantity == 0 {
+		return ErrInvalidQuantity
+	}
+
+	consumable, err := s.consumableRepo.FindBySlug(slug)
+	if err != nil {
+		return ErrConsumableNotFound
+	}
+
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		return purchase(repos, shopPurchase{
+			userID:      userID,
+			slug:        consumable.Slug,
+			quantity:    quantity,
+			reason:      domain.GoldReasonConsumableBuy,
+			referenceID: consumable.ID,
+			price: func(user *domain.User) (uint, error) {
+				return takeConsumablesFromShopStock(repos, user.LocationID, consumable, quantity)
+			},
+			deliver: func(*domain.User) error {
+				return repos.Consumables.AddToInventory(userID, consumable.ID, quantity)
+			},
+		})
+	})
+}
+
+// takeConsumablesFromShopStock sells quantity copies from the stock of the
+// shop the user is in and returns their price, the way takeFromShopStock does
+// for equipment.
+func takeConsumablesFromShopStock(repos *repository.Repositories, locationID uuid.UUID, consumable *domain.Consumable, quantity uint) (uint, error) {
+	stock, err := repos.ShopStock.FindConsumableForUpdate(locationID, consumable.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrShopStockNotFound) {
+			return 0, err
+		}
+
+		stocked, err := repos.ShopStock.IsConsumableStocked(consumable.ID)
+		if err != nil {
+			return 0, err
+		}
+		if stocked {
+			return 0, ErrItemNotSoldHere
+		}
+		return consumable.Price * quantity, nil
+	}
+
+	if err := repos.ShopStock.Take(stock.ID, quantity); err != nil {
+		if errors.Is(err, repository.ErrShopStockNotFound) {
+			return 0, ErrOutOfStock
+		}
+		return 0, err
+	}
+
+	return stock.Price * quantity, nil
+}
+
+// UseConsumable applies a consumable outside of a fight. Fights go through
+// FightService.UseConsumable so the bot gets its turn.
+func (s *ConsumableService) UseConsumable(ctx context.Context, userID uuid.UUID, slug string) (*domain.User, error) {
+	consumable, err := s.consumableRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, ErrConsumableNotFound
+	}
+
+	var user *domain.User
+	err = s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		// The row lock keeps concurrent potions and the hp regen from
+		// overwriting each other's current hp.
+		var err error
+		user, err = repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		if consumable.EffectType == domain.ConsumableEffectMaterial {
+			return ErrConsumableNotUsable
+		}
+		if consumable.EffectType != domain.ConsumableEffectHeal {
+			return ErrConsumableFightOnly
+		}
+		if user.Level < consumable.RequiredLevel {
+			return ErrInsufficientLevel
+		}
+		if user.CurrentHp >= int(user.Hp) {
+			return ErrHpAlreadyFull
+		}
+
+		if err := repos.Consumables.TakeFromInventory(userID, consumable.ID); err != nil {
+			if errors.Is(err, repository.ErrConsumableNotOwned) {
+				return ErrConsumableNotOwned
+			}
+			return err
+		}
+
+		user.CurrentHp = consumable.Heal(user.CurrentHp, user.Hp)
+		return repos.Users.UpdateCurrentHp(userID, user.CurrentHp)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
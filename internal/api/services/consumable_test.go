@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func createTestPotion(t *testing.T) *domain.Consumable {
+	t.Helper()
+	ts := time.Now().UnixNano()
+
+	potion := &domain.Consumable{
+		Name:          fmt.Sprintf("Potion %d", ts),
+		Slug:          fmt.Sprintf("potion-%d", ts),
+		Price:         10,
+		RequiredLevel: 1,
+		EffectType:    domain.ConsumableEffectHeal,
+		EffectValue:   30,
+	}
+	require.NoError(t, repository.NewConsumableRepository(testDB).Create(potion))
+	return potion
+}
+
+func newTestConsumableService() *ConsumableService {
+	return NewConsumableService(testDB, repository.NewConsumableRepository(testDB), repository.NewUserRepository(testDB))
+}
+
+func TestConsumableService_BuyConsumable(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestConsumableService()
+
+	t.Run("success stacks purchases", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+
+		require.NoError(t, service.BuyConsumable(ctx, user.ID, potion.Slug, 3))
+		require.NoError(t, service.BuyConsumable(ctx, user.ID, potion.Slug, 2))
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(450), userAfter.Gold)
+
+		stacks, err := service.GetUserConsumables(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, stacks, 1)
+		assert.Equal(t, uint(5), stacks[0].Quantity)
+	})
+
+	t.Run("buys from the shop stock at the shop price", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+		stockRepo := repository.NewShopStockRepository(testDB)
+		require.NoError(t, stockRepo.Create(&domain.ShopStock{
+			LocationID: user.LocationID, ConsumableID: &potion.ID, Quantity: 3, MaxQuantity: 3, RestockAmount: 1, Price: 20,
+		}))
+
+		require.NoError(t, service.BuyConsumable(ctx, user.ID, potion.Slug, 2))
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold-40, userAfter.Gold)
+
+		err = service.BuyConsumable(ctx, user.ID, potion.Slug, 2)
+		assert.ErrorIs(t, err, ErrOutOfStock)
+
+		other, _ := setupBuyTestData(t)
+		err = service.BuyConsumable(ctx, other.ID, potion.Slug, 1)
+		assert.ErrorIs(t, err, ErrItemNotSoldHere)
+	})
+
+	t.Run("insufficient gold", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+
+		err := service.BuyConsumable(ctx, user.ID, potion.Slug, 51)
+		assert.ErrorIs(t, err, ErrInsufficientGold)
+	})
+
+	t.Run("zero quantity", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+
+		err := service.BuyConsumable(ctx, user.ID, potion.Slug, 0)
+		assert.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+
+		err := service.BuyConsumable(ctx, user.ID, "nonexistent-potion", 1)
+		assert.ErrorIs(t, err, ErrConsumableNotFound)
+	})
+}
+
+func TestConsumableService_UseConsumable(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestConsumableService()
+
+	t.Run("heals up to max hp and spends the potion", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+		_, err := testDB.Exec(`UPDATE users SET current_hp = 90 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+		require.NoError(t, repository.NewConsumableRepository(testDB).AddToInventory(user.ID, potion.ID, 1))
+
+		healed, err := service.UseConsumable(ctx, user.ID, potion.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, 100, healed.CurrentHp)
+
+		stacks, err := service.GetUserConsumables(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stacks)
+	})
+
+	t.Run("concurrent potions both heal", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+		_, err := testDB.Exec(`UPDATE users SET current_hp = 10 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+		require.NoError(t, repository.NewConsumableRepository(testDB).AddToInventory(user.ID, potion.ID, 2))
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = service.UseConsumable(ctx, user.ID, potion.Slug)
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 70, userAfter.CurrentHp)
+	})
+
+	t.Run("full hp", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+		require.NoError(t, repository.NewConsumableRepository(testDB).AddToInventory(user.ID, potion.ID, 1))
+
+		_, err := service.UseConsumable(ctx, user.ID, potion.Slug)
+		assert.ErrorIs(t, err, ErrHpAlreadyFull)
+	})
+
+	t.Run("not owned", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		potion := createTestPotion(t)
+		_, err := testDB.Exec(`UPDATE users SET current_hp = 10 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+
+		_, err = service.UseConsumable(ctx, user.ID, potion.Slug)
+		assert.ErrorIs(t, err, ErrConsumableNotOwned)
+	})
+}
+
+func TestFightService_UseConsumable(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+
+	db := testDB
+	service := NewFightService(
+		db,
+		repository.NewFightRepository(db),
+		repository.NewBotRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewRoundRepository(db),
+	)
+
+	_, user, _, _, err := setupFightTestData(db)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE rounds SET player_hp = 50 WHERE fight_id IN (SELECT id FROM fights WHERE user_id = $1)`, user.ID)
+	require.NoError(t, err)
+
+	potion := createTestPotion(t)
+
+	_, err = service.UseConsumable(ctx, user.ID, potion.Slug, "HEAD")
+	assert.ErrorIs(t, err, ErrConsumableNotOwned)
+
+	require.NoError(t, repository.NewConsumableRepository(db).AddToInventory(user.ID, potion.ID, 1))
+
+	result, err := service.UseConsumable(ctx, user.ID, potion.Slug, "HEAD")
+	require.NoError(t, err)
+
+	var healed *domain.Round
+	for _, round := range result.Fight.Rounds {
+		if round.PlayerConsumableID != nil {
+			healed = round
+		}
+	}
+	require.NotNil(t, healed)
+	assert.Equal(t, potion.ID, *healed.PlayerConsumableID)
+	assert.Equal(t, uint(30), healed.PlayerHeal)
+	assert.Equal(t, uint(0), healed.PlayerDamage)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// BlacksmithLocationSlug is where items get enhanced.
+const BlacksmithLocationSlug = "blacksmith"
+
+var (
+	ErrNotAtBlacksmith       = errors.New("items are only enhanced at the blacksmith")
+	ErrMaxEnhancementLevel   = errors.New("item is already at max enhancement level")
+	ErrInsufficientMaterials = errors.New("insufficient materials")
+)
+
+type EnhancementService struct {
+	uow            *repository.UnitOfWork
+	locationRepo   *repository.LocationRepository
+	consumableRepo *repository.ConsumableRepository
+	intn           func(n int) int
+}
+
+func NewEnhancementService(db *sqlx.DB, locationRepo *repository.LocationRepository, consumableRepo *repository.ConsumableRepository) *EnhancementService {
+	return &EnhancementService{
+		uow:            repository.NewUnitOfWork(db),
+		locationRepo:   locationRepo,
+		consumableRepo: consumableRepo,
+		intn:           rand.Intn,
+	}
+}
+
+// EnhanceItem tries to raise the enhancement level of the user's instance by
+// one. Gold and materials are spent whether the attempt succeeds or not, the
+// returned bool reports the outcome.
+func (s *EnhancementService) EnhanceItem(ctx context.Context, userID, instanceID uuid.UUID) (*domain.InventoryItem, bool, error) {
+	blacksmith, err := s.locationRepo.FindBySlug(BlacksmithLocationSlug)
+	if err != nil {
+		return nil, false, ErrNotAtBlacksmith
+	}
+
+	var result *domain.InventoryItem
+	var success bool
+	err = s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		if user.LocationID != blacksmith.ID {
+			return ErrNotAtBlacksmith
+		}
+
+		instance, err := repos.Inventory.FindByIDForUpdate(userID, instanceID)
+		if err != nil {
+			if errors.Is(err, repository.ErrInventoryNotFound) {
+				return ErrInstanceNotFound
+			}
+			return err
+		}
+
+		step, ok := domain.NextEnhancementStep(instance.EnhancementLevel)
+		if !ok {
+			return ErrMaxEnhancementLevel
+		}
+
+		// Only one copy of a stack is enhanced, it gets a bag slot of its own.
+		if instance.Quantity > 1 {
+			if err := ensureBagSpace(repos, user); err != nil {
+				return err
+			}
+			if instance, err = repos.Inventory.SplitOne(instance); err != nil {
+				return err
+			}
+		}
+
+		item, err := repos.EquipmentItems.FindByID(instance.EquipmentItemID)
+		if err != nil {
+			return ErrEquipmentItemNotFound
+		}
+
+		_, err = repos.GoldTransactions.Apply(userID, -int64(step.GoldCost(item.Price)), domain.GoldReasonEquipmentEnhance, &instance.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrInsufficientGold) {
+				return ErrInsufficientGold
+			}
+			return err
+		}
+
+		if step.MaterialQuantity > 0 {
+			material, err := s.consumableRepo.FindBySlug(step.MaterialSlug)
+			if err != nil {
+				return ErrInsufficientMaterials
+			}
+			if err := repos.Consumables.TakeManyFromInventory(userID, material.ID, step.MaterialQuantity); err != nil {
+				if errors.Is(err, repository.ErrConsumableNotOwned) {
+					return ErrInsufficientMaterials
+				}
+				return err
+			}
+		}
+
+		result = &domain.InventoryItem{EquipmentItem: item, Instance: instance}
+		// A new roll on every retry of the transaction is fine, nothing was
+		// committed for the previous one.
+		success = step.Roll(s.intn)
+		if !success {
+			return nil
+		}
+
+		if err := repos.Inventory.UpdateEnhancementLevel(instance.ID, step.Level); err != nil {
+			return err
+		}
+		instance.EnhancementLevel = step.Level
+
+		if instance.EquippedSlot != nil && !instance.Broken() {
+			if _, err := NewStatsCalculator(repos).Recalculate(userID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, success, nil
+}
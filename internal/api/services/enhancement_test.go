@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func blacksmith(t *testing.T) *domain.Location {
+	t.Helper()
+	locationRepo := repository.NewLocationRepository(testDB)
+	if location, err := locationRepo.FindBySlug(BlacksmithLocationSlug); err == nil {
+		return location
+	}
+
+	location := &domain.Location{Name: "Blacksmith", Slug: BlacksmithLocationSlug}
+	require.NoError(t, locationRepo.Create(location))
+	return location
+}
+
+func TestEnhancementService_EnhanceItem(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewEnhancementService(testDB, repository.NewLocationRepository(testDB), repository.NewConsumableRepository(testDB))
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+	userRepo := repository.NewUserRepository(testDB)
+
+	setup := func(t *testing.T, level uint) (*domain.User, *domain.EquipmentItem, *domain.Inventory) {
+		user, item := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE equipment_items SET attack = 10 WHERE id = $1`, item.ID)
+		require.NoError(t, err)
+		item.Attack = 10
+
+		instance := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+		require.NoError(t, inventoryRepo.UpdateEnhancementLevel(instance.ID, level))
+		require.NoError(t, userRepo.UpdateLocationID(user.ID, blacksmith(t).ID))
+		return user, item, instance
+	}
+
+	t.Run("success updates equipped stats", func(t *testing.T) {
+		user, item, instance := setup(t, 0)
+		slot := "weapon"
+		require.NoError(t, inventoryRepo.SetEquippedSlot(instance.ID, &slot))
+		service.intn = func(n int) int { return 0 }
+
+		enhanced, success, err := service.EnhanceItem(ctx, user.ID, instance.ID)
+		require.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, uint(1), enhanced.Instance.EnhancementLevel)
+
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold-item.Price/10, userAfter.Gold)
+		assert.Equal(t, user.Attack+11, userAfter.Attack)
+	})
+
+	t.Run("failure still costs gold", func(t *testing.T) {
+		user, _, instance := setup(t, 3)
+		service.intn = func(n int) int { return n - 1 }
+
+		enhanced, success, err := service.EnhanceItem(ctx, user.ID, instance.ID)
+		require.NoError(t, err)
+		assert.False(t, success)
+		assert.Equal(t, uint(3), enhanced.Instance.EnhancementLevel)
+
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold-30, userAfter.Gold)
+	})
+
+	t.Run("needs materials", func(t *testing.T) {
+		user, _, instance := setup(t, 5)
+
+		_, _, err := service.EnhanceItem(ctx, user.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrInsufficientMaterials)
+	})
+
+	t.Run("max level", func(t *testing.T) {
+		user, _, instance := setup(t, domain.MaxEnhancementLevel)
+
+		_, _, err := service.EnhanceItem(ctx, user.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrMaxEnhancementLevel)
+	})
+
+	t.Run("away from the blacksmith", func(t *testing.T) {
+		user, _, instance := setup(t, 0)
+		require.NoError(t, userRepo.UpdateLocationID(user.ID, weaponShop(t).ID))
+
+		_, _, err := service.EnhanceItem(ctx, user.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrNotAtBlacksmith)
+	})
+
+	t.Run("item offered in a trade", func(t *testing.T) {
+		user, _, instance := setup(t, 0)
+		partner, _ := setupBuyTestData(t)
+		require.NoError(t, userRepo.UpdateLocationID(partner.ID, user.LocationID))
+
+		tradeService := NewTradeService(testDB, ws.GetHub())
+		trade, err := tradeService.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+		_, err = tradeService.UpdateOffer(ctx, user.ID, trade.ID, []uuid.UUID{instance.ID}, 0)
+		require.NoError(t, err)
+
+		_, _, err = service.EnhanceItem(ctx, user.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrInstanceNotFound)
+	})
+}
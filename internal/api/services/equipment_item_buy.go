@@ -3,21 +3,25 @@ package services
 import (
 	"context"
 	"errors"
+	"math/rand"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
 	"moonshine/internal/domain"
+	"moonshine/internal/events"
 	"moonshine/internal/repository"
 )
 
 var (
 	ErrInsufficientGold      = errors.New("insufficient gold")
 	ErrEquipmentItemNotFound = errors.New("equipment item not found")
+	ErrOutOfStock            = errors.New("item is out of stock")
+	ErrItemNotSoldHere       = errors.New("item is not sold here")
 )
 
 type EquipmentItemBuyService struct {
-	db                *sqlx.DB
+	uow               *repository.UnitOfWork
 	equipmentItemRepo *repository.EquipmentItemRepository
 	inventoryRepo     *repository.InventoryRepository
 	userRepo          *repository.UserRepository
@@ -30,7 +34,7 @@ func NewEquipmentItemBuyService(
 	userRepo *repository.UserRepository,
 ) *EquipmentItemBuyService {
 	return &EquipmentItemBuyService{
-		db:                db,
+		uow:               repository.NewUnitOfWork(db),
 		equipmentItemRepo: equipmentItemRepo,
 		inventoryRepo:     inventoryRepo,
 		userRepo:          userRepo,
@@ -38,46 +42,81 @@ func NewEquipmentItemBuyService(
 }
 
 func (s *EquipmentItemBuyService) BuyEquipmentItem(ctx context.Context, userID uuid.UUID, itemSlug string) error {
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
 	item, err := s.equipmentItemRepo.FindBySlug(itemSlug)
 	if err != nil {
 		return ErrEquipmentItemNotFound
 	}
 
-	user, err := s.userRepo.FindByID(userID)
+	err = s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		return purchase(repos, shopPurchase{
+			userID:      userID,
+			slug:        item.Slug,
+			quantity:    1,
+			reason:      domain.GoldReasonEquipmentBuy,
+			referenceID: item.ID,
+			price: func(user *domain.User) (uint, error) {
+				return takeFromShopStock(repos, user.LocationID, item)
+			},
+			deliver: func(user *domain.User) error {
+				return addToBag(repos, user, item)
+			},
+		})
+	})
 	if err != nil {
-		return repository.ErrUserNotFound
+		return err
 	}
 
-	if user.Gold < item.Price {
-		return ErrInsufficientGold
-	}
+	events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventItemAcquired, UserID: userID, Slug: item.Slug})
+	return nil
+}
 
-	inventory := &domain.Inventory{
-		UserID:          userID,
-		EquipmentItemID: item.ID,
+// takeFromShopStock sells one copy from the stock of the shop the user is in
+// and returns its price. Items no shop keeps stock of are sold anywhere at
+// their base price.
+func takeFromShopStock(repos *repository.Repositories, locationID uuid.UUID, item *domain.EquipmentItem) (uint, error) {
+	stock, err := repos.ShopStock.FindForUpdate(locationID, item.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrShopStockNotFound) {
+			return 0, err
+		}
+
+		stocked, err := repos.ShopStock.IsStocked(item.ID)
+		if err != nil {
+			return 0, err
+		}
+		if stocked {
+			return 0, ErrItemNotSoldHere
+		}
+		return item.Price, nil
 	}
 
-	inventoryRepo := repository.NewInventoryRepository(tx)
-	if err := inventoryRepo.Create(inventory); err != nil {
-		return err
+	if err := repos.ShopStock.TakeOne(stock.ID); err != nil {
+		if errors.Is(err, repository.ErrShopStockNotFound) {
+			return 0, ErrOutOfStock
+		}
+		return 0, err
 	}
 
-	newGold := user.Gold - item.Price
-	updateQuery := `UPDATE users SET gold = $1 WHERE id = $2 AND deleted_at IS NULL`
-	_, err = tx.Exec(updateQuery, newGold, userID)
-	if err != nil {
-		return err
+	return stock.Price, nil
+}
+
+// addToBag gives the user a new copy of the item. Stackable items go onto a
+// stack with room and don't roll bonus stats, so their copies stay alike.
+func addToBag(repos *repository.Repositories, user *domain.User, item *domain.EquipmentItem) error {
+	if item.Stackable() {
+		err := repos.Inventory.AddToStack(user.ID, item.ID)
+		if !errors.Is(err, repository.ErrInventoryNotFound) {
+			return err
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := ensureBagSpace(repos, user); err != nil {
 		return err
 	}
 
-	return nil
+	instance := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+	if !item.Stackable() {
+		instance.BonusAttack, instance.BonusDefense, instance.BonusHp = domain.RollInstanceBonus(item, rand.Intn)
+	}
+	return repos.Inventory.Create(instance)
 }
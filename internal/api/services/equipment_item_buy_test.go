@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -128,4 +129,92 @@ func TestEquipmentItemBuyService_BuyEquipmentItem(t *testing.T) {
 		err := service.BuyEquipmentItem(ctx, uuid.New(), item.Slug)
 		assert.ErrorIs(t, err, repository.ErrUserNotFound)
 	})
+
+	t.Run("concurrent buys charge once", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE users SET gold = $1 WHERE id = $2`, item.Price, user.ID)
+		require.NoError(t, err)
+
+		service := NewEquipmentItemBuyService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		const buyers = 10
+		errs := make([]error, buyers)
+		var wg sync.WaitGroup
+		wg.Add(buyers)
+		for i := 0; i < buyers; i++ {
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = service.BuyEquipmentItem(ctx, user.ID, item.Slug)
+			}(i)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrInsufficientGold)
+		}
+		assert.Equal(t, 1, succeeded)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(0), userAfter.Gold)
+
+		var count int
+		err = testDB.Get(&count, `SELECT COUNT(*) FROM inventory WHERE user_id = $1 AND equipment_item_id = $2`, user.ID, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("full bag", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE users SET inventory_capacity = 1 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+		_, err = testDB.Exec(`INSERT INTO inventory (id, user_id, equipment_item_id) VALUES ($1, $2, $3)`, uuid.New(), user.ID, item.ID)
+		require.NoError(t, err)
+
+		service := NewEquipmentItemBuyService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		err = service.BuyEquipmentItem(ctx, user.ID, item.Slug)
+		assert.ErrorIs(t, err, ErrInventoryFull)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold, userAfter.Gold)
+	})
+
+	t.Run("stackable items share a slot", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE equipment_items SET max_stack = 2 WHERE id = $1`, item.ID)
+		require.NoError(t, err)
+
+		service := NewEquipmentItemBuyService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, service.BuyEquipmentItem(ctx, user.ID, item.Slug))
+		}
+
+		var quantities []int
+		err = testDB.Select(&quantities, `SELECT quantity FROM inventory WHERE user_id = $1 ORDER BY quantity DESC`, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 1}, quantities)
+	})
 }
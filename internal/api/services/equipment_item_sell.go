@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"moonshine/internal/domain"
 	"moonshine/internal/repository"
 )
 
@@ -15,7 +16,7 @@ var (
 )
 
 type EquipmentItemSellService struct {
-	db                *sqlx.DB
+	uow               *repository.UnitOfWork
 	equipmentItemRepo *repository.EquipmentItemRepository
 	inventoryRepo     *repository.InventoryRepository
 	userRepo          *repository.UserRepository
@@ -28,57 +29,73 @@ func NewEquipmentItemSellService(
 	userRepo *repository.UserRepository,
 ) *EquipmentItemSellService {
 	return &EquipmentItemSellService{
-		db:                db,
+		uow:               repository.NewUnitOfWork(db),
 		equipmentItemRepo: equipmentItemRepo,
 		inventoryRepo:     inventoryRepo,
 		userRepo:          userRepo,
 	}
 }
 
-func (s *EquipmentItemSellService) SellEquipmentItem(ctx context.Context, userID uuid.UUID, itemSlug string) error {
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
+// SellEquipmentItem sells quantity plain copies from the user's bag for the
+// sell-back share of the base price each. Enhanced, bonus-rolled and worn
+// copies are only sold by SellInstance.
+func (s *EquipmentItemSellService) SellEquipmentItem(ctx context.Context, userID uuid.UUID, itemSlug string, quantity uint) error {
+	if quantity == 0 {
+		return ErrInvalidQuantity
 	}
-	defer tx.Rollback()
 
 	item, err := s.equipmentItemRepo.FindBySlug(itemSlug)
 	if err != nil {
 		return ErrEquipmentItemNotFound
 	}
 
-	var count int
-	checkOwnershipQuery := `SELECT COUNT(*) FROM inventory WHERE user_id = $1 AND equipment_item_id = $2 AND deleted_at IS NULL`
-	err = tx.Get(&count, checkOwnershipQuery, userID, item.ID)
-	if err != nil {
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Users.FindByIDForUpdate(userID); err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		// Two concurrent sells of the last copies race for the same rows and
+		// the loser gets ErrItemNotOwned.
+		if err := repos.Inventory.RemoveQuantity(userID, item.ID, quantity); err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrItemNotOwned
+			}
+			return err
+		}
+
+		price := domain.CurrentShopSettings().SellPrice(item.Price) * quantity
+		_, err := repos.GoldTransactions.Apply(userID, int64(price), domain.GoldReasonEquipmentSell, &item.ID)
 		return err
-	}
+	})
+}
 
-	if count == 0 {
-		return ErrItemNotOwned
-	}
+// SellInstance sells one copy of the instance from the user's bag for the
+// sell-back share of the base price.
+func (s *EquipmentItemSellService) SellInstance(ctx context.Context, userID, instanceID uuid.UUID) error {
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Users.FindByIDForUpdate(userID); err != nil {
+			return repository.ErrUserNotFound
+		}
 
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return repository.ErrUserNotFound
-	}
+		instance, err := repos.Inventory.FindFreeByIDForUpdate(userID, instanceID)
+		if err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrItemNotOwned
+			}
+			return err
+		}
 
-	newGold := user.Gold + item.Price
-	updateGoldQuery := `UPDATE users SET gold = $1 WHERE id = $2`
-	_, err = tx.Exec(updateGoldQuery, newGold, userID)
-	if err != nil {
-		return err
-	}
+		item, err := repos.EquipmentItems.FindByID(instance.EquipmentItemID)
+		if err != nil {
+			return ErrEquipmentItemNotFound
+		}
 
-	deleteItemQuery := `DELETE FROM inventory WHERE user_id = $1 AND equipment_item_id = $2 AND deleted_at IS NULL`
-	_, err = tx.Exec(deleteItemQuery, userID, item.ID)
-	if err != nil {
-		return err
-	}
+		if err := repos.Inventory.RemoveInstance(instance); err != nil {
+			return err
+		}
 
-	if err := tx.Commit(); err != nil {
+		price := domain.CurrentShopSettings().SellPrice(item.Price)
+		_, err = repos.GoldTransactions.Apply(userID, int64(price), domain.GoldReasonEquipmentSell, &item.ID)
 		return err
-	}
-
-	return nil
+	})
 }
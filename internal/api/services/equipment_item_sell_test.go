@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"moonshine/internal/domain"
 	"moonshine/internal/repository"
 	"moonshine/internal/testutil"
 )
@@ -30,12 +32,12 @@ func TestEquipmentItemSellService_SellEquipmentItem(t *testing.T) {
 			repository.NewUserRepository(testDB),
 		)
 
-		err = service.SellEquipmentItem(ctx, user.ID, item.Slug)
+		err = service.SellEquipmentItem(ctx, user.ID, item.Slug, 1)
 		require.NoError(t, err)
 
 		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
 		require.NoError(t, err)
-		assert.Equal(t, initialGold+item.Price, userAfter.Gold)
+		assert.Equal(t, initialGold+domain.CurrentShopSettings().SellPrice(item.Price), userAfter.Gold)
 
 		var count int
 		err = testDB.Get(&count, `SELECT COUNT(*) FROM inventory WHERE user_id = $1 AND equipment_item_id = $2`, user.ID, item.ID)
@@ -52,7 +54,7 @@ func TestEquipmentItemSellService_SellEquipmentItem(t *testing.T) {
 			repository.NewUserRepository(testDB),
 		)
 
-		err := service.SellEquipmentItem(ctx, user.ID, item.Slug)
+		err := service.SellEquipmentItem(ctx, user.ID, item.Slug, 1)
 		assert.ErrorIs(t, err, ErrItemNotOwned)
 	})
 
@@ -65,7 +67,147 @@ func TestEquipmentItemSellService_SellEquipmentItem(t *testing.T) {
 			repository.NewUserRepository(testDB),
 		)
 
-		err := service.SellEquipmentItem(ctx, user.ID, "nonexistent-slug")
+		err := service.SellEquipmentItem(ctx, user.ID, "nonexistent-slug", 1)
 		assert.ErrorIs(t, err, ErrEquipmentItemNotFound)
 	})
+
+	t.Run("concurrent sells credit once", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		initialGold := user.Gold
+
+		_, err := testDB.Exec(`INSERT INTO inventory (id, user_id, equipment_item_id) VALUES ($1, $2, $3)`, uuid.New(), user.ID, item.ID)
+		require.NoError(t, err)
+
+		service := NewEquipmentItemSellService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		const sellers = 10
+		errs := make([]error, sellers)
+		var wg sync.WaitGroup
+		wg.Add(sellers)
+		for i := 0; i < sellers; i++ {
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = service.SellEquipmentItem(ctx, user.ID, item.Slug, 1)
+			}(i)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrItemNotOwned)
+		}
+		assert.Equal(t, 1, succeeded)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, initialGold+domain.CurrentShopSettings().SellPrice(item.Price), userAfter.Gold)
+	})
+
+	t.Run("sells one copy at a time", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		for i := 0; i < 2; i++ {
+			_, err := testDB.Exec(`INSERT INTO inventory (id, user_id, equipment_item_id) VALUES ($1, $2, $3)`, uuid.New(), user.ID, item.ID)
+			require.NoError(t, err)
+		}
+
+		service := NewEquipmentItemSellService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		require.NoError(t, service.SellEquipmentItem(ctx, user.ID, item.Slug, 1))
+
+		var count int
+		err := testDB.Get(&count, `SELECT COUNT(*) FROM inventory WHERE user_id = $1 AND equipment_item_id = $2`, user.ID, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("sells the given quantity from stacks", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		initialGold := user.Gold
+		_, err := testDB.Exec(`UPDATE equipment_items SET max_stack = 5 WHERE id = $1`, item.ID)
+		require.NoError(t, err)
+		stackID := uuid.New()
+		_, err = testDB.Exec(`INSERT INTO inventory (id, user_id, equipment_item_id, quantity) VALUES ($1, $2, $3, 4)`, stackID, user.ID, item.ID)
+		require.NoError(t, err)
+
+		service := NewEquipmentItemSellService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		require.NoError(t, service.SellEquipmentItem(ctx, user.ID, item.Slug, 3))
+
+		var quantity int
+		require.NoError(t, testDB.Get(&quantity, `SELECT quantity FROM inventory WHERE id = $1`, stackID))
+		assert.Equal(t, 1, quantity)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, initialGold+3*domain.CurrentShopSettings().SellPrice(item.Price), userAfter.Gold)
+
+		err = service.SellEquipmentItem(ctx, user.ID, item.Slug, 2)
+		assert.ErrorIs(t, err, ErrItemNotOwned)
+	})
+
+	t.Run("keeps enhanced copies", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		initialGold := user.Gold
+		enhancedID, plainID := uuid.New(), uuid.New()
+		_, err := testDB.Exec(`INSERT INTO inventory (id, user_id, equipment_item_id, enhancement_level, created_at) VALUES ($1, $2, $3, 10, NOW() - INTERVAL '1 day')`, enhancedID, user.ID, item.ID)
+		require.NoError(t, err)
+		_, err = testDB.Exec(`INSERT INTO inventory (id, user_id, equipment_item_id) VALUES ($1, $2, $3)`, plainID, user.ID, item.ID)
+		require.NoError(t, err)
+
+		service := NewEquipmentItemSellService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		require.NoError(t, service.SellEquipmentItem(ctx, user.ID, item.Slug, 1))
+		var ids []uuid.UUID
+		require.NoError(t, testDB.Select(&ids, `SELECT id FROM inventory WHERE user_id = $1`, user.ID))
+		assert.Equal(t, []uuid.UUID{enhancedID}, ids)
+
+		err = service.SellEquipmentItem(ctx, user.ID, item.Slug, 1)
+		assert.ErrorIs(t, err, ErrItemNotOwned)
+
+		require.NoError(t, service.SellInstance(ctx, user.ID, enhancedID))
+		var count int
+		require.NoError(t, testDB.Get(&count, `SELECT COUNT(*) FROM inventory WHERE user_id = $1`, user.ID))
+		assert.Equal(t, 0, count)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, initialGold+2*domain.CurrentShopSettings().SellPrice(item.Price), userAfter.Gold)
+	})
+
+	t.Run("zero quantity", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		service := NewEquipmentItemSellService(
+			testDB,
+			repository.NewEquipmentItemRepository(testDB),
+			repository.NewInventoryRepository(testDB),
+			repository.NewUserRepository(testDB),
+		)
+
+		err := service.SellEquipmentItem(ctx, user.ID, item.Slug, 0)
+		assert.ErrorIs(t, err, ErrInvalidQuantity)
+	})
 }
@@ -28,7 +28,7 @@ func setupTestDataForTakeOff(db *sqlx.DB) (*domain.User, *domain.EquipmentItem,
 		return nil, nil, uuid.Nil, fmt.Errorf("failed to create location: %w", err)
 	}
 
-	categoryQuery := `INSERT INTO equipment_categories (name, type) VALUES ($1, $2::equipment_category_type) RETURNING id, created_at`
+	categoryQuery := `INSERT INTO equipment_categories (name, type) VALUES ($1, $2) RETURNING id, created_at`
 	category := &domain.EquipmentCategory{
 		Name: "Weapon",
 		Type: "weapon",
@@ -57,20 +57,19 @@ func setupTestDataForTakeOff(db *sqlx.DB) (*domain.User, *domain.EquipmentItem,
 	ts := time.Now().UnixNano()
 	username := fmt.Sprintf("testuser%d", ts)
 	user := &domain.User{
-		Username:              username,
-		Name:                  username,
-		Email:                 fmt.Sprintf("test%d@example.com", ts),
-		Password:              "password",
-		LocationID:            location.ID,
-		Attack:                11,
-		Defense:               6,
-		Hp:                    40,
-		CurrentHp:             40,
-		Level:                 5,
-		Exp:                   0,
-		FreeStats:             15,
-		Gold:                  100,
-		WeaponEquipmentItemID: &item.ID,
+		Username:   username,
+		Name:       username,
+		Email:      fmt.Sprintf("test%d@example.com", ts),
+		Password:   "password",
+		LocationID: location.ID,
+		Attack:     11,
+		Defense:    6,
+		Hp:         40,
+		CurrentHp:  40,
+		Level:      5,
+		Exp:        0,
+		FreeStats:  15,
+		Gold:       100,
 	}
 	userRepo := repository.NewUserRepository(db)
 	err = userRepo.Create(user)
@@ -78,8 +77,7 @@ func setupTestDataForTakeOff(db *sqlx.DB) (*domain.User, *domain.EquipmentItem,
 		return nil, nil, uuid.Nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	updateQuery := `UPDATE users SET weapon_equipment_item_id = $1 WHERE id = $2`
-	_, err = db.Exec(updateQuery, item.ID, user.ID)
+	err = userRepo.UpdateEquipmentSlot(user.ID, "weapon", &item.ID)
 	if err != nil {
 		return nil, nil, uuid.Nil, fmt.Errorf("failed to equip weapon: %w", err)
 	}
@@ -107,11 +105,9 @@ func TestEquipmentItemTakeOffService_TakeOffEquipmentItem(t *testing.T) {
 		err := service.TakeOffEquipmentItem(ctx, user.ID, "weapon")
 		require.NoError(t, err)
 
-		var equippedItemID *uuid.UUID
-		query := `SELECT weapon_equipment_item_id FROM users WHERE id = $1`
-		err = db.Get(&equippedItemID, query, user.ID)
+		userAfter, err := userRepo.FindByID(user.ID)
 		require.NoError(t, err)
-		assert.Nil(t, equippedItemID)
+		assert.Nil(t, userAfter.EquippedItemID("weapon"))
 
 		type stats struct {
 			Attack  uint `db:"attack"`
@@ -169,7 +165,7 @@ func TestEquipmentItemTakeOffService_TakeOffEquipmentItem(t *testing.T) {
 		require.NoError(t, err)
 
 		weaponCatID := uuid.New()
-		categoryQuery := `INSERT INTO equipment_categories (id, name, type) VALUES ($1, $2, $3::equipment_category_type)`
+		categoryQuery := `INSERT INTO equipment_categories (id, name, type) VALUES ($1, $2, $3)`
 		_, err = db.Exec(categoryQuery, weaponCatID, "Weapon", "weapon")
 		require.NoError(t, err)
 
@@ -201,8 +197,8 @@ func TestEquipmentItemTakeOffService_TakeOffEquipmentItem(t *testing.T) {
 		}
 		err = userRepo.Create(multiUser)
 		require.NoError(t, err)
-		_, err = db.Exec(`UPDATE users SET weapon_equipment_item_id = $1, chest_equipment_item_id = $2 WHERE id = $3`, weaponID, chestID, multiUser.ID)
-		require.NoError(t, err)
+		require.NoError(t, userRepo.UpdateEquipmentSlot(multiUser.ID, "weapon", &weaponID))
+		require.NoError(t, userRepo.UpdateEquipmentSlot(multiUser.ID, "chest", &chestID))
 
 		err = service.TakeOffEquipmentItem(ctx, multiUser.ID, "weapon")
 		require.NoError(t, err)
@@ -221,11 +217,9 @@ func TestEquipmentItemTakeOffService_TakeOffEquipmentItem(t *testing.T) {
 		assert.Equal(t, uint(16), userStats.Defense)
 		assert.Equal(t, uint(50), userStats.Hp)
 
-		var chestEquippedID uuid.UUID
-		chestQuery := `SELECT chest_equipment_item_id FROM users WHERE id = $1`
-		err = db.Get(&chestEquippedID, chestQuery, multiUser.ID)
+		multiUserAfter, err := userRepo.FindByID(multiUser.ID)
 		require.NoError(t, err)
-		assert.Equal(t, chestID, chestEquippedID)
+		assert.Equal(t, &chestID, multiUserAfter.EquippedItemID("chest"))
 	})
 
 	t.Run("current hp stays unchanged when below new max hp", func(t *testing.T) {
@@ -234,7 +228,7 @@ func TestEquipmentItemTakeOffService_TakeOffEquipmentItem(t *testing.T) {
 		require.NoError(t, err)
 
 		weaponCatID := uuid.New()
-		_, err = db.Exec(`INSERT INTO equipment_categories (id, name, type) VALUES ($1, $2, $3::equipment_category_type)`, weaponCatID, "Weapon", "weapon")
+		_, err = db.Exec(`INSERT INTO equipment_categories (id, name, type) VALUES ($1, $2, $3)`, weaponCatID, "Weapon", "weapon")
 		require.NoError(t, err)
 
 		weaponID := uuid.New()
@@ -256,8 +250,7 @@ func TestEquipmentItemTakeOffService_TakeOffEquipmentItem(t *testing.T) {
 		}
 		err = userRepo.Create(testUser)
 		require.NoError(t, err)
-		_, err = db.Exec(`UPDATE users SET weapon_equipment_item_id = $1 WHERE id = $2`, weaponID, testUser.ID)
-		require.NoError(t, err)
+		require.NoError(t, userRepo.UpdateEquipmentSlot(testUser.ID, "weapon", &weaponID))
 
 		err = service.TakeOffEquipmentItem(ctx, testUser.ID, "weapon")
 		require.NoError(t, err)
@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -16,10 +15,12 @@ var (
 	ErrItemNotInInventory   = errors.New("item not in inventory")
 	ErrInsufficientLevel    = errors.New("insufficient level")
 	ErrInvalidEquipmentType = errors.New("invalid equipment type")
+	ErrInvalidSlot          = errors.New("slot doesn't take this item")
+	ErrSlotRequired         = errors.New("every slot for this item is taken, pick one")
 )
 
 type EquipmentItemTakeOnService struct {
-	db                *sqlx.DB
+	uow               *repository.UnitOfWork
 	equipmentItemRepo *repository.EquipmentItemRepository
 	inventoryRepo     *repository.InventoryRepository
 	userRepo          *repository.UserRepository
@@ -32,177 +33,145 @@ func NewEquipmentItemTakeOnService(
 	userRepo *repository.UserRepository,
 ) *EquipmentItemTakeOnService {
 	return &EquipmentItemTakeOnService{
-		db:                db,
+		uow:               repository.NewUnitOfWork(db),
 		equipmentItemRepo: equipmentItemRepo,
 		inventoryRepo:     inventoryRepo,
 		userRepo:          userRepo,
 	}
 }
 
-func getEquipmentFieldName(equipmentType string) (string, error) {
-	fieldMap := map[string]string{
-		"chest":  "chest_equipment_item_id",
-		"belt":   "belt_equipment_item_id",
-		"head":   "head_equipment_item_id",
-		"neck":   "neck_equipment_item_id",
-		"weapon": "weapon_equipment_item_id",
-		"shield": "shield_equipment_item_id",
-		"legs":   "legs_equipment_item_id",
-		"feet":   "feet_equipment_item_id",
-		"arms":   "arms_equipment_item_id",
-		"hands":  "hands_equipment_item_id",
-	}
-
-	if fieldName, ok := fieldMap[equipmentType]; ok {
-		return fieldName, nil
-	}
-
-	if equipmentType == "ring" {
-		return "ring", nil
-	}
+func (s *EquipmentItemTakeOnService) TakeOnEquipmentItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error {
+	return s.TakeOnEquipmentItemInSlot(ctx, userID, itemID, "")
+}
 
-	return "", ErrInvalidEquipmentType
+// TakeOnEquipmentItemInSlot equips the item into the named slot, an empty
+// slot name picks the first free slot of the item's type. When every slot of
+// the type is taken the only slot is replaced, types with several slots (the
+// rings) need the slot named.
+func (s *EquipmentItemTakeOnService) TakeOnEquipmentItemInSlot(ctx context.Context, userID uuid.UUID, itemID uuid.UUID, slot string) error {
+	return s.takeOn(ctx, userID, slot, func(repos *repository.Repositories) (*domain.Inventory, error) {
+		return repos.Inventory.FindUnequippedForUpdate(userID, itemID)
+	})
 }
 
-func (s *EquipmentItemTakeOnService) TakeOnEquipmentItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error {
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// TakeOnInstance equips the given instance from the bag, the way to pick one
+// copy among several of the same item. The slot works as in
+// TakeOnEquipmentItemInSlot.
+func (s *EquipmentItemTakeOnService) TakeOnInstance(ctx context.Context, userID uuid.UUID, instanceID uuid.UUID, slot string) error {
+	return s.takeOn(ctx, userID, slot, func(repos *repository.Repositories) (*domain.Inventory, error) {
+		return repos.Inventory.FindFreeByIDForUpdate(userID, instanceID)
+	})
+}
 
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return repository.ErrUserNotFound
-	}
+func (s *EquipmentItemTakeOnService) takeOn(ctx context.Context, userID uuid.UUID, slot string, find func(*repository.Repositories) (*domain.Inventory, error)) error {
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
 
-	checkQuery := `
-		SELECT COUNT(*) 
-		FROM inventory 
-		WHERE user_id = $1 AND equipment_item_id = $2 AND deleted_at IS NULL
-	`
-	var count int
-	err = tx.Get(&count, checkQuery, userID, itemID)
-	if err != nil {
-		return err
-	}
-	if count == 0 {
-		return ErrItemNotInInventory
-	}
+		// Locking the instance keeps two concurrent calls from equipping the
+		// same copy twice.
+		instance, err := find(repos)
+		if err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrItemNotInInventory
+			}
+			return err
+		}
+		itemID := instance.EquipmentItemID
 
-	item, err := s.equipmentItemRepo.FindByID(itemID)
-	if err != nil {
-		return ErrEquipmentItemNotFound
-	}
+		item, err := repos.EquipmentItems.FindByID(itemID)
+		if err != nil {
+			return ErrEquipmentItemNotFound
+		}
 
-	categoryQuery := `SELECT type FROM equipment_categories WHERE id = $1 AND deleted_at IS NULL`
-	var equipmentType string
-	err = tx.Get(&equipmentType, categoryQuery, item.EquipmentCategoryID)
-	if err != nil {
-		return err
-	}
+		equipmentType, err := repos.EquipmentItems.FindCategoryType(item.EquipmentCategoryID)
+		if err != nil {
+			return err
+		}
 
-	if user.Level < item.RequiredLevel {
-		return ErrInsufficientLevel
-	}
+		if user.Level < item.RequiredLevel {
+			return ErrInsufficientLevel
+		}
 
-	fieldName, err := getEquipmentFieldName(equipmentType)
-	if err != nil {
-		return err
-	}
+		slots, err := repos.EquipmentSlots.FindByEquipmentType(equipmentType)
+		if err != nil {
+			return err
+		}
 
-	var oldItemID *uuid.UUID
-
-	if equipmentType == "ring" {
-		switch {
-		case user.Ring1EquipmentItemID == nil:
-			fieldName = "ring1_equipment_item_id"
-		case user.Ring2EquipmentItemID == nil:
-			fieldName = "ring2_equipment_item_id"
-		case user.Ring3EquipmentItemID == nil:
-			fieldName = "ring3_equipment_item_id"
-		case user.Ring4EquipmentItemID == nil:
-			fieldName = "ring4_equipment_item_id"
-		default:
-			fieldName = "ring1_equipment_item_id"
-			oldItemID = user.Ring1EquipmentItemID
+		slot, err := pickSlot(user, slots, slot)
+		if err != nil {
+			return err
 		}
-	} else {
-		getOldItemQuery := fmt.Sprintf(`
-			SELECT %s 
-			FROM users 
-			WHERE id = $1 AND deleted_at IS NULL
-		`, fieldName)
-		err = tx.Get(&oldItemID, getOldItemQuery, userID)
+		oldItemID := user.EquippedItemID(slot)
+
+		// Equipping from a stack leaves the stack in the bag, so the item taken
+		// off needs a free slot.
+		if oldItemID != nil && instance.Quantity > 1 {
+			if err := ensureBagSpace(repos, user); err != nil {
+				return err
+			}
+		}
+		instance, err = repos.Inventory.SplitOne(instance)
 		if err != nil {
-			oldItemID = nil
+			return err
 		}
-	}
 
-	deleteFromInventoryQuery := `
-		DELETE FROM inventory 
-		WHERE id = (
-			SELECT id FROM inventory 
-			WHERE user_id = $1 AND equipment_item_id = $2 AND deleted_at IS NULL 
-			LIMIT 1
-		)
-	`
-	_, err = tx.Exec(deleteFromInventoryQuery, userID, itemID)
-	if err != nil {
-		return err
-	}
+		if oldItemID != nil {
+			if err := unequipSlot(repos, userID, slot, *oldItemID); err != nil {
+				return err
+			}
+		}
 
-	if oldItemID != nil {
-		inventory := &domain.Inventory{
-			UserID:          userID,
-			EquipmentItemID: *oldItemID,
+		if err := repos.Inventory.SetEquippedSlot(instance.ID, &slot); err != nil {
+			return err
 		}
-		inventoryRepo := repository.NewInventoryRepository(tx)
-		err = inventoryRepo.Create(inventory)
-		if err != nil {
+
+		if err := repos.Users.UpdateEquipmentSlot(userID, slot, &itemID); err != nil {
 			return err
 		}
+
+		_, err = NewStatsCalculator(repos).Recalculate(userID)
+		return err
+	})
+}
+
+// pickSlot checks that the requested slot takes the item, or picks a slot
+// when none is requested.
+func pickSlot(user *domain.User, slots []*domain.EquipmentSlot, requested string) (string, error) {
+	if len(slots) == 0 {
+		return "", ErrInvalidEquipmentType
 	}
 
-	var oldItem *domain.EquipmentItem
-	if oldItemID != nil {
-		oldItem, err = s.equipmentItemRepo.FindByID(*oldItemID)
-		if err != nil {
-			return err
+	if requested != "" {
+		for _, slot := range slots {
+			if slot.Name == requested {
+				return requested, nil
+			}
 		}
+		return "", ErrInvalidSlot
 	}
 
-	var updateStatsQuery string
-	if oldItem != nil {
-		updateStatsQuery = fmt.Sprintf(`
-			UPDATE users 
-			SET %s = $1,
-				attack = attack - $2 + $5,
-				defense = defense - $3 + $6,
-				hp = hp - $4 + $7,
-				current_hp = LEAST(current_hp, hp - $4 + $7)
-			WHERE id = $8 AND deleted_at IS NULL
-		`, fieldName)
-		_, err = tx.Exec(updateStatsQuery, itemID, oldItem.Attack, oldItem.Defense, oldItem.Hp, item.Attack, item.Defense, item.Hp, userID)
-	} else {
-		updateStatsQuery = fmt.Sprintf(`
-			UPDATE users 
-			SET %s = $1,
-				attack = attack + $2,
-				defense = defense + $3,
-				hp = hp + $4,
-				current_hp = LEAST(current_hp, hp + $4)
-			WHERE id = $5 AND deleted_at IS NULL
-		`, fieldName)
-		_, err = tx.Exec(updateStatsQuery, itemID, item.Attack, item.Defense, item.Hp, userID)
+	if free, ok := user.FreeSlot(slots); ok {
+		return free, nil
 	}
-	if err != nil {
-		return err
+	if len(slots) == 1 {
+		return slots[0].Name, nil
 	}
+	return "", ErrSlotRequired
+}
 
-	if err := tx.Commit(); err != nil {
+// unequipSlot puts the instance in the slot back into the bag. Items equipped
+// before instances existed get an instance now.
+func unequipSlot(repos *repository.Repositories, userID uuid.UUID, slot string, itemID uuid.UUID) error {
+	instance, err := repos.Inventory.FindEquippedBySlot(userID, slot)
+	switch {
+	case errors.Is(err, repository.ErrInventoryNotFound):
+		return repos.Inventory.Create(&domain.Inventory{UserID: userID, EquipmentItemID: itemID})
+	case err != nil:
 		return err
+	default:
+		return repos.Inventory.SetEquippedSlot(instance.ID, nil)
 	}
-
-	return nil
 }
@@ -28,7 +28,7 @@ func setupTestData(db *sqlx.DB) (*domain.User, *domain.EquipmentItem, uuid.UUID,
 		return nil, nil, uuid.Nil, fmt.Errorf("failed to create location: %w", err)
 	}
 
-	categoryQuery := `INSERT INTO equipment_categories (name, type) VALUES ($1, $2::equipment_category_type) RETURNING id, created_at`
+	categoryQuery := `INSERT INTO equipment_categories (name, type) VALUES ($1, $2) RETURNING id, created_at`
 	category := &domain.EquipmentCategory{
 		Name: "Weapon",
 		Type: "weapon",
@@ -105,7 +105,7 @@ func TestEquipmentItemTakeOnService_TakeOnEquipmentItem(t *testing.T) {
 		require.NoError(t, err)
 
 		var equippedItemID uuid.UUID
-		query := `SELECT weapon_equipment_item_id FROM users WHERE id = $1`
+		query := `SELECT equipment_item_id FROM equipped_items WHERE user_id = $1 AND slot = 'weapon'`
 		err = db.Get(&equippedItemID, query, user.ID)
 		require.NoError(t, err)
 		assert.Equal(t, item.ID, equippedItemID)
@@ -193,7 +193,7 @@ func TestEquipmentItemTakeOnService_TakeOnEquipmentItem(t *testing.T) {
 		require.NoError(t, err)
 
 		var equippedItemID uuid.UUID
-		query := `SELECT weapon_equipment_item_id FROM users WHERE id = $1`
+		query := `SELECT equipment_item_id FROM equipped_items WHERE user_id = $1 AND slot = 'weapon'`
 		err = db.Get(&equippedItemID, query, user.ID)
 		require.NoError(t, err)
 		assert.Equal(t, newItem2.ID, equippedItemID)
@@ -219,3 +219,170 @@ func TestEquipmentItemTakeOnService_TakeOnEquipmentItem(t *testing.T) {
 		assert.Equal(t, 1, inventoryCount)
 	})
 }
+
+func TestEquipmentItemTakeOnService_ItemSetBonus(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	db := testDB
+	ctx := context.Background()
+
+	user, _, _, err := setupTestData(db)
+	require.NoError(t, err)
+
+	var ringCategoryID uuid.UUID
+	err = db.QueryRow(`INSERT INTO equipment_categories (name, type) VALUES ('Ring', 'ring') RETURNING id`).Scan(&ringCategoryID)
+	require.NoError(t, err)
+
+	equipmentItemRepo := repository.NewEquipmentItemRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	itemSetRepo := repository.NewItemSetRepository(db)
+
+	set := &domain.ItemSet{Name: "Twin rings", Slug: fmt.Sprintf("twin-rings-%d", time.Now().UnixNano())}
+	require.NoError(t, itemSetRepo.Create(set))
+	require.NoError(t, itemSetRepo.CreateBonus(&domain.ItemSetBonus{ItemSetID: set.ID, Pieces: 2, Attack: 5}))
+
+	var rings []*domain.EquipmentItem
+	for i := range 2 {
+		ring := &domain.EquipmentItem{
+			Name:                fmt.Sprintf("Twin ring %d", i),
+			Slug:                fmt.Sprintf("twin-ring-%d-%d", i, time.Now().UnixNano()),
+			Attack:              1,
+			RequiredLevel:       1,
+			Price:               100,
+			EquipmentCategoryID: ringCategoryID,
+		}
+		require.NoError(t, equipmentItemRepo.Create(ring))
+		require.NoError(t, itemSetRepo.AddItem(set.ID, ring.ID))
+		require.NoError(t, inventoryRepo.Create(&domain.Inventory{UserID: user.ID, EquipmentItemID: ring.ID}))
+		rings = append(rings, ring)
+	}
+
+	takeOn := NewEquipmentItemTakeOnService(db, equipmentItemRepo, inventoryRepo, userRepo)
+	takeOff := NewEquipmentItemTakeOffService(db, equipmentItemRepo, inventoryRepo, userRepo)
+
+	require.NoError(t, takeOn.TakeOnEquipmentItem(ctx, user.ID, rings[0].ID))
+	userAfter, err := userRepo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Attack+1, userAfter.Attack)
+
+	require.NoError(t, takeOn.TakeOnEquipmentItem(ctx, user.ID, rings[1].ID))
+	userAfter, err = userRepo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Attack+7, userAfter.Attack)
+
+	require.NoError(t, takeOff.TakeOffEquipmentItem(ctx, user.ID, "ring1"))
+	userAfter, err = userRepo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Attack+1, userAfter.Attack)
+}
+
+func TestEquipmentItemTakeOnService_RingSlots(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	db := testDB
+	ctx := context.Background()
+
+	user, _, _, err := setupTestData(db)
+	require.NoError(t, err)
+
+	var ringCategoryID uuid.UUID
+	err = db.QueryRow(`INSERT INTO equipment_categories (name, type) VALUES ('Ring', 'ring') RETURNING id`).Scan(&ringCategoryID)
+	require.NoError(t, err)
+
+	equipmentItemRepo := repository.NewEquipmentItemRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	service := NewEquipmentItemTakeOnService(db, equipmentItemRepo, inventoryRepo, userRepo)
+
+	ring := &domain.EquipmentItem{
+		Name:                "Plain ring",
+		Slug:                fmt.Sprintf("plain-ring-%d", time.Now().UnixNano()),
+		Attack:              1,
+		RequiredLevel:       1,
+		Price:               100,
+		EquipmentCategoryID: ringCategoryID,
+	}
+	require.NoError(t, equipmentItemRepo.Create(ring))
+	for range 5 {
+		require.NoError(t, inventoryRepo.Create(&domain.Inventory{UserID: user.ID, EquipmentItemID: ring.ID}))
+	}
+
+	for range 4 {
+		require.NoError(t, service.TakeOnEquipmentItem(ctx, user.ID, ring.ID))
+	}
+	userAfter, err := userRepo.FindByID(user.ID)
+	require.NoError(t, err)
+	for _, slot := range []string{"ring1", "ring2", "ring3", "ring4"} {
+		assert.Equal(t, &ring.ID, userAfter.EquippedItemID(slot), slot)
+	}
+
+	err = service.TakeOnEquipmentItem(ctx, user.ID, ring.ID)
+	assert.ErrorIs(t, err, ErrSlotRequired)
+
+	err = service.TakeOnEquipmentItemInSlot(ctx, user.ID, ring.ID, "weapon")
+	assert.ErrorIs(t, err, ErrInvalidSlot)
+
+	ring3, err := inventoryRepo.FindEquippedBySlot(user.ID, "ring3")
+	require.NoError(t, err)
+	require.NoError(t, service.TakeOnEquipmentItemInSlot(ctx, user.ID, ring.ID, "ring3"))
+
+	swapped, err := inventoryRepo.FindEquippedBySlot(user.ID, "ring3")
+	require.NoError(t, err)
+	assert.NotEqual(t, ring3.ID, swapped.ID)
+
+	bag, err := inventoryRepo.FindByUserID(user.ID)
+	require.NoError(t, err)
+	assert.Len(t, bag, 1)
+}
+
+func TestEquipmentItemTakeOnService_PicksInstance(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	db := testDB
+	ctx := context.Background()
+
+	user, item, _, err := setupTestData(db)
+	require.NoError(t, err)
+
+	inventoryRepo := repository.NewInventoryRepository(db)
+	enhanced := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+	require.NoError(t, inventoryRepo.Create(enhanced))
+	require.NoError(t, inventoryRepo.UpdateEnhancementLevel(enhanced.ID, 2))
+	require.NoError(t, inventoryRepo.UpdateDurability(enhanced.ID, 10))
+
+	service := NewEquipmentItemTakeOnService(db, repository.NewEquipmentItemRepository(db), inventoryRepo, repository.NewUserRepository(db))
+
+	equippedInstance := func() uuid.UUID {
+		instance, err := inventoryRepo.FindEquippedBySlot(user.ID, "weapon")
+		require.NoError(t, err)
+		return instance.ID
+	}
+
+	t.Run("slug takes the most enhanced copy", func(t *testing.T) {
+		require.NoError(t, service.TakeOnEquipmentItem(ctx, user.ID, item.ID))
+		assert.Equal(t, enhanced.ID, equippedInstance())
+	})
+
+	t.Run("instance id takes that copy", func(t *testing.T) {
+		var plainID uuid.UUID
+		require.NoError(t, db.Get(&plainID, `SELECT id FROM inventory WHERE user_id = $1 AND equipped_slot IS NULL`, user.ID))
+
+		require.NoError(t, service.TakeOnInstance(ctx, user.ID, plainID, ""))
+		assert.Equal(t, plainID, equippedInstance())
+	})
+
+	t.Run("instance of another user", func(t *testing.T) {
+		other, _, _, err := setupTestData(db)
+		require.NoError(t, err)
+
+		err = service.TakeOnInstance(ctx, other.ID, enhanced.ID, "")
+		assert.ErrorIs(t, err, ErrItemNotInInventory)
+	})
+}
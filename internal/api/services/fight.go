@@ -11,6 +11,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"moonshine/internal/domain"
+	"moonshine/internal/events"
 	"moonshine/internal/repository"
 )
 
@@ -76,6 +77,12 @@ func (s *FightService) GetCurrentFight(ctx context.Context, userID uuid.UUID) (*
 	}
 	fight.Rounds = rounds
 
+	effects, err := repository.NewStatusEffectRepository(s.db).FindByFightID(fight.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: find status effects: %w", ErrInternalError, err)
+	}
+	attachStatusEffects(fight, effects)
+
 	bot, err := s.botRepo.FindByID(fight.BotID)
 	if err != nil {
 		return nil, ErrBotNotFound
@@ -96,6 +103,32 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 		return nil, ErrInvalidBodyPart
 	}
 
+	return s.hit(ctx, userID, playerAttackPoint, playerDefensePoint, nil)
+}
+
+// UseConsumable spends the player's turn on a consumable: the player deals no
+// damage this round while the bot attacks as usual. An empty defense point is
+// picked at random.
+func (s *FightService) UseConsumable(ctx context.Context, userID uuid.UUID, consumableSlug, playerDefensePoint string) (*GetCurrentFightResult, error) {
+	if playerDefensePoint == "" {
+		playerDefensePoint = randomBodyPart()
+	}
+	if !isValidBodyPart(playerDefensePoint) {
+		return nil, ErrInvalidBodyPart
+	}
+
+	consumable, err := repository.NewConsumableRepository(s.db).FindBySlug(consumableSlug)
+	if err != nil {
+		return nil, ErrConsumableNotFound
+	}
+	if consumable.EffectType == domain.ConsumableEffectMaterial {
+		return nil, ErrConsumableNotUsable
+	}
+
+	return s.hit(ctx, userID, "", playerDefensePoint, consumable)
+}
+
+func (s *FightService) hit(ctx context.Context, userID uuid.UUID, playerAttackPoint, playerDefensePoint string, consumable *domain.Consumable) (*GetCurrentFightResult, error) {
 	fight, err := s.fightRepo.FindActiveByUserID(userID)
 	if err != nil {
 		return nil, ErrNoActiveFight
@@ -122,14 +155,61 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 
 	currentRound := rounds[0]
 
-	botAttackPoint := string(domain.BodyParts[rand.Intn(len(domain.BodyParts))])
-	botDefensePoint := string(domain.BodyParts[rand.Intn(len(domain.BodyParts))])
+	effects, err := repository.NewStatusEffectRepository(s.db).FindByFightID(fight.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: find status effects: %w", ErrInternalError, err)
+	}
+	tick := resolveStatusEffects(effects)
+
+	stats, err := NewStatsCalculator(repository.NewRepositories(s.db)).Calculate(user, tick.PlayerBuff)
+	if err != nil {
+		return nil, fmt.Errorf("%w: calculate stats: %w", ErrInternalError, err)
+	}
+	user.SetStats(stats)
+
+	var equipped []*domain.EquipmentItem
+	if ids := user.EquippedItemIDs(); len(ids) > 0 {
+		equipped, err = repository.NewEquipmentItemRepository(s.db).FindByIDs(ids)
+		if err != nil {
+			return nil, fmt.Errorf("%w: find equipped items: %w", ErrInternalError, err)
+		}
+	}
+
+	instances, err := repository.NewInventoryRepository(s.db).FindEquipped(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: find equipped instances: %w", ErrInternalError, err)
+	}
+	equipped = workingEquipment(equipped, instances)
+
+	var weapon *domain.EquipmentItem
+	weaponID := user.EquippedItemID("weapon")
+	for _, item := range equipped {
+		if weaponID != nil && item.ID == *weaponID {
+			weapon = item
+		}
+	}
 
-	playerDmg := calculateDamage(user.Attack, bot.Defense, playerAttackPoint, botDefensePoint)
-	botDmg := calculateDamage(bot.Attack, user.Defense, botAttackPoint, playerDefensePoint)
+	player := playerCombatant(user, equipped)
+	botSide := botCombatant(bot).buffed(tick.BotBuff)
 
-	finalPlayerHp := calculateFinalHp(currentRound.PlayerHp, botDmg)
-	finalBotHp := calculateFinalHp(currentRound.BotHp, playerDmg)
+	var outcome roundOutcome
+	if consumable != nil {
+		if user.Level < consumable.RequiredLevel {
+			return nil, ErrInsufficientLevel
+		}
+		if consumable.EffectType == domain.ConsumableEffectHeal && currentRound.PlayerHp >= int(user.Hp) {
+			return nil, ErrHpAlreadyFull
+		}
+		if tick.PlayerStunned {
+			return nil, ErrPlayerStunned
+		}
+		outcome = playConsumableRound(player, user.Hp, consumable, botSide, currentRound.PlayerHp, currentRound.BotHp, playerDefensePoint, tick)
+	} else {
+		outcome = playRound(player, botSide, currentRound.PlayerHp, currentRound.BotHp, playerAttackPoint, playerDefensePoint, tick)
+	}
+	botAttackPoint, botDefensePoint := outcome.BotAttackPoint, outcome.BotDefensePoint
+	playerDmg, botDmg := outcome.PlayerDamage, outcome.BotDamage
+	finalPlayerHp, finalBotHp := outcome.PlayerHp, outcome.BotHp
 
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -140,25 +220,64 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 	roundRepoTx := repository.NewRoundRepository(tx)
 	fightRepoTx := repository.NewFightRepository(tx)
 
+	if err = wearEquipment(tx, user); err != nil {
+		return nil, fmt.Errorf("%w: wear equipment: %w", ErrInternalError, err)
+	}
+
 	if err = roundRepoTx.FinishRound(currentRound.ID, botAttackPoint, botDefensePoint, playerAttackPoint, playerDefensePoint,
 		playerDmg, botDmg, finalPlayerHp, finalBotHp); err != nil {
 		return nil, fmt.Errorf("%w: finish round: %w", ErrInternalError, err)
 	}
 
+	if consumable != nil {
+		if err = repository.NewConsumableRepository(tx).TakeFromInventory(userID, consumable.ID); err != nil {
+			if errors.Is(err, repository.ErrConsumableNotOwned) {
+				return nil, ErrConsumableNotOwned
+			}
+			return nil, fmt.Errorf("%w: take consumable: %w", ErrInternalError, err)
+		}
+		if err = roundRepoTx.SetPlayerHeal(currentRound.ID, consumable.ID, outcome.PlayerHeal); err != nil {
+			return nil, fmt.Errorf("%w: set player heal: %w", ErrInternalError, err)
+		}
+	}
+
+	if err = roundRepoTx.SetStatusEffects(currentRound.ID, outcome.PlayerEffectDamage, outcome.BotEffectDamage,
+		outcome.PlayerAbsorbed, outcome.BotAbsorbed, outcome.PlayerStunned, outcome.BotStunned); err != nil {
+		return nil, fmt.Errorf("%w: set round status effects: %w", ErrInternalError, err)
+	}
+
+	if err = roundRepoTx.SetStrikeFlags(currentRound.ID, outcome.PlayerCrit, outcome.BotCrit,
+		outcome.PlayerDodged, outcome.BotDodged, outcome.PlayerBlocked, outcome.BotBlocked); err != nil {
+		return nil, fmt.Errorf("%w: set round strike flags: %w", ErrInternalError, err)
+	}
+
+	effectRepoTx := repository.NewStatusEffectRepository(tx)
+	for _, effect := range advanceStatusEffects(effects, outcome.PlayerAbsorbed, outcome.BotAbsorbed) {
+		if err = effectRepoTx.Update(effect); err != nil {
+			return nil, fmt.Errorf("%w: update status effect: %w", ErrInternalError, err)
+		}
+	}
+
+	var gameEvents []domain.GameEvent
 	if finalPlayerHp == 0 || finalBotHp == 0 {
 		fight.DroppedGold = calculateDroppedGold(bot.Level)
 		fight.Exp = calculateExp(finalBotHp, user.Level, bot.Level)
+		startLevel := user.Level
 
-		lvl := calculateLvl(user.Level, user.Exp, fight.Exp)
-
-		if lvl > user.Level {
-			user.CurrentHp = int(user.Hp)
-		} else {
-			user.CurrentHp = finalPlayerHp
+		user.CurrentHp = min(finalPlayerHp, int(user.Hp))
+		if err = awardUser(repository.NewRepositories(tx), user, fight.DroppedGold, fight.Exp, domain.GoldReasonFightReward, fight.ID); err != nil {
+			return nil, fmt.Errorf("%w: award user: %w", ErrInternalError, err)
 		}
+		gameEvents = append(gameEvents, domain.GameEvent{Type: domain.EventRewardEarned, UserID: userID, Gold: fight.DroppedGold, Exp: fight.Exp})
 
-		if err = s.userRepo.UpdateWithExt(tx, userID, fight.DroppedGold, fight.Exp, lvl, user.CurrentHp); err != nil {
-			return nil, fmt.Errorf("%w: update user: %w", ErrInternalError, err)
+		if finalBotHp == 0 {
+			if err = repository.NewQuestRepository(tx).AddProgress(userID, domain.QuestObjectiveKillBot, bot.Slug, 1); err != nil {
+				return nil, fmt.Errorf("%w: track quest progress: %w", ErrInternalError, err)
+			}
+			gameEvents = append(gameEvents, domain.GameEvent{Type: domain.EventBotKilled, UserID: userID, Slug: bot.Slug})
+		}
+		if user.Level > startLevel {
+			gameEvents = append(gameEvents, domain.GameEvent{Type: domain.EventLevelReached, UserID: userID})
 		}
 
 		finished, err := fightRepoTx.Finish(fight.ID, fight.DroppedGold, fight.Exp)
@@ -167,6 +286,12 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 		}
 		fight = finished
 	} else {
+		for _, effect := range newStatusEffects(fight.ID, currentRound.ID, weapon, bot, consumable, outcome) {
+			if err = effectRepoTx.Create(effect); err != nil {
+				return nil, fmt.Errorf("%w: create status effect: %w", ErrInternalError, err)
+			}
+		}
+
 		if err = roundRepoTx.Create(fight.ID, finalPlayerHp, uint(finalBotHp)); err != nil {
 			return nil, fmt.Errorf("%w: create next round: %w", ErrInternalError, err)
 		}
@@ -178,10 +303,20 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 	}
 	fight.Rounds = updatedRounds
 
+	updatedEffects, err := effectRepoTx.FindByFightID(fight.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: find updated status effects: %w", ErrInternalError, err)
+	}
+	attachStatusEffects(fight, updatedEffects)
+
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("%w: commit tx: %w", ErrInternalError, err)
 	}
 
+	for _, event := range gameEvents {
+		events.GetBus().Publish(ctx, event)
+	}
+
 	return &GetCurrentFightResult{
 		User:  user,
 		Bot:   bot,
@@ -189,6 +324,226 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 	}, nil
 }
 
+// workingEquipment drops the items whose equipped instances are all broken.
+// Items equipped before instances existed have none and always work.
+func workingEquipment(equipped []*domain.EquipmentItem, instances []*domain.Inventory) []*domain.EquipmentItem {
+	broken := make(map[uuid.UUID]bool)
+	working := make(map[uuid.UUID]bool)
+	for _, instance := range instances {
+		if instance.Broken() {
+			broken[instance.EquipmentItemID] = true
+		} else {
+			working[instance.EquipmentItemID] = true
+		}
+	}
+
+	result := make([]*domain.EquipmentItem, 0, len(equipped))
+	for _, item := range equipped {
+		if !broken[item.ID] || working[item.ID] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// wearEquipment takes domain.EquipmentWearPerRound durability from every
+// equipped instance. Instances that break take their stats off the user.
+func wearEquipment(h repository.ExtHandle, user *domain.User) error {
+	broken, err := repository.NewInventoryRepository(h).WearEquipped(user.ID, domain.EquipmentWearPerRound)
+	if err != nil {
+		return err
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+
+	stats, err := NewStatsCalculator(repository.NewRepositories(h)).Recalculate(user.ID)
+	if err != nil {
+		return err
+	}
+	user.SetStats(stats)
+	return nil
+}
+
+// awardUser adds gold and exp to the user and applies the rewards of every
+// level reached on the way. The user's current hp is stored as is unless the
+// user levels up, which restores full hp.
+func awardUser(repos *repository.Repositories, user *domain.User, gold, exp uint,
+	reason domain.GoldTransactionReason, referenceID uuid.UUID) error {
+	lvl := calculateLvl(user.Level, user.Exp, exp)
+
+	if lvl > user.Level {
+		reward := domain.CurrentProgression().Rewards(user.Level, lvl)
+		if err := repos.Users.AddLevelReward(user.ID, reward); err != nil {
+			return fmt.Errorf("add level reward: %w", err)
+		}
+		stats, err := NewStatsCalculator(repos).Recalculate(user.ID)
+		if err != nil {
+			return fmt.Errorf("recalculate stats: %w", err)
+		}
+		user.FreeStats += reward.FreeStats
+		user.SetStats(stats)
+		user.CurrentHp = int(user.Hp)
+	}
+
+	if err := repos.Users.Update(user.ID, gold, exp, lvl, user.CurrentHp, reason, &referenceID); err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	user.Gold += gold
+	user.Exp += exp
+	user.Level = lvl
+	return nil
+}
+
+type roundOutcome struct {
+	BotAttackPoint     string
+	BotDefensePoint    string
+	PlayerDamage       uint
+	BotDamage          uint
+	PlayerHp           int
+	BotHp              int
+	PlayerHeal         uint
+	PlayerEffectDamage uint
+	BotEffectDamage    uint
+	PlayerAbsorbed     uint
+	BotAbsorbed        uint
+	PlayerStunned      bool
+	BotStunned         bool
+	PlayerCrit         bool
+	BotCrit            bool
+	PlayerDodged       bool
+	BotDodged          bool
+	PlayerBlocked      bool
+	BotBlocked         bool
+}
+
+// combatant is one side of a round.
+type combatant struct {
+	Attack  uint
+	Defense uint
+	Stats   domain.SecondaryStats
+}
+
+func playerCombatant(user *domain.User, equipped []*domain.EquipmentItem) combatant {
+	return combatant{Attack: user.Attack, Defense: user.Defense, Stats: user.SecondaryStats(equipped)}
+}
+
+func botCombatant(bot *domain.Bot) combatant {
+	return combatant{Attack: bot.Attack, Defense: bot.Defense, Stats: bot.SecondaryStats()}
+}
+
+// buffed adds the bonus to the attack and defense. Neither drops below zero.
+func (c combatant) buffed(bonus domain.StatBonus) combatant {
+	c.Attack = uint(max(int(c.Attack)+bonus.Attack, 0))
+	c.Defense = uint(max(int(c.Defense)+bonus.Defense, 0))
+	return c
+}
+
+type strike struct {
+	Damage  uint
+	Crit    bool
+	Dodged  bool
+	Blocked bool
+}
+
+func randomBodyPart() string {
+	return string(domain.BodyParts[rand.Intn(len(domain.BodyParts))])
+}
+
+func rollChance(percent uint) bool {
+	return rand.Intn(100) < int(percent)
+}
+
+// resolveStrike rolls the defender's dodge first, then the attacker's crit
+// and finally the defender's block, which halves the damage.
+func resolveStrike(attacker, defender combatant, attackPoint, defensePoint string) strike {
+	if rollChance(defender.Stats.DodgeChance) {
+		return strike{Dodged: true}
+	}
+
+	result := strike{Damage: calculateDamage(attacker.Attack, defender.Defense, attackPoint, defensePoint)}
+	if rollChance(attacker.Stats.CritChance) {
+		result.Crit = true
+		result.Damage = result.Damage * attacker.Stats.CritMultiplier / 100
+	}
+	if rollChance(defender.Stats.BlockChance) {
+		result.Blocked = true
+		result.Damage /= 2
+	}
+	return result
+}
+
+// startRound applies poison and bleed damage before anyone strikes.
+func startRound(playerHp, botHp int, tick statusTick) roundOutcome {
+	return roundOutcome{
+		BotAttackPoint:     randomBodyPart(),
+		BotDefensePoint:    randomBodyPart(),
+		PlayerHp:           calculateFinalHp(playerHp, tick.PlayerDamage),
+		BotHp:              calculateFinalHp(botHp, tick.BotDamage),
+		PlayerEffectDamage: tick.PlayerDamage,
+		BotEffectDamage:    tick.BotDamage,
+		PlayerStunned:      tick.PlayerStunned,
+		BotStunned:         tick.BotStunned,
+	}
+}
+
+func (o *roundOutcome) playerStrike(player, bot combatant, playerAttackPoint string) uint {
+	hit := resolveStrike(player, bot, playerAttackPoint, o.BotDefensePoint)
+	o.PlayerCrit, o.BotDodged, o.BotBlocked = hit.Crit, hit.Dodged, hit.Blocked
+	return hit.Damage
+}
+
+func (o *roundOutcome) botStrike(player, bot combatant, playerDefensePoint string) uint {
+	hit := resolveStrike(bot, player, o.BotAttackPoint, playerDefensePoint)
+	o.BotCrit, o.PlayerDodged, o.PlayerBlocked = hit.Crit, hit.Dodged, hit.Blocked
+	return hit.Damage
+}
+
+func (o *roundOutcome) exchange(playerDmg, botDmg uint, tick statusTick) {
+	o.PlayerDamage, o.BotAbsorbed = absorb(playerDmg, tick.BotShield)
+	o.BotDamage, o.PlayerAbsorbed = absorb(botDmg, tick.PlayerShield)
+	o.PlayerHp = calculateFinalHp(o.PlayerHp, o.BotDamage)
+	o.BotHp = calculateFinalHp(o.BotHp, o.PlayerDamage)
+}
+
+func playRound(player, bot combatant, playerHp, botHp int, playerAttackPoint, playerDefensePoint string, tick statusTick) roundOutcome {
+	outcome := startRound(playerHp, botHp, tick)
+	if outcome.PlayerHp == 0 || outcome.BotHp == 0 {
+		return outcome
+	}
+
+	var playerDmg, botDmg uint
+	if !tick.PlayerStunned {
+		playerDmg = outcome.playerStrike(player, bot, playerAttackPoint)
+	}
+	if !tick.BotStunned {
+		botDmg = outcome.botStrike(player, bot, playerDefensePoint)
+	}
+
+	outcome.exchange(playerDmg, botDmg, tick)
+	return outcome
+}
+
+func playConsumableRound(player combatant, playerMaxHp uint, consumable *domain.Consumable, bot combatant, playerHp, botHp int, playerDefensePoint string, tick statusTick) roundOutcome {
+	outcome := startRound(playerHp, botHp, tick)
+	if outcome.PlayerHp == 0 || outcome.BotHp == 0 {
+		return outcome
+	}
+
+	healedHp := consumable.Heal(outcome.PlayerHp, playerMaxHp)
+	outcome.PlayerHeal = uint(healedHp - outcome.PlayerHp)
+	outcome.PlayerHp = healedHp
+
+	var botDmg uint
+	if !tick.BotStunned {
+		botDmg = outcome.botStrike(player, bot, playerDefensePoint)
+	}
+
+	outcome.exchange(0, botDmg, tick)
+	return outcome
+}
+
 func calculateDamage(attack, defense uint, attackPoint, defensePoint string) uint {
 	var base int
 	if attackPoint == defensePoint {
@@ -227,61 +582,23 @@ func calculateDroppedGold(botLvl uint) uint {
 }
 
 func calculateExp(botFinalHp int, playerLvl, botLvl uint) uint {
-	if botFinalHp > 0 || playerLvl >= 20 {
+	progression := domain.CurrentProgression()
+	if botFinalHp > 0 || playerLvl >= progression.MaxLevel() {
 		return 0
 	}
 
-	nextLevel := playerLvl + 1
-	requiredExp, exists := domain.LevelMatrix[nextLevel]
+	requiredExp, exists := progression.RequiredExp(playerLvl + 1)
 	if !exists {
 		return 0
 	}
 
-	bots := botsToLevel(playerLvl)
+	bots := progression.BotsToLevel(playerLvl)
 	baseExp := float64(requiredExp) / float64(bots)
-	mod := levelModifier(playerLvl, botLvl)
+	mod := progression.LevelModifier(playerLvl, botLvl)
 
 	return uint(baseExp * mod)
 }
 
-func botsToLevel(playerLvl uint) uint {
-	return uint(5 * math.Pow(1.6, float64(playerLvl-1)))
-}
-
-func levelModifier(playerLvl, botLvl uint) float64 {
-	diff := int(botLvl) - int(playerLvl)
-
-	switch {
-	case diff == 0:
-		return 1.0
-	case diff > 0:
-		return 1.0 + float64(diff)*0.25
-	default:
-		return 1.0 / (1.0 + float64(-diff)*0.5)
-	}
-}
-
 func calculateLvl(playerLvl, currentExp, gotExp uint) uint {
-	newExp := currentExp + gotExp
-	newLevel := playerLvl
-
-	for {
-		nextLevel := newLevel + 1
-		if nextLevel > 20 {
-			break
-		}
-
-		requiredExp, exists := domain.LevelMatrix[nextLevel]
-		if !exists {
-			break
-		}
-
-		if newExp >= requiredExp {
-			newLevel = nextLevel
-		} else {
-			break
-		}
-	}
-
-	return newLevel
+	return domain.CurrentProgression().LevelForExp(playerLvl, currentExp+gotExp)
 }
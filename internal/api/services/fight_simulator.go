@@ -0,0 +1,123 @@
+package services
+
+import (
+	"math"
+
+	"moonshine/internal/domain"
+)
+
+// maxSimulatedRounds stops fights where neither side can deal damage.
+const maxSimulatedRounds = 1000
+
+type SimulationBuild struct {
+	Level   uint
+	Exp     uint
+	Attack  uint
+	Defense uint
+	Hp      uint
+	Agility uint
+	Luck    uint
+	Items   []*domain.EquipmentItem
+}
+
+// Equip adds item stats to the build the same way TakeOnEquipmentItem does.
+func (b *SimulationBuild) Equip(items ...*domain.EquipmentItem) {
+	for _, item := range items {
+		b.Attack += item.Attack
+		b.Defense += item.Defense
+		b.Hp += item.Hp
+	}
+	b.Items = append(b.Items, items...)
+}
+
+func (b *SimulationBuild) SecondaryStats() domain.SecondaryStats {
+	user := &domain.User{Level: b.Level, Agility: b.Agility, Luck: b.Luck}
+	return user.SecondaryStats(b.Items)
+}
+
+type SimulationResult struct {
+	Bot         *domain.Bot
+	Fights      int
+	Wins        int
+	TotalRounds int
+	TotalGold   uint
+	TotalExp    uint
+}
+
+func (r *SimulationResult) WinRate() float64 {
+	if r.Fights == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Fights)
+}
+
+func (r *SimulationResult) AvgRounds() float64 {
+	if r.Fights == 0 {
+		return 0
+	}
+	return float64(r.TotalRounds) / float64(r.Fights)
+}
+
+func (r *SimulationResult) ExpectedGold() float64 {
+	if r.Fights == 0 {
+		return 0
+	}
+	return float64(r.TotalGold) / float64(r.Fights)
+}
+
+func (r *SimulationResult) ExpectedExp() float64 {
+	if r.Fights == 0 {
+		return 0
+	}
+	return float64(r.TotalExp) / float64(r.Fights)
+}
+
+// FightsToLevel returns how many fights against the bot the build needs to
+// reach the next level, or +Inf when the bot gives no exp.
+func (r *SimulationResult) FightsToLevel(build SimulationBuild) float64 {
+	requiredExp, exists := domain.CurrentProgression().RequiredExp(build.Level + 1)
+	if !exists {
+		return math.Inf(1)
+	}
+	if build.Exp >= requiredExp {
+		return 0
+	}
+
+	expPerFight := r.ExpectedExp()
+	if expPerFight == 0 {
+		return math.Inf(1)
+	}
+	return math.Ceil(float64(requiredExp-build.Exp) / expPerFight)
+}
+
+// SimulateFights plays the given number of fights between the build and the
+// bot using the same round and reward rules as FightService.Hit. Attack and
+// defense points are picked at random for both sides. Status effects are not
+// simulated.
+func SimulateFights(build SimulationBuild, bot *domain.Bot, fights int) *SimulationResult {
+	result := &SimulationResult{Bot: bot}
+	player := combatant{Attack: build.Attack, Defense: build.Defense, Stats: build.SecondaryStats()}
+	botSide := botCombatant(bot)
+
+	for i := 0; i < fights; i++ {
+		playerHp := int(build.Hp)
+		botHp := int(bot.Hp)
+
+		rounds := 0
+		for playerHp > 0 && botHp > 0 && rounds < maxSimulatedRounds {
+			outcome := playRound(player, botSide, playerHp, botHp, randomBodyPart(), randomBodyPart(), statusTick{})
+			playerHp, botHp = outcome.PlayerHp, outcome.BotHp
+			rounds++
+		}
+
+		result.Fights++
+		result.TotalRounds += rounds
+		result.TotalGold += calculateDroppedGold(bot.Level)
+		if botHp == 0 {
+			result.Wins++
+			result.TotalExp += calculateExp(botHp, build.Level, bot.Level)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"moonshine/internal/domain"
+)
+
+func TestSimulationBuild_Equip(t *testing.T) {
+	build := SimulationBuild{Level: 1, Attack: 1, Defense: 1, Hp: 20}
+	build.Equip(
+		&domain.EquipmentItem{Attack: 3},
+		&domain.EquipmentItem{Defense: 2, Hp: 10},
+	)
+
+	assert.Equal(t, uint(4), build.Attack)
+	assert.Equal(t, uint(3), build.Defense)
+	assert.Equal(t, uint(30), build.Hp)
+}
+
+func TestSimulationBuild_SecondaryStats(t *testing.T) {
+	build := SimulationBuild{Level: 4, Luck: 3}
+	build.Equip(&domain.EquipmentItem{SecondaryStats: domain.SecondaryStats{CritMultiplier: 25, BlockChance: 10}})
+
+	stats := build.SecondaryStats()
+	assert.Equal(t, uint(domain.BaseCritChance+2+3), stats.CritChance)
+	assert.Equal(t, uint(domain.BaseCritMultiplier+25), stats.CritMultiplier)
+	assert.Equal(t, uint(10), stats.BlockChance)
+}
+
+func TestSimulateFights(t *testing.T) {
+	t.Run("strong player always wins", func(t *testing.T) {
+		build := SimulationBuild{Level: 1, Attack: 100, Defense: 100, Hp: 100}
+		bot := &domain.Bot{Slug: "rat", Attack: 2, Defense: 1, Hp: 20, Level: 1}
+
+		result := SimulateFights(build, bot, 200)
+
+		assert.Equal(t, 200, result.Fights)
+		assert.Equal(t, 1.0, result.WinRate())
+		// The bot dodges now and then, so a one-hit fight can take longer.
+		assert.InDelta(t, 1.0, result.AvgRounds(), 0.2)
+		assert.Equal(t, 20.0, result.ExpectedExp())
+		assert.Equal(t, 5.0, result.FightsToLevel(build))
+	})
+
+	t.Run("weak player never wins", func(t *testing.T) {
+		build := SimulationBuild{Level: 1, Attack: 1, Defense: 1, Hp: 1}
+		bot := &domain.Bot{Slug: "wolf", Attack: 100, Defense: 0, Hp: 1000, Level: 3}
+
+		result := SimulateFights(build, bot, 200)
+
+		assert.Equal(t, 0.0, result.WinRate())
+		assert.Equal(t, 0.0, result.ExpectedExp())
+		assert.True(t, math.IsInf(result.FightsToLevel(build), 1))
+	})
+
+	t.Run("max level cannot level up", func(t *testing.T) {
+		build := SimulationBuild{Level: 20, Attack: 1000, Defense: 1000, Hp: 1000}
+		bot := &domain.Bot{Slug: "rat", Attack: 1, Defense: 1, Hp: 10, Level: 20}
+
+		result := SimulateFights(build, bot, 10)
+
+		assert.Equal(t, 1.0, result.WinRate())
+		assert.True(t, math.IsInf(result.FightsToLevel(build), 1))
+	})
+}
+
+func TestResolveStrike(t *testing.T) {
+	attacker := combatant{Attack: 10}
+
+	t.Run("dodge avoids all damage", func(t *testing.T) {
+		hit := resolveStrike(attacker, combatant{Stats: domain.SecondaryStats{DodgeChance: 100}}, "HEAD", "CHEST")
+		assert.Equal(t, strike{Dodged: true}, hit)
+	})
+
+	t.Run("crit multiplies damage", func(t *testing.T) {
+		critter := combatant{Attack: 100, Stats: domain.SecondaryStats{CritChance: 100, CritMultiplier: 200}}
+		hit := resolveStrike(critter, combatant{}, "HEAD", "CHEST")
+		assert.True(t, hit.Crit)
+		assert.InDelta(t, 200, hit.Damage, 20)
+	})
+
+	t.Run("block halves damage", func(t *testing.T) {
+		hit := resolveStrike(combatant{Attack: 100}, combatant{Stats: domain.SecondaryStats{BlockChance: 100}}, "HEAD", "CHEST")
+		assert.True(t, hit.Blocked)
+		assert.InDelta(t, 50, hit.Damage, 6)
+	})
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+const (
+	DefaultGoldTransactionsLimit = 20
+	MaxGoldTransactionsLimit     = 100
+)
+
+type GoldTransactionService struct {
+	goldTransactionRepo *repository.GoldTransactionRepository
+}
+
+func NewGoldTransactionService(goldTransactionRepo *repository.GoldTransactionRepository) *GoldTransactionService {
+	return &GoldTransactionService{
+		goldTransactionRepo: goldTransactionRepo,
+	}
+}
+
+// GetTransactions returns one page of the user's ledger, newest first. Pages
+// start at 1.
+func (s *GoldTransactionService) GetTransactions(ctx context.Context, userID uuid.UUID, page, limit int) ([]*domain.GoldTransaction, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultGoldTransactionsLimit
+	}
+	if limit > MaxGoldTransactionsLimit {
+		limit = MaxGoldTransactionsLimit
+	}
+
+	return s.goldTransactionRepo.FindByUserID(userID, limit, (page-1)*limit)
+}
+
+// Reconcile returns every user whose gold does not match the ledger.
+func (s *GoldTransactionService) Reconcile(ctx context.Context) ([]repository.GoldMismatch, error) {
+	return s.goldTransactionRepo.FindMismatches()
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestGoldTransactionService_Ledger(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewGoldTransactionService(repository.NewGoldTransactionRepository(testDB))
+	buyService := NewEquipmentItemBuyService(testDB, repository.NewEquipmentItemRepository(testDB),
+		repository.NewInventoryRepository(testDB), repository.NewUserRepository(testDB))
+	sellService := NewEquipmentItemSellService(testDB, repository.NewEquipmentItemRepository(testDB),
+		repository.NewInventoryRepository(testDB), repository.NewUserRepository(testDB))
+
+	user, item := setupBuyTestData(t)
+	require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+	require.NoError(t, sellService.SellEquipmentItem(ctx, user.ID, item.Slug, 1))
+
+	transactions, err := service.GetTransactions(ctx, user.ID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, transactions, 3)
+
+	sellPrice := int64(domain.CurrentShopSettings().SellPrice(item.Price))
+	assert.Equal(t, domain.GoldReasonEquipmentSell, transactions[0].Reason)
+	assert.Equal(t, sellPrice, transactions[0].Delta)
+	assert.Equal(t, 400+sellPrice, transactions[0].BalanceAfter)
+	assert.Equal(t, item.ID, *transactions[0].ReferenceID)
+
+	assert.Equal(t, domain.GoldReasonEquipmentBuy, transactions[1].Reason)
+	assert.Equal(t, -int64(item.Price), transactions[1].Delta)
+	assert.Equal(t, int64(400), transactions[1].BalanceAfter)
+
+	assert.Equal(t, domain.GoldReasonInitial, transactions[2].Reason)
+	assert.Equal(t, int64(500), transactions[2].Delta)
+
+	mismatches, err := service.Reconcile(ctx)
+	require.NoError(t, err)
+	for _, m := range mismatches {
+		assert.NotEqual(t, user.ID, m.UserID)
+	}
+}
+
+func TestGoldTransactionRepository_Apply(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	repo := repository.NewGoldTransactionRepository(testDB)
+
+	user, _ := setupBuyTestData(t)
+
+	_, err := repo.Apply(user.ID, -501, domain.GoldReasonEquipmentBuy, nil)
+	assert.ErrorIs(t, err, repository.ErrInsufficientGold)
+
+	transaction, err := repo.Apply(user.ID, -500, domain.GoldReasonEquipmentBuy, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), transaction.BalanceAfter)
+
+	transaction, err = repo.Apply(user.ID, 0, domain.GoldReasonEquipmentBuy, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, transaction)
+}
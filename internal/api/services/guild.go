@@ -0,0 +1,530 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+const (
+	DefaultGuildTransactionsLimit = 20
+	MaxGuildTransactionsLimit     = 100
+)
+
+var (
+	ErrInvalidGuildName     = errors.New("invalid guild name")
+	ErrInvalidGuildTag      = errors.New("invalid guild tag")
+	ErrGuildNameTaken       = errors.New("guild name or tag already taken")
+	ErrAlreadyInGuild       = errors.New("already in a guild")
+	ErrNotInGuild           = errors.New("not in a guild")
+	ErrGuildMemberNotFound  = errors.New("guild member not found")
+	ErrGuildInviteNotFound  = errors.New("guild invite not found")
+	ErrGuildFull            = errors.New("guild is full")
+	ErrGuildPermission      = errors.New("guild rank doesn't allow this")
+	ErrInvalidGuildRank     = errors.New("invalid guild rank")
+	ErrGuildLeaderCantLeave = errors.New("leader must pass on leadership before leaving")
+	ErrInvalidGuildAmount   = errors.New("invalid amount")
+	ErrInsufficientTreasury = errors.New("insufficient guild treasury")
+	ErrInvalidGuildMessage  = errors.New("invalid guild message")
+)
+
+// GuildService runs guilds: membership, ranks, the shared treasury and the
+// chat channel. The treasury has its own ledger next to the members' gold
+// ledger, every deposit and withdrawal writes to both.
+type GuildService struct {
+	uow   *repository.UnitOfWork
+	repos *repository.Repositories
+	hub   *ws.Hub
+}
+
+func NewGuildService(db *sqlx.DB, hub *ws.Hub) *GuildService {
+	return &GuildService{
+		uow:   repository.NewUnitOfWork(db),
+		repos: repository.NewRepositories(db),
+		hub:   hub,
+	}
+}
+
+// Create founds a guild led by the user for domain.GuildCreateCost gold.
+func (s *GuildService) Create(ctx context.Context, userID uuid.UUID, name, tag string) (*domain.Guild, error) {
+	name, ok := domain.NormalizeGuildName(name)
+	if !ok {
+		return nil, ErrInvalidGuildName
+	}
+	tag, ok = domain.NormalizeGuildTag(tag)
+	if !ok {
+		return nil, ErrInvalidGuildTag
+	}
+
+	guild := &domain.Guild{Name: name, Tag: tag}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Users.FindByIDForUpdate(userID); err != nil {
+			return repository.ErrUserNotFound
+		}
+		if _, err := repos.Guilds.FindMember(userID); err == nil {
+			return ErrAlreadyInGuild
+		} else if !errors.Is(err, repository.ErrGuildMemberNotFound) {
+			return err
+		}
+
+		if err := repos.Guilds.Create(guild); err != nil {
+			if errors.Is(err, repository.ErrGuildExists) {
+				return ErrGuildNameTaken
+			}
+			return err
+		}
+
+		if _, err := repos.GoldTransactions.Apply(userID, -domain.GuildCreateCost, domain.GoldReasonGuildCreate, &guild.ID); err != nil {
+			if errors.Is(err, repository.ErrInsufficientGold) {
+				return ErrInsufficientGold
+			}
+			return err
+		}
+
+		if err := repos.Guilds.AddMember(guild.ID, userID, domain.GuildRankLeader); err != nil {
+			if errors.Is(err, repository.ErrAlreadyInGuild) {
+				return ErrAlreadyInGuild
+			}
+			return err
+		}
+		if err := repos.Guilds.DeleteUserInvites(userID); err != nil {
+			return err
+		}
+
+		members, err := repos.Guilds.FindMembers(guild.ID)
+		guild.Members = members
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return guild, nil
+}
+
+// Get returns the user's guild with its members.
+func (s *GuildService) Get(ctx context.Context, userID uuid.UUID) (*domain.Guild, error) {
+	member, err := findGuildMember(s.repos, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	guild, err := s.repos.Guilds.FindByID(member.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	if guild.Members, err = s.repos.Guilds.FindMembers(guild.ID); err != nil {
+		return nil, err
+	}
+
+	return guild, nil
+}
+
+// Invite invites the player with the username into the user's guild. An
+// invite that is still pending is renewed.
+func (s *GuildService) Invite(ctx context.Context, userID uuid.UUID, username string) (*domain.GuildInvite, error) {
+	member, err := findGuildMember(s.repos, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Rank.Can(domain.GuildPermissionInvite) {
+		return nil, ErrGuildPermission
+	}
+
+	invitee, err := s.repos.Users.FindByUsername(username)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if _, err := s.repos.Guilds.FindMember(invitee.ID); err == nil {
+		return nil, ErrAlreadyInGuild
+	} else if !errors.Is(err, repository.ErrGuildMemberNotFound) {
+		return nil, err
+	}
+
+	guild, err := s.repos.Guilds.FindByID(member.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repos.Guilds.CountMembers(guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxGuildMembers {
+		return nil, ErrGuildFull
+	}
+
+	invite := &domain.GuildInvite{
+		GuildID:   guild.ID,
+		GuildName: guild.Name,
+		GuildTag:  guild.Tag,
+		UserID:    invitee.ID,
+		InviterID: &userID,
+		ExpiresAt: time.Now().UTC().Add(domain.GuildInviteLifetime),
+	}
+	if err := s.repos.Guilds.SaveInvite(invite); err != nil {
+		return nil, err
+	}
+
+	data := ws.GuildInviteData{GuildID: guild.ID.String(), Name: guild.Name, Tag: guild.Tag, From: member.Username}
+	if err := s.hub.SendGuildInvite(invitee.ID, data); err != nil {
+		log.Printf("[Guild] failed to notify %s of invite to %s: %v", invitee.ID, guild.ID, err)
+	}
+
+	return invite, nil
+}
+
+// Invites returns the user's pending invites.
+func (s *GuildService) Invites(ctx context.Context, userID uuid.UUID) ([]*domain.GuildInvite, error) {
+	return s.repos.Guilds.FindInvites(userID, time.Now().UTC())
+}
+
+// AcceptInvite joins the guild as a member. The user's other invites are
+// dropped.
+func (s *GuildService) AcceptInvite(ctx context.Context, userID, guildID uuid.UUID) (*domain.Guild, error) {
+	var guild *domain.Guild
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		guild, err = repos.Guilds.FindByIDForUpdate(guildID)
+		if err != nil {
+			if errors.Is(err, repository.ErrGuildNotFound) {
+				return ErrGuildInviteNotFound
+			}
+			return err
+		}
+
+		if err := repos.Guilds.DeleteInvite(guildID, userID, time.Now().UTC()); err != nil {
+			if errors.Is(err, repository.ErrGuildInviteNotFound) {
+				return ErrGuildInviteNotFound
+			}
+			return err
+		}
+
+		count, err := repos.Guilds.CountMembers(guildID)
+		if err != nil {
+			return err
+		}
+		if count >= domain.MaxGuildMembers {
+			return ErrGuildFull
+		}
+
+		if err := repos.Guilds.AddMember(guildID, userID, domain.GuildRankMember); err != nil {
+			if errors.Is(err, repository.ErrAlreadyInGuild) {
+				return ErrAlreadyInGuild
+			}
+			return err
+		}
+		if err := repos.Guilds.DeleteUserInvites(userID); err != nil {
+			return err
+		}
+
+		guild.Members, err = repos.Guilds.FindMembers(guildID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return guild, nil
+}
+
+func (s *GuildService) DeclineInvite(ctx context.Context, userID, guildID uuid.UUID) error {
+	err := s.repos.Guilds.DeleteInvite(guildID, userID, time.Now().UTC())
+	if errors.Is(err, repository.ErrGuildInviteNotFound) {
+		return ErrGuildInviteNotFound
+	}
+	return err
+}
+
+// Leave takes the user out of the guild. The leader has to pass leadership on
+// first, unless nobody else is left: then the treasury is paid out to the
+// leader through the ledger and the guild is disbanded.
+func (s *GuildService) Leave(ctx context.Context, userID uuid.UUID) error {
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		member, guild, err := lockGuildMember(repos, userID)
+		if err != nil {
+			return err
+		}
+
+		if member.Rank != domain.GuildRankLeader {
+			return repos.Guilds.RemoveMember(userID)
+		}
+
+		count, err := repos.Guilds.CountMembers(guild.ID)
+		if err != nil {
+			return err
+		}
+		if count > 1 {
+			return ErrGuildLeaderCantLeave
+		}
+
+		if guild.Treasury > 0 {
+			if _, err := repos.Guilds.ApplyTreasury(guild.ID, userID, -int64(guild.Treasury)); err != nil {
+				return err
+			}
+			if _, err := repos.GoldTransactions.Apply(userID, int64(guild.Treasury), domain.GoldReasonGuildWithdraw, &guild.ID); err != nil {
+				return err
+			}
+		}
+		return repos.Guilds.Disband(guild.ID)
+	})
+}
+
+// Kick removes a member ranked below the user.
+func (s *GuildService) Kick(ctx context.Context, userID, targetID uuid.UUID) error {
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		member, _, err := lockGuildMember(repos, userID)
+		if err != nil {
+			return err
+		}
+		if !member.Rank.Can(domain.GuildPermissionKick) {
+			return ErrGuildPermission
+		}
+
+		target, err := findGuildMate(repos, member, targetID)
+		if err != nil {
+			return err
+		}
+		if !member.Rank.Outranks(target.Rank) {
+			return ErrGuildPermission
+		}
+
+		return repos.Guilds.RemoveMember(targetID)
+	})
+}
+
+// SetRank changes a member's rank. Making someone leader passes leadership
+// on, the old leader becomes an officer.
+func (s *GuildService) SetRank(ctx context.Context, userID, targetID uuid.UUID, rank domain.GuildRank) error {
+	if !rank.Valid() {
+		return ErrInvalidGuildRank
+	}
+
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		member, _, err := lockGuildMember(repos, userID)
+		if err != nil {
+			return err
+		}
+		if !member.Rank.Can(domain.GuildPermissionManage) {
+			return ErrGuildPermission
+		}
+
+		target, err := findGuildMate(repos, member, targetID)
+		if err != nil {
+			return err
+		}
+		if target.UserID == member.UserID {
+			return ErrGuildPermission
+		}
+
+		if rank == domain.GuildRankLeader {
+			if err := repos.Guilds.UpdateRank(userID, domain.GuildRankOfficer); err != nil {
+				return err
+			}
+		}
+		return repos.Guilds.UpdateRank(targetID, rank)
+	})
+}
+
+// Deposit moves gold from the user into the guild treasury.
+func (s *GuildService) Deposit(ctx context.Context, userID uuid.UUID, amount uint) (*domain.GuildTransaction, error) {
+	if amount == 0 {
+		return nil, ErrInvalidGuildAmount
+	}
+
+	var transaction *domain.GuildTransaction
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		_, guild, err := lockGuildMember(repos, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := repos.GoldTransactions.Apply(userID, -int64(amount), domain.GoldReasonGuildDeposit, &guild.ID); err != nil {
+			if errors.Is(err, repository.ErrInsufficientGold) {
+				return ErrInsufficientGold
+			}
+			return err
+		}
+
+		transaction, err = repos.Guilds.ApplyTreasury(guild.ID, userID, int64(amount))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Withdraw moves gold from the guild treasury to the user.
+func (s *GuildService) Withdraw(ctx context.Context, userID uuid.UUID, amount uint) (*domain.GuildTransaction, error) {
+	if amount == 0 {
+		return nil, ErrInvalidGuildAmount
+	}
+
+	var transaction *domain.GuildTransaction
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		member, guild, err := lockGuildMember(repos, userID)
+		if err != nil {
+			return err
+		}
+		if !member.Rank.Can(domain.GuildPermissionWithdraw) {
+			return ErrGuildPermission
+		}
+
+		transaction, err = repos.Guilds.ApplyTreasury(guild.ID, userID, -int64(amount))
+		if err != nil {
+			if errors.Is(err, repository.ErrInsufficientTreasury) {
+				return ErrInsufficientTreasury
+			}
+			return err
+		}
+
+		_, err = repos.GoldTransactions.Apply(userID, int64(amount), domain.GoldReasonGuildWithdraw, &guild.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Transactions returns one page of the treasury ledger, newest first. Pages
+// start at 1.
+func (s *GuildService) Transactions(ctx context.Context, userID uuid.UUID, page, limit int) ([]*domain.GuildTransaction, error) {
+	member, err := findGuildMember(s.repos, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultGuildTransactionsLimit
+	}
+	if limit > MaxGuildTransactionsLimit {
+		limit = MaxGuildTransactionsLimit
+	}
+
+	return s.repos.Guilds.FindTransactions(member.GuildID, limit, (page-1)*limit)
+}
+
+// Chat posts the text to the guild channel and sends it to the members who
+// are online.
+func (s *GuildService) Chat(ctx context.Context, userID uuid.UUID, text string) (*domain.GuildMessage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > domain.MaxGuildMessageLength {
+		return nil, ErrInvalidGuildMessage
+	}
+
+	member, err := findGuildMember(s.repos, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &domain.GuildMessage{GuildID: member.GuildID, UserID: &userID, Username: member.Username, Text: text}
+	if err := s.repos.Guilds.CreateMessage(message); err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.repos.Guilds.FindMemberIDs(member.GuildID)
+	if err != nil {
+		log.Printf("[Guild] failed to load members of %s: %v", member.GuildID, err)
+		return message, nil
+	}
+
+	data := ws.GuildChatData{
+		ID:        message.ID.String(),
+		UserID:    userID.String(),
+		Username:  message.Username,
+		Text:      message.Text,
+		CreatedAt: message.CreatedAt.Format(time.RFC3339),
+	}
+	for _, id := range memberIDs {
+		if !s.hub.IsConnected(id) {
+			continue
+		}
+		if err := s.hub.SendGuildChat(id, data); err != nil {
+			log.Printf("[Guild] failed to send chat message %s to %s: %v", message.ID, id, err)
+		}
+	}
+
+	return message, nil
+}
+
+// ChatHistory returns the latest messages of the user's guild channel.
+func (s *GuildService) ChatHistory(ctx context.Context, userID uuid.UUID) ([]*domain.GuildMessage, error) {
+	member, err := findGuildMember(s.repos, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repos.Guilds.FindMessages(member.GuildID, domain.GuildChatHistory)
+}
+
+func findGuildMember(repos *repository.Repositories, userID uuid.UUID) (*domain.GuildMember, error) {
+	member, err := repos.Guilds.FindMember(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGuildMemberNotFound) {
+			return nil, ErrNotInGuild
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// lockGuildMember locks the user's guild and returns the membership read
+// under the lock.
+func lockGuildMember(repos *repository.Repositories, userID uuid.UUID) (*domain.GuildMember, *domain.Guild, error) {
+	member, err := findGuildMember(repos, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	guild, err := repos.Guilds.FindByIDForUpdate(member.GuildID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGuildNotFound) {
+			return nil, nil, ErrNotInGuild
+		}
+		return nil, nil, err
+	}
+
+	// The membership may have changed while waiting for the lock.
+	member, err = findGuildMember(repos, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member.GuildID != guild.ID {
+		return nil, nil, ErrNotInGuild
+	}
+
+	return member, guild, nil
+}
+
+func findGuildMate(repos *repository.Repositories, member *domain.GuildMember, targetID uuid.UUID) (*domain.GuildMember, error) {
+	target, err := repos.Guilds.FindMember(targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGuildMemberNotFound) {
+			return nil, ErrGuildMemberNotFound
+		}
+		return nil, err
+	}
+	if target.GuildID != member.GuildID {
+		return nil, ErrGuildMemberNotFound
+	}
+	return target, nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestGuildService(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewGuildService(testDB, ws.GetHub())
+	userRepo := repository.NewUserRepository(testDB)
+
+	newUser := func(t *testing.T) *domain.User {
+		user, _ := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE users SET gold = 5000 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+		user.Gold = 5000
+		return user
+	}
+
+	// setup returns a guild led by the leader with the member in it.
+	setup := func(t *testing.T) (*domain.Guild, *domain.User, *domain.User) {
+		leader := newUser(t)
+		member := newUser(t)
+
+		suffix := strings.ToUpper(uuid.NewString()[:4])
+		guild, err := service.Create(ctx, leader.ID, "Guild "+suffix, "G"+suffix)
+		require.NoError(t, err)
+
+		_, err = service.Invite(ctx, leader.ID, member.Username)
+		require.NoError(t, err)
+		_, err = service.AcceptInvite(ctx, member.ID, guild.ID)
+		require.NoError(t, err)
+
+		return guild, leader, member
+	}
+
+	t.Run("create charges gold and shows the tag", func(t *testing.T) {
+		guild, leader, member := setup(t)
+
+		leaderAfter, err := userRepo.FindByID(leader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, leader.Gold-domain.GuildCreateCost, leaderAfter.Gold)
+		require.NotNil(t, leaderAfter.GuildTag)
+		assert.Equal(t, guild.Tag, *leaderAfter.GuildTag)
+
+		_, err = service.Create(ctx, member.ID, "Another "+guild.Tag, "X"+guild.Tag[1:])
+		assert.ErrorIs(t, err, ErrAlreadyInGuild)
+	})
+
+	t.Run("ranks gate invite, kick and withdraw", func(t *testing.T) {
+		_, leader, member := setup(t)
+		outsider := newUser(t)
+
+		_, err := service.Invite(ctx, member.ID, outsider.Username)
+		assert.ErrorIs(t, err, ErrGuildPermission)
+		_, err = service.Withdraw(ctx, member.ID, 1)
+		assert.ErrorIs(t, err, ErrGuildPermission)
+		assert.ErrorIs(t, service.Kick(ctx, member.ID, leader.ID), ErrGuildPermission)
+
+		require.NoError(t, service.SetRank(ctx, leader.ID, member.ID, domain.GuildRankOfficer))
+		_, err = service.Invite(ctx, member.ID, outsider.Username)
+		require.NoError(t, err)
+		assert.ErrorIs(t, service.Kick(ctx, member.ID, leader.ID), ErrGuildPermission)
+
+		require.NoError(t, service.Kick(ctx, leader.ID, member.ID))
+		_, err = service.Get(ctx, member.ID)
+		assert.ErrorIs(t, err, ErrNotInGuild)
+	})
+
+	t.Run("treasury deposit and withdraw", func(t *testing.T) {
+		_, leader, member := setup(t)
+
+		transaction, err := service.Deposit(ctx, member.ID, 300)
+		require.NoError(t, err)
+		assert.Equal(t, int64(300), transaction.BalanceAfter)
+
+		_, err = service.Withdraw(ctx, leader.ID, 301)
+		assert.ErrorIs(t, err, ErrInsufficientTreasury)
+		transaction, err = service.Withdraw(ctx, leader.ID, 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), transaction.BalanceAfter)
+
+		memberAfter, err := userRepo.FindByID(member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, member.Gold-300, memberAfter.Gold)
+
+		transactions, err := service.Transactions(ctx, member.ID, 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, transactions, 2)
+	})
+
+	t.Run("leader hands on before leaving", func(t *testing.T) {
+		guild, leader, member := setup(t)
+
+		assert.ErrorIs(t, service.Leave(ctx, leader.ID), ErrGuildLeaderCantLeave)
+
+		require.NoError(t, service.SetRank(ctx, leader.ID, member.ID, domain.GuildRankLeader))
+		require.NoError(t, service.Leave(ctx, leader.ID))
+
+		// The last member disbands the guild and takes the treasury, the
+		// payout stays in the ledger.
+		_, err := service.Deposit(ctx, member.ID, 300)
+		require.NoError(t, err)
+		require.NoError(t, service.Leave(ctx, member.ID))
+		_, err = repository.NewGuildRepository(testDB).FindByID(guild.ID)
+		assert.ErrorIs(t, err, repository.ErrGuildNotFound)
+
+		memberAfter, err := userRepo.FindByID(member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, member.Gold, memberAfter.Gold)
+
+		transactions, err := repository.NewGuildRepository(testDB).FindTransactions(guild.ID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		assert.Equal(t, int64(-300), transactions[0].Delta)
+		assert.Equal(t, int64(0), transactions[0].BalanceAfter)
+
+		// The name and tag are free again.
+		_, err = service.Create(ctx, member.ID, guild.Name, guild.Tag)
+		require.NoError(t, err)
+	})
+
+	t.Run("disbanding an empty treasury writes no ledger entry", func(t *testing.T) {
+		guild, leader, member := setup(t)
+
+		require.NoError(t, service.Leave(ctx, member.ID))
+		require.NoError(t, service.Leave(ctx, leader.ID))
+
+		transactions, err := repository.NewGuildRepository(testDB).FindTransactions(guild.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, transactions)
+	})
+
+	t.Run("chat history", func(t *testing.T) {
+		_, leader, member := setup(t)
+
+		_, err := service.Chat(ctx, leader.ID, "  hello  ")
+		require.NoError(t, err)
+		_, err = service.Chat(ctx, member.ID, "hi")
+		require.NoError(t, err)
+		_, err = service.Chat(ctx, member.ID, " ")
+		assert.ErrorIs(t, err, ErrInvalidGuildMessage)
+
+		messages, err := service.ChatHistory(ctx, member.ID)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "hello", messages[0].Text)
+		assert.Equal(t, "hi", messages[1].Text)
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
@@ -9,16 +10,95 @@ import (
 	"moonshine/internal/repository"
 )
 
+var (
+	ErrInventoryFull = errors.New("inventory is full")
+)
+
 type InventoryService struct {
-	inventoryRepo *repository.InventoryRepository
+	inventoryRepo     *repository.InventoryRepository
+	equipmentItemRepo *repository.EquipmentItemRepository
+	itemSetRepo       *repository.ItemSetRepository
 }
 
-func NewInventoryService(inventoryRepo *repository.InventoryRepository) *InventoryService {
+func NewInventoryService(
+	inventoryRepo *repository.InventoryRepository,
+	equipmentItemRepo *repository.EquipmentItemRepository,
+	itemSetRepo *repository.ItemSetRepository,
+) *InventoryService {
 	return &InventoryService{
-		inventoryRepo: inventoryRepo,
+		inventoryRepo:     inventoryRepo,
+		equipmentItemRepo: equipmentItemRepo,
+		itemSetRepo:       itemSetRepo,
+	}
+}
+
+// GetUserInventory returns the unequipped instances in the user's bag.
+func (s *InventoryService) GetUserInventory(ctx context.Context, userID uuid.UUID) ([]*domain.InventoryItem, error) {
+	instances, err := s.inventoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withItems(instances)
+}
+
+// GetEquippedInstances returns the user's equipped instances by slot name.
+func (s *InventoryService) GetEquippedInstances(ctx context.Context, userID uuid.UUID) (map[string]*domain.Inventory, error) {
+	instances, err := s.inventoryRepo.FindEquipped(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bySlot := make(map[string]*domain.Inventory, len(instances))
+	for _, instance := range instances {
+		bySlot[*instance.EquippedSlot] = instance
+	}
+	return bySlot, nil
+}
+
+// GetItemSets returns the sets the equipped items belong to.
+func (s *InventoryService) GetItemSets(ctx context.Context, equippedItemIDs []uuid.UUID) ([]*domain.ItemSet, error) {
+	return s.itemSetRepo.FindByEquipmentItemIDs(equippedItemIDs)
+}
+
+func (s *InventoryService) withItems(instances []*domain.Inventory) ([]*domain.InventoryItem, error) {
+	if len(instances) == 0 {
+		return []*domain.InventoryItem{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(instances))
+	for i, instance := range instances {
+		ids[i] = instance.EquipmentItemID
+	}
+
+	items, err := s.equipmentItemRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
 	}
+
+	byID := make(map[uuid.UUID]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	result := make([]*domain.InventoryItem, 0, len(instances))
+	for _, instance := range instances {
+		if item, ok := byID[instance.EquipmentItemID]; ok {
+			result = append(result, &domain.InventoryItem{EquipmentItem: item, Instance: instance})
+		}
+	}
+	return result, nil
 }
 
-func (s *InventoryService) GetUserInventory(ctx context.Context, userID uuid.UUID) ([]*domain.EquipmentItem, error) {
-	return s.inventoryRepo.FindByUserID(userID)
+// ensureBagSpace returns ErrInventoryFull when every bag slot of the user is
+// taken. Lock the user first so concurrent calls count one after the other.
+func ensureBagSpace(repos *repository.Repositories, user *domain.User) error {
+	used, err := repos.Inventory.CountBagSlots(user.ID)
+	if err != nil {
+		return err
+	}
+	if used >= user.InventoryCapacity {
+		return ErrInventoryFull
+	}
+	return nil
 }
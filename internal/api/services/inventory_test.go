@@ -16,7 +16,11 @@ func TestInventoryService_GetUserInventory(t *testing.T) {
 	testutil.RequireDB(t, testDB)
 	ctx := context.Background()
 
-	service := NewInventoryService(repository.NewInventoryRepository(testDB))
+	service := NewInventoryService(
+		repository.NewInventoryRepository(testDB),
+		repository.NewEquipmentItemRepository(testDB),
+		repository.NewItemSetRepository(testDB),
+	)
 
 	t.Run("empty inventory", func(t *testing.T) {
 		user := setupUserServiceTestData(t)
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrMaxInventoryCapacity = errors.New("inventory is at max capacity")
+)
+
+type InventoryUpgradeService struct {
+	uow *repository.UnitOfWork
+}
+
+func NewInventoryUpgradeService(db *sqlx.DB) *InventoryUpgradeService {
+	return &InventoryUpgradeService{
+		uow: repository.NewUnitOfWork(db),
+	}
+}
+
+// UpgradeCapacity buys InventoryCapacityStep more bag slots and returns the
+// new capacity. Every upgrade costs more than the one before.
+func (s *InventoryUpgradeService) UpgradeCapacity(ctx context.Context, userID uuid.UUID) (uint, error) {
+	var capacity uint
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		cost, ok := domain.InventoryUpgradeCost(user.InventoryCapacity)
+		if !ok {
+			return ErrMaxInventoryCapacity
+		}
+
+		_, err = repos.GoldTransactions.Apply(userID, -int64(cost), domain.GoldReasonInventoryUpgrade, nil)
+		if err != nil {
+			if errors.Is(err, repository.ErrInsufficientGold) {
+				return ErrInsufficientGold
+			}
+			return err
+		}
+
+		capacity = user.InventoryCapacity + domain.InventoryCapacityStep
+		return repos.Users.UpdateInventoryCapacity(userID, capacity)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return capacity, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// MaxComparedItems is how many items one comparison takes.
+const MaxComparedItems = 20
+
+var (
+	ErrNoItemsToCompare      = errors.New("no items to compare")
+	ErrTooManyItemsToCompare = errors.New("too many items to compare")
+)
+
+// ItemComparisonService compares items with what the user is wearing and
+// suggests upgrades the user can buy where they are.
+type ItemComparisonService struct {
+	repos *repository.Repositories
+}
+
+func NewItemComparisonService(db *sqlx.DB) *ItemComparisonService {
+	return &ItemComparisonService{repos: repository.NewRepositories(db)}
+}
+
+// Compare compares every item with the equipped item in the slot it would go
+// to, in the order of the slugs. Unknown slugs and items no slot takes are
+// skipped.
+func (s *ItemComparisonService) Compare(ctx context.Context, userID uuid.UUID, slugs []string) ([]*domain.ItemComparison, error) {
+	if len(slugs) == 0 {
+		return nil, ErrNoItemsToCompare
+	}
+	if len(slugs) > MaxComparedItems {
+		return nil, ErrTooManyItemsToCompare
+	}
+
+	user, err := s.repos.Users.FindByID(userID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	items, err := s.repos.EquipmentItems.FindBySlugs(slugs)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		bySlug[item.Slug] = item
+	}
+
+	comparer, err := s.newComparer(user)
+	if err != nil {
+		return nil, err
+	}
+
+	comparisons := []*domain.ItemComparison{}
+	for _, slug := range slugs {
+		item, ok := bySlug[slug]
+		if !ok {
+			continue
+		}
+		// Repeated slugs are compared once.
+		delete(bySlug, slug)
+
+		price, _ := comparer.prices.price(item)
+		if comparison, ok := comparer.compare(item, price); ok {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+	return comparisons, nil
+}
+
+// SuggestUpgrades returns the best upgrade per slot among the items the user
+// can buy in their location, meets the level of and can afford. The best
+// upgrade adds the most attack, defense and hp together, the cheaper one
+// wins a tie. Slots without an upgrade are left out.
+func (s *ItemComparisonService) SuggestUpgrades(ctx context.Context, userID uuid.UUID) ([]*domain.ItemComparison, error) {
+	user, err := s.repos.Users.FindByID(userID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	items, err := s.repos.EquipmentItems.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	comparer, err := s.newComparer(user)
+	if err != nil {
+		return nil, err
+	}
+
+	best := make(map[string]*domain.ItemComparison)
+	for _, item := range items {
+		price, ok := comparer.prices.price(item)
+		if !ok {
+			continue
+		}
+
+		comparison, ok := comparer.compare(item, price)
+		if !ok || !comparison.MeetsLevel || !comparison.CanAfford || comparison.Delta.Score() <= 0 {
+			continue
+		}
+
+		current, ok := best[comparison.Slot]
+		if !ok || betterUpgrade(comparison, current) {
+			best[comparison.Slot] = comparison
+		}
+	}
+
+	upgrades := []*domain.ItemComparison{}
+	for _, slot := range comparer.registry {
+		if upgrade, ok := best[slot.Name]; ok {
+			upgrades = append(upgrades, upgrade)
+		}
+	}
+	return upgrades, nil
+}
+
+func betterUpgrade(a, b *domain.ItemComparison) bool {
+	if a.Delta.Score() != b.Delta.Score() {
+		return a.Delta.Score() > b.Delta.Score()
+	}
+	return a.Price < b.Price
+}
+
+// itemComparer holds what comparing items for one user needs: the slot
+// registry, the equipped instances by slot and the prices of the user's
+// location.
+type itemComparer struct {
+	user     *domain.User
+	registry []*domain.EquipmentSlot
+	equipped map[string]*domain.InventoryItem
+	prices   shopPrices
+}
+
+func (s *ItemComparisonService) newComparer(user *domain.User) (*itemComparer, error) {
+	registry, err := s.repos.EquipmentSlots.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := s.repos.Inventory.FindEquipped(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := findInstanceItems(s.repos, instances)
+	if err != nil {
+		return nil, err
+	}
+
+	equipped := make(map[string]*domain.InventoryItem, len(instances))
+	for _, instance := range instances {
+		if item, ok := items[instance.EquipmentItemID]; ok {
+			equipped[*instance.EquippedSlot] = &domain.InventoryItem{EquipmentItem: item, Instance: instance}
+		}
+	}
+
+	prices, err := findShopPrices(s.repos, user.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &itemComparer{user: user, registry: registry, equipped: equipped, prices: prices}, nil
+}
+
+// compare is false for items no slot takes.
+func (c *itemComparer) compare(item *domain.EquipmentItem, price uint) (*domain.ItemComparison, bool) {
+	var slots []*domain.EquipmentSlot
+	for _, slot := range c.registry {
+		if slot.EquipmentType == item.EquipmentType {
+			slots = append(slots, slot)
+		}
+	}
+
+	slot, ok := domain.ReplacedSlot(slots, c.equipped)
+	if !ok {
+		return nil, false
+	}
+
+	equipped := c.equipped[slot]
+	return &domain.ItemComparison{
+		Item:       item,
+		Slot:       slot,
+		Equipped:   equipped,
+		Delta:      domain.CompareItem(item, equipped),
+		Price:      price,
+		MeetsLevel: c.user.Level >= item.RequiredLevel,
+		CanAfford:  c.user.Gold >= price,
+	}, true
+}
+
+// shopPrices knows what a location asks for items: the stock price where the
+// location keeps stock, the base price for items no shop stocks.
+type shopPrices struct {
+	here    map[uuid.UUID]*domain.ShopStock
+	stocked map[uuid.UUID]bool
+}
+
+func findShopPrices(repos *repository.Repositories, locationID uuid.UUID) (shopPrices, error) {
+	stocks, err := repos.ShopStock.FindByLocationID(locationID)
+	if err != nil {
+		return shopPrices{}, err
+	}
+	stockedIDs, err := repos.ShopStock.FindStockedItemIDs()
+	if err != nil {
+		return shopPrices{}, err
+	}
+
+	prices := shopPrices{
+		here:    make(map[uuid.UUID]*domain.ShopStock, len(stocks)),
+		stocked: make(map[uuid.UUID]bool, len(stockedIDs)),
+	}
+	for _, stock := range stocks {
+		if stock.ConsumableID == nil {
+			prices.here[stock.EquipmentItemID] = stock
+		}
+	}
+	for _, id := range stockedIDs {
+		prices.stocked[id] = true
+	}
+	return prices, nil
+}
+
+// price returns what the item costs in the location, false when it can't be
+// bought there: another shop keeps it or this one is sold out.
+func (p shopPrices) price(item *domain.EquipmentItem) (uint, bool) {
+	if stock, ok := p.here[item.ID]; ok {
+		return stock.Price, stock.Quantity > 0
+	}
+	return item.Price, !p.stocked[item.ID]
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestItemComparisonService_Compare(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewItemComparisonService(testDB)
+
+	t.Run("empty slot", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		_, err := testDB.Exec(`UPDATE equipment_items SET attack = 7 WHERE id = $1`, item.ID)
+		require.NoError(t, err)
+
+		comparisons, err := service.Compare(ctx, user.ID, []string{item.Slug, "missing-slug", item.Slug})
+		require.NoError(t, err)
+		require.Len(t, comparisons, 1)
+		assert.Equal(t, item.ID, comparisons[0].Item.ID)
+		assert.Nil(t, comparisons[0].Equipped)
+		assert.Equal(t, 7, comparisons[0].Delta.Attack)
+		assert.True(t, comparisons[0].MeetsLevel)
+		assert.True(t, comparisons[0].CanAfford)
+	})
+
+	t.Run("no slugs", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		_, err := service.Compare(ctx, user.ID, nil)
+		assert.ErrorIs(t, err, ErrNoItemsToCompare)
+	})
+
+	t.Run("too many slugs", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		slugs := make([]string, MaxComparedItems+1)
+		for i := range slugs {
+			slugs[i] = fmt.Sprintf("slug-%d", i)
+		}
+		_, err := service.Compare(ctx, user.ID, slugs)
+		assert.ErrorIs(t, err, ErrTooManyItemsToCompare)
+	})
+}
+
+func TestItemComparisonService_SuggestUpgrades(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewItemComparisonService(testDB)
+
+	user, item := setupBuyTestData(t)
+	ts := time.Now().UnixNano()
+
+	stronger := &domain.EquipmentItem{
+		Name:                fmt.Sprintf("Great Sword %d", ts),
+		Slug:                fmt.Sprintf("great-sword-%d", ts),
+		Attack:              50000,
+		Price:               200,
+		RequiredLevel:       1,
+		EquipmentCategoryID: item.EquipmentCategoryID,
+	}
+	require.NoError(t, repository.NewEquipmentItemRepository(testDB).Create(stronger))
+	unaffordable := &domain.EquipmentItem{
+		Name:                fmt.Sprintf("Gold Sword %d", ts),
+		Slug:                fmt.Sprintf("gold-sword-%d", ts),
+		Attack:              90000,
+		Price:               100000,
+		RequiredLevel:       1,
+		EquipmentCategoryID: item.EquipmentCategoryID,
+	}
+	require.NoError(t, repository.NewEquipmentItemRepository(testDB).Create(unaffordable))
+
+	upgrades, err := service.SuggestUpgrades(ctx, user.ID)
+	require.NoError(t, err)
+
+	var weapon *domain.ItemComparison
+	for _, upgrade := range upgrades {
+		if upgrade.Item.EquipmentType == "weapon" {
+			weapon = upgrade
+		}
+	}
+	require.NotNil(t, weapon)
+	assert.Equal(t, stronger.ID, weapon.Item.ID)
+	assert.True(t, weapon.CanAfford)
+}
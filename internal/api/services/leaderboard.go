@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+const (
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+)
+
+var ErrUnknownLeaderboard = errors.New("unknown leaderboard")
+
+type LeaderboardRow struct {
+	Rank  int64
+	Score float64
+	User  *domain.User
+}
+
+type LeaderboardPage struct {
+	Kind  redis.LeaderboardKind
+	Page  int
+	Limit int
+	Total int64
+	Rows  []*LeaderboardRow
+	// Me is the requesting user's place, nil while unranked.
+	Me *redis.LeaderboardEntry
+}
+
+type LeaderboardService struct {
+	leaderboardRepo *repository.LeaderboardRepository
+	userRepo        *repository.UserRepository
+	leaderboard     *redis.Leaderboard
+}
+
+func NewLeaderboardService(
+	leaderboardRepo *repository.LeaderboardRepository,
+	userRepo *repository.UserRepository,
+	leaderboard *redis.Leaderboard,
+) *LeaderboardService {
+	return &LeaderboardService{
+		leaderboardRepo: leaderboardRepo,
+		userRepo:        userRepo,
+		leaderboard:     leaderboard,
+	}
+}
+
+// GetLeaderboard returns one page of the board, pages start at 1.
+func (s *LeaderboardService) GetLeaderboard(ctx context.Context, userID uuid.UUID, kind string, page, limit int) (*LeaderboardPage, error) {
+	boardKind, err := redis.ParseLeaderboardKind(kind)
+	if err != nil {
+		return nil, ErrUnknownLeaderboard
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultLeaderboardLimit
+	}
+	if limit > MaxLeaderboardLimit {
+		limit = MaxLeaderboardLimit
+	}
+
+	entries, err := s.leaderboard.Top(ctx, boardKind, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("read leaderboard: %w", err)
+	}
+
+	total, err := s.leaderboard.Count(ctx, boardKind)
+	if err != nil {
+		return nil, fmt.Errorf("count leaderboard: %w", err)
+	}
+
+	me, err := s.leaderboard.Rank(ctx, boardKind, userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("rank user: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		if id, err := uuid.Parse(entry.Member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	users, err := s.userRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.User, len(users))
+	for _, user := range users {
+		byID[user.ID.String()] = user
+	}
+
+	rows := make([]*LeaderboardRow, 0, len(entries))
+	for _, entry := range entries {
+		user, ok := byID[entry.Member]
+		if !ok {
+			continue
+		}
+		rows = append(rows, &LeaderboardRow{Rank: entry.Rank, Score: entry.Score, User: user})
+	}
+
+	return &LeaderboardPage{
+		Kind:  boardKind,
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Rows:  rows,
+		Me:    me,
+	}, nil
+}
+
+// HandleEvent counts kills when a fight is won and moves the level and exp
+// boards when a reward is committed. Gold follows the ledger, see
+// HandleGoldTransaction.
+func (s *LeaderboardService) HandleEvent(ctx context.Context, event domain.GameEvent) error {
+	member := event.UserID.String()
+
+	switch event.Type {
+	case domain.EventBotKilled:
+		return errors.Join(
+			s.leaderboard.Incr(ctx, redis.LeaderboardKills, member, 1),
+			s.leaderboard.Incr(ctx, redis.LeaderboardWeeklyKills, member, 1),
+		)
+	case domain.EventRewardEarned:
+		user, err := s.userRepo.FindByID(event.UserID)
+		if err != nil {
+			return fmt.Errorf("load user: %w", err)
+		}
+		return errors.Join(
+			s.leaderboard.Set(ctx, redis.LeaderboardLevel, member, float64(user.Level)),
+			s.leaderboard.Set(ctx, redis.LeaderboardExp, member, float64(user.Exp)),
+			s.leaderboard.Incr(ctx, redis.LeaderboardWeeklyExp, member, float64(event.Exp)),
+		)
+	default:
+		return nil
+	}
+}
+
+// HandleGoldTransaction moves the gold board to the balance after a committed
+// ledger entry, whatever changed the gold, and adds earned gold to the weekly
+// board.
+func (s *LeaderboardService) HandleGoldTransaction(ctx context.Context, transaction *domain.GoldTransaction) error {
+	member := transaction.UserID.String()
+
+	err := s.leaderboard.Set(ctx, redis.LeaderboardGold, member, float64(transaction.BalanceAfter))
+	if transaction.Reason.Earned() && transaction.Delta > 0 {
+		err = errors.Join(err, s.leaderboard.Incr(ctx, redis.LeaderboardWeeklyGold, member, float64(transaction.Delta)))
+	}
+	return err
+}
+
+// Rebuild replaces the boards with scores read from Postgres. Weekly exp
+// comes from finished fights and quest claims, weekly gold from the ledger.
+func (s *LeaderboardService) Rebuild(ctx context.Context, now time.Time) error {
+	totals, err := s.leaderboardRepo.FindUserTotals()
+	if err != nil {
+		return fmt.Errorf("load user totals: %w", err)
+	}
+
+	level := make(map[string]float64, len(totals))
+	exp := make(map[string]float64, len(totals))
+	gold := make(map[string]float64, len(totals))
+	for _, t := range totals {
+		member := t.UserID.String()
+		level[member] = float64(t.Level)
+		exp[member] = float64(t.Exp)
+		gold[member] = float64(t.Gold)
+	}
+
+	kills, err := s.leaderboardRepo.FindKillCounts(time.Time{})
+	if err != nil {
+		return fmt.Errorf("load kills: %w", err)
+	}
+
+	weekStart := startOfWeek(now)
+	weeklyKills, err := s.leaderboardRepo.FindKillCounts(weekStart)
+	if err != nil {
+		return fmt.Errorf("load weekly kills: %w", err)
+	}
+
+	weeklyExp, err := s.leaderboardRepo.FindEarnedExp(weekStart)
+	if err != nil {
+		return fmt.Errorf("load weekly exp: %w", err)
+	}
+
+	weeklyGold, err := s.leaderboardRepo.FindEarnedGold(weekStart)
+	if err != nil {
+		return fmt.Errorf("load weekly gold: %w", err)
+	}
+
+	return errors.Join(
+		s.leaderboard.Replace(ctx, redis.LeaderboardLevel, level),
+		s.leaderboard.Replace(ctx, redis.LeaderboardExp, exp),
+		s.leaderboard.Replace(ctx, redis.LeaderboardGold, gold),
+		s.leaderboard.Replace(ctx, redis.LeaderboardKills, killScores(kills)),
+		s.leaderboard.Replace(ctx, redis.LeaderboardWeeklyKills, killScores(weeklyKills)),
+		s.leaderboard.Replace(ctx, redis.LeaderboardWeeklyExp, userScores(weeklyExp)),
+		s.leaderboard.Replace(ctx, redis.LeaderboardWeeklyGold, userScores(weeklyGold)),
+	)
+}
+
+func userScores(scores []repository.UserScore) map[string]float64 {
+	result := make(map[string]float64, len(scores))
+	for _, s := range scores {
+		result[s.UserID.String()] = float64(s.Score)
+	}
+	return result
+}
+
+func killScores(counts []repository.KillCount) map[string]float64 {
+	scores := make(map[string]float64, len(counts))
+	for _, c := range counts {
+		scores[c.UserID.String()] = float64(c.Kills)
+	}
+	return scores
+}
+
+// startOfWeek returns Monday 00:00 UTC of the ISO week the time falls in.
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC().Truncate(24 * time.Hour)
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/redis"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func newTestLeaderboard(t *testing.T) *redis.Leaderboard {
+	t.Helper()
+	s := miniredis.RunT(t)
+	return redis.NewLeaderboard(goredis.NewClient(&goredis.Options{Addr: s.Addr()}), "leaderboard")
+}
+
+func TestLeaderboardService_HandleEvent(t *testing.T) {
+	board := newTestLeaderboard(t)
+	service := NewLeaderboardService(nil, nil, board)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventBotKilled, UserID: userID, Slug: "rat"}))
+	require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventBotKilled, UserID: userID, Slug: "rat"}))
+	require.NoError(t, service.HandleEvent(ctx, domain.GameEvent{Type: domain.EventLevelReached, UserID: userID}))
+
+	for _, kind := range []redis.LeaderboardKind{redis.LeaderboardKills, redis.LeaderboardWeeklyKills} {
+		rank, err := board.Rank(ctx, kind, userID.String())
+		require.NoError(t, err)
+		require.NotNil(t, rank)
+		assert.Equal(t, 2.0, rank.Score)
+	}
+}
+
+func TestLeaderboardService_HandleGoldTransaction(t *testing.T) {
+	board := newTestLeaderboard(t)
+	service := NewLeaderboardService(nil, nil, board)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for _, transaction := range []*domain.GoldTransaction{
+		{UserID: userID, Delta: 100, Reason: domain.GoldReasonQuestReward, BalanceAfter: 600},
+		{UserID: userID, Delta: 300, Reason: domain.GoldReasonAuctionSale, BalanceAfter: 900},
+		{UserID: userID, Delta: -250, Reason: domain.GoldReasonEquipmentBuy, BalanceAfter: 650},
+	} {
+		require.NoError(t, service.HandleGoldTransaction(ctx, transaction))
+	}
+
+	gold, err := board.Rank(ctx, redis.LeaderboardGold, userID.String())
+	require.NoError(t, err)
+	require.NotNil(t, gold)
+	assert.Equal(t, 650.0, gold.Score)
+
+	// Only rewards count as earned.
+	weekly, err := board.Rank(ctx, redis.LeaderboardWeeklyGold, userID.String())
+	require.NoError(t, err)
+	require.NotNil(t, weekly)
+	assert.Equal(t, 100.0, weekly.Score)
+}
+
+func TestLeaderboardService_GetLeaderboard(t *testing.T) {
+	t.Run("unknown kind", func(t *testing.T) {
+		service := NewLeaderboardService(nil, nil, newTestLeaderboard(t))
+
+		_, err := service.GetLeaderboard(context.Background(), uuid.New(), "hp", 1, 10)
+		assert.ErrorIs(t, err, ErrUnknownLeaderboard)
+	})
+
+	t.Run("page with my rank", func(t *testing.T) {
+		testutil.RequireDB(t, testDB)
+		ctx := context.Background()
+		board := newTestLeaderboard(t)
+		userRepo := repository.NewUserRepository(testDB)
+		service := NewLeaderboardService(repository.NewLeaderboardRepository(testDB), userRepo, board)
+
+		rich, _ := setupBuyTestData(t)
+		poor, _ := setupBuyTestData(t)
+		require.NoError(t, userRepo.Update(rich.ID, 1000, 0, rich.Level, rich.CurrentHp, domain.GoldReasonFightReward, nil))
+		require.NoError(t, userRepo.Update(poor.ID, 10, 0, poor.Level, poor.CurrentHp, domain.GoldReasonFightReward, nil))
+		require.NoError(t, service.HandleGoldTransaction(ctx, &domain.GoldTransaction{
+			UserID: rich.ID, Delta: 1000, Reason: domain.GoldReasonFightReward, BalanceAfter: int64(rich.Gold) + 1000,
+		}))
+		require.NoError(t, service.HandleGoldTransaction(ctx, &domain.GoldTransaction{
+			UserID: poor.ID, Delta: 10, Reason: domain.GoldReasonFightReward, BalanceAfter: int64(poor.Gold) + 10,
+		}))
+
+		page, err := service.GetLeaderboard(ctx, poor.ID, string(redis.LeaderboardGold), 1, 1)
+		require.NoError(t, err)
+		require.Len(t, page.Rows, 1)
+		assert.Equal(t, rich.ID, page.Rows[0].User.ID)
+		assert.Equal(t, float64(1500), page.Rows[0].Score)
+		assert.Equal(t, int64(2), page.Total)
+		require.NotNil(t, page.Me)
+		assert.Equal(t, int64(2), page.Me.Rank)
+
+		weekly, err := service.GetLeaderboard(ctx, poor.ID, string(redis.LeaderboardWeeklyGold), 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, float64(10), weekly.Me.Score)
+	})
+}
+
+func TestLeaderboardService_Rebuild(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	board := newTestLeaderboard(t)
+	userRepo := repository.NewUserRepository(testDB)
+	service := NewLeaderboardService(repository.NewLeaderboardRepository(testDB), userRepo, board)
+
+	user, _ := setupBuyTestData(t)
+	require.NoError(t, userRepo.Update(user.ID, 70, 0, user.Level, user.CurrentHp, domain.GoldReasonFightReward, nil))
+	_, err := repository.NewGoldTransactionRepository(testDB).Apply(user.ID, -20, domain.GoldReasonEquipmentBuy, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, service.Rebuild(ctx, time.Now()))
+
+	gold, err := board.Rank(ctx, redis.LeaderboardGold, user.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, gold)
+	assert.Equal(t, float64(user.Gold+50), gold.Score)
+
+	weekly, err := board.Rank(ctx, redis.LeaderboardWeeklyGold, user.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, weekly)
+	assert.Equal(t, 70.0, weekly.Score)
+}
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), startOfWeek(sunday))
+
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, startOfWeek(monday))
+}
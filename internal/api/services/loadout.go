@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrLoadoutNotFound    = errors.New("loadout not found")
+	ErrInvalidLoadoutName = errors.New("invalid loadout name")
+	ErrLoadoutNameTaken   = errors.New("loadout name already taken")
+	ErrTooManyLoadouts    = errors.New("too many loadouts")
+)
+
+// LoadoutService saves the user's equipment as named loadouts and puts a
+// saved loadout on in one go.
+type LoadoutService struct {
+	uow   *repository.UnitOfWork
+	repos *repository.Repositories
+}
+
+func NewLoadoutService(db *sqlx.DB) *LoadoutService {
+	return &LoadoutService{
+		uow:   repository.NewUnitOfWork(db),
+		repos: repository.NewRepositories(db),
+	}
+}
+
+func (s *LoadoutService) GetLoadouts(ctx context.Context, userID uuid.UUID) ([]*domain.Loadout, error) {
+	loadouts, err := s.repos.Loadouts.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadLoadoutItems(s.repos, loadouts); err != nil {
+		return nil, err
+	}
+
+	return loadouts, nil
+}
+
+// Save stores what the user has equipped right now as a new loadout.
+func (s *LoadoutService) Save(ctx context.Context, userID uuid.UUID, name string) (*domain.Loadout, error) {
+	name, ok := domain.NormalizeLoadoutName(name)
+	if !ok {
+		return nil, ErrInvalidLoadoutName
+	}
+
+	loadout := &domain.Loadout{UserID: userID, Name: name}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		count, err := repos.Loadouts.CountByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if count >= domain.MaxLoadouts {
+			return ErrTooManyLoadouts
+		}
+
+		if loadout.Slots, err = snapshotSlots(repos, user); err != nil {
+			return err
+		}
+
+		if err := repos.Loadouts.Create(loadout); err != nil {
+			if errors.Is(err, repository.ErrLoadoutNameExists) {
+				return ErrLoadoutNameTaken
+			}
+			return err
+		}
+
+		return loadLoadoutItems(repos, []*domain.Loadout{loadout})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loadout, nil
+}
+
+// Overwrite replaces the saved slots of the loadout with what the user has
+// equipped right now.
+func (s *LoadoutService) Overwrite(ctx context.Context, userID, loadoutID uuid.UUID) (*domain.Loadout, error) {
+	var loadout *domain.Loadout
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		loadout, err = findLoadoutForUpdate(repos, userID, loadoutID)
+		if err != nil {
+			return err
+		}
+
+		if loadout.Slots, err = snapshotSlots(repos, user); err != nil {
+			return err
+		}
+		if err := repos.Loadouts.ReplaceSlots(loadout); err != nil {
+			return err
+		}
+
+		return loadLoadoutItems(repos, []*domain.Loadout{loadout})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loadout, nil
+}
+
+func (s *LoadoutService) Delete(ctx context.Context, userID, loadoutID uuid.UUID) error {
+	if err := s.repos.Loadouts.Delete(userID, loadoutID); err != nil {
+		if errors.Is(err, repository.ErrLoadoutNotFound) {
+			return ErrLoadoutNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Apply puts the loadout on in one transaction. Slots the loadout leaves
+// empty are emptied, the rest get the saved instance or, when it is gone,
+// another copy of the item from the bag. Slots whose item the user doesn't
+// have any more stay empty and are returned as missing.
+func (s *LoadoutService) Apply(ctx context.Context, userID, loadoutID uuid.UUID) (*domain.Loadout, []*domain.LoadoutSlot, error) {
+	var loadout *domain.Loadout
+	var missing []*domain.LoadoutSlot
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		missing = nil
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		loadout, err = findLoadoutForUpdate(repos, userID, loadoutID)
+		if err != nil {
+			return err
+		}
+
+		slots, err := repos.EquipmentSlots.FindAll()
+		if err != nil {
+			return err
+		}
+
+		// Everything the loadout changes comes off first, so instances moving
+		// between slots are back in the bag before they are put on again.
+		var toEquip []*domain.LoadoutSlot
+		for _, registered := range slots {
+			slot := registered.Name
+			want := loadout.Slot(slot)
+			current := user.EquippedItemID(slot)
+
+			if current != nil {
+				keep, err := keepsSlot(repos, userID, slot, *current, want)
+				if err != nil {
+					return err
+				}
+				if keep {
+					continue
+				}
+				if err := takeOffSlot(repos, userID, slot, *current); err != nil {
+					return err
+				}
+			}
+
+			if want != nil {
+				toEquip = append(toEquip, want)
+			}
+		}
+
+		for _, want := range toEquip {
+			instance, err := findLoadoutInstance(repos, userID, want)
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				missing = append(missing, want)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := putOnSlot(repos, userID, want.Slot, instance); err != nil {
+				return err
+			}
+		}
+
+		// Taking off more than is put on can overfill the bag.
+		used, err := repos.Inventory.CountBagSlots(userID)
+		if err != nil {
+			return err
+		}
+		if used > user.InventoryCapacity {
+			return ErrInventoryFull
+		}
+
+		if err := loadLoadoutItems(repos, []*domain.Loadout{loadout}); err != nil {
+			return err
+		}
+
+		_, err = NewStatsCalculator(repos).Recalculate(userID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return loadout, missing, nil
+}
+
+// snapshotSlots records the item and the instance in every filled slot.
+func snapshotSlots(repos *repository.Repositories, user *domain.User) ([]*domain.LoadoutSlot, error) {
+	registry, err := repos.EquipmentSlots.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := repos.Inventory.FindEquipped(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	bySlot := make(map[string]*domain.Inventory, len(instances))
+	for _, instance := range instances {
+		bySlot[*instance.EquippedSlot] = instance
+	}
+
+	var slots []*domain.LoadoutSlot
+	for _, registered := range registry {
+		name := registered.Name
+		itemID := user.EquippedItemID(name)
+		if itemID == nil {
+			continue
+		}
+
+		slot := &domain.LoadoutSlot{Slot: name, EquipmentItemID: *itemID}
+		if instance, ok := bySlot[name]; ok && instance.EquipmentItemID == *itemID {
+			slot.InventoryID = &instance.ID
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// keepsSlot reports whether what is in the slot already is what the loadout
+// wants there.
+func keepsSlot(repos *repository.Repositories, userID uuid.UUID, slot string, current uuid.UUID, want *domain.LoadoutSlot) (bool, error) {
+	if want == nil || want.EquipmentItemID != current {
+		return false, nil
+	}
+	if want.InventoryID == nil {
+		return true, nil
+	}
+
+	instance, err := repos.Inventory.FindEquippedBySlot(userID, slot)
+	if errors.Is(err, repository.ErrInventoryNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return instance.ID == *want.InventoryID, nil
+}
+
+// findLoadoutInstance picks the saved instance when it is still free in the
+// bag, or the best kept copy of the item otherwise.
+func findLoadoutInstance(repos *repository.Repositories, userID uuid.UUID, want *domain.LoadoutSlot) (*domain.Inventory, error) {
+	if want.InventoryID != nil {
+		instance, err := repos.Inventory.FindFreeByIDForUpdate(userID, *want.InventoryID)
+		if err == nil && instance.EquipmentItemID == want.EquipmentItemID {
+			return instance, nil
+		}
+		if err != nil && !errors.Is(err, repository.ErrItemNotInInventory) {
+			return nil, err
+		}
+	}
+
+	return repos.Inventory.FindUnequippedForUpdate(userID, want.EquipmentItemID)
+}
+
+func takeOffSlot(repos *repository.Repositories, userID uuid.UUID, slot string, itemID uuid.UUID) error {
+	if err := unequipSlot(repos, userID, slot, itemID); err != nil {
+		return err
+	}
+	return repos.Users.UpdateEquipmentSlot(userID, slot, nil)
+}
+
+func putOnSlot(repos *repository.Repositories, userID uuid.UUID, slot string, instance *domain.Inventory) error {
+	instance, err := repos.Inventory.SplitOne(instance)
+	if err != nil {
+		return err
+	}
+	if err := repos.Inventory.SetEquippedSlot(instance.ID, &slot); err != nil {
+		return err
+	}
+	return repos.Users.UpdateEquipmentSlot(userID, slot, &instance.EquipmentItemID)
+}
+
+func findLoadoutForUpdate(repos *repository.Repositories, userID, loadoutID uuid.UUID) (*domain.Loadout, error) {
+	loadout, err := repos.Loadouts.FindByIDForUpdate(userID, loadoutID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLoadoutNotFound) {
+			return nil, ErrLoadoutNotFound
+		}
+		return nil, err
+	}
+	return loadout, nil
+}
+
+// loadLoadoutItems fills the item of every saved slot.
+func loadLoadoutItems(repos *repository.Repositories, loadouts []*domain.Loadout) error {
+	var ids []uuid.UUID
+	for _, loadout := range loadouts {
+		for _, slot := range loadout.Slots {
+			ids = append(ids, slot.EquipmentItemID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	items, err := repos.EquipmentItems.FindByIDs(uniqueIDs(ids))
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for _, loadout := range loadouts {
+		for _, slot := range loadout.Slots {
+			slot.Item = byID[slot.EquipmentItemID]
+		}
+	}
+	return nil
+}
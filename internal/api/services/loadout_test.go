@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestLoadoutService(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewLoadoutService(testDB)
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+	userRepo := repository.NewUserRepository(testDB)
+	takeOn := NewEquipmentItemTakeOnService(testDB, repository.NewEquipmentItemRepository(testDB), inventoryRepo, userRepo)
+	takeOff := NewEquipmentItemTakeOffService(testDB, repository.NewEquipmentItemRepository(testDB), inventoryRepo, userRepo)
+
+	setup := func(t *testing.T) (*domain.User, *domain.EquipmentItem) {
+		user, item := setupBuyTestData(t)
+		require.NoError(t, inventoryRepo.Create(&domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}))
+		require.NoError(t, takeOn.TakeOnEquipmentItem(ctx, user.ID, item.ID))
+		return user, item
+	}
+
+	t.Run("apply puts the saved gear back on", func(t *testing.T) {
+		user, item := setup(t)
+		loadout, err := service.Save(ctx, user.ID, fmt.Sprintf("Damage %d", time.Now().UnixNano()))
+		require.NoError(t, err)
+		require.Len(t, loadout.Slots, 1)
+		assert.Equal(t, "weapon", loadout.Slots[0].Slot)
+		assert.NotNil(t, loadout.Slots[0].InventoryID)
+
+		require.NoError(t, takeOff.TakeOffEquipmentItem(ctx, user.ID, "weapon"))
+
+		_, missing, err := service.Apply(ctx, user.ID, loadout.ID)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &item.ID, userAfter.EquippedItemID("weapon"))
+
+		equipped, err := inventoryRepo.FindEquippedBySlot(user.ID, "weapon")
+		require.NoError(t, err)
+		assert.Equal(t, *loadout.Slots[0].InventoryID, equipped.ID)
+	})
+
+	t.Run("empty loadout takes everything off", func(t *testing.T) {
+		user, item := setup(t)
+		require.NoError(t, takeOff.TakeOffEquipmentItem(ctx, user.ID, "weapon"))
+		loadout, err := service.Save(ctx, user.ID, "Naked")
+		require.NoError(t, err)
+		assert.Empty(t, loadout.Slots)
+		require.NoError(t, takeOn.TakeOnEquipmentItem(ctx, user.ID, item.ID))
+
+		_, missing, err := service.Apply(ctx, user.ID, loadout.ID)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, userAfter.EquippedItemID("weapon"))
+		bag, err := inventoryRepo.FindByUserID(user.ID)
+		require.NoError(t, err)
+		assert.Len(t, bag, 1)
+	})
+
+	t.Run("missing items are reported", func(t *testing.T) {
+		user, item := setup(t)
+		loadout, err := service.Save(ctx, user.ID, "Tank")
+		require.NoError(t, err)
+
+		require.NoError(t, takeOff.TakeOffEquipmentItem(ctx, user.ID, "weapon"))
+		require.NoError(t, inventoryRepo.RemoveOne(user.ID, item.ID))
+
+		_, missing, err := service.Apply(ctx, user.ID, loadout.ID)
+		require.NoError(t, err)
+		require.Len(t, missing, 1)
+		assert.Equal(t, "weapon", missing[0].Slot)
+		assert.Equal(t, item.ID, missing[0].Item.ID)
+
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, userAfter.EquippedItemID("weapon"))
+	})
+
+	t.Run("names are unique per user", func(t *testing.T) {
+		user, _ := setup(t)
+		_, err := service.Save(ctx, user.ID, "Tank")
+		require.NoError(t, err)
+
+		_, err = service.Save(ctx, user.ID, " Tank ")
+		assert.ErrorIs(t, err, ErrLoadoutNameTaken)
+
+		_, err = service.Save(ctx, user.ID, "")
+		assert.ErrorIs(t, err, ErrInvalidLoadoutName)
+	})
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/events"
+	"moonshine/internal/repository"
+)
+
+// mailExpireBatch is how many expired mails one worker run handles.
+const mailExpireBatch = 100
+
+var (
+	ErrMailNotFound       = errors.New("mail not found")
+	ErrMailToSelf         = errors.New("can't send mail to yourself")
+	ErrRecipientNotFound  = errors.New("recipient not found")
+	ErrInvalidMailSubject = errors.New("invalid mail subject")
+	ErrMailBodyTooLong    = errors.New("mail body too long")
+	ErrTooManyAttachments = errors.New("too many attachments")
+	ErrNothingToCollect   = errors.New("mail has nothing to collect")
+	ErrMailHasAttachments = errors.New("mail has uncollected attachments")
+)
+
+// MailService runs the mailboxes. Attached instances are held in escrow and
+// attached gold is taken from the sender right away, the recipient gets both
+// on collecting. Expired mail is handled by the mail worker.
+type MailService struct {
+	uow   *repository.UnitOfWork
+	repos *repository.Repositories
+	hub   *ws.Hub
+}
+
+func NewMailService(db *sqlx.DB, hub *ws.Hub) *MailService {
+	return &MailService{
+		uow:   repository.NewUnitOfWork(db),
+		repos: repository.NewRepositories(db),
+		hub:   hub,
+	}
+}
+
+// List returns the user's mailbox with the attachments, newest first.
+func (s *MailService) List(ctx context.Context, userID uuid.UUID) ([]*domain.Mail, error) {
+	mails, err := s.repos.Mails.FindByRecipientID(userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadMailItems(s.repos, mails); err != nil {
+		return nil, err
+	}
+
+	return mails, nil
+}
+
+func (s *MailService) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repos.Mails.CountUnread(userID, time.Now().UTC())
+}
+
+// Read marks the mail read and returns it.
+func (s *MailService) Read(ctx context.Context, userID, mailID uuid.UUID) (*domain.Mail, error) {
+	var mail *domain.Mail
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		mail, err = findMailForUpdate(repos, userID, mailID)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Mails.MarkRead(mail); err != nil {
+			return err
+		}
+		return loadMailItems(repos, []*domain.Mail{mail})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mail, nil
+}
+
+// Send mails the subject and body to the player with the username. The gold
+// is taken and the instances leave the sender's bag right away, stacks go
+// whole.
+func (s *MailService) Send(ctx context.Context, senderID uuid.UUID, recipientName, subject, body string, gold uint, instanceIDs []uuid.UUID) (*domain.Mail, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" || utf8.RuneCountInString(subject) > domain.MaxMailSubjectLength {
+		return nil, ErrInvalidMailSubject
+	}
+	if utf8.RuneCountInString(body) > domain.MaxMailBodyLength {
+		return nil, ErrMailBodyTooLong
+	}
+	instanceIDs = uniqueIDs(instanceIDs)
+	if len(instanceIDs) > domain.MaxMailAttachments {
+		return nil, ErrTooManyAttachments
+	}
+
+	recipient, err := s.repos.Users.FindByUsername(recipientName)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+	if recipient.ID == senderID {
+		return nil, ErrMailToSelf
+	}
+
+	mail := &domain.Mail{
+		SenderID:    &senderID,
+		RecipientID: recipient.ID,
+		Subject:     subject,
+		Body:        body,
+		Gold:        gold,
+		ExpiresAt:   time.Now().UTC().Add(domain.MailLifetime),
+	}
+	err = s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		sender, err := repos.Users.FindByIDForUpdate(senderID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+		mail.SenderName = sender.Username
+
+		if err := repos.Mails.Create(mail); err != nil {
+			return err
+		}
+
+		if gold > 0 {
+			if _, err := repos.GoldTransactions.Apply(senderID, -int64(gold), domain.GoldReasonMailSend, &mail.ID); err != nil {
+				if errors.Is(err, repository.ErrInsufficientGold) {
+					return ErrInsufficientGold
+				}
+				return err
+			}
+		}
+
+		if err := repos.Inventory.EscrowForMail(mail.ID, senderID, instanceIDs); err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrItemNotInInventory
+			}
+			return err
+		}
+
+		return loadMailItems(repos, []*domain.Mail{mail})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(mail)
+	return mail, nil
+}
+
+// Collect moves the attached gold and instances to the recipient. The bag
+// needs a free slot for every attached instance, each collected item counts
+// as acquired.
+func (s *MailService) Collect(ctx context.Context, userID, mailID uuid.UUID) (*domain.Mail, error) {
+	var mail *domain.Mail
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		mail, err = findMailForUpdate(repos, userID, mailID)
+		if err != nil {
+			return err
+		}
+		if err := loadMailItems(repos, []*domain.Mail{mail}); err != nil {
+			return err
+		}
+		if !mail.HasAttachments() {
+			return ErrNothingToCollect
+		}
+
+		if len(mail.Items) > 0 {
+			used, err := repos.Inventory.CountBagSlots(userID)
+			if err != nil {
+				return err
+			}
+			if used+uint(len(mail.Items)) > user.InventoryCapacity {
+				return ErrInventoryFull
+			}
+			if err := repos.Inventory.ReleaseMailEscrow(mail.ID, userID); err != nil {
+				return err
+			}
+		}
+
+		if mail.Gold > 0 {
+			if _, err := repos.GoldTransactions.Apply(userID, int64(mail.Gold), domain.GoldReasonMailCollect, &mail.ID); err != nil {
+				return err
+			}
+		}
+
+		return repos.Mails.MarkCollected(mail)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range mail.Items {
+		if item.EquipmentItem == nil {
+			continue
+		}
+		events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventItemAcquired, UserID: userID, Slug: item.EquipmentItem.Slug})
+	}
+	return mail, nil
+}
+
+// Delete removes the mail from the mailbox, mail with uncollected
+// attachments can't be deleted.
+func (s *MailService) Delete(ctx context.Context, userID, mailID uuid.UUID) error {
+	return s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		mail, err := findMailForUpdate(repos, userID, mailID)
+		if err != nil {
+			return err
+		}
+		if err := loadMailItems(repos, []*domain.Mail{mail}); err != nil {
+			return err
+		}
+		if mail.HasAttachments() {
+			return ErrMailHasAttachments
+		}
+
+		return repos.Mails.Delete(mail.ID)
+	})
+}
+
+// ExpireMail handles the mail that expired before now and returns how many
+// were handled. Player mail with uncollected attachments goes back to the
+// sender as returned mail, the rest is deleted with what is still attached.
+func (s *MailService) ExpireMail(ctx context.Context, now time.Time) (int, error) {
+	var returned []*domain.Mail
+	var count int
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		returned, count = nil, 0
+		mails, err := repos.Mails.FindExpiredForUpdate(now, mailExpireBatch)
+		if err != nil {
+			return err
+		}
+		if err := loadMailItems(repos, mails); err != nil {
+			return err
+		}
+
+		for _, mail := range mails {
+			if mail.Returnable() {
+				back, err := returnMail(repos, mail, now)
+				if err != nil {
+					return err
+				}
+				returned = append(returned, back)
+			} else if err := repos.Inventory.DeleteMailed(mail.ID); err != nil {
+				return err
+			}
+
+			if err := repos.Mails.Delete(mail.ID); err != nil {
+				return err
+			}
+		}
+
+		count = len(mails)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, mail := range returned {
+		s.notify(mail)
+	}
+	return count, nil
+}
+
+// returnMail sends the attachments of the expired mail back to its sender.
+func returnMail(repos *repository.Repositories, mail *domain.Mail, now time.Time) (*domain.Mail, error) {
+	recipient, err := repos.Users.FindByID(mail.RecipientID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	back := &domain.Mail{
+		SenderID:    &mail.RecipientID,
+		SenderName:  recipient.Username,
+		RecipientID: *mail.SenderID,
+		Subject:     truncateRunes("Returned: "+mail.Subject, domain.MaxMailSubjectLength),
+		Body:        mail.Body,
+		Gold:        mail.Gold,
+		Returned:    true,
+		ExpiresAt:   now.Add(domain.MailLifetime),
+	}
+	if err := repos.Mails.Create(back); err != nil {
+		return nil, err
+	}
+	if err := repos.Inventory.MoveMailEscrow(mail.ID, back.ID); err != nil {
+		return nil, err
+	}
+
+	return back, nil
+}
+
+// SendSystemMail mails the recipient new copies of the items and the gold
+// from the game. It runs in the caller's transaction, the caller notifies the
+// recipient with NotifyNewMail after committing.
+func SendSystemMail(repos *repository.Repositories, recipientID uuid.UUID, subject, body string, gold uint, equipmentItemIDs []uuid.UUID) (*domain.Mail, error) {
+	if len(equipmentItemIDs) > domain.MaxMailAttachments {
+		return nil, ErrTooManyAttachments
+	}
+
+	mail := &domain.Mail{
+		RecipientID: recipientID,
+		Subject:     truncateRunes(subject, domain.MaxMailSubjectLength),
+		Body:        truncateRunes(body, domain.MaxMailBodyLength),
+		Gold:        gold,
+		ExpiresAt:   time.Now().UTC().Add(domain.MailLifetime),
+	}
+	if err := repos.Mails.Create(mail); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(equipmentItemIDs))
+	for _, itemID := range equipmentItemIDs {
+		instance := &domain.Inventory{UserID: recipientID, EquipmentItemID: itemID}
+		if err := repos.Inventory.Create(instance); err != nil {
+			return nil, err
+		}
+		ids = append(ids, instance.ID)
+	}
+	if err := repos.Inventory.EscrowForMail(mail.ID, recipientID, ids); err != nil {
+		return nil, err
+	}
+
+	return mail, nil
+}
+
+// NotifyNewMail tells the recipient over the hub that the mail arrived.
+func NotifyNewMail(hub *ws.Hub, mail *domain.Mail) {
+	data := ws.NewMailData{MailID: mail.ID.String(), From: mail.SenderName, Subject: mail.Subject, System: mail.System()}
+	if err := hub.SendNewMail(mail.RecipientID, data); err != nil {
+		log.Printf("[Mail] failed to notify %s of mail %s: %v", mail.RecipientID, mail.ID, err)
+	}
+}
+
+func (s *MailService) notify(mail *domain.Mail) {
+	NotifyNewMail(s.hub, mail)
+}
+
+func findMailForUpdate(repos *repository.Repositories, userID, mailID uuid.UUID) (*domain.Mail, error) {
+	mail, err := repos.Mails.FindByIDForUpdate(userID, mailID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMailNotFound) {
+			return nil, ErrMailNotFound
+		}
+		return nil, err
+	}
+	// The worker hasn't picked it up yet.
+	if mail.Expired(time.Now().UTC()) {
+		return nil, ErrMailNotFound
+	}
+
+	return mail, nil
+}
+
+// loadMailItems fills the attached instances of every mail.
+func loadMailItems(repos *repository.Repositories, mails []*domain.Mail) error {
+	if len(mails) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(mails))
+	for i, mail := range mails {
+		ids[i] = mail.ID
+		mail.Items = []*domain.InventoryItem{}
+	}
+
+	instances, err := repos.Inventory.FindMailed(ids)
+	if err != nil {
+		return err
+	}
+	items, err := findInstanceItems(repos, instances)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Mail, len(mails))
+	for _, mail := range mails {
+		byID[mail.ID] = mail
+	}
+	for _, instance := range instances {
+		if mail, ok := byID[*instance.EscrowMailID]; ok {
+			mail.Items = append(mail.Items, &domain.InventoryItem{EquipmentItem: items[instance.EquipmentItemID], Instance: instance})
+		}
+	}
+	return nil
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestMailService(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewMailService(testDB, ws.GetHub())
+	userRepo := repository.NewUserRepository(testDB)
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+
+	// setup returns a sender owning one item and a recipient.
+	setup := func(t *testing.T) (*domain.User, *domain.Inventory, *domain.User) {
+		sender, item := setupBuyTestData(t)
+		recipient, _ := setupBuyTestData(t)
+
+		instance := &domain.Inventory{UserID: sender.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+
+		return sender, instance, recipient
+	}
+
+	t.Run("send and collect", func(t *testing.T) {
+		sender, instance, recipient := setup(t)
+
+		mail, err := service.Send(ctx, sender.ID, recipient.Username, "Gift", "For you", 100, []uuid.UUID{instance.ID})
+		require.NoError(t, err)
+		assert.Len(t, mail.Items, 1)
+
+		senderAfter, err := userRepo.FindByID(sender.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sender.Gold-100, senderAfter.Gold)
+		_, err = inventoryRepo.FindByIDForUpdate(sender.ID, instance.ID)
+		assert.ErrorIs(t, err, repository.ErrInventoryNotFound)
+
+		unread, err := service.CountUnread(ctx, recipient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, unread)
+
+		err = service.Delete(ctx, recipient.ID, mail.ID)
+		assert.ErrorIs(t, err, ErrMailHasAttachments)
+
+		mail, err = service.Collect(ctx, recipient.ID, mail.ID)
+		require.NoError(t, err)
+		assert.True(t, mail.Read())
+
+		recipientAfter, err := userRepo.FindByID(recipient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, recipient.Gold+100, recipientAfter.Gold)
+		_, err = inventoryRepo.FindByIDForUpdate(recipient.ID, instance.ID)
+		require.NoError(t, err)
+
+		_, err = service.Collect(ctx, recipient.ID, mail.ID)
+		assert.ErrorIs(t, err, ErrNothingToCollect)
+		require.NoError(t, service.Delete(ctx, recipient.ID, mail.ID))
+	})
+
+	t.Run("expired mail returns to sender", func(t *testing.T) {
+		sender, instance, recipient := setup(t)
+
+		mail, err := service.Send(ctx, sender.ID, recipient.Username, "Gift", "", 50, []uuid.UUID{instance.ID})
+		require.NoError(t, err)
+		_, err = testDB.Exec(`UPDATE mails SET expires_at = $2 WHERE id = $1`, mail.ID, time.Now().UTC().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = service.ExpireMail(ctx, time.Now().UTC())
+		require.NoError(t, err)
+
+		mails, err := service.List(ctx, recipient.ID)
+		require.NoError(t, err)
+		assert.Empty(t, mails)
+
+		mails, err = service.List(ctx, sender.ID)
+		require.NoError(t, err)
+		require.Len(t, mails, 1)
+		assert.True(t, mails[0].Returned)
+		assert.Equal(t, uint(50), mails[0].Gold)
+		require.Len(t, mails[0].Items, 1)
+		assert.Equal(t, instance.ID, mails[0].Items[0].Instance.ID)
+
+		_, err = service.Collect(ctx, sender.ID, mails[0].ID)
+		require.NoError(t, err)
+		senderAfter, err := userRepo.FindByID(sender.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sender.Gold, senderAfter.Gold)
+	})
+
+	t.Run("system mail", func(t *testing.T) {
+		_, item := setupBuyTestData(t)
+		recipient, _ := setupBuyTestData(t)
+
+		var mail *domain.Mail
+		err := repository.NewUnitOfWork(testDB).WithTx(ctx, func(repos *repository.Repositories) error {
+			var err error
+			mail, err = SendSystemMail(repos, recipient.ID, "Reward", "Well done", 25, []uuid.UUID{item.ID})
+			return err
+		})
+		require.NoError(t, err)
+		assert.True(t, mail.System())
+
+		mail, err = service.Collect(ctx, recipient.ID, mail.ID)
+		require.NoError(t, err)
+		recipientAfter, err := userRepo.FindByID(recipient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, recipient.Gold+25, recipientAfter.Gold)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		sender, instance, recipient := setup(t)
+
+		_, err := service.Send(ctx, sender.ID, sender.Username, "Hi", "", 0, nil)
+		assert.ErrorIs(t, err, ErrMailToSelf)
+		_, err = service.Send(ctx, sender.ID, "nobody-"+uuid.NewString(), "Hi", "", 0, nil)
+		assert.ErrorIs(t, err, ErrRecipientNotFound)
+		_, err = service.Send(ctx, sender.ID, recipient.Username, "  ", "", 0, nil)
+		assert.ErrorIs(t, err, ErrInvalidMailSubject)
+		_, err = service.Send(ctx, sender.ID, recipient.Username, "Hi", "", sender.Gold+1, nil)
+		assert.ErrorIs(t, err, ErrInsufficientGold)
+		_, err = service.Send(ctx, recipient.ID, sender.Username, "Hi", "", 0, []uuid.UUID{instance.ID})
+		assert.ErrorIs(t, err, ErrItemNotInInventory)
+	})
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// LoadProgression reads the progression config from the file when a path is
+// given, otherwise takes the latest version stored in the database. The
+// built-in default is used when the database has none.
+func LoadProgression(path string, progressionRepo *repository.ProgressionRepository) (*domain.Progression, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read progression config: %w", err)
+		}
+		return domain.ParseProgression(data)
+	}
+
+	progression, err := progressionRepo.FindLatest()
+	if err != nil {
+		if errors.Is(err, repository.ErrProgressionNotFound) {
+			return domain.DefaultProgression(), nil
+		}
+		return nil, fmt.Errorf("load progression config: %w", err)
+	}
+
+	return progression, nil
+}
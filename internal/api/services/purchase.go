@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// shopPurchase describes one purchase from a shop. price returns the total
+// price for the user, deliver puts the goods into their inventory.
+type shopPurchase struct {
+	userID      uuid.UUID
+	slug        string
+	quantity    uint
+	reason      domain.GoldTransactionReason
+	referenceID uuid.UUID
+	price       func(user *domain.User) (uint, error)
+	deliver     func(user *domain.User) error
+}
+
+// purchase runs a shop purchase inside the caller's transaction: it delivers
+// the goods, advances the user's buy quests and takes the gold.
+func purchase(repos *repository.Repositories, p shopPurchase) error {
+	// The row lock makes concurrent purchases by the same user queue up
+	// behind each other, so the balance check below sees the latest gold.
+	user, err := repos.Users.FindByIDForUpdate(p.userID)
+	if err != nil {
+		return repository.ErrUserNotFound
+	}
+
+	price, err := p.price(user)
+	if err != nil {
+		return err
+	}
+
+	if user.Gold < price {
+		return ErrInsufficientGold
+	}
+
+	if err := p.deliver(user); err != nil {
+		return err
+	}
+
+	if err := repos.Quests.AddProgress(p.userID, domain.QuestObjectiveBuyItem, p.slug, p.quantity); err != nil {
+		return err
+	}
+
+	_, err = repos.GoldTransactions.Apply(p.userID, -int64(price), p.reason, &p.referenceID)
+	if errors.Is(err, repository.ErrInsufficientGold) {
+		return ErrInsufficientGold
+	}
+	return err
+}
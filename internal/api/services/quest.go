@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/events"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrQuestNotFound        = errors.New("quest not found")
+	ErrQuestAlreadyAccepted = errors.New("quest already accepted")
+	ErrQuestNotCompleted    = errors.New("quest not completed")
+	ErrQuestNotAvailable    = errors.New("quest not available")
+)
+
+type QuestService struct {
+	uow       *repository.UnitOfWork
+	questRepo *repository.QuestRepository
+	userRepo  *repository.UserRepository
+	hub       *ws.Hub
+}
+
+func NewQuestService(
+	db *sqlx.DB,
+	questRepo *repository.QuestRepository,
+	userRepo *repository.UserRepository,
+	hub *ws.Hub,
+) *QuestService {
+	return &QuestService{
+		uow:       repository.NewUnitOfWork(db),
+		questRepo: questRepo,
+		userRepo:  userRepo,
+		hub:       hub,
+	}
+}
+
+// GetQuests returns every quest together with the user's progress on it.
+// Quests with an objective nothing tracks are left out.
+func (s *QuestService) GetQuests(ctx context.Context, userID uuid.UUID) ([]*domain.QuestProgress, error) {
+	quests, err := s.questRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	userQuests, err := s.questRepo.FindUserQuests(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byQuestID := make(map[uuid.UUID]*domain.UserQuest, len(userQuests))
+	for _, userQuest := range userQuests {
+		byQuestID[userQuest.QuestID] = userQuest
+	}
+
+	result := make([]*domain.QuestProgress, 0, len(quests))
+	for _, quest := range quests {
+		if !quest.ObjectiveType.Tracked() {
+			continue
+		}
+		result = append(result, &domain.QuestProgress{Quest: quest, UserQuest: byQuestID[quest.ID]})
+	}
+
+	return result, nil
+}
+
+func (s *QuestService) AcceptQuest(ctx context.Context, userID uuid.UUID, slug string) (*domain.QuestProgress, error) {
+	quest, err := s.questRepo.FindBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestNotFound) {
+			return nil, ErrQuestNotFound
+		}
+		return nil, err
+	}
+	if !quest.ObjectiveType.Tracked() {
+		return nil, ErrQuestNotAvailable
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	if user.Level < quest.RequiredLevel {
+		return nil, ErrInsufficientLevel
+	}
+
+	userQuest, err := s.questRepo.Accept(userID, quest.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestAlreadyAccepted) {
+			return nil, ErrQuestAlreadyAccepted
+		}
+		return nil, err
+	}
+
+	return &domain.QuestProgress{Quest: quest, UserQuest: userQuest}, nil
+}
+
+// ClaimQuest pays out the rewards of a completed quest and returns the
+// updated user. A reward item that doesn't fit into the bag is mailed.
+func (s *QuestService) ClaimQuest(ctx context.Context, userID uuid.UUID, slug string) (*domain.User, error) {
+	quest, err := s.questRepo.FindBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestNotFound) {
+			return nil, ErrQuestNotFound
+		}
+		return nil, err
+	}
+
+	var user *domain.User
+	var item *domain.EquipmentItem
+	var mail *domain.Mail
+	var startLevel uint
+	err = s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		mail = nil
+		user, err = repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		if err := repos.Quests.MarkClaimed(userID, quest.ID); err != nil {
+			if errors.Is(err, repository.ErrQuestNotCompleted) {
+				return ErrQuestNotCompleted
+			}
+			return err
+		}
+		if err := repos.Quests.RecordClaim(userID, quest); err != nil {
+			return err
+		}
+
+		startLevel = user.Level
+		if err := awardUser(repos, user, quest.RewardGold, quest.RewardExp, domain.GoldReasonQuestReward, quest.ID); err != nil {
+			return err
+		}
+
+		if quest.RewardEquipmentItemID == nil {
+			return nil
+		}
+		item, err = repos.EquipmentItems.FindByID(*quest.RewardEquipmentItemID)
+		if err != nil {
+			return err
+		}
+
+		err = addToBag(repos, user, item)
+		if !errors.Is(err, ErrInventoryFull) {
+			return err
+		}
+		body := "Your bag was full, collect the reward of the quest here."
+		mail, err = SendSystemMail(repos, userID, "Quest reward: "+quest.Name, body, 0, []uuid.UUID{item.ID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if mail != nil {
+		NotifyNewMail(s.hub, mail)
+	}
+
+	events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventRewardEarned, UserID: userID, Gold: quest.RewardGold, Exp: quest.RewardExp})
+	if user.Level > startLevel {
+		events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventLevelReached, UserID: userID})
+	}
+	if item != nil && mail == nil {
+		events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventItemAcquired, UserID: userID, Slug: item.Slug})
+	}
+
+	return user, nil
+}
+
+// ResetDailyQuests makes daily quests accepted before the start of the
+// current UTC day available again. Completed quests wait for their claim.
+func (s *QuestService) ResetDailyQuests(ctx context.Context, now time.Time) (int64, error) {
+	return s.questRepo.ResetDaily(now.UTC().Truncate(24 * time.Hour))
+}
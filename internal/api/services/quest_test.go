@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func createTestQuest(t *testing.T, objective domain.QuestObjectiveType, targetSlug string, count uint) *domain.Quest {
+	t.Helper()
+	ts := time.Now().UnixNano()
+
+	quest := &domain.Quest{
+		Name:          fmt.Sprintf("Quest %d", ts),
+		Slug:          fmt.Sprintf("quest-%d", ts),
+		RequiredLevel: 1,
+		ObjectiveType: objective,
+		TargetSlug:    targetSlug,
+		TargetCount:   count,
+		RewardGold:    40,
+		RewardExp:     10,
+		Daily:         true,
+	}
+	require.NoError(t, repository.NewQuestRepository(testDB).Create(quest))
+	return quest
+}
+
+func newTestQuestService() *QuestService {
+	return NewQuestService(testDB, repository.NewQuestRepository(testDB), repository.NewUserRepository(testDB), ws.GetHub())
+}
+
+func TestQuestService_AcceptQuest(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestQuestService()
+
+	t.Run("success", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		quest := createTestQuest(t, domain.QuestObjectiveBuyItem, item.Slug, 1)
+
+		progress, err := service.AcceptQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, domain.QuestStatusInProgress, progress.UserQuest.Status)
+
+		_, err = service.AcceptQuest(ctx, user.ID, quest.Slug)
+		assert.ErrorIs(t, err, ErrQuestAlreadyAccepted)
+	})
+
+	t.Run("insufficient level", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		quest := createTestQuest(t, domain.QuestObjectiveBuyItem, item.Slug, 1)
+		_, err := testDB.Exec(`UPDATE quests SET required_level = 5 WHERE id = $1`, quest.ID)
+		require.NoError(t, err)
+
+		_, err = service.AcceptQuest(ctx, user.ID, quest.Slug)
+		assert.ErrorIs(t, err, ErrInsufficientLevel)
+	})
+
+	t.Run("untracked objective", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+		quest := &domain.Quest{
+			Name: "Gather", Slug: fmt.Sprintf("gather-%d", time.Now().UnixNano()), RequiredLevel: 1,
+			ObjectiveType: domain.QuestObjectiveGatherResource, TargetSlug: "wood", TargetCount: 1,
+		}
+		err := repository.NewQuestRepository(testDB).Create(quest)
+		assert.ErrorIs(t, err, repository.ErrQuestObjectiveNotTracked)
+
+		// Quests stored before the check can't be accepted either.
+		_, err = testDB.Exec(`INSERT INTO quests (name, slug, objective_type, target_slug, target_count)
+			VALUES ($1, $2, $3, $4, $5)`, quest.Name, quest.Slug, quest.ObjectiveType, quest.TargetSlug, quest.TargetCount)
+		require.NoError(t, err)
+		_, err = service.AcceptQuest(ctx, user.ID, quest.Slug)
+		assert.ErrorIs(t, err, ErrQuestNotAvailable)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		user, _ := setupBuyTestData(t)
+
+		_, err := service.AcceptQuest(ctx, user.ID, "nonexistent-quest")
+		assert.ErrorIs(t, err, ErrQuestNotFound)
+	})
+}
+
+func TestQuestService_ClaimQuest(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestQuestService()
+	buyService := NewEquipmentItemBuyService(testDB, repository.NewEquipmentItemRepository(testDB), repository.NewInventoryRepository(testDB), repository.NewUserRepository(testDB))
+
+	t.Run("buying completes the quest and claim pays out once", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		quest := createTestQuest(t, domain.QuestObjectiveBuyItem, item.Slug, 1)
+
+		_, err := service.AcceptQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+
+		_, err = service.ClaimQuest(ctx, user.ID, quest.Slug)
+		assert.ErrorIs(t, err, ErrQuestNotCompleted)
+
+		require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+
+		claimed, err := service.ClaimQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, uint(500-100+40), claimed.Gold)
+		assert.Equal(t, uint(10), claimed.Exp)
+
+		_, err = service.ClaimQuest(ctx, user.ID, quest.Slug)
+		assert.ErrorIs(t, err, ErrQuestNotCompleted)
+	})
+
+	t.Run("reward item goes to the bag or by mail when it is full", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		quest := createTestQuest(t, domain.QuestObjectiveBuyItem, item.Slug, 1)
+		_, err := testDB.Exec(`UPDATE quests SET reward_equipment_item_id = $2 WHERE id = $1`, quest.ID, item.ID)
+		require.NoError(t, err)
+
+		_, err = service.AcceptQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+		require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+		_, err = testDB.Exec(`UPDATE users SET inventory_capacity = 1 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+
+		_, err = service.ClaimQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+
+		mails, err := repository.NewMailRepository(testDB).FindByRecipientID(user.ID, time.Now().UTC())
+		require.NoError(t, err)
+		require.Len(t, mails, 1)
+		used, err := repository.NewInventoryRepository(testDB).CountBagSlots(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), used)
+	})
+}
+
+func TestQuestService_ResetDailyQuests(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := newTestQuestService()
+
+	t.Run("accepted quests can be taken again", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		quest := createTestQuest(t, domain.QuestObjectiveBuyItem, item.Slug, 1)
+
+		_, err := service.AcceptQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+
+		_, err = service.ResetDailyQuests(ctx, time.Now().Add(48*time.Hour))
+		require.NoError(t, err)
+
+		_, err = service.AcceptQuest(ctx, user.ID, quest.Slug)
+		assert.NoError(t, err)
+	})
+
+	t.Run("completed quests stay claimable", func(t *testing.T) {
+		buyService := NewEquipmentItemBuyService(testDB, repository.NewEquipmentItemRepository(testDB), repository.NewInventoryRepository(testDB), repository.NewUserRepository(testDB))
+		user, item := setupBuyTestData(t)
+		quest := createTestQuest(t, domain.QuestObjectiveBuyItem, item.Slug, 1)
+
+		_, err := service.AcceptQuest(ctx, user.ID, quest.Slug)
+		require.NoError(t, err)
+		require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+
+		_, err = service.ResetDailyQuests(ctx, time.Now().Add(48*time.Hour))
+		require.NoError(t, err)
+
+		_, err = service.ClaimQuest(ctx, user.ID, quest.Slug)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// WeaponShopLocationSlug is where items get repaired.
+const WeaponShopLocationSlug = "weapon_shop"
+
+var (
+	ErrInstanceNotFound = errors.New("item instance not found")
+	ErrItemNotDamaged   = errors.New("item is not damaged")
+	ErrNotInWeaponShop  = errors.New("repairs are only done in the weapon shop")
+)
+
+type RepairService struct {
+	uow          *repository.UnitOfWork
+	locationRepo *repository.LocationRepository
+}
+
+func NewRepairService(db *sqlx.DB, locationRepo *repository.LocationRepository) *RepairService {
+	return &RepairService{
+		uow:          repository.NewUnitOfWork(db),
+		locationRepo: locationRepo,
+	}
+}
+
+// RepairItem restores full durability of the user's instance for gold. An
+// equipped instance that was broken gives its stats back.
+func (s *RepairService) RepairItem(ctx context.Context, userID, instanceID uuid.UUID) (*domain.InventoryItem, error) {
+	shop, err := s.locationRepo.FindBySlug(WeaponShopLocationSlug)
+	if err != nil {
+		return nil, ErrNotInWeaponShop
+	}
+
+	var result *domain.InventoryItem
+	err = s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, err := repos.Users.FindByIDForUpdate(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+
+		if user.LocationID != shop.ID {
+			return ErrNotInWeaponShop
+		}
+
+		instance, err := repos.Inventory.FindByIDForUpdate(userID, instanceID)
+		if err != nil {
+			if errors.Is(err, repository.ErrInventoryNotFound) {
+				return ErrInstanceNotFound
+			}
+			return err
+		}
+
+		if instance.Durability == instance.MaxDurability {
+			return ErrItemNotDamaged
+		}
+
+		item, err := repos.EquipmentItems.FindByID(instance.EquipmentItemID)
+		if err != nil {
+			return ErrEquipmentItemNotFound
+		}
+
+		cost := instance.RepairCost(item)
+		_, err = repos.GoldTransactions.Apply(userID, -int64(cost), domain.GoldReasonEquipmentRepair, &instance.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrInsufficientGold) {
+				return ErrInsufficientGold
+			}
+			return err
+		}
+
+		wasBroken := instance.Broken()
+		if err := repos.Inventory.UpdateDurability(instance.ID, instance.MaxDurability); err != nil {
+			return err
+		}
+		instance.Durability = instance.MaxDurability
+
+		if wasBroken && instance.EquippedSlot != nil {
+			if _, err := NewStatsCalculator(repos).Recalculate(userID); err != nil {
+				return err
+			}
+		}
+
+		result = &domain.InventoryItem{EquipmentItem: item, Instance: instance}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
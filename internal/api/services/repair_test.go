@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestWorkingEquipment(t *testing.T) {
+	sword := &domain.EquipmentItem{Model: domain.Model{ID: uuid.New()}}
+	ring := &domain.EquipmentItem{Model: domain.Model{ID: uuid.New()}}
+	helmet := &domain.EquipmentItem{Model: domain.Model{ID: uuid.New()}}
+
+	instances := []*domain.Inventory{
+		{EquipmentItemID: sword.ID, Durability: 0, MaxDurability: 100},
+		{EquipmentItemID: ring.ID, Durability: 0, MaxDurability: 100},
+		{EquipmentItemID: ring.ID, Durability: 10, MaxDurability: 100},
+	}
+
+	working := workingEquipment([]*domain.EquipmentItem{sword, ring, helmet}, instances)
+	assert.Equal(t, []*domain.EquipmentItem{ring, helmet}, working)
+}
+
+func weaponShop(t *testing.T) *domain.Location {
+	t.Helper()
+	locationRepo := repository.NewLocationRepository(testDB)
+	if shop, err := locationRepo.FindBySlug(WeaponShopLocationSlug); err == nil {
+		return shop
+	}
+
+	shop := &domain.Location{Name: "Weapon shop", Slug: WeaponShopLocationSlug}
+	require.NoError(t, locationRepo.Create(shop))
+	return shop
+}
+
+func TestRepairService_RepairItem(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewRepairService(testDB, repository.NewLocationRepository(testDB))
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+
+	setup := func(t *testing.T, durability uint) (*domain.User, *domain.EquipmentItem, *domain.Inventory) {
+		user, item := setupBuyTestData(t)
+		instance := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+		require.NoError(t, inventoryRepo.UpdateDurability(instance.ID, durability))
+		return user, item, instance
+	}
+
+	t.Run("success", func(t *testing.T) {
+		user, _, instance := setup(t, 50)
+		require.NoError(t, repository.NewUserRepository(testDB).UpdateLocationID(user.ID, weaponShop(t).ID))
+
+		repaired, err := service.RepairItem(ctx, user.ID, instance.ID)
+		require.NoError(t, err)
+		assert.Equal(t, repaired.Instance.MaxDurability, repaired.Instance.Durability)
+
+		userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold-25, userAfter.Gold)
+	})
+
+	t.Run("outside the weapon shop", func(t *testing.T) {
+		weaponShop(t)
+		user, _, instance := setup(t, 50)
+
+		_, err := service.RepairItem(ctx, user.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrNotInWeaponShop)
+	})
+
+	t.Run("not damaged", func(t *testing.T) {
+		user, _, instance := setup(t, 100)
+		require.NoError(t, repository.NewUserRepository(testDB).UpdateLocationID(user.ID, weaponShop(t).ID))
+
+		_, err := service.RepairItem(ctx, user.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrItemNotDamaged)
+	})
+
+	t.Run("someone else's item", func(t *testing.T) {
+		_, _, instance := setup(t, 50)
+		other, _ := setupBuyTestData(t)
+		require.NoError(t, repository.NewUserRepository(testDB).UpdateLocationID(other.ID, weaponShop(t).ID))
+
+		_, err := service.RepairItem(ctx, other.ID, instance.ID)
+		assert.ErrorIs(t, err, ErrInstanceNotFound)
+	})
+}
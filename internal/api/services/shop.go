@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var ErrShopNotFound = errors.New("shop not found")
+
+// ShopService shows what NPC shops have in stock and restocks them.
+type ShopService struct {
+	uow   *repository.UnitOfWork
+	repos *repository.Repositories
+}
+
+func NewShopService(db *sqlx.DB) *ShopService {
+	return &ShopService{
+		uow:   repository.NewUnitOfWork(db),
+		repos: repository.NewRepositories(db),
+	}
+}
+
+// GetStock returns the shop's stock with the items and consumables.
+func (s *ShopService) GetStock(ctx context.Context, locationSlug string) ([]*domain.ShopStock, error) {
+	location, err := s.repos.Locations.FindBySlug(locationSlug)
+	if err != nil {
+		return nil, ErrShopNotFound
+	}
+
+	stocks, err := s.repos.ShopStock.FindByLocationID(location.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := findStockItems(s.repos, stocks)
+	if err != nil {
+		return nil, err
+	}
+	consumables, err := findStockConsumables(s.repos, stocks)
+	if err != nil {
+		return nil, err
+	}
+	for _, stock := range stocks {
+		if stock.ConsumableID != nil {
+			stock.Consumable = consumables[*stock.ConsumableID]
+		} else {
+			stock.Item = items[stock.EquipmentItemID]
+		}
+	}
+
+	return stocks, nil
+}
+
+// Restock refills every stock, moves prices when price drift is on and
+// records the new prices. It returns how many stocks were restocked.
+func (s *ShopService) Restock(ctx context.Context) (int, error) {
+	settings := domain.CurrentShopSettings()
+
+	count := 0
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		stocks, err := repos.ShopStock.FindAllForUpdate()
+		if err != nil {
+			return err
+		}
+
+		items, err := findStockItems(repos, stocks)
+		if err != nil {
+			return err
+		}
+		consumables, err := findStockConsumables(repos, stocks)
+		if err != nil {
+			return err
+		}
+
+		count = 0
+		for _, stock := range stocks {
+			var basePrice uint
+			if stock.ConsumableID != nil {
+				consumable, ok := consumables[*stock.ConsumableID]
+				if !ok {
+					continue
+				}
+				basePrice = consumable.Price
+			} else {
+				item, ok := items[stock.EquipmentItemID]
+				if !ok {
+					continue
+				}
+				basePrice = item.Price
+			}
+
+			sold := stock.SoldSinceRestock
+			stock.Restock(basePrice, settings.PriceDrift)
+			if err := repos.ShopStock.UpdateRestock(stock); err != nil {
+				return err
+			}
+
+			err := repos.ShopStock.CreatePriceHistory(&domain.ShopPriceHistory{
+				LocationID:      stock.LocationID,
+				EquipmentItemID: stock.EquipmentItemID,
+				ConsumableID:    stock.ConsumableID,
+				Price:           stock.Price,
+				Quantity:        stock.Quantity,
+				Sold:            sold,
+			})
+			if err != nil {
+				return err
+			}
+			count++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func findStockItems(repos *repository.Repositories, stocks []*domain.ShopStock) (map[uuid.UUID]*domain.EquipmentItem, error) {
+	ids := make([]uuid.UUID, 0, len(stocks))
+	for _, stock := range stocks {
+		if stock.ConsumableID == nil {
+			ids = append(ids, stock.EquipmentItemID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	items, err := repos.EquipmentItems.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID, nil
+}
+
+func findStockConsumables(repos *repository.Repositories, stocks []*domain.ShopStock) (map[uuid.UUID]*domain.Consumable, error) {
+	ids := make([]uuid.UUID, 0, len(stocks))
+	for _, stock := range stocks {
+		if stock.ConsumableID != nil {
+			ids = append(ids, *stock.ConsumableID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	consumables, err := repos.Consumables.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Consumable, len(consumables))
+	for _, consumable := range consumables {
+		byID[consumable.ID] = consumable
+	}
+	return byID, nil
+}
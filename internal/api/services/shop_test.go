@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestShopStock(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	buyService := NewEquipmentItemBuyService(testDB, repository.NewEquipmentItemRepository(testDB),
+		repository.NewInventoryRepository(testDB), repository.NewUserRepository(testDB))
+	shopService := NewShopService(testDB)
+	stockRepo := repository.NewShopStockRepository(testDB)
+	userRepo := repository.NewUserRepository(testDB)
+
+	// stock puts quantity copies of the item into the user's location at the
+	// given price.
+	stock := func(t *testing.T, user *domain.User, item *domain.EquipmentItem, quantity, price uint) *domain.ShopStock {
+		s := &domain.ShopStock{
+			LocationID:      user.LocationID,
+			EquipmentItemID: item.ID,
+			Quantity:        quantity,
+			MaxQuantity:     2,
+			RestockAmount:   1,
+			Price:           price,
+		}
+		require.NoError(t, stockRepo.Create(s))
+		return s
+	}
+
+	t.Run("buys at the shop price until out of stock", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		stock(t, user, item, 1, 150)
+
+		require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold-150, userAfter.Gold)
+
+		err = buyService.BuyEquipmentItem(ctx, user.ID, item.Slug)
+		assert.ErrorIs(t, err, ErrOutOfStock)
+	})
+
+	t.Run("stocked items are only sold in their shops", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		other, _ := setupBuyTestData(t)
+		stock(t, other, item, 2, 100)
+
+		err := buyService.BuyEquipmentItem(ctx, user.ID, item.Slug)
+		assert.ErrorIs(t, err, ErrItemNotSoldHere)
+	})
+
+	t.Run("restock refills and records prices", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		s := stock(t, user, item, 2, item.Price)
+		require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+		require.NoError(t, buyService.BuyEquipmentItem(ctx, user.ID, item.Slug))
+
+		settings := domain.CurrentShopSettings()
+		require.NoError(t, domain.SetShopSettings(domain.ShopSettings{SellBackPercent: 50, PriceDrift: true}))
+		t.Cleanup(func() { _ = domain.SetShopSettings(settings) })
+
+		_, err := shopService.Restock(ctx)
+		require.NoError(t, err)
+
+		history, err := stockRepo.FindPriceHistory(s.LocationID, item.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, uint(2), history[0].Sold)
+		assert.Equal(t, uint(1), history[0].Quantity)
+		assert.Equal(t, item.Price+item.Price/10, history[0].Price)
+	})
+
+	t.Run("lists the shop stock", func(t *testing.T) {
+		user, item := setupBuyTestData(t)
+		stock(t, user, item, 2, 120)
+
+		location, err := repository.NewLocationRepository(testDB).FindByID(user.LocationID)
+		require.NoError(t, err)
+
+		stocks, err := shopService.GetStock(ctx, location.Slug)
+		require.NoError(t, err)
+		require.Len(t, stocks, 1)
+		assert.Equal(t, item.ID, stocks[0].Item.ID)
+		assert.Equal(t, uint(120), stocks[0].Price)
+
+		_, err = shopService.GetStock(ctx, fmt.Sprintf("no-shop-%d", time.Now().UnixNano()))
+		assert.ErrorIs(t, err, ErrShopNotFound)
+	})
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// StatsCalculator derives a user's effective stats from the base stats, the
+// equipped instances, set bonuses and buffs. Anything that changes one of
+// them calls Recalculate instead of adjusting the stored stats. Buffs only
+// last for a fight, so they are passed to Calculate and never stored.
+type StatsCalculator struct {
+	repos *repository.Repositories
+}
+
+func NewStatsCalculator(repos *repository.Repositories) *StatsCalculator {
+	return &StatsCalculator{repos: repos}
+}
+
+func (c *StatsCalculator) Calculate(user *domain.User, buffs ...domain.StatBonus) (domain.Stats, error) {
+	instances, err := c.repos.Inventory.FindEquipped(user.ID)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+
+	ids := make([]uuid.UUID, len(instances))
+	for i, instance := range instances {
+		ids[i] = instance.EquipmentItemID
+	}
+
+	var items []*domain.EquipmentItem
+	if len(ids) > 0 {
+		items, err = c.repos.EquipmentItems.FindByIDs(ids)
+		if err != nil {
+			return domain.Stats{}, err
+		}
+	}
+
+	byID := make(map[uuid.UUID]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	equipped := make([]*domain.InventoryItem, 0, len(instances))
+	for _, instance := range instances {
+		if item, ok := byID[instance.EquipmentItemID]; ok {
+			equipped = append(equipped, &domain.InventoryItem{EquipmentItem: item, Instance: instance})
+		}
+	}
+
+	sets, err := c.repos.ItemSets.FindByEquipmentItemIDs(ids)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+
+	return domain.EffectiveStats(user.BaseStats(), equipped, sets, buffs...), nil
+}
+
+// Recalculate stores the user's effective stats and returns them.
+func (c *StatsCalculator) Recalculate(userID uuid.UUID) (domain.Stats, error) {
+	user, err := c.repos.Users.FindByID(userID)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+
+	stats, err := c.Calculate(user)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+
+	if err := c.repos.Users.UpdateStats(userID, stats); err != nil {
+		return domain.Stats{}, err
+	}
+
+	return stats, nil
+}
+
+// StatsChange is a user whose stored stats differed from the calculated ones.
+type StatsChange struct {
+	UserID   uuid.UUID
+	Username string
+	Before   domain.Stats
+	After    domain.Stats
+}
+
+type StatsRepairService struct {
+	uow      *repository.UnitOfWork
+	userRepo *repository.UserRepository
+}
+
+func NewStatsRepairService(db *sqlx.DB, userRepo *repository.UserRepository) *StatsRepairService {
+	return &StatsRepairService{
+		uow:      repository.NewUnitOfWork(db),
+		userRepo: userRepo,
+	}
+}
+
+// RecalculateAll recalculates every user, one transaction per user, and
+// returns the users whose stats changed.
+func (s *StatsRepairService) RecalculateAll(ctx context.Context) ([]StatsChange, error) {
+	ids, err := s.userRepo.FindAllIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []StatsChange{}
+	for _, id := range ids {
+		var change *StatsChange
+		err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+			change = nil
+			user, err := repos.Users.FindByIDForUpdate(id)
+			if err != nil {
+				return err
+			}
+
+			stats, err := NewStatsCalculator(repos).Calculate(user)
+			if err != nil {
+				return err
+			}
+			if stats == user.Stats() {
+				return nil
+			}
+
+			if err := repos.Users.UpdateStats(id, stats); err != nil {
+				return err
+			}
+			change = &StatsChange{UserID: id, Username: user.Username, Before: user.Stats(), After: stats}
+			return nil
+		})
+		if err != nil {
+			return changes, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	return changes, nil
+}
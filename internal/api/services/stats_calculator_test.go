@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestStatsCalculator_Recalculate(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+
+	user, item := setupBuyTestData(t)
+	_, err := testDB.Exec(`UPDATE equipment_items SET attack = 7, hp = 10 WHERE id = $1`, item.ID)
+	require.NoError(t, err)
+
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+	instance := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+	require.NoError(t, inventoryRepo.Create(instance))
+	slot := "weapon"
+	require.NoError(t, inventoryRepo.SetEquippedSlot(instance.ID, &slot))
+
+	stats, err := NewStatsCalculator(repository.NewRepositories(testDB)).Recalculate(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Stats{Attack: user.BaseAttack + 7, Defense: user.BaseDefense, Hp: user.BaseHp + 10}, stats)
+
+	userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stats, userAfter.Stats())
+}
+
+func TestStatsCalculator_Calculate_Buffs(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+
+	user, _ := setupBuyTestData(t)
+
+	stats, err := NewStatsCalculator(repository.NewRepositories(testDB)).Calculate(user,
+		domain.StatBonus{Attack: 5}, domain.StatBonus{Defense: 3})
+	require.NoError(t, err)
+	assert.Equal(t, domain.Stats{Attack: user.BaseAttack + 5, Defense: user.BaseDefense + 3, Hp: user.BaseHp}, stats)
+
+	userAfter, err := repository.NewUserRepository(testDB).FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Stats(), userAfter.Stats())
+}
+
+func TestStatsRepairService_RecalculateAll(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+
+	user, _ := setupBuyTestData(t)
+	_, err := testDB.Exec(`UPDATE users SET attack = attack + 40 WHERE id = $1`, user.ID)
+	require.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(testDB)
+	changes, err := NewStatsRepairService(testDB, userRepo).RecalculateAll(context.Background())
+	require.NoError(t, err)
+
+	var found bool
+	for _, change := range changes {
+		if change.UserID == user.ID {
+			found = true
+			assert.Equal(t, user.Attack+40, change.Before.Attack)
+			assert.Equal(t, user.Attack, change.After.Attack)
+		}
+	}
+	assert.True(t, found)
+
+	userAfter, err := userRepo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Attack, userAfter.Attack)
+}
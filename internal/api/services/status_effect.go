@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var ErrPlayerStunned = errors.New("player is stunned")
+
+// statusTick is what the active status effects do at the start of a round.
+type statusTick struct {
+	PlayerDamage  uint
+	BotDamage     uint
+	PlayerStunned bool
+	BotStunned    bool
+	PlayerShield  uint
+	BotShield     uint
+	PlayerBuff    domain.StatBonus
+	BotBuff       domain.StatBonus
+}
+
+func resolveStatusEffects(effects []*domain.StatusEffect) statusTick {
+	var tick statusTick
+	for _, effect := range effects {
+		if !effect.Active() {
+			continue
+		}
+
+		player := effect.Target == domain.FightParticipantPlayer
+		switch effect.EffectType {
+		case domain.StatusEffectPoison, domain.StatusEffectBleed:
+			if player {
+				tick.PlayerDamage += effect.Value
+			} else {
+				tick.BotDamage += effect.Value
+			}
+		case domain.StatusEffectStun:
+			if player {
+				tick.PlayerStunned = true
+			} else {
+				tick.BotStunned = true
+			}
+		case domain.StatusEffectShield:
+			if player {
+				tick.PlayerShield += effect.Value
+			} else {
+				tick.BotShield += effect.Value
+			}
+		case domain.StatusEffectAttackUp, domain.StatusEffectDefenseUp:
+			buff := &tick.BotBuff
+			if player {
+				buff = &tick.PlayerBuff
+			}
+			if effect.EffectType == domain.StatusEffectAttackUp {
+				buff.Attack += int(effect.Value)
+			} else {
+				buff.Defense += int(effect.Value)
+			}
+		}
+	}
+	return tick
+}
+
+// absorb splits damage into the part that gets through the shield and the
+// part the shield takes.
+func absorb(damage, shield uint) (uint, uint) {
+	if shield >= damage {
+		return 0, damage
+	}
+	return damage - shield, shield
+}
+
+// advanceStatusEffects spends the absorbed damage from shields and counts
+// every active effect down by one round. It returns the effects that changed.
+func advanceStatusEffects(effects []*domain.StatusEffect, playerAbsorbed, botAbsorbed uint) []*domain.StatusEffect {
+	var changed []*domain.StatusEffect
+	for _, effect := range effects {
+		if !effect.Active() {
+			continue
+		}
+
+		if effect.EffectType == domain.StatusEffectShield {
+			absorbed := &botAbsorbed
+			if effect.Target == domain.FightParticipantPlayer {
+				absorbed = &playerAbsorbed
+			}
+			spent := min(effect.Value, *absorbed)
+			effect.Value -= spent
+			*absorbed -= spent
+		}
+
+		effect.RoundsLeft--
+		if effect.EffectType == domain.StatusEffectShield && effect.Value == 0 {
+			effect.RoundsLeft = 0
+		}
+		changed = append(changed, effect)
+	}
+	return changed
+}
+
+func rollStatusEffect(source domain.StatusEffectSource) bool {
+	return source.HasStatusEffect() && rollChance(source.StatusEffectChance)
+}
+
+// newStatusEffects returns the effects applied during the round: the weapon
+// and the bot ability on a hit that dealt damage, the consumable always.
+func newStatusEffects(fightID, roundID uuid.UUID, weapon *domain.EquipmentItem, bot *domain.Bot, consumable *domain.Consumable, outcome roundOutcome) []*domain.StatusEffect {
+	var effects []*domain.StatusEffect
+	if weapon != nil && outcome.PlayerDamage > 0 && rollStatusEffect(weapon.StatusEffectSource) {
+		effects = append(effects, weapon.NewStatusEffect(fightID, roundID, domain.FightParticipantPlayer))
+	}
+	if outcome.BotDamage > 0 && rollStatusEffect(bot.StatusEffectSource) {
+		effects = append(effects, bot.NewStatusEffect(fightID, roundID, domain.FightParticipantBot))
+	}
+	if consumable != nil && consumable.HasStatusEffect() {
+		effects = append(effects, consumable.NewStatusEffect(fightID, roundID, domain.FightParticipantPlayer))
+	}
+	return effects
+}
+
+// attachStatusEffects puts every effect on the round it was applied in and
+// the still active ones on the fight while it is in progress.
+func attachStatusEffects(fight *domain.Fight, effects []*domain.StatusEffect) {
+	byRound := make(map[uuid.UUID][]*domain.StatusEffect)
+	fight.Effects = []*domain.StatusEffect{}
+	for _, effect := range effects {
+		byRound[effect.RoundID] = append(byRound[effect.RoundID], effect)
+		if effect.Active() && fight.Status == domain.FightStatusInProgress {
+			fight.Effects = append(fight.Effects, effect)
+		}
+	}
+
+	for _, round := range fight.Rounds {
+		round.Effects = byRound[round.ID]
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func newTestEffect(target domain.FightParticipant, effectType domain.StatusEffectType, value, roundsLeft uint) *domain.StatusEffect {
+	return &domain.StatusEffect{
+		Model:      domain.Model{ID: uuid.New()},
+		Target:     target,
+		EffectType: effectType,
+		Value:      value,
+		RoundsLeft: roundsLeft,
+	}
+}
+
+func TestResolveStatusEffects(t *testing.T) {
+	effects := []*domain.StatusEffect{
+		newTestEffect(domain.FightParticipantPlayer, domain.StatusEffectPoison, 3, 2),
+		newTestEffect(domain.FightParticipantPlayer, domain.StatusEffectBleed, 2, 1),
+		newTestEffect(domain.FightParticipantBot, domain.StatusEffectStun, 0, 1),
+		newTestEffect(domain.FightParticipantBot, domain.StatusEffectShield, 10, 3),
+		newTestEffect(domain.FightParticipantBot, domain.StatusEffectPoison, 50, 0),
+		newTestEffect(domain.FightParticipantPlayer, domain.StatusEffectAttackUp, 5, 2),
+		newTestEffect(domain.FightParticipantPlayer, domain.StatusEffectAttackUp, 3, 1),
+		newTestEffect(domain.FightParticipantBot, domain.StatusEffectDefenseUp, 4, 1),
+		newTestEffect(domain.FightParticipantBot, domain.StatusEffectAttackUp, 9, 0),
+	}
+
+	tick := resolveStatusEffects(effects)
+
+	assert.Equal(t, statusTick{
+		PlayerDamage: 5, BotStunned: true, BotShield: 10,
+		PlayerBuff: domain.StatBonus{Attack: 8}, BotBuff: domain.StatBonus{Defense: 4},
+	}, tick)
+}
+
+func TestAdvanceStatusEffects(t *testing.T) {
+	poison := newTestEffect(domain.FightParticipantBot, domain.StatusEffectPoison, 3, 2)
+	shield := newTestEffect(domain.FightParticipantPlayer, domain.StatusEffectShield, 10, 3)
+	brokenShield := newTestEffect(domain.FightParticipantBot, domain.StatusEffectShield, 4, 3)
+	expired := newTestEffect(domain.FightParticipantBot, domain.StatusEffectStun, 0, 0)
+
+	changed := advanceStatusEffects([]*domain.StatusEffect{poison, shield, brokenShield, expired}, 6, 8)
+
+	assert.Len(t, changed, 3)
+	assert.Equal(t, uint(1), poison.RoundsLeft)
+	assert.Equal(t, uint(4), shield.Value)
+	assert.Equal(t, uint(2), shield.RoundsLeft)
+	assert.Equal(t, uint(0), brokenShield.Value)
+	assert.Equal(t, uint(0), brokenShield.RoundsLeft)
+}
+
+func TestPlayRound_StatusEffects(t *testing.T) {
+	bot := combatant{Attack: 10}
+
+	t.Run("poison kills before anyone strikes", func(t *testing.T) {
+		outcome := playRound(combatant{Attack: 100}, bot, 50, 5, "HEAD", "HEAD", statusTick{BotDamage: 5})
+
+		assert.Equal(t, 0, outcome.BotHp)
+		assert.Equal(t, 50, outcome.PlayerHp)
+		assert.Equal(t, uint(5), outcome.BotEffectDamage)
+		assert.Zero(t, outcome.PlayerDamage)
+		assert.Zero(t, outcome.BotDamage)
+	})
+
+	t.Run("stunned sides deal no damage", func(t *testing.T) {
+		outcome := playRound(combatant{Attack: 100}, bot, 50, 100, "HEAD", "HEAD", statusTick{PlayerStunned: true, BotStunned: true})
+
+		assert.Zero(t, outcome.PlayerDamage)
+		assert.Zero(t, outcome.BotDamage)
+		assert.True(t, outcome.PlayerStunned)
+		assert.True(t, outcome.BotStunned)
+	})
+
+	t.Run("shield absorbs damage", func(t *testing.T) {
+		outcome := playRound(combatant{Attack: 1}, bot, 50, 100, "HEAD", "CHEST", statusTick{PlayerShield: 1000, BotShield: 1000})
+
+		assert.Zero(t, outcome.PlayerDamage)
+		assert.Zero(t, outcome.BotDamage)
+		assert.Positive(t, outcome.PlayerAbsorbed)
+		assert.Positive(t, outcome.BotAbsorbed)
+		assert.Equal(t, 50, outcome.PlayerHp)
+		assert.Equal(t, 100, outcome.BotHp)
+	})
+}
+
+func TestNewStatusEffects(t *testing.T) {
+	poison := domain.StatusEffectPoison
+	shield := domain.StatusEffectShield
+	fightID, roundID := uuid.New(), uuid.New()
+
+	weapon := &domain.EquipmentItem{StatusEffectSource: domain.StatusEffectSource{
+		StatusEffectType: &poison, StatusEffectValue: 2, StatusEffectDuration: 3, StatusEffectChance: 100,
+	}}
+	bot := &domain.Bot{}
+	potion := &domain.Consumable{StatusEffectSource: domain.StatusEffectSource{
+		StatusEffectType: &shield, StatusEffectValue: 20, StatusEffectDuration: 2,
+	}}
+
+	effects := newStatusEffects(fightID, roundID, weapon, bot, potion, roundOutcome{PlayerDamage: 5, BotDamage: 5})
+	require.Len(t, effects, 2)
+
+	assert.Equal(t, domain.FightParticipantBot, effects[0].Target)
+	assert.Equal(t, domain.StatusEffectPoison, effects[0].EffectType)
+	assert.Equal(t, uint(3), effects[0].RoundsLeft)
+	assert.Equal(t, roundID, effects[0].RoundID)
+
+	assert.Equal(t, domain.FightParticipantPlayer, effects[1].Target)
+	assert.Equal(t, domain.StatusEffectShield, effects[1].EffectType)
+
+	effects = newStatusEffects(fightID, roundID, weapon, bot, nil, roundOutcome{})
+	assert.Empty(t, effects)
+}
+
+func TestAttachStatusEffects(t *testing.T) {
+	round := &domain.Round{Model: domain.Model{ID: uuid.New()}}
+	active := newTestEffect(domain.FightParticipantBot, domain.StatusEffectBleed, 1, 2)
+	expired := newTestEffect(domain.FightParticipantPlayer, domain.StatusEffectStun, 0, 0)
+	active.RoundID, expired.RoundID = round.ID, round.ID
+
+	fight := &domain.Fight{Status: domain.FightStatusInProgress, Rounds: []*domain.Round{round}}
+	attachStatusEffects(fight, []*domain.StatusEffect{active, expired})
+
+	assert.Equal(t, []*domain.StatusEffect{active}, fight.Effects)
+	assert.Len(t, round.Effects, 2)
+
+	fight.Status = domain.FightStatusFinished
+	attachStatusEffects(fight, []*domain.StatusEffect{active, expired})
+	assert.Empty(t, fight.Effects)
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/events"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrTradeNotFound       = errors.New("trade not found")
+	ErrTradeWithSelf       = errors.New("can't trade with yourself")
+	ErrNotInSameLocation   = errors.New("players are not in the same location")
+	ErrAlreadyTrading      = errors.New("player already has an open trade")
+	ErrTradeClosed         = errors.New("trade is already closed")
+	ErrTradePartnerInFight = errors.New("trade partner is in fight")
+)
+
+// Trade events sent to both sides over the hub.
+const (
+	TradeEventStarted   = "started"
+	TradeEventUpdated   = "updated"
+	TradeEventConfirmed = "confirmed"
+	TradeEventCompleted = "completed"
+	TradeEventCancelled = "cancelled"
+)
+
+// TradeService runs trades between two players. Offered instances are held in
+// escrow so they can't be sold or equipped meanwhile, gold is only checked
+// when the swap runs. Any change to an offer resets both confirmations and the
+// swap runs in the transaction of the second confirmation.
+type TradeService struct {
+	uow   *repository.UnitOfWork
+	repos *repository.Repositories
+	hub   *ws.Hub
+}
+
+func NewTradeService(db *sqlx.DB, hub *ws.Hub) *TradeService {
+	return &TradeService{
+		uow:   repository.NewUnitOfWork(db),
+		repos: repository.NewRepositories(db),
+		hub:   hub,
+	}
+}
+
+// GetCurrent returns the user's open trade with the offered items.
+func (s *TradeService) GetCurrent(ctx context.Context, userID uuid.UUID) (*domain.Trade, error) {
+	trade, err := s.repos.Trades.FindOpenByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTradeNotFound) {
+			return nil, ErrTradeNotFound
+		}
+		return nil, err
+	}
+
+	if err := loadTradeItems(s.repos, trade); err != nil {
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// Start opens a trade with a player in the same location. Neither side may
+// have another open trade.
+func (s *TradeService) Start(ctx context.Context, userID, partnerID uuid.UUID) (*domain.Trade, error) {
+	if userID == partnerID {
+		return nil, ErrTradeWithSelf
+	}
+
+	trade := &domain.Trade{InitiatorID: userID, PartnerID: partnerID}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		user, partner, err := lockTradeUsers(repos, userID, partnerID)
+		if err != nil {
+			return err
+		}
+
+		if user.LocationID != partner.LocationID {
+			return ErrNotInSameLocation
+		}
+
+		inFight, err := repos.Users.InFight(partnerID)
+		if err != nil {
+			return err
+		}
+		if inFight {
+			return ErrTradePartnerInFight
+		}
+
+		for _, id := range []uuid.UUID{userID, partnerID} {
+			_, err := repos.Trades.FindOpenByUserID(id)
+			if err == nil {
+				return ErrAlreadyTrading
+			}
+			if !errors.Is(err, repository.ErrTradeNotFound) {
+				return err
+			}
+		}
+
+		trade.LocationID = user.LocationID
+		return repos.Trades.Create(trade)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(trade, TradeEventStarted)
+	return trade, nil
+}
+
+// UpdateOffer replaces the user's offer with the given instances and gold.
+func (s *TradeService) UpdateOffer(ctx context.Context, userID, tradeID uuid.UUID, instanceIDs []uuid.UUID, gold uint) (*domain.Trade, error) {
+	var trade *domain.Trade
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		trade, err = findOpenTradeForUpdate(repos, userID, tradeID)
+		if err != nil {
+			return err
+		}
+
+		user, err := repos.Users.FindByID(userID)
+		if err != nil {
+			return repository.ErrUserNotFound
+		}
+		if user.Gold < gold {
+			return ErrInsufficientGold
+		}
+
+		if err := repos.Inventory.ReleaseUserEscrow(trade.ID, userID); err != nil {
+			return err
+		}
+		if err := repos.Inventory.Escrow(trade.ID, userID, uniqueIDs(instanceIDs)); err != nil {
+			if errors.Is(err, repository.ErrItemNotInInventory) {
+				return ErrItemNotInInventory
+			}
+			return err
+		}
+
+		if err := repos.Trades.UpdateOffer(trade, userID, gold); err != nil {
+			return err
+		}
+
+		return loadTradeItems(repos, trade)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(trade, TradeEventUpdated)
+	return trade, nil
+}
+
+// Confirm accepts the current offers for the user. The second confirmation
+// runs the swap.
+func (s *TradeService) Confirm(ctx context.Context, userID, tradeID uuid.UUID) (*domain.Trade, error) {
+	var trade *domain.Trade
+	var received map[uuid.UUID][]*domain.EquipmentItem
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		trade, err = findOpenTradeForUpdate(repos, userID, tradeID)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Trades.Confirm(trade, userID); err != nil {
+			return err
+		}
+
+		received = nil
+		if trade.InitiatorConfirmed && trade.PartnerConfirmed {
+			received, err = executeTrade(repos, trade)
+			if err != nil {
+				return err
+			}
+		}
+
+		return loadTradeItems(repos, trade)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if trade.Status != domain.TradeStatusCompleted {
+		s.notify(trade, TradeEventConfirmed)
+		return trade, nil
+	}
+
+	log.Printf("[Trade] %s completed: %s gave %d gold, %s gave %d gold",
+		trade.ID, trade.InitiatorID, trade.InitiatorGold, trade.PartnerID, trade.PartnerGold)
+	for userID, items := range received {
+		for _, item := range items {
+			events.GetBus().Publish(ctx, domain.GameEvent{Type: domain.EventItemAcquired, UserID: userID, Slug: item.Slug})
+		}
+	}
+	s.notify(trade, TradeEventCompleted)
+	return trade, nil
+}
+
+// Cancel closes the trade and gives the escrowed instances back.
+func (s *TradeService) Cancel(ctx context.Context, userID, tradeID uuid.UUID) error {
+	var trade *domain.Trade
+	err := s.uow.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		trade, err = findOpenTradeForUpdate(repos, userID, tradeID)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Inventory.ReleaseEscrow(trade.ID); err != nil {
+			return err
+		}
+
+		return repos.Trades.UpdateStatus(trade, domain.TradeStatusCancelled)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notify(trade, TradeEventCancelled)
+	return nil
+}
+
+// executeTrade swaps gold and escrowed instances, logs every transfer and
+// returns the items each user received.
+func executeTrade(repos *repository.Repositories, trade *domain.Trade) (map[uuid.UUID][]*domain.EquipmentItem, error) {
+	initiator, partner, err := lockTradeUsers(repos, trade.InitiatorID, trade.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+	if initiator.LocationID != trade.LocationID || partner.LocationID != trade.LocationID {
+		return nil, ErrNotInSameLocation
+	}
+
+	received := make(map[uuid.UUID][]*domain.EquipmentItem, 2)
+	for _, fromID := range []uuid.UUID{trade.InitiatorID, trade.PartnerID} {
+		toID := trade.Counterpart(fromID)
+
+		if gold := trade.Gold(fromID); gold > 0 {
+			if _, err := repos.GoldTransactions.Apply(fromID, -int64(gold), domain.GoldReasonTrade, &trade.ID); err != nil {
+				if errors.Is(err, repository.ErrInsufficientGold) {
+					return nil, ErrInsufficientGold
+				}
+				return nil, err
+			}
+			if _, err := repos.GoldTransactions.Apply(toID, int64(gold), domain.GoldReasonTrade, &trade.ID); err != nil {
+				return nil, err
+			}
+			if err := repos.Trades.CreateLog(&domain.TradeLog{TradeID: trade.ID, FromUserID: fromID, ToUserID: toID, Gold: gold}); err != nil {
+				return nil, err
+			}
+		}
+
+		instances, err := repos.Inventory.TransferEscrowed(trade.ID, fromID, toID)
+		if err != nil {
+			return nil, err
+		}
+		items, err := findInstanceItems(repos, instances)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			entry := &domain.TradeLog{
+				TradeID:         trade.ID,
+				FromUserID:      fromID,
+				ToUserID:        toID,
+				InventoryID:     &instance.ID,
+				EquipmentItemID: &instance.EquipmentItemID,
+			}
+			if err := repos.Trades.CreateLog(entry); err != nil {
+				return nil, err
+			}
+			received[toID] = append(received[toID], items[instance.EquipmentItemID])
+		}
+	}
+
+	// Counted after the exchange, so the items a user gives make room for the
+	// ones they get.
+	for _, user := range []*domain.User{initiator, partner} {
+		if len(received[user.ID]) == 0 {
+			continue
+		}
+		used, err := repos.Inventory.CountBagSlots(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if used > user.InventoryCapacity {
+			return nil, ErrInventoryFull
+		}
+	}
+
+	if err := repos.Trades.UpdateStatus(trade, domain.TradeStatusCompleted); err != nil {
+		return nil, err
+	}
+
+	return received, nil
+}
+
+// lockTradeUsers locks both users in id order, so two trades between the same
+// players can't deadlock, and returns them in the order asked for.
+func lockTradeUsers(repos *repository.Repositories, firstID, secondID uuid.UUID) (*domain.User, *domain.User, error) {
+	ids := []uuid.UUID{firstID, secondID}
+	if bytes.Compare(secondID[:], firstID[:]) < 0 {
+		ids[0], ids[1] = secondID, firstID
+	}
+
+	users := make(map[uuid.UUID]*domain.User, 2)
+	for _, id := range ids {
+		user, err := repos.Users.FindByIDForUpdate(id)
+		if err != nil {
+			return nil, nil, repository.ErrUserNotFound
+		}
+		users[id] = user
+	}
+
+	return users[firstID], users[secondID], nil
+}
+
+func findOpenTradeForUpdate(repos *repository.Repositories, userID, tradeID uuid.UUID) (*domain.Trade, error) {
+	trade, err := repos.Trades.FindByIDForUpdate(tradeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTradeNotFound) {
+			return nil, ErrTradeNotFound
+		}
+		return nil, err
+	}
+	if !trade.HasUser(userID) {
+		return nil, ErrTradeNotFound
+	}
+	if trade.Status != domain.TradeStatusOpen {
+		return nil, ErrTradeClosed
+	}
+
+	return trade, nil
+}
+
+// loadTradeItems fills the instances held by an open trade.
+func loadTradeItems(repos *repository.Repositories, trade *domain.Trade) error {
+	trade.Items = nil
+	if trade.Status != domain.TradeStatusOpen {
+		return nil
+	}
+
+	instances, err := repos.Inventory.FindEscrowed(trade.ID)
+	if err != nil {
+		return err
+	}
+	items, err := findInstanceItems(repos, instances)
+	if err != nil {
+		return err
+	}
+
+	for _, instance := range instances {
+		trade.Items = append(trade.Items, &domain.InventoryItem{EquipmentItem: items[instance.EquipmentItemID], Instance: instance})
+	}
+	return nil
+}
+
+func findInstanceItems(repos *repository.Repositories, instances []*domain.Inventory) (map[uuid.UUID]*domain.EquipmentItem, error) {
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(instances))
+	for i, instance := range instances {
+		ids[i] = instance.EquipmentItemID
+	}
+
+	items, err := repos.EquipmentItems.FindByIDs(uniqueIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.EquipmentItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func (s *TradeService) notify(trade *domain.Trade, event string) {
+	data := ws.TradeEventData{TradeID: trade.ID.String(), Event: event, Status: string(trade.Status)}
+	for _, userID := range []uuid.UUID{trade.InitiatorID, trade.PartnerID} {
+		if err := s.hub.SendTradeEvent(userID, data); err != nil {
+			log.Printf("[Trade] failed to send %s event to %s: %v", event, userID, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+	"moonshine/internal/testutil"
+)
+
+func TestTradeService(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+	service := NewTradeService(testDB, ws.GetHub())
+	userRepo := repository.NewUserRepository(testDB)
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+
+	// setup returns two players in the same location, each owning one item.
+	setup := func(t *testing.T) (*domain.User, *domain.Inventory, *domain.User, *domain.Inventory) {
+		user, item := setupBuyTestData(t)
+		partner, partnerItem := setupBuyTestData(t)
+		require.NoError(t, userRepo.UpdateLocationID(partner.ID, user.LocationID))
+
+		instance := &domain.Inventory{UserID: user.ID, EquipmentItemID: item.ID}
+		require.NoError(t, inventoryRepo.Create(instance))
+		partnerInstance := &domain.Inventory{UserID: partner.ID, EquipmentItemID: partnerItem.ID}
+		require.NoError(t, inventoryRepo.Create(partnerInstance))
+
+		return user, instance, partner, partnerInstance
+	}
+
+	t.Run("swap after both confirmations", func(t *testing.T) {
+		user, instance, partner, partnerInstance := setup(t)
+
+		trade, err := service.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+
+		_, err = service.UpdateOffer(ctx, user.ID, trade.ID, []uuid.UUID{instance.ID}, 100)
+		require.NoError(t, err)
+		trade, err = service.UpdateOffer(ctx, partner.ID, trade.ID, []uuid.UUID{partnerInstance.ID}, 0)
+		require.NoError(t, err)
+		assert.Len(t, trade.Items, 2)
+
+		trade, err = service.Confirm(ctx, user.ID, trade.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TradeStatusOpen, trade.Status)
+
+		trade, err = service.Confirm(ctx, partner.ID, trade.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TradeStatusCompleted, trade.Status)
+
+		userAfter, err := userRepo.FindByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Gold-100, userAfter.Gold)
+		partnerAfter, err := userRepo.FindByID(partner.ID)
+		require.NoError(t, err)
+		assert.Equal(t, partner.Gold+100, partnerAfter.Gold)
+
+		received, err := inventoryRepo.FindByIDForUpdate(partner.ID, instance.ID)
+		require.NoError(t, err)
+		assert.Equal(t, partner.ID, received.UserID)
+		_, err = inventoryRepo.FindByIDForUpdate(user.ID, partnerInstance.ID)
+		require.NoError(t, err)
+
+		logs, err := repository.NewTradeRepository(testDB).FindLogs(trade.ID)
+		require.NoError(t, err)
+		assert.Len(t, logs, 3)
+	})
+
+	t.Run("changing an offer resets confirmations", func(t *testing.T) {
+		user, instance, partner, _ := setup(t)
+
+		trade, err := service.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+
+		_, err = service.Confirm(ctx, partner.ID, trade.ID)
+		require.NoError(t, err)
+		trade, err = service.UpdateOffer(ctx, user.ID, trade.ID, []uuid.UUID{instance.ID}, 0)
+		require.NoError(t, err)
+		assert.False(t, trade.PartnerConfirmed)
+
+		_, err = service.Confirm(ctx, user.ID, trade.ID)
+		require.NoError(t, err)
+		trade, err = service.GetCurrent(ctx, partner.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TradeStatusOpen, trade.Status)
+	})
+
+	t.Run("escrowed items can't be sold or offered twice", func(t *testing.T) {
+		user, instance, partner, _ := setup(t)
+
+		trade, err := service.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+		_, err = service.UpdateOffer(ctx, user.ID, trade.ID, []uuid.UUID{instance.ID}, 0)
+		require.NoError(t, err)
+
+		_, err = service.UpdateOffer(ctx, partner.ID, trade.ID, []uuid.UUID{instance.ID}, 0)
+		assert.ErrorIs(t, err, ErrItemNotInInventory)
+		err = inventoryRepo.RemoveOne(user.ID, instance.EquipmentItemID)
+		assert.Error(t, err)
+
+		require.NoError(t, service.Cancel(ctx, partner.ID, trade.ID))
+		err = inventoryRepo.RemoveOne(user.ID, instance.EquipmentItemID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("gold spent after the offer fails the swap", func(t *testing.T) {
+		user, _, partner, _ := setup(t)
+
+		trade, err := service.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+		_, err = service.UpdateOffer(ctx, user.ID, trade.ID, nil, user.Gold)
+		require.NoError(t, err)
+		_, err = repository.NewGoldTransactionRepository(testDB).Apply(user.ID, -1, domain.GoldReasonEquipmentBuy, nil)
+		require.NoError(t, err)
+
+		_, err = service.Confirm(ctx, user.ID, trade.ID)
+		require.NoError(t, err)
+		_, err = service.Confirm(ctx, partner.ID, trade.ID)
+		assert.ErrorIs(t, err, ErrInsufficientGold)
+
+		trade, err = service.GetCurrent(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, trade.PartnerConfirmed)
+	})
+
+	t.Run("start checks", func(t *testing.T) {
+		user, _, partner, _ := setup(t)
+
+		_, err := service.Start(ctx, user.ID, user.ID)
+		assert.ErrorIs(t, err, ErrTradeWithSelf)
+
+		other, _ := setupBuyTestData(t)
+		_, err = service.Start(ctx, user.ID, other.ID)
+		assert.ErrorIs(t, err, ErrNotInSameLocation)
+
+		_, err = service.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+		_, err = service.Start(ctx, partner.ID, user.ID)
+		assert.ErrorIs(t, err, ErrAlreadyTrading)
+	})
+
+	t.Run("outsiders can't touch the trade", func(t *testing.T) {
+		user, _, partner, _ := setup(t)
+		other, _ := setupBuyTestData(t)
+
+		trade, err := service.Start(ctx, user.ID, partner.ID)
+		require.NoError(t, err)
+
+		_, err = service.Confirm(ctx, other.ID, trade.ID)
+		assert.ErrorIs(t, err, ErrTradeNotFound)
+		assert.ErrorIs(t, service.Cancel(ctx, other.ID, trade.ID), ErrTradeNotFound)
+
+		require.NoError(t, service.Cancel(ctx, user.ID, trade.ID))
+		assert.ErrorIs(t, service.Cancel(ctx, user.ID, trade.ID), ErrTradeClosed)
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
@@ -13,6 +14,11 @@ import (
 	goredis "github.com/redis/go-redis/v9"
 )
 
+var (
+	ErrInsufficientFreeStats = errors.New("insufficient free stats")
+	ErrNoStatsToAllocate     = errors.New("no stats to allocate")
+)
+
 type UserService struct {
 	userRepo     *repository.UserRepository
 	avatarRepo   *repository.AvatarRepository
@@ -92,3 +98,22 @@ func (s *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, avatarID
 
 	return user, nil
 }
+
+// AllocateStats spends free stats on agility and luck.
+func (s *UserService) AllocateStats(ctx context.Context, userID uuid.UUID, agility, luck uint) (*domain.User, error) {
+	if agility+luck == 0 {
+		return nil, ErrNoStatsToAllocate
+	}
+
+	err := s.userRepo.AllocateStats(userID, agility, luck)
+	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientFreeStats) {
+			return nil, ErrInsufficientFreeStats
+		}
+		return nil, err
+	}
+
+	_ = s.userCache.Delete(ctx, userID.String())
+
+	return s.GetCurrentUser(ctx, userID)
+}
@@ -121,3 +121,41 @@ func TestUserService_UpdateUser(t *testing.T) {
 		assert.ErrorIs(t, err, repository.ErrAvatarNotFound)
 	})
 }
+
+func TestUserService_AllocateStats(t *testing.T) {
+	testutil.RequireDB(t, testDB)
+	ctx := context.Background()
+
+	service := NewUserService(
+		repository.NewUserRepository(testDB),
+		repository.NewAvatarRepository(testDB),
+		repository.NewLocationRepository(testDB),
+		nil,
+	)
+
+	t.Run("success", func(t *testing.T) {
+		user := setupUserServiceTestData(t)
+		_, err := testDB.Exec(`UPDATE users SET free_stats = 5 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+
+		result, err := service.AllocateStats(ctx, user.ID, 2, 3)
+		require.NoError(t, err)
+		assert.Equal(t, uint(0), result.FreeStats)
+		assert.Equal(t, uint(2), result.Agility)
+		assert.Equal(t, uint(3), result.Luck)
+	})
+
+	t.Run("insufficient free stats", func(t *testing.T) {
+		user := setupUserServiceTestData(t)
+
+		_, err := service.AllocateStats(ctx, user.ID, 1, 0)
+		assert.ErrorIs(t, err, ErrInsufficientFreeStats)
+	})
+
+	t.Run("nothing to allocate", func(t *testing.T) {
+		user := setupUserServiceTestData(t)
+
+		_, err := service.AllocateStats(ctx, user.ID, 0, 0)
+		assert.ErrorIs(t, err, ErrNoStatsToAllocate)
+	})
+}
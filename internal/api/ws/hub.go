@@ -20,6 +20,47 @@ type HPUpdateData struct {
 	Hp        uint `json:"hp"`
 }
 
+type AchievementUnlockedData struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Title       *string `json:"title,omitempty"`
+}
+
+// TradeEventData tells both sides that a trade changed, clients fetch the
+// trade again to show it.
+type TradeEventData struct {
+	TradeID string `json:"tradeId"`
+	Event   string `json:"event"`
+	Status  string `json:"status"`
+}
+
+// NewMailData tells the recipient that mail arrived, clients fetch the
+// mailbox again to show it.
+type NewMailData struct {
+	MailID  string `json:"mailId"`
+	From    string `json:"from"`
+	Subject string `json:"subject"`
+	System  bool   `json:"system"`
+}
+
+// GuildChatData is one message of the guild chat channel.
+type GuildChatData struct {
+	ID        string `json:"id"`
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// GuildInviteData tells the invitee about a guild invite.
+type GuildInviteData struct {
+	GuildID string `json:"guildId"`
+	Name    string `json:"name"`
+	Tag     string `json:"tag"`
+	From    string `json:"from"`
+}
+
 type Hub struct {
 	connections map[uuid.UUID]*websocket.Conn
 	mu          sync.RWMutex
@@ -92,6 +133,46 @@ func (h *Hub) SendHPUpdate(userID uuid.UUID, currentHp int, hp uint) error {
 	return h.SendToUser(userID, msg)
 }
 
+func (h *Hub) SendAchievementUnlocked(userID uuid.UUID, data AchievementUnlockedData) error {
+	msg := Message{
+		Type: "achievement_unlocked",
+		Data: data,
+	}
+	return h.SendToUser(userID, msg)
+}
+
+func (h *Hub) SendTradeEvent(userID uuid.UUID, data TradeEventData) error {
+	msg := Message{
+		Type: "trade",
+		Data: data,
+	}
+	return h.SendToUser(userID, msg)
+}
+
+func (h *Hub) SendNewMail(userID uuid.UUID, data NewMailData) error {
+	msg := Message{
+		Type: "new_mail",
+		Data: data,
+	}
+	return h.SendToUser(userID, msg)
+}
+
+func (h *Hub) SendGuildChat(userID uuid.UUID, data GuildChatData) error {
+	msg := Message{
+		Type: "guild_chat",
+		Data: data,
+	}
+	return h.SendToUser(userID, msg)
+}
+
+func (h *Hub) SendGuildInvite(userID uuid.UUID, data GuildInviteData) error {
+	msg := Message{
+		Type: "guild_invite",
+		Data: data,
+	}
+	return h.SendToUser(userID, msg)
+}
+
 func (h *Hub) IsConnected(userID uuid.UUID) bool {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
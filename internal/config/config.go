@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -12,6 +14,8 @@ type Config struct {
 	PprofEnabled   bool
 	TracingEnabled bool
 	JaegerEndpoint string
+	ProgressionPath string
+	Shop           ShopConfig
 	Database       DatabaseConfig
 	Redis          RedisConfig
 }
@@ -25,6 +29,13 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// ShopConfig tunes the NPC shop economy.
+type ShopConfig struct {
+	SellBackPercent uint
+	PriceDrift      bool
+	RestockInterval time.Duration
+}
+
 type RedisConfig struct {
 	Addr     string
 	Password string
@@ -38,6 +49,12 @@ func Load() *Config {
 		PprofEnabled:   getEnvBool("PPROF_ENABLED", strings.ToLower(getEnv("ENV", "development")) != "production" && strings.ToLower(getEnv("ENV", "development")) != "prod"),
 		TracingEnabled: getEnvBool("TRACING_ENABLED", false),
 		JaegerEndpoint: getEnv("JAEGER_ENDPOINT", "localhost:4317"),
+		ProgressionPath: getEnv("PROGRESSION_CONFIG_PATH", ""),
+		Shop: ShopConfig{
+			SellBackPercent: getEnvUint("SHOP_SELL_BACK_PERCENT", 50),
+			PriceDrift:      getEnvBool("SHOP_PRICE_DRIFT", false),
+			RestockInterval: getEnvDuration("SHOP_RESTOCK_INTERVAL", 15*time.Minute),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DATABASE_HOST", "localhost"),
 			Port:     getEnv("DATABASE_PORT", "5433"),
@@ -79,6 +96,22 @@ func getEnvBool(key string, fallback bool) bool {
 	}
 }
 
+func getEnvUint(key string, fallback uint) uint {
+	v, err := strconv.ParseUint(strings.TrimSpace(os.Getenv(key)), 10, 32)
+	if err != nil {
+		return fallback
+	}
+	return uint(v)
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+	return v
+}
+
 func normalizeAddr(addr string) string {
 	if addr == "" {
 		return addr
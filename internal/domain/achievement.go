@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AchievementType string
+
+const (
+	AchievementKillBots    AchievementType = "KILL_BOTS"
+	AchievementReachLevel  AchievementType = "REACH_LEVEL"
+	AchievementOwnArtifact AchievementType = "OWN_ARTIFACT"
+	AchievementVisitCells  AchievementType = "VISIT_CELLS"
+)
+
+type Achievement struct {
+	Model
+	Name        string          `db:"name"`
+	Slug        string          `db:"slug"`
+	Description string          `db:"description"`
+	Type        AchievementType `db:"achievement_type"`
+	Threshold   uint            `db:"threshold"`
+	Title       *string         `db:"title"`
+}
+
+type UserAchievement struct {
+	ID            uuid.UUID `db:"id"`
+	UnlockedAt    time.Time `db:"unlocked_at"`
+	UserID        uuid.UUID `db:"user_id"`
+	AchievementID uuid.UUID `db:"achievement_id"`
+}
+
+// AchievementProgress is an achievement as seen by a user; UnlockedAt is nil
+// while it is locked.
+type AchievementProgress struct {
+	Achievement *Achievement
+	UnlockedAt  *time.Time
+}
+
+type GameEventType string
+
+const (
+	EventBotKilled    GameEventType = "BOT_KILLED"
+	EventLevelReached GameEventType = "LEVEL_REACHED"
+	EventItemAcquired GameEventType = "ITEM_ACQUIRED"
+	EventCellVisited  GameEventType = "CELL_VISITED"
+	// EventRewardEarned is published once fight or quest gold and exp are
+	// committed.
+	EventRewardEarned GameEventType = "REWARD_EARNED"
+)
+
+// GameEvent is something a user did. Slug names the bot, item or cell
+// involved, if any. Gold and Exp are what a reward added.
+type GameEvent struct {
+	Type   GameEventType
+	UserID uuid.UUID
+	Slug   string
+	Gold   uint
+	Exp    uint
+}
+
+// AchievementTypesFor returns the achievement types an event can unlock.
+func AchievementTypesFor(event GameEventType) []AchievementType {
+	switch event {
+	case EventBotKilled:
+		return []AchievementType{AchievementKillBots}
+	case EventLevelReached:
+		return []AchievementType{AchievementReachLevel}
+	case EventItemAcquired:
+		return []AchievementType{AchievementOwnArtifact}
+	case EventCellVisited:
+		return []AchievementType{AchievementVisitCells}
+	default:
+		return nil
+	}
+}
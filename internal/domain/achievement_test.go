@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAchievementTypesFor(t *testing.T) {
+	assert.Equal(t, []AchievementType{AchievementKillBots}, AchievementTypesFor(EventBotKilled))
+	assert.Equal(t, []AchievementType{AchievementReachLevel}, AchievementTypesFor(EventLevelReached))
+	assert.Equal(t, []AchievementType{AchievementOwnArtifact}, AchievementTypesFor(EventItemAcquired))
+	assert.Equal(t, []AchievementType{AchievementVisitCells}, AchievementTypesFor(EventCellVisited))
+	assert.Empty(t, AchievementTypesFor("UNKNOWN"))
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuctionStatus string
+
+const (
+	AuctionStatusActive  AuctionStatus = "ACTIVE"
+	AuctionStatusSold    AuctionStatus = "SOLD"
+	AuctionStatusExpired AuctionStatus = "EXPIRED"
+)
+
+const (
+	// AuctionListingFeePercent of the start price is charged when the auction
+	// is settled, sold or not.
+	AuctionListingFeePercent = 5
+	// AuctionBidStepPercent is how much a bid has to raise the current one.
+	AuctionBidStepPercent = 5
+)
+
+// AuctionDurations are the durations a seller can pick from.
+var AuctionDurations = []time.Duration{12 * time.Hour, 24 * time.Hour, 48 * time.Hour}
+
+// Auction is an instance listed by a player. The instance is held in escrow
+// and the gold of the top bid is taken from the bidder until the auction is
+// settled.
+type Auction struct {
+	ID              uuid.UUID     `db:"id"`
+	CreatedAt       time.Time     `db:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at"`
+	SellerID        uuid.UUID     `db:"seller_id"`
+	InventoryID     uuid.UUID     `db:"inventory_id"`
+	EquipmentItemID uuid.UUID     `db:"equipment_item_id"`
+	StartPrice      uint          `db:"start_price"`
+	BuyoutPrice     *uint         `db:"buyout_price"`
+	CurrentBid      *uint         `db:"current_bid"`
+	BidderID        *uuid.UUID    `db:"bidder_id"`
+	ListingFee      uint          `db:"listing_fee"`
+	Status          AuctionStatus `db:"status"`
+	ExpiresAt       time.Time     `db:"expires_at"`
+	SettledAt       *time.Time    `db:"settled_at"`
+	Item            *InventoryItem
+}
+
+// AuctionFilter narrows the search over active auctions, zero values match
+// everything.
+type AuctionFilter struct {
+	CategoryType string
+	MinLevel     uint
+	MaxLevel     uint
+	MinPrice     uint
+	MaxPrice     uint
+	Limit        int
+	Offset       int
+}
+
+func ValidAuctionDuration(duration time.Duration) bool {
+	return slices.Contains(AuctionDurations, duration)
+}
+
+// AuctionListingFee is a percent of the start price, at least one gold.
+func AuctionListingFee(startPrice uint) uint {
+	return max(startPrice*AuctionListingFeePercent/100, 1)
+}
+
+// Price is the current bid, or the start price before the first bid.
+func (a *Auction) Price() uint {
+	if a.CurrentBid != nil {
+		return *a.CurrentBid
+	}
+	return a.StartPrice
+}
+
+// MinBid is the lowest amount the next bid may offer.
+func (a *Auction) MinBid() uint {
+	if a.CurrentBid == nil {
+		return a.StartPrice
+	}
+	return *a.CurrentBid + max(*a.CurrentBid*AuctionBidStepPercent/100, 1)
+}
+
+func (a *Auction) Expired(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
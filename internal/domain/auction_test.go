@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuction_MinBid(t *testing.T) {
+	auction := &Auction{StartPrice: 100}
+	assert.Equal(t, uint(100), auction.Price())
+	assert.Equal(t, uint(100), auction.MinBid())
+
+	bid := uint(200)
+	auction.CurrentBid = &bid
+	assert.Equal(t, uint(200), auction.Price())
+	assert.Equal(t, uint(210), auction.MinBid())
+
+	small := uint(10)
+	auction.CurrentBid = &small
+	assert.Equal(t, uint(11), auction.MinBid())
+}
+
+func TestAuctionListingFee(t *testing.T) {
+	assert.Equal(t, uint(5), AuctionListingFee(100))
+	assert.Equal(t, uint(1), AuctionListingFee(10))
+}
+
+func TestAuction_Expired(t *testing.T) {
+	now := time.Now()
+	auction := &Auction{ExpiresAt: now}
+	assert.True(t, auction.Expired(now))
+	assert.False(t, auction.Expired(now.Add(-time.Second)))
+}
+
+func TestValidAuctionDuration(t *testing.T) {
+	assert.True(t, ValidAuctionDuration(24*time.Hour))
+	assert.False(t, ValidAuctionDuration(time.Hour))
+	assert.False(t, ValidAuctionDuration(0))
+}
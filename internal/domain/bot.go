@@ -9,4 +9,5 @@ type Bot struct {
 	Defense uint   `db:"defense"`
 	Hp      uint   `db:"hp"`
 	Level   uint   `db:"level"`
+	StatusEffectSource
 }
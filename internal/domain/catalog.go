@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CatalogDefaultLimit = 20
+	CatalogMaxLimit     = 100
+)
+
+var ErrInvalidCatalogCursor = errors.New("invalid catalog cursor")
+
+// CatalogSort is a column the shop catalog can be ordered by.
+type CatalogSort string
+
+const (
+	CatalogSortLevel   CatalogSort = "level"
+	CatalogSortPrice   CatalogSort = "price"
+	CatalogSortName    CatalogSort = "name"
+	CatalogSortAttack  CatalogSort = "attack"
+	CatalogSortDefense CatalogSort = "defense"
+	CatalogSortHp      CatalogSort = "hp"
+)
+
+func (s CatalogSort) Valid() bool {
+	switch s {
+	case CatalogSortLevel, CatalogSortPrice, CatalogSortName, CatalogSortAttack, CatalogSortDefense, CatalogSortHp:
+		return true
+	}
+	return false
+}
+
+// Value is what the item is ordered by, as the cursor keeps it.
+func (s CatalogSort) Value(item *EquipmentItem) string {
+	switch s {
+	case CatalogSortPrice:
+		return strconv.FormatUint(uint64(item.Price), 10)
+	case CatalogSortName:
+		return item.Name
+	case CatalogSortAttack:
+		return strconv.FormatUint(uint64(item.Attack), 10)
+	case CatalogSortDefense:
+		return strconv.FormatUint(uint64(item.Defense), 10)
+	case CatalogSortHp:
+		return strconv.FormatUint(uint64(item.Hp), 10)
+	}
+	return strconv.FormatUint(uint64(item.RequiredLevel), 10)
+}
+
+// CatalogCursor points at the last item of a page, the next page starts
+// after it. Items are ordered by the sort value and then by id. The cursor
+// keeps the order it was made for, it is only valid with the same sort and
+// direction.
+type CatalogCursor struct {
+	Sort  CatalogSort
+	Desc  bool
+	Value string
+	ID    uuid.UUID
+}
+
+func (c CatalogCursor) Encode() string {
+	direction := "asc"
+	if c.Desc {
+		direction = "desc"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(string(c.Sort) + ":" + direction + ":" + c.ID.String() + ":" + c.Value))
+}
+
+// SortValue is the value as the sort column compares it, a number for every
+// sort but the name.
+func (c CatalogCursor) SortValue() any {
+	if c.Sort == CatalogSortName {
+		return c.Value
+	}
+	value, _ := strconv.ParseUint(c.Value, 10, 31)
+	return int(value)
+}
+
+// Matches reports whether the cursor was made for the sort and direction.
+func (c CatalogCursor) Matches(sort CatalogSort, desc bool) bool {
+	return c.Sort == sort && c.Desc == desc
+}
+
+func ParseCatalogCursor(s string) (*CatalogCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCatalogCursor
+	}
+	parts := strings.SplitN(string(data), ":", 4)
+	if len(parts) != 4 {
+		return nil, ErrInvalidCatalogCursor
+	}
+
+	cursor := &CatalogCursor{Sort: CatalogSort(parts[0]), Value: parts[3]}
+	if !cursor.Sort.Valid() {
+		return nil, ErrInvalidCatalogCursor
+	}
+	switch parts[1] {
+	case "asc":
+	case "desc":
+		cursor.Desc = true
+	default:
+		return nil, ErrInvalidCatalogCursor
+	}
+	if cursor.ID, err = uuid.Parse(parts[2]); err != nil {
+		return nil, ErrInvalidCatalogCursor
+	}
+	if cursor.Sort != CatalogSortName {
+		if _, err := strconv.ParseUint(cursor.Value, 10, 31); err != nil {
+			return nil, ErrInvalidCatalogCursor
+		}
+	}
+	return cursor, nil
+}
+
+// CatalogQuery filters, orders and pages the shop catalog, zero values match
+// everything.
+type CatalogQuery struct {
+	Search        string
+	CategoryTypes []string
+	Artifact      *bool
+	MinLevel      uint
+	MaxLevel      uint
+	MinPrice      uint
+	MaxPrice      uint
+	MinAttack     uint
+	MinDefense    uint
+	MinHp         uint
+	Sort          CatalogSort
+	Desc          bool
+	After         *CatalogCursor
+	Limit         int
+}
+
+// CatalogPage is one page of the catalog, NextCursor is empty on the last.
+type CatalogPage struct {
+	Items      []*EquipmentItem
+	NextCursor string
+}
+
+// SearchTerms splits a search string into the words matched as name
+// prefixes. Anything but letters and digits separates words.
+func SearchTerms(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogCursor_RoundTrip(t *testing.T) {
+	cursor := CatalogCursor{Sort: CatalogSortName, Desc: true, Value: "Sword: of fire", ID: uuid.New()}
+
+	parsed, err := ParseCatalogCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *parsed)
+	assert.True(t, parsed.Matches(CatalogSortName, true))
+	assert.False(t, parsed.Matches(CatalogSortName, false))
+	assert.False(t, parsed.Matches(CatalogSortPrice, true))
+
+	numeric := CatalogCursor{Sort: CatalogSortPrice, Value: "120", ID: uuid.New()}
+	parsed, err = ParseCatalogCursor(numeric.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, 120, parsed.SortValue())
+}
+
+func TestParseCatalogCursor_Invalid(t *testing.T) {
+	id := uuid.NewString()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	for _, s := range []string{
+		"!!!",
+		encode("no-colon"),
+		encode("price:asc:not-a-uuid:10"),
+		encode("created_at:asc:" + id + ":10"),
+		encode("price:up:" + id + ":10"),
+		encode("price:asc:" + id + ":abc"),
+		encode("level:desc:" + id + ":-1"),
+		encode("hp:asc:" + id + ":99999999999"),
+	} {
+		_, err := ParseCatalogCursor(s)
+		assert.ErrorIs(t, err, ErrInvalidCatalogCursor, s)
+	}
+}
+
+func TestCatalogSort_Value(t *testing.T) {
+	item := &EquipmentItem{Name: "Axe", Price: 120, RequiredLevel: 3, Attack: 7, Defense: 2, Hp: 10}
+
+	assert.Equal(t, "3", CatalogSortLevel.Value(item))
+	assert.Equal(t, "120", CatalogSortPrice.Value(item))
+	assert.Equal(t, "Axe", CatalogSortName.Value(item))
+	assert.Equal(t, "7", CatalogSortAttack.Value(item))
+	assert.Equal(t, "2", CatalogSortDefense.Value(item))
+	assert.Equal(t, "10", CatalogSortHp.Value(item))
+	assert.False(t, CatalogSort("created_at").Valid())
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"iron", "sword"}, SearchTerms("  Iron-SWORD! "))
+	assert.Empty(t, SearchTerms(" :*& "))
+}
@@ -0,0 +1,43 @@
+package domain
+
+type ConsumableEffectType string
+
+const (
+	ConsumableEffectHeal         ConsumableEffectType = "HEAL"
+	ConsumableEffectStatusEffect ConsumableEffectType = "STATUS_EFFECT"
+	// ConsumableEffectMaterial can't be used, it is spent on enhancements.
+	ConsumableEffectMaterial ConsumableEffectType = "MATERIAL"
+)
+
+type Consumable struct {
+	Model
+	Name          string               `db:"name"`
+	Slug          string               `db:"slug"`
+	Image         string               `db:"image"`
+	Price         uint                 `db:"price"`
+	RequiredLevel uint                 `db:"required_level"`
+	EffectType    ConsumableEffectType `db:"effect_type"`
+	EffectValue   uint                 `db:"effect_value"`
+	StatusEffectSource
+}
+
+type ConsumableStack struct {
+	Consumable
+	Quantity uint `db:"quantity"`
+}
+
+// Heal returns the hp after applying the consumable, capped at maxHp.
+func (c *Consumable) Heal(currentHp int, maxHp uint) int {
+	if currentHp < 0 {
+		currentHp = 0
+	}
+	if c.EffectType != ConsumableEffectHeal {
+		return currentHp
+	}
+
+	newHp := currentHp + int(c.EffectValue)
+	if newHp > int(maxHp) {
+		return int(maxHp)
+	}
+	return newHp
+}
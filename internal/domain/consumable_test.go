@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumable_Heal(t *testing.T) {
+	potion := &Consumable{EffectType: ConsumableEffectHeal, EffectValue: 30}
+
+	assert.Equal(t, 80, potion.Heal(50, 100))
+	assert.Equal(t, 100, potion.Heal(90, 100))
+	assert.Equal(t, 30, potion.Heal(-5, 100))
+
+	unknown := &Consumable{EffectType: "UNKNOWN", EffectValue: 30}
+	assert.Equal(t, 50, unknown.Heal(50, 100))
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "fmt"
+
+const (
+	MaxEnhancementLevel = 10
+	// EnhancementStatPercent is how much each enhancement level adds to the
+	// item's attack, defense and hp.
+	EnhancementStatPercent = 10
+	// EnhancementStoneSlug is the material higher enhancement levels need.
+	EnhancementStoneSlug = "enhancement-stone"
+)
+
+// EnhancementStep describes the attempt that takes an instance to Level.
+// A failed attempt still spends the gold and the material.
+type EnhancementStep struct {
+	Level            uint
+	GoldCostPercent  uint
+	SuccessChance    uint
+	MaterialSlug     string
+	MaterialQuantity uint
+}
+
+var enhancementSteps = []EnhancementStep{
+	{Level: 1, GoldCostPercent: 10, SuccessChance: 100},
+	{Level: 2, GoldCostPercent: 15, SuccessChance: 95},
+	{Level: 3, GoldCostPercent: 20, SuccessChance: 90},
+	{Level: 4, GoldCostPercent: 30, SuccessChance: 80},
+	{Level: 5, GoldCostPercent: 40, SuccessChance: 70},
+	{Level: 6, GoldCostPercent: 50, SuccessChance: 60, MaterialSlug: EnhancementStoneSlug, MaterialQuantity: 1},
+	{Level: 7, GoldCostPercent: 65, SuccessChance: 50, MaterialSlug: EnhancementStoneSlug, MaterialQuantity: 1},
+	{Level: 8, GoldCostPercent: 80, SuccessChance: 40, MaterialSlug: EnhancementStoneSlug, MaterialQuantity: 2},
+	{Level: 9, GoldCostPercent: 100, SuccessChance: 30, MaterialSlug: EnhancementStoneSlug, MaterialQuantity: 2},
+	{Level: 10, GoldCostPercent: 125, SuccessChance: 20, MaterialSlug: EnhancementStoneSlug, MaterialQuantity: 3},
+}
+
+// NextEnhancementStep returns the step after the current level, false once
+// the instance is at MaxEnhancementLevel.
+func NextEnhancementStep(current uint) (EnhancementStep, bool) {
+	if current >= MaxEnhancementLevel {
+		return EnhancementStep{}, false
+	}
+	return enhancementSteps[current], true
+}
+
+// GoldCost is a percent of the item's price, at least one gold.
+func (s EnhancementStep) GoldCost(price uint) uint {
+	return max(price*s.GoldCostPercent/100, 1)
+}
+
+// Roll decides the attempt, intn is rand.Intn.
+func (s EnhancementStep) Roll(intn func(n int) int) bool {
+	return uint(intn(100)) < s.SuccessChance
+}
+
+// EnhanceStat raises the stat by EnhancementStatPercent per level, rounded up
+// so every level adds at least one point to stats the item has.
+func EnhanceStat(stat, level uint) uint {
+	if stat == 0 || level == 0 {
+		return stat
+	}
+	return stat + (stat*level*EnhancementStatPercent+99)/100
+}
+
+// EnhancedName shows the level after the name, e.g. "Sword +3".
+func EnhancedName(name string, level uint) string {
+	if level == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s +%d", name, level)
+}
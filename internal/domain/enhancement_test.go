@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextEnhancementStep(t *testing.T) {
+	step, ok := NextEnhancementStep(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), step.Level)
+	assert.Equal(t, uint(100), step.SuccessChance)
+	assert.Zero(t, step.MaterialQuantity)
+
+	step, ok = NextEnhancementStep(9)
+	assert.True(t, ok)
+	assert.Equal(t, uint(MaxEnhancementLevel), step.Level)
+	assert.Equal(t, EnhancementStoneSlug, step.MaterialSlug)
+
+	_, ok = NextEnhancementStep(MaxEnhancementLevel)
+	assert.False(t, ok)
+
+	for level := uint(1); level < MaxEnhancementLevel; level++ {
+		prev, _ := NextEnhancementStep(level - 1)
+		next, _ := NextEnhancementStep(level)
+		assert.LessOrEqual(t, next.SuccessChance, prev.SuccessChance)
+	}
+}
+
+func TestEnhancementStep_GoldCost(t *testing.T) {
+	step, _ := NextEnhancementStep(0)
+	assert.Equal(t, uint(10), step.GoldCost(100))
+	assert.Equal(t, uint(1), step.GoldCost(3))
+}
+
+func TestEnhancementStep_Roll(t *testing.T) {
+	step := EnhancementStep{SuccessChance: 30}
+	assert.True(t, step.Roll(func(n int) int { return 29 }))
+	assert.False(t, step.Roll(func(n int) int { return 30 }))
+}
+
+func TestEnhanceStat(t *testing.T) {
+	assert.Equal(t, uint(10), EnhanceStat(10, 0))
+	assert.Equal(t, uint(0), EnhanceStat(0, 5))
+	assert.Equal(t, uint(11), EnhanceStat(10, 1))
+	assert.Equal(t, uint(20), EnhanceStat(10, 10))
+	assert.Equal(t, uint(4), EnhanceStat(3, 1))
+}
+
+func TestEnhancedName(t *testing.T) {
+	assert.Equal(t, "Sword", EnhancedName("Sword", 0))
+	assert.Equal(t, "Sword +3", EnhancedName("Sword", 3))
+}
@@ -15,4 +15,12 @@ type EquipmentItem struct {
 	EquipmentCategoryID uuid.UUID `db:"equipment_category_id"`
 	Image               string    `db:"image"`
 	EquipmentType       string    `db:"equipment_type"`
+	MaxStack            uint      `db:"max_stack"`
+	StatusEffectSource
+	SecondaryStats
+}
+
+// Stackable items keep up to MaxStack copies in one inventory row.
+func (i *EquipmentItem) Stackable() bool {
+	return i.MaxStack > 1
 }
@@ -0,0 +1,29 @@
+package domain
+
+import "github.com/google/uuid"
+
+// EquipmentSlot is an entry of the slot registry. A slot takes items whose
+// category has its equipment type, slots sharing a type are filled in
+// position order.
+type EquipmentSlot struct {
+	Name          string `db:"name"`
+	EquipmentType string `db:"equipment_type"`
+	Position      int    `db:"position"`
+}
+
+// EquippedItem is the item in one of the user's slots.
+type EquippedItem struct {
+	UserID          uuid.UUID `db:"user_id"`
+	Slot            string    `db:"slot"`
+	EquipmentItemID uuid.UUID `db:"equipment_item_id"`
+}
+
+// FreeSlot returns the first of the slots the user has nothing in.
+func (user *User) FreeSlot(slots []*EquipmentSlot) (string, bool) {
+	for _, slot := range slots {
+		if user.EquippedItemID(slot.Name) == nil {
+			return slot.Name, true
+		}
+	}
+	return "", false
+}
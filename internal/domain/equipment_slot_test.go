@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_EquippedItemID(t *testing.T) {
+	ring := uuid.New()
+	user := &User{Equipment: map[string]uuid.UUID{"ring3": ring}}
+
+	assert.Equal(t, &ring, user.EquippedItemID("ring3"))
+	assert.Nil(t, user.EquippedItemID("weapon"))
+	assert.Nil(t, user.EquippedItemID("tail"))
+	assert.Equal(t, []uuid.UUID{ring}, user.EquippedItemIDs())
+}
+
+func TestUser_FreeSlot(t *testing.T) {
+	rings := []*EquipmentSlot{
+		{Name: "ring1", EquipmentType: "ring", Position: 11},
+		{Name: "ring2", EquipmentType: "ring", Position: 12},
+	}
+
+	user := &User{}
+	slot, ok := user.FreeSlot(rings)
+	assert.True(t, ok)
+	assert.Equal(t, "ring1", slot)
+
+	user.Equipment = map[string]uuid.UUID{"ring1": uuid.New()}
+	slot, ok = user.FreeSlot(rings)
+	assert.True(t, ok)
+	assert.Equal(t, "ring2", slot)
+
+	user.Equipment["ring2"] = uuid.New()
+	_, ok = user.FreeSlot(rings)
+	assert.False(t, ok)
+}
@@ -18,4 +18,6 @@ type Fight struct {
 	Exp           uint        `db:"exp"`
 	DroppedItemID *uuid.UUID  `db:"dropped_item_id"`
 	Rounds        []*Round    `db:"-"`
+	// Effects still active on the fight participants.
+	Effects []*StatusEffect `db:"-"`
 }
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GoldTransactionReason string
+
+const (
+	// GoldReasonOpeningBalance carries the gold users had before the ledger
+	// existed.
+	GoldReasonOpeningBalance   GoldTransactionReason = "OPENING_BALANCE"
+	GoldReasonInitial          GoldTransactionReason = "INITIAL"
+	GoldReasonFightReward      GoldTransactionReason = "FIGHT_REWARD"
+	GoldReasonQuestReward      GoldTransactionReason = "QUEST_REWARD"
+	GoldReasonEquipmentBuy     GoldTransactionReason = "EQUIPMENT_BUY"
+	GoldReasonEquipmentSell    GoldTransactionReason = "EQUIPMENT_SELL"
+	GoldReasonConsumableBuy    GoldTransactionReason = "CONSUMABLE_BUY"
+	GoldReasonEquipmentRepair  GoldTransactionReason = "EQUIPMENT_REPAIR"
+	GoldReasonEquipmentEnhance GoldTransactionReason = "EQUIPMENT_ENHANCE"
+	GoldReasonTrade            GoldTransactionReason = "TRADE"
+	GoldReasonAuctionBid       GoldTransactionReason = "AUCTION_BID"
+	GoldReasonAuctionRefund    GoldTransactionReason = "AUCTION_REFUND"
+	GoldReasonAuctionSale      GoldTransactionReason = "AUCTION_SALE"
+	GoldReasonAuctionFee       GoldTransactionReason = "AUCTION_FEE"
+	GoldReasonInventoryUpgrade GoldTransactionReason = "INVENTORY_UPGRADE"
+	GoldReasonMailSend         GoldTransactionReason = "MAIL_SEND"
+	GoldReasonMailCollect      GoldTransactionReason = "MAIL_COLLECT"
+	GoldReasonGuildCreate      GoldTransactionReason = "GUILD_CREATE"
+	GoldReasonGuildDeposit     GoldTransactionReason = "GUILD_DEPOSIT"
+	GoldReasonGuildWithdraw    GoldTransactionReason = "GUILD_WITHDRAW"
+)
+
+// EarnedGoldReasons are the rewards the weekly gold board counts.
+var EarnedGoldReasons = []GoldTransactionReason{GoldReasonFightReward, GoldReasonQuestReward}
+
+// Earned reports whether the gold is a reward rather than trading or
+// spending.
+func (r GoldTransactionReason) Earned() bool {
+	return slices.Contains(EarnedGoldReasons, r)
+}
+
+// GoldTransaction is one entry of the gold ledger. ReferenceID points at the
+// fight, quest or item behind the change.
+type GoldTransaction struct {
+	ID           uuid.UUID             `db:"id"`
+	CreatedAt    time.Time             `db:"created_at"`
+	UserID       uuid.UUID             `db:"user_id"`
+	Delta        int64                 `db:"delta"`
+	Reason       GoldTransactionReason `db:"reason"`
+	ReferenceID  *uuid.UUID            `db:"reference_id"`
+	BalanceAfter int64                 `db:"balance_after"`
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// GuildCreateCost is the gold founding a guild costs.
+	GuildCreateCost    = 1000
+	MinGuildNameLength = 3
+	MaxGuildNameLength = 32
+	MinGuildTagLength  = 2
+	MaxGuildTagLength  = 5
+	// MaxGuildMembers is how many players one guild takes.
+	MaxGuildMembers = 50
+	// GuildInviteLifetime is how long an invite can be accepted.
+	GuildInviteLifetime = 7 * 24 * time.Hour
+	// MaxGuildMessageLength counts characters.
+	MaxGuildMessageLength = 500
+	// GuildChatHistory is how many recent messages the chat history returns.
+	GuildChatHistory = 50
+)
+
+type GuildRank string
+
+const (
+	GuildRankLeader  GuildRank = "leader"
+	GuildRankOfficer GuildRank = "officer"
+	GuildRankMember  GuildRank = "member"
+)
+
+type GuildPermission string
+
+const (
+	GuildPermissionInvite   GuildPermission = "invite"
+	GuildPermissionKick     GuildPermission = "kick"
+	GuildPermissionWithdraw GuildPermission = "withdraw"
+	// GuildPermissionManage covers setting ranks and passing on leadership.
+	GuildPermissionManage GuildPermission = "manage"
+)
+
+var guildRankPermissions = map[GuildRank][]GuildPermission{
+	GuildRankLeader:  {GuildPermissionInvite, GuildPermissionKick, GuildPermissionWithdraw, GuildPermissionManage},
+	GuildRankOfficer: {GuildPermissionInvite, GuildPermissionKick, GuildPermissionWithdraw},
+	GuildRankMember:  {},
+}
+
+var guildRankOrder = map[GuildRank]int{
+	GuildRankLeader:  3,
+	GuildRankOfficer: 2,
+	GuildRankMember:  1,
+}
+
+func (r GuildRank) Valid() bool {
+	_, ok := guildRankOrder[r]
+	return ok
+}
+
+func (r GuildRank) Can(permission GuildPermission) bool {
+	return slices.Contains(guildRankPermissions[r], permission)
+}
+
+// Permissions lists what the rank may do.
+func (r GuildRank) Permissions() []GuildPermission {
+	return guildRankPermissions[r]
+}
+
+// Outranks is true when r is above other, members can only kick below them.
+func (r GuildRank) Outranks(other GuildRank) bool {
+	return guildRankOrder[r] > guildRankOrder[other]
+}
+
+// NormalizeGuildName trims the name and reports whether it is usable.
+func NormalizeGuildName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	length := utf8.RuneCountInString(name)
+	return name, length >= MinGuildNameLength && length <= MaxGuildNameLength
+}
+
+// NormalizeGuildTag upper-cases the tag and reports whether it is usable,
+// tags are latin letters and digits only.
+func NormalizeGuildTag(tag string) (string, bool) {
+	tag = strings.ToUpper(strings.TrimSpace(tag))
+	if len(tag) < MinGuildTagLength || len(tag) > MaxGuildTagLength {
+		return tag, false
+	}
+	for _, r := range tag {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return tag, false
+		}
+	}
+	return tag, true
+}
+
+type Guild struct {
+	ID        uuid.UUID `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	Name      string    `db:"name"`
+	Tag       string    `db:"tag"`
+	Treasury  uint      `db:"treasury"`
+
+	Members []*GuildMember
+}
+
+type GuildMember struct {
+	UserID   uuid.UUID `db:"user_id"`
+	GuildID  uuid.UUID `db:"guild_id"`
+	Rank     GuildRank `db:"rank"`
+	JoinedAt time.Time `db:"joined_at"`
+	Username string    `db:"username"`
+	Level    uint      `db:"level"`
+}
+
+type GuildInvite struct {
+	GuildID   uuid.UUID  `db:"guild_id"`
+	GuildName string     `db:"guild_name"`
+	GuildTag  string     `db:"guild_tag"`
+	UserID    uuid.UUID  `db:"user_id"`
+	InviterID *uuid.UUID `db:"inviter_id"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at"`
+}
+
+// GuildTransaction is one entry of the treasury ledger, UserID is the member
+// who deposited or withdrew.
+type GuildTransaction struct {
+	ID           uuid.UUID  `db:"id"`
+	CreatedAt    time.Time  `db:"created_at"`
+	GuildID      uuid.UUID  `db:"guild_id"`
+	UserID       *uuid.UUID `db:"user_id"`
+	Username     string     `db:"username"`
+	Delta        int64      `db:"delta"`
+	BalanceAfter int64      `db:"balance_after"`
+}
+
+type GuildMessage struct {
+	ID        uuid.UUID  `db:"id"`
+	CreatedAt time.Time  `db:"created_at"`
+	GuildID   uuid.UUID  `db:"guild_id"`
+	UserID    *uuid.UUID `db:"user_id"`
+	Username  string     `db:"username"`
+	Text      string     `db:"text"`
+}
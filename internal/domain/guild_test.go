@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuildRank_Can(t *testing.T) {
+	assert.True(t, GuildRankLeader.Can(GuildPermissionManage))
+	assert.True(t, GuildRankOfficer.Can(GuildPermissionInvite))
+	assert.True(t, GuildRankOfficer.Can(GuildPermissionKick))
+	assert.True(t, GuildRankOfficer.Can(GuildPermissionWithdraw))
+	assert.False(t, GuildRankOfficer.Can(GuildPermissionManage))
+	assert.False(t, GuildRankMember.Can(GuildPermissionInvite))
+	assert.False(t, GuildRank("founder").Can(GuildPermissionInvite))
+}
+
+func TestGuildRank_Outranks(t *testing.T) {
+	assert.True(t, GuildRankLeader.Outranks(GuildRankOfficer))
+	assert.True(t, GuildRankOfficer.Outranks(GuildRankMember))
+	assert.False(t, GuildRankOfficer.Outranks(GuildRankOfficer))
+	assert.False(t, GuildRankMember.Outranks(GuildRankLeader))
+}
+
+func TestNormalizeGuildTag(t *testing.T) {
+	tag, ok := NormalizeGuildTag(" moon ")
+	assert.True(t, ok)
+	assert.Equal(t, "MOON", tag)
+
+	_, ok = NormalizeGuildTag("M")
+	assert.False(t, ok, "too short")
+	_, ok = NormalizeGuildTag("MOONSH")
+	assert.False(t, ok, "too long")
+	_, ok = NormalizeGuildTag("M-1")
+	assert.False(t, ok, "not alphanumeric")
+}
+
+func TestNormalizeGuildName(t *testing.T) {
+	name, ok := NormalizeGuildName("  Moon Riders ")
+	assert.True(t, ok)
+	assert.Equal(t, "Moon Riders", name)
+
+	_, ok = NormalizeGuildName(" ab ")
+	assert.False(t, ok)
+}
@@ -2,8 +2,113 @@ package domain
 
 import "github.com/google/uuid"
 
+const (
+	// InstanceBonusChance is the percent chance for each stat of a bought item
+	// to roll a bonus.
+	InstanceBonusChance = 20
+	// RepairCostDivisor makes a full repair cost this fraction of the price.
+	RepairCostDivisor = 2
+	// EquipmentWearPerRound is the durability every equipped item loses each
+	// fight round.
+	EquipmentWearPerRound = 1
+
+	// DefaultInventoryCapacity is the number of bag slots new users get.
+	DefaultInventoryCapacity = 30
+	// InventoryCapacityStep is how many slots one upgrade adds.
+	InventoryCapacityStep = 10
+	// MaxInventoryCapacity is the largest bag upgrades reach.
+	MaxInventoryCapacity = 100
+	// InventoryUpgradeBaseCost is the gold the first upgrade costs, every
+	// further one costs that much more.
+	InventoryUpgradeBaseCost = 200
+	// BankCapacity is the number of slots in every user's bank.
+	BankCapacity = 50
+)
+
+// Inventory is an item instance owned by a user. Instances stay in the
+// inventory while equipped, EquippedSlot then names the slot. Copies of a
+// stackable item share one row, Quantity counts them.
 type Inventory struct {
 	Model
-	UserID          uuid.UUID `db:"user_id"`
-	EquipmentItemID uuid.UUID `db:"equipment_item_id"`
+	UserID           uuid.UUID `db:"user_id"`
+	EquipmentItemID  uuid.UUID `db:"equipment_item_id"`
+	Durability       uint      `db:"durability"`
+	MaxDurability    uint      `db:"max_durability"`
+	BonusAttack      uint      `db:"bonus_attack"`
+	BonusDefense     uint      `db:"bonus_defense"`
+	BonusHp          uint      `db:"bonus_hp"`
+	EnhancementLevel uint      `db:"enhancement_level"`
+	EquippedSlot     *string   `db:"equipped_slot"`
+	Quantity         uint      `db:"quantity"`
+	Banked           bool      `db:"banked"`
+	// EscrowMailID is the mail the instance is attached to, only loaded with
+	// mail attachments.
+	EscrowMailID *uuid.UUID `db:"escrow_mail_id"`
+}
+
+// InventoryItem is an instance together with the item it was made from.
+type InventoryItem struct {
+	*EquipmentItem
+	Instance *Inventory
+}
+
+// Broken instances stay equipped but add nothing to the user's stats.
+func (i *Inventory) Broken() bool {
+	return i.Durability == 0
+}
+
+// Stats returns what the instance adds to the user while equipped.
+func (i *Inventory) Stats(item *EquipmentItem) (attack, defense, hp int) {
+	if i.Broken() {
+		return 0, 0, 0
+	}
+	return i.FullStats(item)
+}
+
+// FullStats returns the enhanced item stats plus the rolled bonus, ignoring
+// durability.
+func (i *Inventory) FullStats(item *EquipmentItem) (attack, defense, hp int) {
+	return int(EnhanceStat(item.Attack, i.EnhancementLevel) + i.BonusAttack),
+		int(EnhanceStat(item.Defense, i.EnhancementLevel) + i.BonusDefense),
+		int(EnhanceStat(item.Hp, i.EnhancementLevel) + i.BonusHp)
+}
+
+// RepairCost is the gold needed to restore full durability, rounded up so any
+// repair costs at least one gold.
+func (i *Inventory) RepairCost(item *EquipmentItem) uint {
+	missing := i.MaxDurability - i.Durability
+	if missing == 0 || i.MaxDurability == 0 {
+		return 0
+	}
+
+	total := item.Price * missing
+	divisor := i.MaxDurability * RepairCostDivisor
+	return max((total+divisor-1)/divisor, 1)
+}
+
+// RollInstanceBonus rolls the bonus stats of a new instance. Each stat the
+// item has gets a bonus of up to a tenth of it with InstanceBonusChance.
+// intn is rand.Intn, passed in so tests can fix the rolls.
+func RollInstanceBonus(item *EquipmentItem, intn func(n int) int) (attack, defense, hp uint) {
+	roll := func(stat uint) uint {
+		if stat == 0 || intn(100) >= InstanceBonusChance {
+			return 0
+		}
+		return uint(intn(int(max(stat/10, 1)))) + 1
+	}
+	return roll(item.Attack), roll(item.Defense), roll(item.Hp)
+}
+
+// InventoryUpgradeCost returns the gold for the next bag upgrade from the
+// given capacity, false when the bag can't grow any more.
+func InventoryUpgradeCost(capacity uint) (uint, bool) {
+	if capacity+InventoryCapacityStep > MaxInventoryCapacity {
+		return 0, false
+	}
+
+	upgrades := uint(0)
+	if capacity > DefaultInventoryCapacity {
+		upgrades = (capacity - DefaultInventoryCapacity) / InventoryCapacityStep
+	}
+	return InventoryUpgradeBaseCost * (upgrades + 1), true
 }
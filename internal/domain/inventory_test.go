@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventory_Stats(t *testing.T) {
+	item := &EquipmentItem{Attack: 10, Defense: 5, Hp: 20}
+	instance := &Inventory{Durability: 3, MaxDurability: 100, BonusAttack: 2, BonusHp: 1}
+
+	attack, defense, hp := instance.Stats(item)
+	assert.Equal(t, []int{12, 5, 21}, []int{attack, defense, hp})
+
+	instance.Durability = 0
+	assert.True(t, instance.Broken())
+	attack, defense, hp = instance.Stats(item)
+	assert.Equal(t, []int{0, 0, 0}, []int{attack, defense, hp})
+}
+
+func TestInventory_RepairCost(t *testing.T) {
+	item := &EquipmentItem{Price: 100}
+
+	assert.Equal(t, uint(0), (&Inventory{Durability: 100, MaxDurability: 100}).RepairCost(item))
+	assert.Equal(t, uint(50), (&Inventory{Durability: 0, MaxDurability: 100}).RepairCost(item))
+	assert.Equal(t, uint(13), (&Inventory{Durability: 75, MaxDurability: 100}).RepairCost(item))
+	assert.Equal(t, uint(1), (&Inventory{Durability: 99, MaxDurability: 100}).RepairCost(&EquipmentItem{Price: 1}))
+}
+
+func TestRollInstanceBonus(t *testing.T) {
+	item := &EquipmentItem{Attack: 30, Defense: 0, Hp: 5}
+
+	always := func(n int) int { return 0 }
+	attack, defense, hp := RollInstanceBonus(item, always)
+	assert.Equal(t, []uint{1, 0, 1}, []uint{attack, defense, hp})
+
+	never := func(n int) int { return n - 1 }
+	attack, defense, hp = RollInstanceBonus(item, never)
+	assert.Equal(t, []uint{0, 0, 0}, []uint{attack, defense, hp})
+}
+
+func TestInventory_FullStats(t *testing.T) {
+	item := &EquipmentItem{Attack: 10, Defense: 5, Hp: 20}
+	instance := &Inventory{Durability: 0, MaxDurability: 100, BonusAttack: 2, EnhancementLevel: 2}
+
+	attack, defense, hp := instance.FullStats(item)
+	assert.Equal(t, []int{14, 6, 24}, []int{attack, defense, hp})
+}
+
+func TestInventoryUpgradeCost(t *testing.T) {
+	cost, ok := InventoryUpgradeCost(DefaultInventoryCapacity)
+	assert.True(t, ok)
+	assert.Equal(t, uint(InventoryUpgradeBaseCost), cost)
+
+	cost, ok = InventoryUpgradeCost(DefaultInventoryCapacity + 2*InventoryCapacityStep)
+	assert.True(t, ok)
+	assert.Equal(t, uint(3*InventoryUpgradeBaseCost), cost)
+
+	_, ok = InventoryUpgradeCost(MaxInventoryCapacity)
+	assert.False(t, ok)
+}
@@ -0,0 +1,76 @@
+package domain
+
+// StatDelta is how the stats change when an item replaces what is in a slot,
+// negative where the new item is worse.
+type StatDelta struct {
+	Attack      int
+	Defense     int
+	Hp          int
+	CritChance  int
+	DodgeChance int
+	BlockChance int
+}
+
+// Score ranks upgrades by the attack, defense and hp they add.
+func (d StatDelta) Score() int {
+	return d.Attack + d.Defense + d.Hp
+}
+
+// ItemComparison is what equipping an item would do for the user. Equipped
+// is the instance the item replaces, nil for an empty slot.
+type ItemComparison struct {
+	Item       *EquipmentItem
+	Slot       string
+	Equipped   *InventoryItem
+	Delta      StatDelta
+	Price      uint
+	MeetsLevel bool
+	CanAfford  bool
+}
+
+// CompareItem returns the delta of a new copy of the item replacing the
+// equipped instance, nil for an empty slot. Broken instances add nothing, so
+// the item counts in full against them. Rolled bonuses of the new copy and
+// set bonuses are left out.
+func CompareItem(item *EquipmentItem, equipped *InventoryItem) StatDelta {
+	delta := StatDelta{
+		Attack:      int(item.Attack),
+		Defense:     int(item.Defense),
+		Hp:          int(item.Hp),
+		CritChance:  int(item.CritChance),
+		DodgeChance: int(item.DodgeChance),
+		BlockChance: int(item.BlockChance),
+	}
+	if equipped == nil || equipped.Instance.Broken() {
+		return delta
+	}
+
+	attack, defense, hp := equipped.Instance.Stats(equipped.EquipmentItem)
+	delta.Attack -= attack
+	delta.Defense -= defense
+	delta.Hp -= hp
+	delta.CritChance -= int(equipped.CritChance)
+	delta.DodgeChance -= int(equipped.DodgeChance)
+	delta.BlockChance -= int(equipped.BlockChance)
+	return delta
+}
+
+// ReplacedSlot picks the slot a new item of the slots' type would go to: the
+// first empty one, else the one whose instance adds the least. It is false
+// when there are no slots.
+func ReplacedSlot(slots []*EquipmentSlot, equipped map[string]*InventoryItem) (string, bool) {
+	var weakest string
+	weakestScore := 0
+	for _, slot := range slots {
+		current, ok := equipped[slot.Name]
+		if !ok {
+			return slot.Name, true
+		}
+
+		score := -CompareItem(&EquipmentItem{}, current).Score()
+		if weakest == "" || score < weakestScore {
+			weakest, weakestScore = slot.Name, score
+		}
+	}
+	return weakest, weakest != ""
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareItem(t *testing.T) {
+	sword := &EquipmentItem{Attack: 10, Hp: 5, SecondaryStats: SecondaryStats{CritChance: 3}}
+	axe := &EquipmentItem{Attack: 14, Defense: 2}
+
+	t.Run("empty slot", func(t *testing.T) {
+		delta := CompareItem(axe, nil)
+		assert.Equal(t, StatDelta{Attack: 14, Defense: 2}, delta)
+		assert.Equal(t, 16, delta.Score())
+	})
+
+	t.Run("against the equipped instance", func(t *testing.T) {
+		equipped := &InventoryItem{EquipmentItem: sword, Instance: &Inventory{Durability: 50, BonusAttack: 2}}
+		delta := CompareItem(axe, equipped)
+		assert.Equal(t, StatDelta{Attack: 2, Defense: 2, Hp: -5, CritChance: -3}, delta)
+		assert.Equal(t, -1, delta.Score())
+	})
+
+	t.Run("broken instance adds nothing", func(t *testing.T) {
+		equipped := &InventoryItem{EquipmentItem: sword, Instance: &Inventory{Durability: 0}}
+		assert.Equal(t, StatDelta{Attack: 14, Defense: 2}, CompareItem(axe, equipped))
+	})
+}
+
+func TestReplacedSlot(t *testing.T) {
+	rings := []*EquipmentSlot{{Name: "ring1"}, {Name: "ring2"}}
+	strong := &InventoryItem{EquipmentItem: &EquipmentItem{Attack: 5}, Instance: &Inventory{Durability: 10}}
+	weak := &InventoryItem{EquipmentItem: &EquipmentItem{Attack: 1}, Instance: &Inventory{Durability: 10}}
+
+	slot, ok := ReplacedSlot(rings, map[string]*InventoryItem{"ring1": strong})
+	assert.True(t, ok)
+	assert.Equal(t, "ring2", slot)
+
+	slot, ok = ReplacedSlot(rings, map[string]*InventoryItem{"ring1": strong, "ring2": weak})
+	assert.True(t, ok)
+	assert.Equal(t, "ring2", slot)
+
+	slot, ok = ReplacedSlot(rings, map[string]*InventoryItem{"ring1": weak, "ring2": strong})
+	assert.True(t, ok)
+	assert.Equal(t, "ring1", slot)
+
+	_, ok = ReplacedSlot(nil, nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ItemSet groups equipment items, wearing several of them unlocks bonuses.
+type ItemSet struct {
+	Model
+	Name    string `db:"name"`
+	Slug    string `db:"slug"`
+	ItemIDs []uuid.UUID
+	Bonuses []*ItemSetBonus
+}
+
+// ItemSetBonus is active while at least Pieces items of the set are equipped.
+// Tiers add up, a full set gets every bonus.
+type ItemSetBonus struct {
+	ID        uuid.UUID `db:"id"`
+	ItemSetID uuid.UUID `db:"item_set_id"`
+	Pieces    uint      `db:"pieces"`
+	Attack    uint      `db:"attack"`
+	Defense   uint      `db:"defense"`
+	Hp        uint      `db:"hp"`
+}
+
+// EquippedPieces counts the different items of the set among the equipped
+// ones, two copies of the same ring count once. Broken items still count.
+func (s *ItemSet) EquippedPieces(equipped []uuid.UUID) uint {
+	members := make(map[uuid.UUID]bool, len(s.ItemIDs))
+	for _, id := range s.ItemIDs {
+		members[id] = true
+	}
+
+	var pieces uint
+	for _, id := range equipped {
+		if members[id] {
+			pieces++
+			delete(members, id)
+		}
+	}
+	return pieces
+}
+
+// ActiveBonuses returns the tiers unlocked by the equipped pieces.
+func (s *ItemSet) ActiveBonuses(pieces uint) []*ItemSetBonus {
+	var active []*ItemSetBonus
+	for _, bonus := range s.Bonuses {
+		if pieces >= bonus.Pieces {
+			active = append(active, bonus)
+		}
+	}
+	return active
+}
+
+// SetBonusStats sums the active bonuses of every set.
+func SetBonusStats(sets []*ItemSet, equipped []uuid.UUID) (attack, defense, hp int) {
+	for _, set := range sets {
+		for _, bonus := range set.ActiveBonuses(set.EquippedPieces(equipped)) {
+			attack += int(bonus.Attack)
+			defense += int(bonus.Defense)
+			hp += int(bonus.Hp)
+		}
+	}
+	return attack, defense, hp
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemSet_EquippedPieces(t *testing.T) {
+	helmet, armor, ring := uuid.New(), uuid.New(), uuid.New()
+	set := &ItemSet{ItemIDs: []uuid.UUID{helmet, armor, ring}}
+
+	assert.Equal(t, uint(0), set.EquippedPieces(nil))
+	assert.Equal(t, uint(2), set.EquippedPieces([]uuid.UUID{helmet, uuid.New(), ring}))
+	assert.Equal(t, uint(1), set.EquippedPieces([]uuid.UUID{ring, ring}))
+}
+
+func TestSetBonusStats(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	set := &ItemSet{
+		ItemIDs: ids,
+		Bonuses: []*ItemSetBonus{
+			{Pieces: 2, Attack: 5},
+			{Pieces: 4, Hp: 50},
+		},
+	}
+
+	attack, defense, hp := SetBonusStats([]*ItemSet{set}, ids[:1])
+	assert.Equal(t, []int{0, 0, 0}, []int{attack, defense, hp})
+
+	attack, defense, hp = SetBonusStats([]*ItemSet{set}, ids[:3])
+	assert.Equal(t, []int{5, 0, 0}, []int{attack, defense, hp})
+
+	attack, defense, hp = SetBonusStats([]*ItemSet{set}, ids)
+	assert.Equal(t, []int{5, 0, 50}, []int{attack, defense, hp})
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxLoadouts is how many loadouts one user can save.
+	MaxLoadouts = 10
+	// MaxLoadoutNameLength is the longest loadout name in characters.
+	MaxLoadoutNameLength = 32
+)
+
+// Loadout is a saved set of equipment. Every filled slot remembers the
+// instance that was in it and its item, so another copy of the item is used
+// when that instance is gone.
+type Loadout struct {
+	ID        uuid.UUID `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	UserID    uuid.UUID `db:"user_id"`
+	Name      string    `db:"name"`
+	Slots     []*LoadoutSlot
+}
+
+type LoadoutSlot struct {
+	LoadoutID       uuid.UUID  `db:"loadout_id"`
+	Slot            string     `db:"slot"`
+	InventoryID     *uuid.UUID `db:"inventory_id"`
+	EquipmentItemID uuid.UUID  `db:"equipment_item_id"`
+	Item            *EquipmentItem
+}
+
+// Slot returns the saved slot with the given name, nil when the loadout
+// leaves it empty.
+func (l *Loadout) Slot(name string) *LoadoutSlot {
+	for _, slot := range l.Slots {
+		if slot.Slot == name {
+			return slot
+		}
+	}
+	return nil
+}
+
+// NormalizeLoadoutName trims the name and reports whether it is usable.
+func NormalizeLoadoutName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	length := utf8.RuneCountInString(name)
+	return name, length > 0 && length <= MaxLoadoutNameLength
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLoadoutName(t *testing.T) {
+	name, ok := NormalizeLoadoutName("  Tank  ")
+	assert.True(t, ok)
+	assert.Equal(t, "Tank", name)
+
+	_, ok = NormalizeLoadoutName("   ")
+	assert.False(t, ok)
+
+	_, ok = NormalizeLoadoutName(strings.Repeat("щ", MaxLoadoutNameLength))
+	assert.True(t, ok)
+
+	_, ok = NormalizeLoadoutName(strings.Repeat("a", MaxLoadoutNameLength+1))
+	assert.False(t, ok)
+}
+
+func TestLoadout_Slot(t *testing.T) {
+	weapon := &LoadoutSlot{Slot: "weapon", EquipmentItemID: uuid.New()}
+	loadout := &Loadout{Slots: []*LoadoutSlot{weapon}}
+
+	assert.Equal(t, weapon, loadout.Slot("weapon"))
+	assert.Nil(t, loadout.Slot("ring1"))
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MailLifetime is how long mail stays in the mailbox. Expired player mail
+	// with uncollected attachments goes back to the sender, everything else
+	// is deleted with its attachments.
+	MailLifetime = 30 * 24 * time.Hour
+	// MaxMailAttachments is how many instances one mail carries.
+	MaxMailAttachments = 5
+	// MaxMailSubjectLength and MaxMailBodyLength count characters.
+	MaxMailSubjectLength = 64
+	MaxMailBodyLength    = 1000
+)
+
+// Mail is a message in the recipient's mailbox. Attached gold and instances
+// are held in escrow until the recipient collects them. Mail without a sender
+// is system mail.
+type Mail struct {
+	ID          uuid.UUID  `db:"id"`
+	CreatedAt   time.Time  `db:"created_at"`
+	SenderID    *uuid.UUID `db:"sender_id"`
+	SenderName  string     `db:"sender_name"`
+	RecipientID uuid.UUID  `db:"recipient_id"`
+	Subject     string     `db:"subject"`
+	Body        string     `db:"body"`
+	Gold        uint       `db:"gold"`
+	Returned    bool       `db:"returned"`
+	ReadAt      *time.Time `db:"read_at"`
+	CollectedAt *time.Time `db:"collected_at"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+
+	Items []*InventoryItem
+}
+
+func (m *Mail) System() bool {
+	return m.SenderID == nil
+}
+
+func (m *Mail) Read() bool {
+	return m.ReadAt != nil
+}
+
+// HasAttachments is false once the attachments were collected. Items have
+// to be loaded.
+func (m *Mail) HasAttachments() bool {
+	return m.CollectedAt == nil && (m.Gold > 0 || len(m.Items) > 0)
+}
+
+func (m *Mail) Expired(now time.Time) bool {
+	return !now.Before(m.ExpiresAt)
+}
+
+// Returnable mail goes back to the sender when it expires uncollected.
+func (m *Mail) Returnable() bool {
+	return !m.System() && !m.Returned && m.HasAttachments()
+}
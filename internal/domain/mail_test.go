@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMail_Returnable(t *testing.T) {
+	senderID := uuid.New()
+	now := time.Now()
+
+	assert.True(t, (&Mail{SenderID: &senderID, Gold: 10}).Returnable())
+	assert.True(t, (&Mail{SenderID: &senderID, Items: []*InventoryItem{{}}}).Returnable())
+	assert.False(t, (&Mail{SenderID: &senderID}).Returnable(), "nothing attached")
+	assert.False(t, (&Mail{Gold: 10}).Returnable(), "system mail")
+	assert.False(t, (&Mail{SenderID: &senderID, Gold: 10, Returned: true}).Returnable(), "already returned")
+	assert.False(t, (&Mail{SenderID: &senderID, Gold: 10, CollectedAt: &now}).Returnable(), "collected")
+}
+
+func TestMail_Expired(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, (&Mail{ExpiresAt: now}).Expired(now))
+	assert.False(t, (&Mail{ExpiresAt: now.Add(time.Second)}).Expired(now))
+}
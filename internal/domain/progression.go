@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+var ErrInvalidProgression = errors.New("invalid progression config")
+
+type LevelReward struct {
+	FreeStats uint `json:"free_stats"`
+	Hp        uint `json:"hp"`
+}
+
+type ProgressionLevel struct {
+	Level  uint        `json:"level"`
+	Exp    uint        `json:"exp"`
+	Reward LevelReward `json:"reward"`
+}
+
+// ExpCurve controls how much exp a bot gives: the exp of the next level is
+// split across BotsToLevelBase * BotsToLevelGrowth^(level-1) same-level bots,
+// then scaled by the level difference between the player and the bot.
+type ExpCurve struct {
+	BotsToLevelBase   float64 `json:"bots_to_level_base"`
+	BotsToLevelGrowth float64 `json:"bots_to_level_growth"`
+	HigherLevelBonus  float64 `json:"higher_level_bonus"`
+	LowerLevelPenalty float64 `json:"lower_level_penalty"`
+}
+
+type Progression struct {
+	Version  uint               `json:"version"`
+	Levels   []ProgressionLevel `json:"levels"`
+	ExpCurve ExpCurve           `json:"exp_curve"`
+}
+
+func DefaultProgression() *Progression {
+	thresholds := []uint{0, 100, 200, 400, 800, 1500, 3000, 5000, 10000, 15000,
+		20000, 25000, 30000, 35000, 40000, 45000, 50000, 55000, 60000, 65000}
+
+	levels := make([]ProgressionLevel, len(thresholds))
+	for i, exp := range thresholds {
+		levels[i] = ProgressionLevel{Level: uint(i + 1), Exp: exp}
+	}
+
+	return &Progression{
+		Version: 1,
+		Levels:  levels,
+		ExpCurve: ExpCurve{
+			BotsToLevelBase:   5,
+			BotsToLevelGrowth: 1.6,
+			HigherLevelBonus:  0.25,
+			LowerLevelPenalty: 0.5,
+		},
+	}
+}
+
+func ParseProgression(data []byte) (*Progression, error) {
+	p := &Progression{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProgression, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks that levels start at 1 with zero exp, go up one by one and
+// require strictly growing exp.
+func (p *Progression) Validate() error {
+	if p.Version == 0 {
+		return fmt.Errorf("%w: version is required", ErrInvalidProgression)
+	}
+	if len(p.Levels) == 0 {
+		return fmt.Errorf("%w: no levels", ErrInvalidProgression)
+	}
+	if p.Levels[0].Level != 1 || p.Levels[0].Exp != 0 {
+		return fmt.Errorf("%w: first level must be 1 with 0 exp", ErrInvalidProgression)
+	}
+
+	for i := 1; i < len(p.Levels); i++ {
+		prev, cur := p.Levels[i-1], p.Levels[i]
+		if cur.Level != prev.Level+1 {
+			return fmt.Errorf("%w: level %d follows level %d", ErrInvalidProgression, cur.Level, prev.Level)
+		}
+		if cur.Exp <= prev.Exp {
+			return fmt.Errorf("%w: exp of level %d must be greater than %d", ErrInvalidProgression, cur.Level, prev.Exp)
+		}
+	}
+
+	curve := p.ExpCurve
+	// BotsToLevel truncates, a base below one bot would make it 0.
+	if curve.BotsToLevelBase < 1 || curve.BotsToLevelGrowth < 1 {
+		return fmt.Errorf("%w: bots to level curve must start at one bot and not decrease", ErrInvalidProgression)
+	}
+	if curve.HigherLevelBonus < 0 || curve.LowerLevelPenalty < 0 {
+		return fmt.Errorf("%w: level modifiers must not be negative", ErrInvalidProgression)
+	}
+
+	return nil
+}
+
+func (p *Progression) MaxLevel() uint {
+	return p.Levels[len(p.Levels)-1].Level
+}
+
+// RequiredExp returns the total exp needed to reach the level.
+func (p *Progression) RequiredExp(level uint) (uint, bool) {
+	if level < 1 || level > p.MaxLevel() {
+		return 0, false
+	}
+	return p.Levels[level-1].Exp, true
+}
+
+// Rewards sums the rewards of every level above from up to and including to.
+func (p *Progression) Rewards(from, to uint) LevelReward {
+	var reward LevelReward
+	for level := from + 1; level <= to && level <= p.MaxLevel(); level++ {
+		reward.FreeStats += p.Levels[level-1].Reward.FreeStats
+		reward.Hp += p.Levels[level-1].Reward.Hp
+	}
+	return reward
+}
+
+// LevelForExp returns the highest level reachable from the given level with
+// the given total exp.
+func (p *Progression) LevelForExp(level, exp uint) uint {
+	for level < p.MaxLevel() {
+		requiredExp, _ := p.RequiredExp(level + 1)
+		if exp < requiredExp {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+func (p *Progression) BotsToLevel(level uint) uint {
+	return uint(p.ExpCurve.BotsToLevelBase * math.Pow(p.ExpCurve.BotsToLevelGrowth, float64(level-1)))
+}
+
+func (p *Progression) LevelModifier(playerLvl, botLvl uint) float64 {
+	diff := int(botLvl) - int(playerLvl)
+
+	switch {
+	case diff == 0:
+		return 1.0
+	case diff > 0:
+		return 1.0 + float64(diff)*p.ExpCurve.HigherLevelBonus
+	default:
+		return 1.0 / (1.0 + float64(-diff)*p.ExpCurve.LowerLevelPenalty)
+	}
+}
+
+var currentProgression atomic.Pointer[Progression]
+
+func init() {
+	currentProgression.Store(DefaultProgression())
+}
+
+// CurrentProgression returns the progression loaded at startup.
+func CurrentProgression() *Progression {
+	return currentProgression.Load()
+}
+
+func SetProgression(p *Progression) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	currentProgression.Store(p)
+	return nil
+}
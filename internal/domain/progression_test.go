@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgression_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *Progression)
+		wantErr bool
+	}{
+		{
+			name:   "default is valid",
+			modify: func(p *Progression) {},
+		},
+		{
+			name:    "missing version",
+			modify:  func(p *Progression) { p.Version = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "no levels",
+			modify:  func(p *Progression) { p.Levels = nil },
+			wantErr: true,
+		},
+		{
+			name:    "first level requires exp",
+			modify:  func(p *Progression) { p.Levels[0].Exp = 10 },
+			wantErr: true,
+		},
+		{
+			name:    "level gap",
+			modify:  func(p *Progression) { p.Levels[5].Level = 7 },
+			wantErr: true,
+		},
+		{
+			name:    "exp not increasing",
+			modify:  func(p *Progression) { p.Levels[5].Exp = p.Levels[4].Exp },
+			wantErr: true,
+		},
+		{
+			name:    "shrinking bots to level curve",
+			modify:  func(p *Progression) { p.ExpCurve.BotsToLevelGrowth = 0.5 },
+			wantErr: true,
+		},
+		{
+			name:    "less than one bot to the first level",
+			modify:  func(p *Progression) { p.ExpCurve.BotsToLevelBase = 0.5 },
+			wantErr: true,
+		},
+		{
+			name: "cap raised above 20",
+			modify: func(p *Progression) {
+				p.Levels = append(p.Levels, ProgressionLevel{Level: 21, Exp: 80000})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultProgression()
+			tt.modify(p)
+
+			err := p.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidProgression)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProgression_LevelForExp(t *testing.T) {
+	p := DefaultProgression()
+
+	assert.Equal(t, uint(1), p.LevelForExp(1, 99))
+	assert.Equal(t, uint(2), p.LevelForExp(1, 100))
+	assert.Equal(t, uint(4), p.LevelForExp(1, 450))
+	assert.Equal(t, uint(20), p.LevelForExp(19, 1000000))
+	assert.Equal(t, uint(20), p.LevelForExp(20, 1000000))
+}
+
+func TestProgression_Rewards(t *testing.T) {
+	p := DefaultProgression()
+	for i := range p.Levels {
+		p.Levels[i].Reward = LevelReward{FreeStats: 3, Hp: uint(i + 1)}
+	}
+
+	assert.Equal(t, LevelReward{}, p.Rewards(2, 2))
+	assert.Equal(t, LevelReward{FreeStats: 3, Hp: 3}, p.Rewards(2, 3))
+	assert.Equal(t, LevelReward{FreeStats: 6, Hp: 7}, p.Rewards(2, 4))
+}
+
+func TestParseProgression(t *testing.T) {
+	data := []byte(`{
+		"version": 2,
+		"levels": [
+			{"level": 1, "exp": 0},
+			{"level": 2, "exp": 50, "reward": {"free_stats": 5, "hp": 10}}
+		],
+		"exp_curve": {"bots_to_level_base": 5, "bots_to_level_growth": 1.6, "higher_level_bonus": 0.25, "lower_level_penalty": 0.5}
+	}`)
+
+	p, err := ParseProgression(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), p.Version)
+	assert.Equal(t, uint(2), p.MaxLevel())
+	assert.Equal(t, LevelReward{FreeStats: 5, Hp: 10}, p.Rewards(1, 2))
+
+	_, err = ParseProgression([]byte(`{"version": 1, "levels": [{"level": 1, "exp": 0}, {"level": 2, "exp": 0}]}`))
+	assert.ErrorIs(t, err, ErrInvalidProgression)
+}
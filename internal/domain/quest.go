@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuestObjectiveType string
+
+const (
+	QuestObjectiveKillBot   QuestObjectiveType = "KILL_BOT"
+	QuestObjectiveVisitCell QuestObjectiveType = "VISIT_CELL"
+	QuestObjectiveBuyItem   QuestObjectiveType = "BUY_ITEM"
+	// Nothing reports gathering yet, resources come with the tool items.
+	// Until then quests with this objective can't be created or accepted.
+	QuestObjectiveGatherResource QuestObjectiveType = "GATHER_RESOURCE"
+)
+
+// Tracked reports whether some game action moves quests with the objective
+// forward.
+func (t QuestObjectiveType) Tracked() bool {
+	switch t {
+	case QuestObjectiveKillBot, QuestObjectiveVisitCell, QuestObjectiveBuyItem:
+		return true
+	}
+	return false
+}
+
+type QuestStatus string
+
+const (
+	QuestStatusInProgress QuestStatus = "IN_PROGRESS"
+	QuestStatusCompleted  QuestStatus = "COMPLETED"
+	QuestStatusClaimed    QuestStatus = "CLAIMED"
+)
+
+type Quest struct {
+	Model
+	Name                  string             `db:"name"`
+	Slug                  string             `db:"slug"`
+	Description           string             `db:"description"`
+	RequiredLevel         uint               `db:"required_level"`
+	ObjectiveType         QuestObjectiveType `db:"objective_type"`
+	TargetSlug            string             `db:"target_slug"`
+	TargetCount           uint               `db:"target_count"`
+	RewardGold            uint               `db:"reward_gold"`
+	RewardExp             uint               `db:"reward_exp"`
+	RewardEquipmentItemID *uuid.UUID         `db:"reward_equipment_item_id"`
+	Daily                 bool               `db:"daily"`
+}
+
+type UserQuest struct {
+	ID          uuid.UUID   `db:"id"`
+	CreatedAt   time.Time   `db:"created_at"`
+	UserID      uuid.UUID   `db:"user_id"`
+	QuestID     uuid.UUID   `db:"quest_id"`
+	Progress    uint        `db:"progress"`
+	Status      QuestStatus `db:"status"`
+	CompletedAt *time.Time  `db:"completed_at"`
+	ClaimedAt   *time.Time  `db:"claimed_at"`
+}
+
+// QuestProgress is a quest as seen by a user; UserQuest is nil until the
+// quest is accepted.
+type QuestProgress struct {
+	Quest     *Quest
+	UserQuest *UserQuest
+}
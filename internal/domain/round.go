@@ -39,4 +39,20 @@ type Round struct {
 	PlayerDefensePoint *BodyPart   `db:"player_defense_point"`
 	BotAttackPoint     *BodyPart   `db:"bot_attack_point"`
 	BotDefensePoint    *BodyPart   `db:"bot_defense_point"`
+	PlayerHeal         uint        `db:"player_heal"`
+	PlayerConsumableID *uuid.UUID  `db:"player_consumable_id"`
+	PlayerEffectDamage uint        `db:"player_effect_damage"`
+	BotEffectDamage    uint        `db:"bot_effect_damage"`
+	PlayerAbsorbed     uint        `db:"player_absorbed"`
+	BotAbsorbed        uint        `db:"bot_absorbed"`
+	PlayerStunned      bool        `db:"player_stunned"`
+	BotStunned         bool        `db:"bot_stunned"`
+	PlayerCrit         bool        `db:"player_crit"`
+	BotCrit            bool        `db:"bot_crit"`
+	PlayerDodged       bool        `db:"player_dodged"`
+	BotDodged          bool        `db:"bot_dodged"`
+	PlayerBlocked      bool        `db:"player_blocked"`
+	BotBlocked         bool        `db:"bot_blocked"`
+	// Effects applied during the round.
+	Effects []*StatusEffect `db:"-"`
 }
@@ -0,0 +1,65 @@
+package domain
+
+const (
+	BaseCritChance     = 5
+	BaseCritMultiplier = 150
+	BaseDodgeChance    = 5
+
+	MaxCritChance  = 75
+	MaxDodgeChance = 50
+	MaxBlockChance = 50
+)
+
+// SecondaryStats are percents: chances out of 100 and the crit multiplier as
+// the share of normal damage a crit deals. On equipment items the multiplier
+// is a bonus on top of the base one.
+type SecondaryStats struct {
+	CritChance     uint `db:"crit_chance"`
+	CritMultiplier uint `db:"crit_multiplier"`
+	DodgeChance    uint `db:"dodge_chance"`
+	BlockChance    uint `db:"block_chance"`
+}
+
+// LevelSecondaryStats returns the stats every fighter of the level has: one
+// percent of crit and dodge chance per two levels on top of the base.
+func LevelSecondaryStats(level uint) SecondaryStats {
+	return SecondaryStats{
+		CritChance:     BaseCritChance + level/2,
+		CritMultiplier: BaseCritMultiplier,
+		DodgeChance:    BaseDodgeChance + level/2,
+	}
+}
+
+func (s SecondaryStats) Add(other SecondaryStats) SecondaryStats {
+	return SecondaryStats{
+		CritChance:     s.CritChance + other.CritChance,
+		CritMultiplier: s.CritMultiplier + other.CritMultiplier,
+		DodgeChance:    s.DodgeChance + other.DodgeChance,
+		BlockChance:    s.BlockChance + other.BlockChance,
+	}
+}
+
+func (s SecondaryStats) Capped() SecondaryStats {
+	s.CritChance = min(s.CritChance, MaxCritChance)
+	s.DodgeChance = min(s.DodgeChance, MaxDodgeChance)
+	s.BlockChance = min(s.BlockChance, MaxBlockChance)
+	return s
+}
+
+// SecondaryStats combines the level, the allocated agility and luck and the
+// equipped items. Each point of luck adds a percent of crit chance, each point
+// of agility a percent of dodge chance.
+func (user *User) SecondaryStats(equipped []*EquipmentItem) SecondaryStats {
+	stats := LevelSecondaryStats(user.Level).Add(SecondaryStats{
+		CritChance:  user.Luck,
+		DodgeChance: user.Agility,
+	})
+	for _, item := range equipped {
+		stats = stats.Add(item.SecondaryStats)
+	}
+	return stats.Capped()
+}
+
+func (bot *Bot) SecondaryStats() SecondaryStats {
+	return LevelSecondaryStats(bot.Level).Capped()
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_SecondaryStats(t *testing.T) {
+	user := &User{Level: 10, Agility: 4, Luck: 2}
+	equipped := []*EquipmentItem{
+		{SecondaryStats: SecondaryStats{CritChance: 3, CritMultiplier: 20}},
+		{SecondaryStats: SecondaryStats{BlockChance: 15}},
+	}
+
+	assert.Equal(t, SecondaryStats{
+		CritChance:     BaseCritChance + 5 + 2 + 3,
+		CritMultiplier: BaseCritMultiplier + 20,
+		DodgeChance:    BaseDodgeChance + 5 + 4,
+		BlockChance:    15,
+	}, user.SecondaryStats(equipped))
+}
+
+func TestSecondaryStats_Capped(t *testing.T) {
+	stats := SecondaryStats{CritChance: 200, CritMultiplier: 400, DodgeChance: 90, BlockChance: 60}.Capped()
+
+	assert.Equal(t, SecondaryStats{
+		CritChance:     MaxCritChance,
+		CritMultiplier: 400,
+		DodgeChance:    MaxDodgeChance,
+		BlockChance:    MaxBlockChance,
+	}, stats)
+}
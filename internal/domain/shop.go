@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidShopSettings = errors.New("invalid shop settings")
+
+const (
+	// PriceDriftStepPercent is how much a restock moves the price.
+	PriceDriftStepPercent = 10
+	// PriceDriftMinPercent and PriceDriftMaxPercent bound the drifted price
+	// as a percent of the item's base price.
+	PriceDriftMinPercent = 80
+	PriceDriftMaxPercent = 150
+)
+
+// ShopSettings are the NPC shop economy knobs, loaded from the config at
+// startup.
+type ShopSettings struct {
+	// SellBackPercent of the base price is paid for items sold to a shop.
+	SellBackPercent uint
+	// PriceDrift lets restocks move prices with demand.
+	PriceDrift bool
+}
+
+func DefaultShopSettings() ShopSettings {
+	return ShopSettings{SellBackPercent: 50}
+}
+
+// Validate keeps the sell-back price below the lowest price a shop may ask,
+// so buying and selling back never makes gold.
+func (s ShopSettings) Validate() error {
+	if s.SellBackPercent > 100 {
+		return fmt.Errorf("%w: sell-back percent above 100", ErrInvalidShopSettings)
+	}
+	if s.PriceDrift && s.SellBackPercent > PriceDriftMinPercent {
+		return fmt.Errorf("%w: sell-back percent above the lowest drifted price", ErrInvalidShopSettings)
+	}
+	return nil
+}
+
+// SellPrice is what a shop pays for the item.
+func (s ShopSettings) SellPrice(basePrice uint) uint {
+	return basePrice * s.SellBackPercent / 100
+}
+
+var currentShopSettings atomic.Pointer[ShopSettings]
+
+func init() {
+	settings := DefaultShopSettings()
+	currentShopSettings.Store(&settings)
+}
+
+// CurrentShopSettings returns the settings loaded at startup.
+func CurrentShopSettings() ShopSettings {
+	return *currentShopSettings.Load()
+}
+
+func SetShopSettings(s ShopSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	currentShopSettings.Store(&s)
+	return nil
+}
+
+// ShopStock is how many copies of an item or a consumable a shop location
+// has and what it asks for them. EquipmentItemID is zero for consumable
+// stock.
+type ShopStock struct {
+	ID               uuid.UUID  `db:"id"`
+	CreatedAt        time.Time  `db:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at"`
+	LocationID       uuid.UUID  `db:"location_id"`
+	EquipmentItemID  uuid.UUID  `db:"equipment_item_id"`
+	ConsumableID     *uuid.UUID `db:"consumable_id"`
+	Quantity         uint       `db:"quantity"`
+	MaxQuantity      uint       `db:"max_quantity"`
+	RestockAmount    uint       `db:"restock_amount"`
+	Price            uint       `db:"price"`
+	SoldSinceRestock uint       `db:"sold_since_restock"`
+	RestockedAt      time.Time  `db:"restocked_at"`
+	Item             *EquipmentItem
+	Consumable       *Consumable
+}
+
+// ShopPriceHistory is the state of a stock at one restock.
+type ShopPriceHistory struct {
+	ID              uuid.UUID  `db:"id"`
+	RecordedAt      time.Time  `db:"recorded_at"`
+	LocationID      uuid.UUID  `db:"location_id"`
+	EquipmentItemID uuid.UUID  `db:"equipment_item_id"`
+	ConsumableID    *uuid.UUID `db:"consumable_id"`
+	Price           uint       `db:"price"`
+	Quantity        uint       `db:"quantity"`
+	Sold            uint       `db:"sold"`
+}
+
+// Restock adds RestockAmount copies up to MaxQuantity and sets the price for
+// the next period: drifted by demand, or back to the base price.
+func (s *ShopStock) Restock(basePrice uint, drift bool) {
+	s.Quantity = min(s.Quantity+s.RestockAmount, s.MaxQuantity)
+	if drift {
+		s.Price = DriftPrice(s.Price, basePrice, s.SoldSinceRestock, s.MaxQuantity)
+	} else {
+		s.Price = basePrice
+	}
+	s.SoldSinceRestock = 0
+}
+
+// DriftPrice raises the price a step when at least half of the max stock sold
+// since the last restock and lowers it when nothing sold, staying within
+// PriceDriftMinPercent and PriceDriftMaxPercent of the base price.
+func DriftPrice(price, basePrice, sold, maxQuantity uint) uint {
+	step := max(price*PriceDriftStepPercent/100, 1)
+	switch {
+	case sold*2 >= maxQuantity && sold > 0:
+		price += step
+	case sold == 0 && price > step:
+		price -= step
+	}
+
+	low := max(basePrice*PriceDriftMinPercent/100, 1)
+	high := max(basePrice*PriceDriftMaxPercent/100, low)
+	return min(max(price, low), high)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShopSettings_Validate(t *testing.T) {
+	assert.NoError(t, DefaultShopSettings().Validate())
+	assert.NoError(t, ShopSettings{SellBackPercent: 100}.Validate())
+	assert.ErrorIs(t, ShopSettings{SellBackPercent: 101}.Validate(), ErrInvalidShopSettings)
+	assert.NoError(t, ShopSettings{SellBackPercent: PriceDriftMinPercent, PriceDrift: true}.Validate())
+	assert.ErrorIs(t, ShopSettings{SellBackPercent: PriceDriftMinPercent + 1, PriceDrift: true}.Validate(), ErrInvalidShopSettings)
+}
+
+func TestShopSettings_SellPrice(t *testing.T) {
+	assert.Equal(t, uint(50), ShopSettings{SellBackPercent: 50}.SellPrice(100))
+	assert.Equal(t, uint(0), ShopSettings{SellBackPercent: 50}.SellPrice(1))
+}
+
+func TestDriftPrice(t *testing.T) {
+	// Half the stock sold raises the price, nothing sold lowers it.
+	assert.Equal(t, uint(110), DriftPrice(100, 100, 5, 10))
+	assert.Equal(t, uint(100), DriftPrice(100, 100, 4, 10))
+	assert.Equal(t, uint(90), DriftPrice(100, 100, 0, 10))
+
+	// The price stays between 80% and 150% of the base price.
+	assert.Equal(t, uint(150), DriftPrice(145, 100, 10, 10))
+	assert.Equal(t, uint(80), DriftPrice(85, 100, 0, 10))
+	assert.Equal(t, uint(1), DriftPrice(1, 1, 0, 10))
+}
+
+func TestShopStock_Restock(t *testing.T) {
+	stock := &ShopStock{Quantity: 9, MaxQuantity: 10, RestockAmount: 2, Price: 120, SoldSinceRestock: 0}
+	stock.Restock(100, false)
+	assert.Equal(t, uint(10), stock.Quantity)
+	assert.Equal(t, uint(100), stock.Price)
+
+	stock = &ShopStock{Quantity: 0, MaxQuantity: 10, RestockAmount: 2, Price: 100, SoldSinceRestock: 10}
+	stock.Restock(100, true)
+	assert.Equal(t, uint(2), stock.Quantity)
+	assert.Equal(t, uint(110), stock.Price)
+	assert.Equal(t, uint(0), stock.SoldSinceRestock)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Stats are a user's attack, defense and max hp.
+type Stats struct {
+	Attack  uint
+	Defense uint
+	Hp      uint
+}
+
+// StatBonus is a temporary change of the stats, e.g. a buff. It can be
+// negative.
+type StatBonus struct {
+	Attack  int
+	Defense int
+	Hp      int
+}
+
+// EffectiveStats adds the equipped instances, the set bonuses and the buffs
+// to the base stats. Broken instances add nothing but still count towards
+// their sets. Hp never drops below one.
+func EffectiveStats(base Stats, equipped []*InventoryItem, sets []*ItemSet, buffs ...StatBonus) Stats {
+	attack, defense, hp := int(base.Attack), int(base.Defense), int(base.Hp)
+
+	ids := make([]uuid.UUID, 0, len(equipped))
+	for _, item := range equipped {
+		itemAttack, itemDefense, itemHp := item.Instance.Stats(item.EquipmentItem)
+		attack += itemAttack
+		defense += itemDefense
+		hp += itemHp
+		ids = append(ids, item.ID)
+	}
+
+	setAttack, setDefense, setHp := SetBonusStats(sets, ids)
+	attack += setAttack
+	defense += setDefense
+	hp += setHp
+
+	for _, buff := range buffs {
+		attack += buff.Attack
+		defense += buff.Defense
+		hp += buff.Hp
+	}
+
+	return Stats{
+		Attack:  uint(max(attack, 0)),
+		Defense: uint(max(defense, 0)),
+		Hp:      uint(max(hp, 1)),
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveStats(t *testing.T) {
+	base := Stats{Attack: 5, Defense: 3, Hp: 100}
+	sword := &EquipmentItem{Model: Model{ID: uuid.New()}, Attack: 10}
+	helmet := &EquipmentItem{Model: Model{ID: uuid.New()}, Defense: 4, Hp: 20}
+
+	equipped := []*InventoryItem{
+		{EquipmentItem: sword, Instance: &Inventory{Durability: 50, MaxDurability: 100, BonusAttack: 1}},
+		{EquipmentItem: helmet, Instance: &Inventory{Durability: 0, MaxDurability: 100}},
+	}
+	set := &ItemSet{
+		ItemIDs: []uuid.UUID{sword.ID, helmet.ID},
+		Bonuses: []*ItemSetBonus{{Pieces: 2, Hp: 50}},
+	}
+
+	stats := EffectiveStats(base, equipped, []*ItemSet{set})
+	assert.Equal(t, Stats{Attack: 16, Defense: 3, Hp: 150}, stats)
+
+	stats = EffectiveStats(base, equipped, []*ItemSet{set}, StatBonus{Attack: -100, Hp: -500})
+	assert.Equal(t, Stats{Attack: 0, Defense: 3, Hp: 1}, stats)
+}
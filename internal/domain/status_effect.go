@@ -0,0 +1,84 @@
+package domain
+
+import "github.com/google/uuid"
+
+type StatusEffectType string
+
+const (
+	StatusEffectPoison StatusEffectType = "POISON"
+	StatusEffectBleed  StatusEffectType = "BLEED"
+	StatusEffectStun   StatusEffectType = "STUN"
+	StatusEffectShield StatusEffectType = "SHIELD"
+	// Attack and defense buffs add their value to the target's stats while
+	// they last.
+	StatusEffectAttackUp  StatusEffectType = "ATTACK_UP"
+	StatusEffectDefenseUp StatusEffectType = "DEFENSE_UP"
+)
+
+// Harmful effects land on the opponent, the rest on whoever applies them.
+func (t StatusEffectType) Harmful() bool {
+	switch t {
+	case StatusEffectShield, StatusEffectAttackUp, StatusEffectDefenseUp:
+		return false
+	}
+	return true
+}
+
+type FightParticipant string
+
+const (
+	FightParticipantPlayer FightParticipant = "PLAYER"
+	FightParticipantBot    FightParticipant = "BOT"
+)
+
+func (p FightParticipant) Opponent() FightParticipant {
+	if p == FightParticipantPlayer {
+		return FightParticipantBot
+	}
+	return FightParticipantPlayer
+}
+
+// StatusEffectSource is the effect a weapon, bot or consumable applies.
+// Value is the damage per round for poison and bleed and the absorbed damage
+// for shields; Chance is in percent.
+type StatusEffectSource struct {
+	StatusEffectType     *StatusEffectType `db:"status_effect_type"`
+	StatusEffectValue    uint              `db:"status_effect_value"`
+	StatusEffectDuration uint              `db:"status_effect_duration"`
+	StatusEffectChance   uint              `db:"status_effect_chance"`
+}
+
+func (s StatusEffectSource) HasStatusEffect() bool {
+	return s.StatusEffectType != nil && s.StatusEffectDuration > 0
+}
+
+// NewStatusEffect builds the effect applied by the owner in the given round.
+func (s StatusEffectSource) NewStatusEffect(fightID, roundID uuid.UUID, owner FightParticipant) *StatusEffect {
+	target := owner
+	if s.StatusEffectType.Harmful() {
+		target = owner.Opponent()
+	}
+
+	return &StatusEffect{
+		FightID:    fightID,
+		RoundID:    roundID,
+		Target:     target,
+		EffectType: *s.StatusEffectType,
+		Value:      s.StatusEffectValue,
+		RoundsLeft: s.StatusEffectDuration,
+	}
+}
+
+type StatusEffect struct {
+	Model
+	FightID    uuid.UUID        `db:"fight_id"`
+	RoundID    uuid.UUID        `db:"round_id"`
+	Target     FightParticipant `db:"target"`
+	EffectType StatusEffectType `db:"effect_type"`
+	Value      uint             `db:"value"`
+	RoundsLeft uint             `db:"rounds_left"`
+}
+
+func (e *StatusEffect) Active() bool {
+	return e.RoundsLeft > 0
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TradeStatus string
+
+const (
+	TradeStatusOpen      TradeStatus = "OPEN"
+	TradeStatusCompleted TradeStatus = "COMPLETED"
+	TradeStatusCancelled TradeStatus = "CANCELLED"
+)
+
+// Trade is a swap between two players in the same location. Each side offers
+// gold and instances, the instances are held in escrow by the trade. The swap
+// runs once both sides confirmed the current offers.
+type Trade struct {
+	ID                 uuid.UUID   `db:"id"`
+	CreatedAt          time.Time   `db:"created_at"`
+	UpdatedAt          time.Time   `db:"updated_at"`
+	InitiatorID        uuid.UUID   `db:"initiator_id"`
+	PartnerID          uuid.UUID   `db:"partner_id"`
+	LocationID         uuid.UUID   `db:"location_id"`
+	Status             TradeStatus `db:"status"`
+	InitiatorGold      uint        `db:"initiator_gold"`
+	PartnerGold        uint        `db:"partner_gold"`
+	InitiatorConfirmed bool        `db:"initiator_confirmed"`
+	PartnerConfirmed   bool        `db:"partner_confirmed"`
+	CompletedAt        *time.Time  `db:"completed_at"`
+	Items              []*InventoryItem
+}
+
+func (t *Trade) HasUser(userID uuid.UUID) bool {
+	return t.InitiatorID == userID || t.PartnerID == userID
+}
+
+// Counterpart returns the other side of the trade.
+func (t *Trade) Counterpart(userID uuid.UUID) uuid.UUID {
+	if t.InitiatorID == userID {
+		return t.PartnerID
+	}
+	return t.InitiatorID
+}
+
+// Gold returns the gold offered by the user.
+func (t *Trade) Gold(userID uuid.UUID) uint {
+	if t.InitiatorID == userID {
+		return t.InitiatorGold
+	}
+	return t.PartnerGold
+}
+
+func (t *Trade) Confirmed(userID uuid.UUID) bool {
+	if t.InitiatorID == userID {
+		return t.InitiatorConfirmed
+	}
+	return t.PartnerConfirmed
+}
+
+// ItemsOf returns the instances offered by the user.
+func (t *Trade) ItemsOf(userID uuid.UUID) []*InventoryItem {
+	var items []*InventoryItem
+	for _, item := range t.Items {
+		if item.Instance.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// TradeLog is one item or gold amount that changed hands in a trade.
+type TradeLog struct {
+	ID              uuid.UUID  `db:"id"`
+	CreatedAt       time.Time  `db:"created_at"`
+	TradeID         uuid.UUID  `db:"trade_id"`
+	FromUserID      uuid.UUID  `db:"from_user_id"`
+	ToUserID        uuid.UUID  `db:"to_user_id"`
+	InventoryID     *uuid.UUID `db:"inventory_id"`
+	EquipmentItemID *uuid.UUID `db:"equipment_item_id"`
+	Gold            uint       `db:"gold"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrade_Sides(t *testing.T) {
+	initiator, partner := uuid.New(), uuid.New()
+	sword := &InventoryItem{EquipmentItem: &EquipmentItem{Name: "Sword"}, Instance: &Inventory{UserID: initiator}}
+	shield := &InventoryItem{EquipmentItem: &EquipmentItem{Name: "Shield"}, Instance: &Inventory{UserID: partner}}
+
+	trade := &Trade{
+		InitiatorID:      initiator,
+		PartnerID:        partner,
+		InitiatorGold:    10,
+		PartnerGold:      20,
+		PartnerConfirmed: true,
+		Items:            []*InventoryItem{sword, shield},
+	}
+
+	assert.True(t, trade.HasUser(initiator))
+	assert.False(t, trade.HasUser(uuid.New()))
+	assert.Equal(t, partner, trade.Counterpart(initiator))
+	assert.Equal(t, initiator, trade.Counterpart(partner))
+	assert.Equal(t, uint(10), trade.Gold(initiator))
+	assert.Equal(t, uint(20), trade.Gold(partner))
+	assert.False(t, trade.Confirmed(initiator))
+	assert.True(t, trade.Confirmed(partner))
+	assert.Equal(t, []*InventoryItem{sword}, trade.ItemsOf(initiator))
+	assert.Equal(t, []*InventoryItem{shield}, trade.ItemsOf(partner))
+}
@@ -8,64 +8,76 @@ import (
 
 type User struct {
 	Model
-	UpdatedAt             time.Time  `db:"updated_at"`
-	Attack                uint       `db:"attack"`
-	AvatarID              *uuid.UUID `db:"avatar_id"`
-	CurrentHp             int        `db:"current_hp"`
-	Defense               uint       `db:"defense"`
-	Email                 string     `db:"email"`
-	Exp                   uint       `db:"exp"`
-	FreeStats             uint       `db:"free_stats"`
-	Gold                  uint       `db:"gold"`
-	Hp                    uint       `db:"hp"`
-	Level                 uint       `db:"level"`
-	LocationID            uuid.UUID  `db:"location_id"`
-	Name                  string     `db:"name"`
-	Password              string     `db:"password"`
-	Username              string     `db:"username"`
-	ChestEquipmentItemID  *uuid.UUID `db:"chest_equipment_item_id"`
-	BeltEquipmentItemID   *uuid.UUID `db:"belt_equipment_item_id"`
-	HeadEquipmentItemID   *uuid.UUID `db:"head_equipment_item_id"`
-	NeckEquipmentItemID   *uuid.UUID `db:"neck_equipment_item_id"`
-	WeaponEquipmentItemID *uuid.UUID `db:"weapon_equipment_item_id"`
-	ShieldEquipmentItemID *uuid.UUID `db:"shield_equipment_item_id"`
-	LegsEquipmentItemID   *uuid.UUID `db:"legs_equipment_item_id"`
-	FeetEquipmentItemID   *uuid.UUID `db:"feet_equipment_item_id"`
-	ArmsEquipmentItemID   *uuid.UUID `db:"arms_equipment_item_id"`
-	HandsEquipmentItemID  *uuid.UUID `db:"hands_equipment_item_id"`
-	Ring1EquipmentItemID  *uuid.UUID `db:"ring1_equipment_item_id"`
-	Ring2EquipmentItemID  *uuid.UUID `db:"ring2_equipment_item_id"`
-	Ring3EquipmentItemID  *uuid.UUID `db:"ring3_equipment_item_id"`
-	Ring4EquipmentItemID  *uuid.UUID `db:"ring4_equipment_item_id"`
-	Avatar                string     `db:"avatar"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	Attack     uint       `db:"attack"`
+	AvatarID   *uuid.UUID `db:"avatar_id"`
+	CurrentHp  int        `db:"current_hp"`
+	Defense    uint       `db:"defense"`
+	Email      string     `db:"email"`
+	Exp        uint       `db:"exp"`
+	FreeStats  uint       `db:"free_stats"`
+	Gold       uint       `db:"gold"`
+	Hp         uint       `db:"hp"`
+	Level      uint       `db:"level"`
+	LocationID uuid.UUID  `db:"location_id"`
+	Name       string     `db:"name"`
+	Password   string     `db:"password"`
+	Username   string     `db:"username"`
+	Avatar     string     `db:"avatar"`
+	Agility    uint       `db:"agility"`
+	Luck       uint       `db:"luck"`
+	Title      *string    `db:"title"`
+	// Attack, Defense and Hp are the effective stats, derived from the base
+	// stats by the stats calculator.
+	BaseAttack  uint `db:"base_attack"`
+	BaseDefense uint `db:"base_defense"`
+	BaseHp      uint `db:"base_hp"`
+	// InventoryCapacity is how many bag slots the user has.
+	InventoryCapacity uint `db:"inventory_capacity"`
+	// GuildTag is the tag of the user's guild, nil outside a guild.
+	GuildTag *string `db:"guild_tag"`
+	// Equipment maps the name of every filled slot to its item, the user
+	// repository loads it from equipped_items.
+	Equipment map[string]uuid.UUID `db:"-"`
 }
 
-var LevelMatrix = map[uint]uint{
-	1:  0,
-	2:  100,
-	3:  200,
-	4:  400,
-	5:  800,
-	6:  1500,
-	7:  3000,
-	8:  5000,
-	9:  10000,
-	10: 15000,
-	11: 20000,
-	12: 25000,
-	13: 30000,
-	14: 35000,
-	15: 40000,
-	16: 45000,
-	17: 50000,
-	18: 55000,
-	19: 60000,
-	20: 65000,
+func (user *User) BaseStats() Stats {
+	return Stats{Attack: user.BaseAttack, Defense: user.BaseDefense, Hp: user.BaseHp}
+}
+
+func (user *User) Stats() Stats {
+	return Stats{Attack: user.Attack, Defense: user.Defense, Hp: user.Hp}
+}
+
+// SetStats replaces the effective stats, current hp is capped at the new max.
+func (user *User) SetStats(stats Stats) {
+	user.Attack = stats.Attack
+	user.Defense = stats.Defense
+	user.Hp = stats.Hp
+	user.CurrentHp = min(user.CurrentHp, int(user.Hp))
+}
+
+// EquippedItemID returns the item in the named slot, nil when the slot is
+// empty or unknown.
+func (user *User) EquippedItemID(slot string) *uuid.UUID {
+	id, ok := user.Equipment[slot]
+	if !ok {
+		return nil
+	}
+	return &id
+}
+
+// EquippedItemIDs returns the ids of the items in every equipment slot.
+func (user *User) EquippedItemIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, id := range user.Equipment {
+		ids = append(ids, id)
+	}
+	return ids
 }
 
 func (user *User) ReachedNewLevel() bool {
-	nextLevel := user.Level + 1
-	requiredExp, exists := LevelMatrix[nextLevel]
+	requiredExp, exists := CurrentProgression().RequiredExp(user.Level + 1)
 	if !exists {
 		return false
 	}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"moonshine/internal/domain"
+)
+
+type Handler func(ctx context.Context, event domain.GameEvent) error
+
+// Bus delivers game events to the subscribed handlers. Handlers run
+// synchronously in the publisher's goroutine after its work is committed;
+// their errors are logged and never reach the publisher.
+type Bus struct {
+	handlers []Handler
+	mu       sync.RWMutex
+}
+
+var globalBus *Bus
+var once sync.Once
+
+func GetBus() *Bus {
+	once.Do(func() {
+		globalBus = &Bus{}
+	})
+	return globalBus
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *Bus) Publish(ctx context.Context, event domain.GameEvent) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			log.Printf("[Bus] Error handling %s for %s: %v\n", event.Type, event.UserID, err)
+		}
+	}
+}
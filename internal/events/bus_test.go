@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"moonshine/internal/domain"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := &Bus{}
+	event := domain.GameEvent{Type: domain.EventBotKilled, UserID: uuid.New(), Slug: "rat"}
+
+	var received []domain.GameEvent
+	bus.Subscribe(func(ctx context.Context, e domain.GameEvent) error {
+		return errors.New("handler failed")
+	})
+	bus.Subscribe(func(ctx context.Context, e domain.GameEvent) error {
+		received = append(received, e)
+		return nil
+	})
+
+	bus.Publish(context.Background(), event)
+
+	assert.Equal(t, []domain.GameEvent{event}, received)
+}
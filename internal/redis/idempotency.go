@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// idempotencyLockTTL bounds how long a crashed request can hold its key.
+	idempotencyLockTTL = 30 * time.Second
+	idempotencyTTL     = 24 * time.Hour
+	idempotencyPending = "pending"
+)
+
+var ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+
+type IdempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyStore remembers responses by idempotency key so a retried request
+// gets the first response back instead of running again. A nil store or
+// client never finds anything and lets every request through.
+type IdempotencyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewIdempotencyStore(client *redis.Client, prefix string) *IdempotencyStore {
+	return &IdempotencyStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *IdempotencyStore) enabled() bool {
+	return s != nil && s.client != nil
+}
+
+// Begin claims the key. It returns the stored response when the key was
+// already completed, ErrIdempotencyInProgress while another request holds it,
+// and nil, nil when the caller now owns the key and should run the request.
+func (s *IdempotencyStore) Begin(ctx context.Context, key string) (*IdempotentResponse, error) {
+	if !s.enabled() {
+		return nil, nil
+	}
+
+	ok, err := s.client.SetNX(ctx, s.formatKey(key), idempotencyPending, idempotencyLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+
+	value, err := s.client.Get(ctx, s.formatKey(key)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// The lock expired between the two calls, treat it as still busy.
+			return nil, ErrIdempotencyInProgress
+		}
+		return nil, err
+	}
+	if value == idempotencyPending {
+		return nil, ErrIdempotencyInProgress
+	}
+
+	var response IdempotentResponse
+	if err := json.Unmarshal([]byte(value), &response); err != nil {
+		return nil, fmt.Errorf("unmarshal idempotent response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// Complete stores the response for replays.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response *IdempotentResponse) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("marshal idempotent response: %w", err)
+	}
+
+	return s.client.Set(ctx, s.formatKey(key), data, idempotencyTTL).Err()
+}
+
+// Release frees the key so the request can be retried.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	return s.client.Del(ctx, s.formatKey(key)).Err()
+}
+
+func (s *IdempotencyStore) formatKey(key string) string {
+	return s.prefix + ":" + key
+}
@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore_BeginCompleteReplay(t *testing.T) {
+	store := NewIdempotencyStore(setupTestRedis(t), "idempotency")
+	ctx := context.Background()
+
+	response, err := store.Begin(ctx, "key1")
+	require.NoError(t, err)
+	assert.Nil(t, response)
+
+	_, err = store.Begin(ctx, "key1")
+	assert.ErrorIs(t, err, ErrIdempotencyInProgress)
+
+	stored := &IdempotentResponse{Status: 200, ContentType: "application/json", Body: []byte(`{"ok":true}`)}
+	require.NoError(t, store.Complete(ctx, "key1", stored))
+
+	response, err = store.Begin(ctx, "key1")
+	require.NoError(t, err)
+	assert.Equal(t, stored, response)
+}
+
+func TestIdempotencyStore_Release(t *testing.T) {
+	store := NewIdempotencyStore(setupTestRedis(t), "idempotency")
+	ctx := context.Background()
+
+	_, err := store.Begin(ctx, "key1")
+	require.NoError(t, err)
+	require.NoError(t, store.Release(ctx, "key1"))
+
+	response, err := store.Begin(ctx, "key1")
+	require.NoError(t, err)
+	assert.Nil(t, response)
+}
+
+func TestIdempotencyStore_NilClient(t *testing.T) {
+	store := NewIdempotencyStore(nil, "idempotency")
+	ctx := context.Background()
+
+	response, err := store.Begin(ctx, "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.NoError(t, store.Complete(ctx, "key1", &IdempotentResponse{Status: 200}))
+	assert.NoError(t, store.Release(ctx, "key1"))
+}
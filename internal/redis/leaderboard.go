@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LeaderboardKind string
+
+const (
+	LeaderboardLevel       LeaderboardKind = "level"
+	LeaderboardExp         LeaderboardKind = "exp"
+	LeaderboardGold        LeaderboardKind = "gold"
+	LeaderboardKills       LeaderboardKind = "kills"
+	LeaderboardWeeklyExp   LeaderboardKind = "weekly_exp"
+	LeaderboardWeeklyGold  LeaderboardKind = "weekly_gold"
+	LeaderboardWeeklyKills LeaderboardKind = "weekly_kills"
+)
+
+// weeklyTTL keeps last week's board around for a while after the week ends.
+const weeklyTTL = 14 * 24 * time.Hour
+
+var ErrUnknownLeaderboard = errors.New("unknown leaderboard")
+
+func ParseLeaderboardKind(value string) (LeaderboardKind, error) {
+	kind := LeaderboardKind(value)
+	switch kind {
+	case LeaderboardLevel, LeaderboardExp, LeaderboardGold, LeaderboardKills,
+		LeaderboardWeeklyExp, LeaderboardWeeklyGold, LeaderboardWeeklyKills:
+		return kind, nil
+	default:
+		return "", ErrUnknownLeaderboard
+	}
+}
+
+// Weekly boards count what was gained during the current ISO week and start
+// from scratch every Monday under a new key.
+func (k LeaderboardKind) Weekly() bool {
+	return k == LeaderboardWeeklyExp || k == LeaderboardWeeklyGold || k == LeaderboardWeeklyKills
+}
+
+type LeaderboardEntry struct {
+	Member string
+	Rank   int64
+	Score  float64
+}
+
+// Leaderboard keeps rankings in sorted sets, one per kind. A nil leaderboard
+// or client turns every call into a no-op.
+type Leaderboard struct {
+	client *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+func NewLeaderboard(client *redis.Client, prefix string) *Leaderboard {
+	return &Leaderboard{
+		client: client,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+func (l *Leaderboard) enabled() bool {
+	return l != nil && l.client != nil
+}
+
+func (l *Leaderboard) key(kind LeaderboardKind) string {
+	if kind.Weekly() {
+		year, week := l.now().UTC().ISOWeek()
+		return fmt.Sprintf("%s:%s:%d-W%02d", l.prefix, kind, year, week)
+	}
+	return l.prefix + ":" + string(kind)
+}
+
+// Set stores the member's total score on an all-time board.
+func (l *Leaderboard) Set(ctx context.Context, kind LeaderboardKind, member string, score float64) error {
+	if !l.enabled() {
+		return nil
+	}
+
+	return l.client.ZAdd(ctx, l.key(kind), redis.Z{Score: score, Member: member}).Err()
+}
+
+// Incr adds to the member's score. Weekly keys expire once the week is over.
+func (l *Leaderboard) Incr(ctx context.Context, kind LeaderboardKind, member string, delta float64) error {
+	if !l.enabled() || delta == 0 {
+		return nil
+	}
+
+	key := l.key(kind)
+	pipe := l.client.TxPipeline()
+	pipe.ZIncrBy(ctx, key, delta, member)
+	if kind.Weekly() {
+		pipe.Expire(ctx, key, weeklyTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Replace swaps the board for the given scores at once, so readers never see
+// a half rebuilt ranking.
+func (l *Leaderboard) Replace(ctx context.Context, kind LeaderboardKind, scores map[string]float64) error {
+	if !l.enabled() {
+		return nil
+	}
+
+	key := l.key(kind)
+	if len(scores) == 0 {
+		return l.client.Del(ctx, key).Err()
+	}
+
+	members := make([]redis.Z, 0, len(scores))
+	for member, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: member})
+	}
+
+	tmpKey := key + ":rebuild"
+	pipe := l.client.TxPipeline()
+	pipe.Del(ctx, tmpKey)
+	pipe.ZAdd(ctx, tmpKey, members...)
+	pipe.Rename(ctx, tmpKey, key)
+	if kind.Weekly() {
+		pipe.Expire(ctx, key, weeklyTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Top returns a page of the board, highest score first. Ranks start at 1.
+func (l *Leaderboard) Top(ctx context.Context, kind LeaderboardKind, offset, limit int64) ([]LeaderboardEntry, error) {
+	if !l.enabled() || limit <= 0 {
+		return nil, nil
+	}
+
+	scores, err := l.client.ZRevRangeWithScores(ctx, l.key(kind), offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]LeaderboardEntry, len(scores))
+	for i, z := range scores {
+		member, _ := z.Member.(string)
+		entries[i] = LeaderboardEntry{Member: member, Rank: offset + int64(i) + 1, Score: z.Score}
+	}
+	return entries, nil
+}
+
+// Rank returns the member's place on the board, or nil when it is not ranked.
+func (l *Leaderboard) Rank(ctx context.Context, kind LeaderboardKind, member string) (*LeaderboardEntry, error) {
+	if !l.enabled() {
+		return nil, nil
+	}
+
+	key := l.key(kind)
+	rank, err := l.client.ZRevRank(ctx, key, member).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	score, err := l.client.ZScore(ctx, key, member).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeaderboardEntry{Member: member, Rank: rank + 1, Score: score}, nil
+}
+
+func (l *Leaderboard) Count(ctx context.Context, kind LeaderboardKind) (int64, error) {
+	if !l.enabled() {
+		return 0, nil
+	}
+
+	return l.client.ZCard(ctx, l.key(kind)).Result()
+}
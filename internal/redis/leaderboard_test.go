@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboard_TopAndRank(t *testing.T) {
+	board := NewLeaderboard(setupTestRedis(t), "leaderboard")
+	ctx := context.Background()
+
+	require.NoError(t, board.Set(ctx, LeaderboardGold, "alice", 300))
+	require.NoError(t, board.Set(ctx, LeaderboardGold, "bob", 500))
+	require.NoError(t, board.Set(ctx, LeaderboardGold, "carol", 100))
+
+	top, err := board.Top(ctx, LeaderboardGold, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{
+		{Member: "bob", Rank: 1, Score: 500},
+		{Member: "alice", Rank: 2, Score: 300},
+	}, top)
+
+	page, err := board.Top(ctx, LeaderboardGold, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{{Member: "carol", Rank: 3, Score: 100}}, page)
+
+	rank, err := board.Rank(ctx, LeaderboardGold, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &LeaderboardEntry{Member: "alice", Rank: 2, Score: 300}, rank)
+
+	missing, err := board.Rank(ctx, LeaderboardGold, "dave")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	count, err := board.Count(ctx, LeaderboardGold)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestLeaderboard_Replace(t *testing.T) {
+	board := NewLeaderboard(setupTestRedis(t), "leaderboard")
+	ctx := context.Background()
+
+	require.NoError(t, board.Set(ctx, LeaderboardKills, "stale", 10))
+	require.NoError(t, board.Replace(ctx, LeaderboardKills, map[string]float64{"alice": 3, "bob": 7}))
+
+	top, err := board.Top(ctx, LeaderboardKills, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{
+		{Member: "bob", Rank: 1, Score: 7},
+		{Member: "alice", Rank: 2, Score: 3},
+	}, top)
+}
+
+func TestLeaderboard_WeeklyReset(t *testing.T) {
+	client := setupTestRedis(t)
+	board := NewLeaderboard(client, "leaderboard")
+	ctx := context.Background()
+
+	monday := time.Date(2026, 10, 12, 12, 0, 0, 0, time.UTC)
+	board.now = func() time.Time { return monday }
+	require.NoError(t, board.Incr(ctx, LeaderboardWeeklyExp, "alice", 40))
+	require.NoError(t, board.Incr(ctx, LeaderboardWeeklyExp, "alice", 2))
+
+	rank, err := board.Rank(ctx, LeaderboardWeeklyExp, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, rank.Score)
+
+	ttl, err := client.TTL(ctx, "leaderboard:weekly_exp:2026-W42").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+
+	board.now = func() time.Time { return monday.AddDate(0, 0, 7) }
+	rank, err = board.Rank(ctx, LeaderboardWeeklyExp, "alice")
+	require.NoError(t, err)
+	assert.Nil(t, rank)
+}
+
+func TestLeaderboard_NilClient(t *testing.T) {
+	board := NewLeaderboard(nil, "leaderboard")
+	ctx := context.Background()
+
+	assert.NoError(t, board.Set(ctx, LeaderboardGold, "alice", 1))
+	assert.NoError(t, board.Incr(ctx, LeaderboardWeeklyGold, "alice", 1))
+
+	top, err := board.Top(ctx, LeaderboardGold, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, top)
+}
+
+func TestParseLeaderboardKind(t *testing.T) {
+	kind, err := ParseLeaderboardKind("weekly_kills")
+	require.NoError(t, err)
+	assert.True(t, kind.Weekly())
+
+	_, err = ParseLeaderboardKind("hp")
+	assert.ErrorIs(t, err, ErrUnknownLeaderboard)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+type AchievementRepository struct {
+	db ExtHandle
+}
+
+func NewAchievementRepository(db ExtHandle) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+func (r *AchievementRepository) Create(achievement *domain.Achievement) error {
+	query := `
+		INSERT INTO achievements (name, slug, description, achievement_type, threshold, title)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		achievement.Name, achievement.Slug, achievement.Description, achievement.Type, achievement.Threshold, achievement.Title,
+	).Scan(&achievement.ID, &achievement.CreatedAt)
+}
+
+func (r *AchievementRepository) FindAll() ([]*domain.Achievement, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, description, achievement_type, threshold, title
+		FROM achievements
+		WHERE deleted_at IS NULL
+		ORDER BY achievement_type ASC, threshold ASC
+	`
+
+	achievements := []*domain.Achievement{}
+	if err := r.db.Select(&achievements, query); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// FindLockedByTypes returns the achievements of the given types the user has
+// not unlocked yet.
+func (r *AchievementRepository) FindLockedByTypes(userID uuid.UUID, types []domain.AchievementType) ([]*domain.Achievement, error) {
+	query := `
+		SELECT a.id, a.created_at, a.deleted_at, a.name, a.slug, a.description, a.achievement_type, a.threshold, a.title
+		FROM achievements a
+		WHERE a.deleted_at IS NULL
+		  AND a.achievement_type = ANY($2::achievement_type[])
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_achievements ua
+		      WHERE ua.achievement_id = a.id AND ua.user_id = $1
+		  )
+		ORDER BY a.threshold ASC
+	`
+
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = string(t)
+	}
+
+	achievements := []*domain.Achievement{}
+	if err := r.db.Select(&achievements, query, userID, pq.Array(typeNames)); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (r *AchievementRepository) FindUserAchievements(userID uuid.UUID) ([]*domain.UserAchievement, error) {
+	query := `
+		SELECT id, unlocked_at, user_id, achievement_id
+		FROM user_achievements
+		WHERE user_id = $1
+	`
+
+	userAchievements := []*domain.UserAchievement{}
+	if err := r.db.Select(&userAchievements, query, userID); err != nil {
+		return nil, err
+	}
+
+	return userAchievements, nil
+}
+
+// Unlock stores the achievement for the user and reports whether it was
+// unlocked by this call.
+func (r *AchievementRepository) Unlock(userID, achievementID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO user_achievements (user_id, achievement_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, achievement_id) DO NOTHING
+	`
+
+	res, err := r.db.Exec(query, userID, achievementID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *AchievementRepository) RecordVisitedCell(userID uuid.UUID, cellSlug string) error {
+	query := `
+		INSERT INTO user_visited_cells (user_id, location_id)
+		SELECT $1, id FROM locations
+		WHERE slug = $2 AND cell = true AND deleted_at IS NULL
+		ON CONFLICT (user_id, location_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, userID, cellSlug)
+	return err
+}
+
+// CountVisitedCells counts the Wayward Pines cells the user has been to. The
+// cells are told apart from other locations by their image folder.
+func (r *AchievementRepository) CountVisitedCells(userID uuid.UUID) (uint, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM user_visited_cells uvc
+		INNER JOIN locations l ON l.id = uvc.location_id
+		WHERE uvc.user_id = $1 AND l.cell = true AND l.deleted_at IS NULL
+		  AND l.image LIKE $2
+	`
+
+	var count uint
+	err := r.db.Get(&count, query, userID, domain.WaywardPinesSlug+"/cells/%")
+	return count, err
+}
+
+// CountKilledBots counts finished fights whose last round left the bot
+// without hp.
+func (r *AchievementRepository) CountKilledBots(userID uuid.UUID) (uint, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM fights f
+		WHERE f.user_id = $1 AND f.status = 'FINISHED' AND f.deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM rounds r WHERE r.fight_id = f.id AND r.bot_hp = 0)
+	`
+
+	var count uint
+	err := r.db.Get(&count, query, userID)
+	return count, err
+}
+
+// OwnsArtifact reports whether the user has an artifact in the inventory or
+// in an equipment slot.
+func (r *AchievementRepository) OwnsArtifact(userID uuid.UUID, equippedItemIDs []uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM inventory i
+			JOIN equipment_items e ON e.id = i.equipment_item_id
+			WHERE i.user_id = $1 AND i.deleted_at IS NULL AND e.artifact
+		) OR EXISTS (
+			SELECT 1 FROM equipment_items
+			WHERE id = ANY($2::uuid[]) AND artifact
+		)
+	`
+
+	ids := make([]string, len(equippedItemIDs))
+	for i, id := range equippedItemIDs {
+		ids[i] = id.String()
+	}
+
+	var owns bool
+	err := r.db.Get(&owns, query, userID, pq.Array(ids))
+	return owns, err
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var ErrAuctionNotFound = errors.New("auction not found")
+
+const auctionColumns = `id, created_at, updated_at, seller_id, inventory_id, equipment_item_id, start_price,
+	buyout_price, current_bid, bidder_id, listing_fee, status, expires_at, settled_at`
+
+type AuctionRepository struct {
+	db ExtHandle
+}
+
+func NewAuctionRepository(db ExtHandle) *AuctionRepository {
+	return &AuctionRepository{db: db}
+}
+
+func (r *AuctionRepository) Create(auction *domain.Auction) error {
+	query := `
+		INSERT INTO auctions (seller_id, inventory_id, equipment_item_id, start_price, buyout_price, listing_fee, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at, status
+	`
+
+	return r.db.QueryRow(query,
+		auction.SellerID,
+		auction.InventoryID,
+		auction.EquipmentItemID,
+		auction.StartPrice,
+		auction.BuyoutPrice,
+		auction.ListingFee,
+		auction.ExpiresAt,
+	).Scan(&auction.ID, &auction.CreatedAt, &auction.UpdatedAt, &auction.Status)
+}
+
+// FindByIDForUpdate locks the auction, so bids, buyouts and settlement are
+// applied one at a time.
+func (r *AuctionRepository) FindByIDForUpdate(id uuid.UUID) (*domain.Auction, error) {
+	query := `SELECT ` + auctionColumns + ` FROM auctions WHERE id = $1 FOR UPDATE`
+
+	auction := &domain.Auction{}
+	if err := r.db.Get(auction, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAuctionNotFound
+		}
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// Search returns active auctions matching the filter, the ones ending soonest
+// first.
+func (r *AuctionRepository) Search(filter domain.AuctionFilter, now time.Time) ([]*domain.Auction, error) {
+	query := `
+		SELECT a.id, a.created_at, a.updated_at, a.seller_id, a.inventory_id, a.equipment_item_id, a.start_price,
+			a.buyout_price, a.current_bid, a.bidder_id, a.listing_fee, a.status, a.expires_at, a.settled_at
+		FROM auctions a
+		INNER JOIN equipment_items ei ON ei.id = a.equipment_item_id
+		LEFT JOIN equipment_categories ec ON ec.id = ei.equipment_category_id
+		WHERE a.status = 'ACTIVE' AND a.expires_at > $1
+			AND ($2 = '' OR ec.type::text = $2)
+			AND ($3 = 0 OR ei.required_level >= $3)
+			AND ($4 = 0 OR ei.required_level <= $4)
+			AND ($5 = 0 OR COALESCE(a.current_bid, a.start_price) >= $5)
+			AND ($6 = 0 OR COALESCE(a.current_bid, a.start_price) <= $6)
+		ORDER BY a.expires_at ASC, a.id ASC
+		LIMIT $7 OFFSET $8
+	`
+
+	auctions := []*domain.Auction{}
+	err := r.db.Select(&auctions, query, now, filter.CategoryType, int(filter.MinLevel), int(filter.MaxLevel),
+		int(filter.MinPrice), int(filter.MaxPrice), filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return auctions, nil
+}
+
+// FindExpiredIDs returns active auctions that ended before now.
+func (r *AuctionRepository) FindExpiredIDs(now time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM auctions
+		WHERE status = 'ACTIVE' AND expires_at <= $1
+		ORDER BY expires_at ASC
+		LIMIT $2
+	`
+
+	ids := []uuid.UUID{}
+	if err := r.db.Select(&ids, query, now, limit); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *AuctionRepository) UpdateBid(auction *domain.Auction, bidderID uuid.UUID, amount uint) error {
+	query := `
+		UPDATE auctions
+		SET current_bid = $2, bidder_id = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at, current_bid, bidder_id
+	`
+
+	return r.db.QueryRow(query, auction.ID, amount, bidderID).Scan(&auction.UpdatedAt, &auction.CurrentBid, &auction.BidderID)
+}
+
+func (r *AuctionRepository) Settle(auction *domain.Auction, status domain.AuctionStatus) error {
+	query := `
+		UPDATE auctions
+		SET status = $2, settled_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, settled_at, updated_at
+	`
+
+	return r.db.QueryRow(query, auction.ID, status).Scan(&auction.Status, &auction.SettledAt, &auction.UpdatedAt)
+}
@@ -16,10 +16,10 @@ var (
 )
 
 type AvatarRepository struct {
-	db *sqlx.DB
+	db ExtHandle
 }
 
-func NewAvatarRepository(db *sqlx.DB) *AvatarRepository {
+func NewAvatarRepository(db ExtHandle) *AvatarRepository {
 	return &AvatarRepository{db: db}
 }
 
@@ -5,7 +5,6 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 
 	"moonshine/internal/domain"
 )
@@ -15,22 +14,24 @@ var (
 )
 
 type BotRepository struct {
-	db *sqlx.DB
+	db ExtHandle
 }
 
-func NewBotRepository(db *sqlx.DB) *BotRepository {
+func NewBotRepository(db ExtHandle) *BotRepository {
 	return &BotRepository{db: db}
 }
 
 func (r *BotRepository) Create(bot *domain.Bot) error {
 	query := `
-		INSERT INTO bots (name, slug, attack, defense, hp, level, avatar)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO bots (name, slug, attack, defense, hp, level, avatar,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at
 	`
 
 	err := r.db.QueryRow(query,
 		bot.Name, bot.Slug, bot.Attack, bot.Defense, bot.Hp, bot.Level, bot.Avatar,
+		bot.StatusEffectType, bot.StatusEffectValue, bot.StatusEffectDuration, bot.StatusEffectChance,
 	).Scan(&bot.ID, &bot.CreatedAt)
 	if err != nil {
 		return err
@@ -40,7 +41,8 @@ func (r *BotRepository) Create(bot *domain.Bot) error {
 
 func (r *BotRepository) FindBotsByLocationID(locationID uuid.UUID) ([]*domain.Bot, error) {
 	query := `
-		SELECT b.id, b.created_at, b.deleted_at, b.name, b.slug, b.attack, b.defense, b.hp, b.level, b.avatar
+		SELECT b.id, b.created_at, b.deleted_at, b.name, b.slug, b.attack, b.defense, b.hp, b.level, b.avatar,
+			b.status_effect_type, b.status_effect_value, b.status_effect_duration, b.status_effect_chance
 		FROM bots b
 		INNER JOIN location_bots lb ON lb.bot_id = b.id
 		WHERE lb.location_id = $1 AND b.deleted_at IS NULL AND lb.deleted_at IS NULL
@@ -55,9 +57,28 @@ func (r *BotRepository) FindBotsByLocationID(locationID uuid.UUID) ([]*domain.Bo
 	return bots, nil
 }
 
+func (r *BotRepository) FindAll() ([]*domain.Bot, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance
+		FROM bots
+		WHERE deleted_at IS NULL
+		ORDER BY level ASC, name ASC
+	`
+
+	var bots []*domain.Bot
+	err := r.db.Select(&bots, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return bots, nil
+}
+
 func (r *BotRepository) FindBySlug(slug string) (*domain.Bot, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar
+		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance
 		FROM bots
 		WHERE slug = $1 AND deleted_at IS NULL
 	`
@@ -76,7 +97,8 @@ func (r *BotRepository) FindBySlug(slug string) (*domain.Bot, error) {
 
 func (r *BotRepository) FindByID(id uuid.UUID) (*domain.Bot, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar
+		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance
 		FROM bots
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrConsumableNotFound = errors.New("consumable not found")
+	ErrConsumableNotOwned = errors.New("consumable not owned")
+)
+
+type ConsumableRepository struct {
+	db ExtHandle
+}
+
+func NewConsumableRepository(db ExtHandle) *ConsumableRepository {
+	return &ConsumableRepository{db: db}
+}
+
+func (r *ConsumableRepository) Create(consumable *domain.Consumable) error {
+	query := `
+		INSERT INTO consumables (name, slug, image, price, required_level, effect_type, effect_value,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		consumable.Name, consumable.Slug, consumable.Image, consumable.Price,
+		consumable.RequiredLevel, consumable.EffectType, consumable.EffectValue,
+		consumable.StatusEffectType, consumable.StatusEffectValue, consumable.StatusEffectDuration, consumable.StatusEffectChance,
+	).Scan(&consumable.ID, &consumable.CreatedAt)
+}
+
+func (r *ConsumableRepository) FindAll() ([]*domain.Consumable, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, COALESCE(image, '') as image, price,
+			required_level, effect_type, effect_value,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance
+		FROM consumables
+		WHERE deleted_at IS NULL
+		ORDER BY required_level ASC, price ASC
+	`
+
+	consumables := []*domain.Consumable{}
+	if err := r.db.Select(&consumables, query); err != nil {
+		return nil, err
+	}
+
+	return consumables, nil
+}
+
+func (r *ConsumableRepository) FindBySlug(slug string) (*domain.Consumable, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, COALESCE(image, '') as image, price,
+			required_level, effect_type, effect_value,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance
+		FROM consumables
+		WHERE slug = $1 AND deleted_at IS NULL
+	`
+
+	consumable := &domain.Consumable{}
+	err := r.db.Get(consumable, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrConsumableNotFound
+		}
+		return nil, err
+	}
+
+	return consumable, nil
+}
+
+func (r *ConsumableRepository) FindByIDs(ids []uuid.UUID) ([]*domain.Consumable, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, COALESCE(image, '') as image, price,
+			required_level, effect_type, effect_value,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance
+		FROM consumables
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	consumables := []*domain.Consumable{}
+	if err := r.db.Select(&consumables, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	return consumables, nil
+}
+
+func (r *ConsumableRepository) FindByUserID(userID uuid.UUID) ([]*domain.ConsumableStack, error) {
+	query := `
+		SELECT c.id, c.created_at, c.deleted_at, c.name, c.slug, COALESCE(c.image, '') as image, c.price,
+			c.required_level, c.effect_type, c.effect_value, c.status_effect_type, c.status_effect_value,
+			c.status_effect_duration, c.status_effect_chance, ci.quantity
+		FROM consumable_inventory ci
+		INNER JOIN consumables c ON c.id = ci.consumable_id
+		WHERE ci.user_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.name ASC
+	`
+
+	stacks := []*domain.ConsumableStack{}
+	if err := r.db.Select(&stacks, query, userID); err != nil {
+		return nil, err
+	}
+
+	return stacks, nil
+}
+
+func (r *ConsumableRepository) AddToInventory(userID, consumableID uuid.UUID, quantity uint) error {
+	query := `
+		INSERT INTO consumable_inventory (user_id, consumable_id, quantity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, consumable_id)
+		DO UPDATE SET quantity = consumable_inventory.quantity + EXCLUDED.quantity
+	`
+
+	_, err := r.db.Exec(query, userID, consumableID, quantity)
+	return err
+}
+
+// TakeFromInventory removes one consumable from the user's stack and deletes
+// the stack when it becomes empty.
+func (r *ConsumableRepository) TakeFromInventory(userID, consumableID uuid.UUID) error {
+	query := `
+		UPDATE consumable_inventory
+		SET quantity = quantity - 1
+		WHERE user_id = $1 AND consumable_id = $2 AND quantity > 1
+	`
+
+	res, err := r.db.Exec(query, userID, consumableID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	res, err = r.db.Exec(`DELETE FROM consumable_inventory WHERE user_id = $1 AND consumable_id = $2`, userID, consumableID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrConsumableNotOwned
+	}
+
+	return nil
+}
+
+// TakeManyFromInventory removes quantity consumables from the user's stack,
+// ErrConsumableNotOwned when the stack is smaller. Empty stacks are deleted.
+func (r *ConsumableRepository) TakeManyFromInventory(userID, consumableID uuid.UUID, quantity uint) error {
+	res, err := r.db.Exec(`DELETE FROM consumable_inventory WHERE user_id = $1 AND consumable_id = $2 AND quantity = $3`,
+		userID, consumableID, quantity)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE consumable_inventory
+		SET quantity = quantity - $3
+		WHERE user_id = $1 AND consumable_id = $2 AND quantity > $3
+	`
+
+	res, err = r.db.Exec(query, userID, consumableID, quantity)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrConsumableNotOwned
+	}
+
+	return nil
+}
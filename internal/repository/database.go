@@ -15,8 +15,9 @@ type Database struct {
 	db *sqlx.DB
 }
 
-func New(cfg *config.Config) (*Database, error) {
-	dsn := fmt.Sprintf(
+// DSN is the connection string of the configured database.
+func DSN(cfg *config.Config) string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Database.Host,
 		cfg.Database.Port,
@@ -25,6 +26,10 @@ func New(cfg *config.Config) (*Database, error) {
 		cfg.Database.Name,
 		cfg.Database.SSLMode,
 	)
+}
+
+func New(cfg *config.Config) (*Database, error) {
+	dsn := DSN(cfg)
 
 	driverName, err := otelsql.Register("postgres",
 		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
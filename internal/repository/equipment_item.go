@@ -3,9 +3,10 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 
 	"moonshine/internal/domain"
@@ -16,10 +17,10 @@ var (
 )
 
 type EquipmentItemRepository struct {
-	db *sqlx.DB
+	db ExtHandle
 }
 
-func NewEquipmentItemRepository(db *sqlx.DB) *EquipmentItemRepository {
+func NewEquipmentItemRepository(db ExtHandle) *EquipmentItemRepository {
 	return &EquipmentItemRepository{db: db}
 }
 
@@ -30,10 +31,12 @@ func (r *EquipmentItemRepository) FindByCategorySlug(slug string) ([]*domain.Equ
 func (r *EquipmentItemRepository) FindByCategorySlugAndArtifact(slug string, artifact bool) ([]*domain.EquipmentItem, error) {
 	query := `
 		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
-			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image,
+			ei.status_effect_type, ei.status_effect_value, ei.status_effect_duration, ei.status_effect_chance,
+			ei.crit_chance, ei.crit_multiplier, ei.dodge_chance, ei.block_chance, ei.max_stack
 		FROM equipment_items ei
 		INNER JOIN equipment_categories ec ON ei.equipment_category_id = ec.id
-		WHERE ec.type = $1::equipment_category_type 
+		WHERE ec.type = $1
 			AND ei.artifact = $2
 			AND ei.deleted_at IS NULL
 			AND ec.deleted_at IS NULL
@@ -52,7 +55,9 @@ func (r *EquipmentItemRepository) FindByCategorySlugAndArtifact(slug string, art
 func (r *EquipmentItemRepository) FindByID(id uuid.UUID) (*domain.EquipmentItem, error) {
 	query := `
 		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp,
-			required_level, price, artifact, equipment_category_id, COALESCE(image, '') as image
+			required_level, price, artifact, equipment_category_id, COALESCE(image, '') as image,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance,
+			crit_chance, crit_multiplier, dodge_chance, block_chance, max_stack
 		FROM equipment_items
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -72,7 +77,9 @@ func (r *EquipmentItemRepository) FindByID(id uuid.UUID) (*domain.EquipmentItem,
 func (r *EquipmentItemRepository) FindByIDs(ids []uuid.UUID) ([]*domain.EquipmentItem, error) {
 	query := `
 		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
-			required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image, ec.type as equipment_type
+			required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image, ec.type as equipment_type,
+			ei.status_effect_type, ei.status_effect_value, ei.status_effect_duration, ei.status_effect_chance,
+			ei.crit_chance, ei.crit_multiplier, ei.dodge_chance, ei.block_chance, ei.max_stack
 		FROM equipment_items ei
 		INNER JOIN equipment_categories ec 
 		    ON ei.equipment_category_id = ec.id
@@ -88,10 +95,33 @@ func (r *EquipmentItemRepository) FindByIDs(ids []uuid.UUID) ([]*domain.Equipmen
 	return items, nil
 }
 
+func (r *EquipmentItemRepository) FindAll() ([]*domain.EquipmentItem, error) {
+	query := `
+		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image,
+			COALESCE(ec.type, '') as equipment_type,
+			ei.status_effect_type, ei.status_effect_value, ei.status_effect_duration, ei.status_effect_chance,
+			ei.crit_chance, ei.crit_multiplier, ei.dodge_chance, ei.block_chance, ei.max_stack
+		FROM equipment_items ei
+		LEFT JOIN equipment_categories ec ON ec.id = ei.equipment_category_id
+		WHERE ei.deleted_at IS NULL
+		ORDER BY ei.required_level ASC, ei.name ASC
+	`
+
+	var items []*domain.EquipmentItem
+	if err := r.db.Select(&items, query); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 func (r *EquipmentItemRepository) FindBySlug(slug string) (*domain.EquipmentItem, error) {
 	query := `
 		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp,
-			required_level, price, artifact, equipment_category_id, COALESCE(image, '') as image
+			required_level, price, artifact, equipment_category_id, COALESCE(image, '') as image,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance,
+			crit_chance, crit_multiplier, dodge_chance, block_chance, max_stack
 		FROM equipment_items
 		WHERE slug = $1 AND deleted_at IS NULL
 	`
@@ -108,16 +138,129 @@ func (r *EquipmentItemRepository) FindBySlug(slug string) (*domain.EquipmentItem
 	return item, nil
 }
 
+// FindBySlugs returns the items with their category type, slugs of missing
+// items are skipped.
+func (r *EquipmentItemRepository) FindBySlugs(slugs []string) ([]*domain.EquipmentItem, error) {
+	query := `
+		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image,
+			COALESCE(ec.type, '') as equipment_type,
+			ei.status_effect_type, ei.status_effect_value, ei.status_effect_duration, ei.status_effect_chance,
+			ei.crit_chance, ei.crit_multiplier, ei.dodge_chance, ei.block_chance, ei.max_stack
+		FROM equipment_items ei
+		LEFT JOIN equipment_categories ec ON ec.id = ei.equipment_category_id
+		WHERE ei.slug = ANY($1) AND ei.deleted_at IS NULL
+	`
+
+	items := []*domain.EquipmentItem{}
+	if err := r.db.Select(&items, query, pq.Array(slugs)); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+var catalogSortColumns = map[domain.CatalogSort]string{
+	domain.CatalogSortLevel:   "ei.required_level",
+	domain.CatalogSortPrice:   "ei.price",
+	domain.CatalogSortName:    "ei.name",
+	domain.CatalogSortAttack:  "ei.attack",
+	domain.CatalogSortDefense: "ei.defense",
+	domain.CatalogSortHp:      "ei.hp",
+}
+
+// Search returns up to query.Limit catalog items after the cursor. The sort
+// must be valid, search words match name prefixes.
+func (r *EquipmentItemRepository) Search(query domain.CatalogQuery) ([]*domain.EquipmentItem, error) {
+	column, ok := catalogSortColumns[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown catalog sort %q", query.Sort)
+	}
+
+	conditions := []string{"ei.deleted_at IS NULL"}
+	args := []any{}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if terms := domain.SearchTerms(query.Search); len(terms) > 0 {
+		for i, term := range terms {
+			terms[i] = term + ":*"
+		}
+		conditions = append(conditions, "ei.search_vector @@ to_tsquery('simple', "+arg(strings.Join(terms, " & "))+")")
+	}
+	if len(query.CategoryTypes) > 0 {
+		conditions = append(conditions, "ec.type = ANY("+arg(pq.Array(query.CategoryTypes))+")")
+	}
+	if query.Artifact != nil {
+		conditions = append(conditions, "ei.artifact = "+arg(*query.Artifact))
+	}
+	minimums := []struct {
+		column string
+		value  uint
+	}{
+		{"ei.required_level", query.MinLevel},
+		{"ei.price", query.MinPrice},
+		{"ei.attack", query.MinAttack},
+		{"ei.defense", query.MinDefense},
+		{"ei.hp", query.MinHp},
+	}
+	for _, minimum := range minimums {
+		if minimum.value > 0 {
+			conditions = append(conditions, minimum.column+" >= "+arg(int(minimum.value)))
+		}
+	}
+	if query.MaxLevel > 0 {
+		conditions = append(conditions, "ei.required_level <= "+arg(int(query.MaxLevel)))
+	}
+	if query.MaxPrice > 0 {
+		conditions = append(conditions, "ei.price <= "+arg(int(query.MaxPrice)))
+	}
+
+	direction, compare := "ASC", ">"
+	if query.Desc {
+		direction, compare = "DESC", "<"
+	}
+	if query.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, ei.id) %s (%s, %s)", column, compare, arg(query.After.SortValue()), arg(query.After.ID)))
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, COALESCE(ei.image, '') as image,
+			COALESCE(ec.type, '') as equipment_type,
+			ei.status_effect_type, ei.status_effect_value, ei.status_effect_duration, ei.status_effect_chance,
+			ei.crit_chance, ei.crit_multiplier, ei.dodge_chance, ei.block_chance, ei.max_stack
+		FROM equipment_items ei
+		LEFT JOIN equipment_categories ec ON ec.id = ei.equipment_category_id
+		WHERE %s
+		ORDER BY %s %s, ei.id %s
+		LIMIT %s
+	`, strings.Join(conditions, " AND "), column, direction, direction, arg(query.Limit))
+
+	items := []*domain.EquipmentItem{}
+	if err := r.db.Select(&items, sqlQuery, args...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 func (r *EquipmentItemRepository) Create(item *domain.EquipmentItem) error {
 	query := `
-		INSERT INTO equipment_items (name, slug, attack, defense, hp, required_level, price, artifact, equipment_category_id, image)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO equipment_items (name, slug, attack, defense, hp, required_level, price, artifact, equipment_category_id, image,
+			status_effect_type, status_effect_value, status_effect_duration, status_effect_chance,
+			crit_chance, crit_multiplier, dodge_chance, block_chance, max_stack)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, GREATEST($19, 1))
 		RETURNING id
 	`
 
 	err := r.db.QueryRow(query,
 		item.Name, item.Slug, item.Attack, item.Defense, item.Hp,
 		item.RequiredLevel, item.Price, item.Artifact, item.EquipmentCategoryID, item.Image,
+		item.StatusEffectType, item.StatusEffectValue, item.StatusEffectDuration, item.StatusEffectChance,
+		item.CritChance, item.CritMultiplier, item.DodgeChance, item.BlockChance, item.MaxStack,
 	).Scan(&item.ID)
 	if err != nil {
 		return err
@@ -125,3 +268,15 @@ func (r *EquipmentItemRepository) Create(item *domain.EquipmentItem) error {
 
 	return nil
 }
+
+// FindCategoryType returns the slot type of the category, e.g. "weapon" or "ring".
+func (r *EquipmentItemRepository) FindCategoryType(categoryID uuid.UUID) (string, error) {
+	query := `SELECT type FROM equipment_categories WHERE id = $1 AND deleted_at IS NULL`
+
+	var equipmentType string
+	if err := r.db.Get(&equipmentType, query, categoryID); err != nil {
+		return "", err
+	}
+
+	return equipmentType, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"moonshine/internal/domain"
+)
+
+var ErrEquipmentSlotNotFound = errors.New("equipment slot not found")
+
+type EquipmentSlotRepository struct {
+	db ExtHandle
+}
+
+func NewEquipmentSlotRepository(db ExtHandle) *EquipmentSlotRepository {
+	return &EquipmentSlotRepository{db: db}
+}
+
+func (r *EquipmentSlotRepository) Create(slot *domain.EquipmentSlot) error {
+	query := `INSERT INTO equipment_slots (name, equipment_type, position) VALUES ($1, $2, $3)`
+	_, err := r.db.Exec(query, slot.Name, slot.EquipmentType, slot.Position)
+	return err
+}
+
+// FindAll returns the slot registry in position order.
+func (r *EquipmentSlotRepository) FindAll() ([]*domain.EquipmentSlot, error) {
+	query := `SELECT name, equipment_type, position FROM equipment_slots ORDER BY position`
+
+	slots := []*domain.EquipmentSlot{}
+	if err := r.db.Select(&slots, query); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (r *EquipmentSlotRepository) FindByName(name string) (*domain.EquipmentSlot, error) {
+	query := `SELECT name, equipment_type, position FROM equipment_slots WHERE name = $1`
+
+	slot := &domain.EquipmentSlot{}
+	if err := r.db.Get(slot, query, name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEquipmentSlotNotFound
+		}
+		return nil, err
+	}
+	return slot, nil
+}
+
+// FindByEquipmentType returns the slots taking items of the type in position
+// order, none for a type no slot takes.
+func (r *EquipmentSlotRepository) FindByEquipmentType(equipmentType string) ([]*domain.EquipmentSlot, error) {
+	query := `
+		SELECT name, equipment_type, position
+		FROM equipment_slots
+		WHERE equipment_type = $1
+		ORDER BY position
+	`
+
+	slots := []*domain.EquipmentSlot{}
+	if err := r.db.Select(&slots, query, equipmentType); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
@@ -7,4 +7,5 @@ type ExtHandle interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 	Get(dest interface{}, query string, args ...interface{}) error
 	Select(dest interface{}, query string, args ...interface{}) error
+	Rebind(query string) string
 }
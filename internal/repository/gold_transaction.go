@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var ErrInsufficientGold = errors.New("insufficient gold")
+
+type GoldTransactionRepository struct {
+	db ExtHandle
+}
+
+func NewGoldTransactionRepository(db ExtHandle) *GoldTransactionRepository {
+	return &GoldTransactionRepository{db: db}
+}
+
+// Apply changes the user's gold by delta and writes the ledger entry in the
+// same statement. It fails with ErrInsufficientGold instead of letting the
+// balance go negative. A zero delta writes nothing and returns nil.
+func (r *GoldTransactionRepository) Apply(userID uuid.UUID, delta int64, reason domain.GoldTransactionReason, referenceID *uuid.UUID) (*domain.GoldTransaction, error) {
+	if delta == 0 {
+		return nil, nil
+	}
+
+	query := `
+		WITH updated AS (
+			UPDATE users
+			SET gold = gold + $2
+			WHERE id = $1 AND deleted_at IS NULL AND gold + $2 >= 0
+			RETURNING id, gold
+		)
+		INSERT INTO gold_transactions (user_id, delta, reason, reference_id, balance_after)
+		SELECT id, $2, $3, $4, gold FROM updated
+		RETURNING id, created_at, user_id, delta, reason, reference_id, balance_after
+	`
+
+	transaction := &domain.GoldTransaction{}
+	err := r.db.Get(transaction, query, userID, delta, reason, referenceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInsufficientGold
+		}
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Record writes a ledger entry for a change the caller already applied to
+// users.gold.
+func (r *GoldTransactionRepository) Record(userID uuid.UUID, delta int64, reason domain.GoldTransactionReason, referenceID *uuid.UUID, balanceAfter int64) error {
+	query := `
+		INSERT INTO gold_transactions (user_id, delta, reason, reference_id, balance_after)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Exec(query, userID, delta, reason, referenceID, balanceAfter)
+	return err
+}
+
+func (r *GoldTransactionRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*domain.GoldTransaction, error) {
+	query := `
+		SELECT id, created_at, user_id, delta, reason, reference_id, balance_after
+		FROM gold_transactions
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	transactions := []*domain.GoldTransaction{}
+	if err := r.db.Select(&transactions, query, userID, limit, offset); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+type GoldMismatch struct {
+	UserID    uuid.UUID `db:"id"`
+	Username  string    `db:"username"`
+	Gold      int64     `db:"gold"`
+	LedgerSum int64     `db:"ledger_sum"`
+}
+
+// FindMismatches returns the users whose gold differs from the sum of their
+// ledger entries.
+func (r *GoldTransactionRepository) FindMismatches() ([]GoldMismatch, error) {
+	query := `
+		SELECT u.id, u.username, u.gold, COALESCE(l.sum, 0) AS ledger_sum
+		FROM users u
+		LEFT JOIN (
+			SELECT user_id, SUM(delta) AS sum FROM gold_transactions GROUP BY user_id
+		) l ON l.user_id = u.id
+		WHERE u.gold <> COALESCE(l.sum, 0)
+		ORDER BY u.username
+	`
+
+	mismatches := []GoldMismatch{}
+	if err := r.db.Select(&mismatches, query); err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
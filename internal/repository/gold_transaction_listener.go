@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+// GoldTransactionsChannel is where the gold_transactions trigger announces
+// every ledger entry once its transaction commits.
+const GoldTransactionsChannel = "gold_transactions"
+
+type goldTransactionNotification struct {
+	UserID       uuid.UUID                    `json:"user_id"`
+	Delta        int64                        `json:"delta"`
+	Reason       domain.GoldTransactionReason `json:"reason"`
+	BalanceAfter int64                        `json:"balance_after"`
+}
+
+// GoldTransactionListener follows the gold ledger on a connection of its
+// own, reconnecting when it drops.
+type GoldTransactionListener struct {
+	listener *pq.Listener
+}
+
+func NewGoldTransactionListener(dsn string) *GoldTransactionListener {
+	return &GoldTransactionListener{listener: pq.NewListener(dsn, time.Second, time.Minute, nil)}
+}
+
+func (l *GoldTransactionListener) Listen() error {
+	return l.listener.Listen(GoldTransactionsChannel)
+}
+
+func (l *GoldTransactionListener) Close() error {
+	return l.listener.Close()
+}
+
+// Next waits for the next committed entry. After a reconnect it returns nil
+// without an error: entries committed while the connection was down are
+// lost.
+func (l *GoldTransactionListener) Next(ctx context.Context) (*domain.GoldTransaction, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case n := <-l.listener.Notify:
+			if n == nil {
+				return nil, nil
+			}
+			var payload goldTransactionNotification
+			if err := json.Unmarshal([]byte(n.Extra), &payload); err != nil {
+				return nil, fmt.Errorf("decode gold transaction: %w", err)
+			}
+			return &domain.GoldTransaction{
+				UserID:       payload.UserID,
+				Delta:        payload.Delta,
+				Reason:       payload.Reason,
+				BalanceAfter: payload.BalanceAfter,
+			}, nil
+		case <-time.After(time.Minute):
+			// Finds a silently dropped connection.
+			go func() { _ = l.listener.Ping() }()
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrGuildNotFound        = errors.New("guild not found")
+	ErrGuildExists          = errors.New("guild name or tag taken")
+	ErrGuildMemberNotFound  = errors.New("guild member not found")
+	ErrAlreadyInGuild       = errors.New("user is already in a guild")
+	ErrGuildInviteNotFound  = errors.New("guild invite not found")
+	ErrInsufficientTreasury = errors.New("insufficient guild treasury")
+)
+
+type GuildRepository struct {
+	db ExtHandle
+}
+
+func NewGuildRepository(db ExtHandle) *GuildRepository {
+	return &GuildRepository{db: db}
+}
+
+func (r *GuildRepository) Create(guild *domain.Guild) error {
+	query := `INSERT INTO guilds (name, tag) VALUES ($1, $2) RETURNING id, created_at, treasury`
+
+	err := r.db.QueryRow(query, guild.Name, guild.Tag).Scan(&guild.ID, &guild.CreatedAt, &guild.Treasury)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrGuildExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *GuildRepository) FindByID(id uuid.UUID) (*domain.Guild, error) {
+	query := `SELECT id, created_at, name, tag, treasury FROM guilds WHERE id = $1 AND deleted_at IS NULL`
+
+	guild := &domain.Guild{}
+	if err := r.db.Get(guild, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGuildNotFound
+		}
+		return nil, err
+	}
+	return guild, nil
+}
+
+// FindByIDForUpdate locks the guild, so membership and treasury changes run
+// one at a time.
+func (r *GuildRepository) FindByIDForUpdate(id uuid.UUID) (*domain.Guild, error) {
+	query := `SELECT id, created_at, name, tag, treasury FROM guilds WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	guild := &domain.Guild{}
+	if err := r.db.Get(guild, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGuildNotFound
+		}
+		return nil, err
+	}
+	return guild, nil
+}
+
+// Disband removes the guild's members and invites and soft-deletes the guild,
+// its treasury ledger stays.
+func (r *GuildRepository) Disband(id uuid.UUID) error {
+	query := `
+		WITH members AS (
+			DELETE FROM guild_members WHERE guild_id = $1
+		), invites AS (
+			DELETE FROM guild_invites WHERE guild_id = $1
+		)
+		UPDATE guilds SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// AddMember fails with ErrAlreadyInGuild when the user is in a guild.
+func (r *GuildRepository) AddMember(guildID, userID uuid.UUID, rank domain.GuildRank) error {
+	query := `INSERT INTO guild_members (user_id, guild_id, rank) VALUES ($1, $2, $3)`
+
+	if _, err := r.db.Exec(query, userID, guildID, rank); err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrAlreadyInGuild
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *GuildRepository) RemoveMember(userID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM guild_members WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *GuildRepository) UpdateRank(userID uuid.UUID, rank domain.GuildRank) error {
+	_, err := r.db.Exec(`UPDATE guild_members SET rank = $2 WHERE user_id = $1`, userID, rank)
+	return err
+}
+
+const guildMemberColumns = `gm.user_id, gm.guild_id, gm.rank, gm.joined_at, u.username, u.level`
+
+// FindMember returns the user's membership, ErrGuildMemberNotFound outside a
+// guild.
+func (r *GuildRepository) FindMember(userID uuid.UUID) (*domain.GuildMember, error) {
+	query := `
+		SELECT ` + guildMemberColumns + `
+		FROM guild_members gm
+		INNER JOIN users u ON u.id = gm.user_id
+		WHERE gm.user_id = $1
+	`
+
+	member := &domain.GuildMember{}
+	if err := r.db.Get(member, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGuildMemberNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// FindMembers returns the guild's members, highest rank first.
+func (r *GuildRepository) FindMembers(guildID uuid.UUID) ([]*domain.GuildMember, error) {
+	query := `
+		SELECT ` + guildMemberColumns + `
+		FROM guild_members gm
+		INNER JOIN users u ON u.id = gm.user_id
+		WHERE gm.guild_id = $1
+		ORDER BY CASE gm.rank WHEN 'leader' THEN 0 WHEN 'officer' THEN 1 ELSE 2 END, gm.joined_at ASC
+	`
+
+	members := []*domain.GuildMember{}
+	if err := r.db.Select(&members, query, guildID); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *GuildRepository) CountMembers(guildID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM guild_members WHERE guild_id = $1`, guildID)
+	return count, err
+}
+
+// FindMemberIDs returns the user ids of the guild's members.
+func (r *GuildRepository) FindMemberIDs(guildID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := r.db.Select(&ids, `SELECT user_id FROM guild_members WHERE guild_id = $1`, guildID)
+	return ids, err
+}
+
+// SaveInvite invites the user again when an invite is pending.
+func (r *GuildRepository) SaveInvite(invite *domain.GuildInvite) error {
+	query := `
+		INSERT INTO guild_invites (guild_id, user_id, inviter_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (guild_id, user_id)
+		DO UPDATE SET inviter_id = EXCLUDED.inviter_id, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(query, invite.GuildID, invite.UserID, invite.InviterID, invite.ExpiresAt).Scan(&invite.CreatedAt)
+}
+
+// FindInvites returns the user's pending invites, newest first.
+func (r *GuildRepository) FindInvites(userID uuid.UUID, now time.Time) ([]*domain.GuildInvite, error) {
+	query := `
+		SELECT gi.guild_id, g.name as guild_name, g.tag as guild_tag, gi.user_id, gi.inviter_id,
+			gi.created_at, gi.expires_at
+		FROM guild_invites gi
+		INNER JOIN guilds g ON g.id = gi.guild_id
+		WHERE gi.user_id = $1 AND gi.expires_at > $2
+		ORDER BY gi.created_at DESC
+	`
+
+	invites := []*domain.GuildInvite{}
+	if err := r.db.Select(&invites, query, userID, now); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// DeleteInvite fails with ErrGuildInviteNotFound when the invite is missing
+// or expired before now.
+func (r *GuildRepository) DeleteInvite(guildID, userID uuid.UUID, now time.Time) error {
+	query := `DELETE FROM guild_invites WHERE guild_id = $1 AND user_id = $2 AND expires_at > $3`
+
+	res, err := r.db.Exec(query, guildID, userID, now)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrGuildInviteNotFound
+	}
+	return nil
+}
+
+func (r *GuildRepository) DeleteUserInvites(userID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM guild_invites WHERE user_id = $1`, userID)
+	return err
+}
+
+// ApplyTreasury changes the treasury by delta and writes the ledger entry in
+// the same statement. It fails with ErrInsufficientTreasury instead of
+// letting the treasury go negative.
+func (r *GuildRepository) ApplyTreasury(guildID, userID uuid.UUID, delta int64) (*domain.GuildTransaction, error) {
+	query := `
+		WITH updated AS (
+			UPDATE guilds
+			SET treasury = treasury + $2
+			WHERE id = $1 AND treasury + $2 >= 0
+			RETURNING id, treasury
+		)
+		INSERT INTO guild_transactions (guild_id, user_id, delta, balance_after)
+		SELECT id, $3, $2, treasury FROM updated
+		RETURNING id, created_at, guild_id, user_id, delta, balance_after
+	`
+
+	transaction := &domain.GuildTransaction{}
+	if err := r.db.Get(transaction, query, guildID, delta, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInsufficientTreasury
+		}
+		return nil, err
+	}
+	return transaction, nil
+}
+
+func (r *GuildRepository) FindTransactions(guildID uuid.UUID, limit, offset int) ([]*domain.GuildTransaction, error) {
+	query := `
+		SELECT gt.id, gt.created_at, gt.guild_id, gt.user_id, COALESCE(u.username, '') as username,
+			gt.delta, gt.balance_after
+		FROM guild_transactions gt
+		LEFT JOIN users u ON u.id = gt.user_id
+		WHERE gt.guild_id = $1
+		ORDER BY gt.created_at DESC, gt.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	transactions := []*domain.GuildTransaction{}
+	if err := r.db.Select(&transactions, query, guildID, limit, offset); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *GuildRepository) CreateMessage(message *domain.GuildMessage) error {
+	query := `
+		INSERT INTO guild_messages (guild_id, user_id, text)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query, message.GuildID, message.UserID, message.Text).Scan(&message.ID, &message.CreatedAt)
+}
+
+// FindMessages returns the guild's latest messages, oldest first.
+func (r *GuildRepository) FindMessages(guildID uuid.UUID, limit int) ([]*domain.GuildMessage, error) {
+	query := `
+		SELECT * FROM (
+			SELECT gm.id, gm.created_at, gm.guild_id, gm.user_id, COALESCE(u.username, '') as username, gm.text
+			FROM guild_messages gm
+			LEFT JOIN users u ON u.id = gm.user_id
+			WHERE gm.guild_id = $1
+			ORDER BY gm.created_at DESC, gm.id DESC
+			LIMIT $2
+		) latest
+		ORDER BY created_at ASC, id ASC
+	`
+
+	messages := []*domain.GuildMessage{}
+	if err := r.db.Select(&messages, query, guildID, limit); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
@@ -7,63 +7,622 @@ import (
 	"moonshine/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 var (
-	ErrInventoryNotFound = errors.New("inventory item not found")
+	ErrInventoryNotFound  = errors.New("inventory item not found")
+	ErrItemNotInInventory = errors.New("item not in inventory")
 )
 
-type dbInterface interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Select(dest interface{}, query string, args ...interface{}) error
-	QueryRow(query string, args ...interface{}) *sql.Row
-}
-
 type InventoryRepository struct {
-	db dbInterface
+	db ExtHandle
 }
 
-func NewInventoryRepository(db dbInterface) *InventoryRepository {
+func NewInventoryRepository(db ExtHandle) *InventoryRepository {
 	return &InventoryRepository{db: db}
 }
 
+// Create adds an instance of the item. Durability starts at the item's
+// max durability, bonus stats and the slot are taken from inventory. A zero
+// quantity creates a single copy.
 func (r *InventoryRepository) Create(inventory *domain.Inventory) error {
 	query := `
-		INSERT INTO inventory (user_id, equipment_item_id)
-		VALUES ($1, $2)
-		RETURNING id, created_at
+		INSERT INTO inventory (user_id, equipment_item_id, durability, max_durability,
+			bonus_attack, bonus_defense, bonus_hp, equipped_slot, quantity)
+		SELECT $1, id, max_durability, max_durability, $3, $4, $5, $6, GREATEST($7, 1)
+		FROM equipment_items
+		WHERE id = $2
+		RETURNING id, created_at, durability, max_durability, quantity
 	`
 
 	err := r.db.QueryRow(query,
 		inventory.UserID,
 		inventory.EquipmentItemID,
-	).Scan(&inventory.ID, &inventory.CreatedAt)
+		inventory.BonusAttack,
+		inventory.BonusDefense,
+		inventory.BonusHp,
+		inventory.EquippedSlot,
+		inventory.Quantity,
+	).Scan(&inventory.ID, &inventory.CreatedAt, &inventory.Durability, &inventory.MaxDurability, &inventory.Quantity)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEquipmentItemNotFound
+		}
 		return err
 	}
 
 	return nil
 }
 
-func (r *InventoryRepository) FindByUserID(userID uuid.UUID) ([]*domain.EquipmentItem, error) {
+// FindByUserID returns the instances in the user's bag, equipped, banked,
+// auctioned and mailed ones are left out.
+func (r *InventoryRepository) FindByUserID(userID uuid.UUID) ([]*domain.Inventory, error) {
 	query := `
-		SELECT 
-			ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
-			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image
+		SELECT i.id, i.created_at, i.deleted_at, i.user_id, i.equipment_item_id, i.durability,
+			i.max_durability, i.bonus_attack, i.bonus_defense, i.bonus_hp, i.enhancement_level, i.equipped_slot,
+			i.quantity, i.banked
 		FROM inventory i
 		INNER JOIN equipment_items ei ON i.equipment_item_id = ei.id
-		WHERE i.user_id = $1 
+		WHERE i.user_id = $1
+			AND i.equipped_slot IS NULL
+			AND i.escrow_auction_id IS NULL AND i.escrow_mail_id IS NULL
+			AND NOT i.banked
 			AND i.deleted_at IS NULL
 			AND ei.deleted_at IS NULL
-		ORDER BY ei.name ASC
+		ORDER BY ei.name ASC, i.created_at ASC
+	`
+
+	var instances []*domain.Inventory
+	if err := r.db.Select(&instances, query, userID); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// FindEquipped returns the instances the user has equipped.
+func (r *InventoryRepository) FindEquipped(userID uuid.UUID) ([]*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE user_id = $1 AND equipped_slot IS NOT NULL AND deleted_at IS NULL
+	`
+
+	var instances []*domain.Inventory
+	if err := r.db.Select(&instances, query, userID); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// FindEquippedBySlot returns the instance in the slot, ErrInventoryNotFound
+// when the slot is empty or was filled before instances existed.
+func (r *InventoryRepository) FindEquippedBySlot(userID uuid.UUID, slot string) (*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE user_id = $1 AND equipped_slot = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	instance := &domain.Inventory{}
+	if err := r.db.Get(instance, query, userID, slot); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInventoryNotFound
+		}
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// FindByIDForUpdate returns the user's instance and locks it. Traded,
+// auctioned, mailed and banked instances are left out.
+func (r *InventoryRepository) FindByIDForUpdate(userID, id uuid.UUID) (*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE id = $1 AND user_id = $2
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	instance := &domain.Inventory{}
+	if err := r.db.Get(instance, query, id, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInventoryNotFound
+		}
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// FindFreeByIDForUpdate returns the user's instance when it is in the bag and
+// no trade, auction or mail holds it, and locks it. ErrItemNotInInventory otherwise.
+func (r *InventoryRepository) FindFreeByIDForUpdate(userID, id uuid.UUID) (*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE id = $1 AND user_id = $2 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+		FOR UPDATE
 	`
 
-	var items []*domain.EquipmentItem
+	instance := &domain.Inventory{}
+	if err := r.db.Get(instance, query, id, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrItemNotInInventory
+		}
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// FindUnequippedForUpdate picks the best instance of the item in the user's
+// bag that no trade, auction or mail holds and locks it: the most enhanced,
+// then the best kept one. ErrItemNotInInventory when there is none.
+func (r *InventoryRepository) FindUnequippedForUpdate(userID, equipmentItemID uuid.UUID) (*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE user_id = $1 AND equipment_item_id = $2 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+		ORDER BY enhancement_level DESC, durability DESC, created_at ASC
+		LIMIT 1
+		FOR UPDATE
+	`
 
-	err := r.db.Select(&items, query, userID)
+	instance := &domain.Inventory{}
+	if err := r.db.Get(instance, query, userID, equipmentItemID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrItemNotInInventory
+		}
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// SetEquippedSlot moves the instance into the slot, nil puts it back in the bag.
+func (r *InventoryRepository) SetEquippedSlot(id uuid.UUID, slot *string) error {
+	_, err := r.db.Exec(`UPDATE inventory SET equipped_slot = $2 WHERE id = $1`, id, slot)
+	return err
+}
+
+func (r *InventoryRepository) UpdateDurability(id uuid.UUID, durability uint) error {
+	_, err := r.db.Exec(`UPDATE inventory SET durability = $2 WHERE id = $1`, id, durability)
+	return err
+}
+
+func (r *InventoryRepository) UpdateEnhancementLevel(id uuid.UUID, level uint) error {
+	_, err := r.db.Exec(`UPDATE inventory SET enhancement_level = $2 WHERE id = $1`, id, level)
+	return err
+}
+
+// WearEquipped takes amount of durability from every equipped instance that
+// is not broken yet and returns the ones that broke now.
+func (r *InventoryRepository) WearEquipped(userID uuid.UUID, amount uint) ([]*domain.Inventory, error) {
+	query := `
+		UPDATE inventory
+		SET durability = GREATEST(durability - $2, 0)
+		WHERE user_id = $1 AND equipped_slot IS NOT NULL AND durability > 0 AND deleted_at IS NULL
+		RETURNING id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+	`
+
+	var worn []*domain.Inventory
+	if err := r.db.Select(&worn, query, userID, int(amount)); err != nil {
+		return nil, err
+	}
+
+	var broken []*domain.Inventory
+	for _, instance := range worn {
+		if instance.Broken() {
+			broken = append(broken, instance)
+		}
+	}
+
+	return broken, nil
+}
+
+// RemoveOne takes a single copy of the item from the user's bag.
+func (r *InventoryRepository) RemoveOne(userID, equipmentItemID uuid.UUID) error {
+	return r.RemoveQuantity(userID, equipmentItemID, 1)
+}
+
+// RemoveQuantity takes quantity copies of the item from the user's bag,
+// oldest first, deleting the rows it empties. Only plain unequipped copies no
+// trade, auction or mail holds count: enhanced, bonus-rolled and worn ones are
+// left for RemoveInstance. The rows are locked first, so of two concurrent
+// calls for the last copies only one succeeds and the other gets
+// ErrItemNotInInventory.
+func (r *InventoryRepository) RemoveQuantity(userID, equipmentItemID uuid.UUID, quantity uint) error {
+	query := `
+		SELECT id, quantity
+		FROM inventory
+		WHERE user_id = $1 AND equipment_item_id = $2 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+			AND enhancement_level = 0 AND bonus_attack = 0 AND bonus_defense = 0 AND bonus_hp = 0
+			AND durability = max_durability
+		ORDER BY created_at ASC
+		FOR UPDATE
+	`
+
+	var rows []struct {
+		ID       uuid.UUID `db:"id"`
+		Quantity uint      `db:"quantity"`
+	}
+	if err := r.db.Select(&rows, query, userID, equipmentItemID); err != nil {
+		return err
+	}
+
+	var owned uint
+	for _, row := range rows {
+		owned += row.Quantity
+	}
+	if quantity == 0 || owned < quantity {
+		return ErrItemNotInInventory
+	}
+
+	left := quantity
+	for _, row := range rows {
+		if left == 0 {
+			break
+		}
+		if row.Quantity <= left {
+			if _, err := r.db.Exec(`DELETE FROM inventory WHERE id = $1`, row.ID); err != nil {
+				return err
+			}
+			left -= row.Quantity
+			continue
+		}
+		if _, err := r.db.Exec(`UPDATE inventory SET quantity = quantity - $2 WHERE id = $1`, row.ID, left); err != nil {
+			return err
+		}
+		left = 0
+	}
+
+	return nil
+}
+
+// AddToStack puts one more copy on a stack of the item in the user's bag that
+// has room. Only untouched copies stack, ErrInventoryNotFound when no stack
+// takes it.
+func (r *InventoryRepository) AddToStack(userID, equipmentItemID uuid.UUID) error {
+	query := `
+		UPDATE inventory
+		SET quantity = quantity + 1
+		WHERE id = (
+			SELECT i.id FROM inventory i
+			INNER JOIN equipment_items ei ON ei.id = i.equipment_item_id
+			WHERE i.user_id = $1 AND i.equipment_item_id = $2 AND i.equipped_slot IS NULL
+				AND i.escrow_trade_id IS NULL AND i.escrow_auction_id IS NULL AND i.escrow_mail_id IS NULL AND NOT i.banked
+				AND i.deleted_at IS NULL AND i.quantity < ei.max_stack
+				AND i.durability = i.max_durability AND i.enhancement_level = 0
+				AND i.bonus_attack = 0 AND i.bonus_defense = 0 AND i.bonus_hp = 0
+			ORDER BY i.quantity DESC
+			LIMIT 1
+			FOR UPDATE OF i
+		)
+	`
+
+	res, err := r.db.Exec(query, userID, equipmentItemID)
 	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInventoryNotFound
+	}
+
+	return nil
+}
+
+// RemoveInstance takes one copy of the locked instance from the bag, deleting
+// the row when it was the last.
+func (r *InventoryRepository) RemoveInstance(instance *domain.Inventory) error {
+	if instance.Quantity > 1 {
+		_, err := r.db.Exec(`UPDATE inventory SET quantity = quantity - 1 WHERE id = $1`, instance.ID)
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM inventory WHERE id = $1`, instance.ID)
+	return err
+}
+
+// SplitOne takes one copy off the stack into a row of its own and returns it.
+// A single copy is returned as is. The stack has to be locked.
+func (r *InventoryRepository) SplitOne(instance *domain.Inventory) (*domain.Inventory, error) {
+	if instance.Quantity <= 1 {
+		return instance, nil
+	}
+
+	if _, err := r.db.Exec(`UPDATE inventory SET quantity = quantity - 1 WHERE id = $1`, instance.ID); err != nil {
+		return nil, err
+	}
+	instance.Quantity--
+
+	query := `
+		INSERT INTO inventory (user_id, equipment_item_id, durability, max_durability,
+			bonus_attack, bonus_defense, bonus_hp, enhancement_level)
+		SELECT user_id, equipment_item_id, durability, max_durability,
+			bonus_attack, bonus_defense, bonus_hp, enhancement_level
+		FROM inventory
+		WHERE id = $1
+		RETURNING id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+	`
+
+	single := &domain.Inventory{}
+	if err := r.db.Get(single, query, instance.ID); err != nil {
 		return nil, err
 	}
 
-	return items, nil
+	return single, nil
+}
+
+// CountBagSlots returns how many bag slots the user fills. Every unequipped
+// row takes one, instances held by a trade included, auctioned, mailed and
+// banked ones not.
+func (r *InventoryRepository) CountBagSlots(userID uuid.UUID) (uint, error) {
+	query := `
+		SELECT COUNT(*) FROM inventory
+		WHERE user_id = $1 AND equipped_slot IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL
+			AND NOT banked AND deleted_at IS NULL
+	`
+
+	var count uint
+	err := r.db.Get(&count, query, userID)
+	return count, err
+}
+
+// CountBanked returns how many bank slots the user fills.
+func (r *InventoryRepository) CountBanked(userID uuid.UUID) (uint, error) {
+	query := `SELECT COUNT(*) FROM inventory WHERE user_id = $1 AND banked AND deleted_at IS NULL`
+
+	var count uint
+	err := r.db.Get(&count, query, userID)
+	return count, err
+}
+
+// FindBanked returns the instances the user keeps in the bank.
+func (r *InventoryRepository) FindBanked(userID uuid.UUID) ([]*domain.Inventory, error) {
+	query := `
+		SELECT i.id, i.created_at, i.deleted_at, i.user_id, i.equipment_item_id, i.durability,
+			i.max_durability, i.bonus_attack, i.bonus_defense, i.bonus_hp, i.enhancement_level, i.equipped_slot,
+			i.quantity, i.banked
+		FROM inventory i
+		INNER JOIN equipment_items ei ON i.equipment_item_id = ei.id
+		WHERE i.user_id = $1 AND i.banked AND i.deleted_at IS NULL AND ei.deleted_at IS NULL
+		ORDER BY ei.name ASC, i.created_at ASC
+	`
+
+	instances := []*domain.Inventory{}
+	if err := r.db.Select(&instances, query, userID); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// SetBanked moves the user's instance from the bag into the bank or back.
+// Equipped instances and ones a trade, an auction or a mail holds don't move, nor do
+// ones already where they are sent, ErrItemNotInInventory then.
+func (r *InventoryRepository) SetBanked(userID, id uuid.UUID, banked bool) error {
+	query := `
+		UPDATE inventory
+		SET banked = $3
+		WHERE id = $2 AND user_id = $1 AND banked <> $3 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND deleted_at IS NULL
+	`
+
+	res, err := r.db.Exec(query, userID, id, banked)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrItemNotInInventory
+	}
+
+	return nil
+}
+
+// Escrow puts the user's instances into the trade's escrow, stacks go whole.
+// Every instance has to be in the user's bag and free, otherwise nothing is held and
+// ErrItemNotInInventory is returned.
+func (r *InventoryRepository) Escrow(tradeID, userID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE inventory
+		SET escrow_trade_id = $1
+		WHERE id = ANY($3) AND user_id = $2 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+	`
+
+	res, err := r.db.Exec(query, tradeID, userID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != int64(len(ids)) {
+		return ErrItemNotInInventory
+	}
+
+	return nil
+}
+
+// ReleaseEscrow gives every instance held by the trade back to its owner.
+func (r *InventoryRepository) ReleaseEscrow(tradeID uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE inventory SET escrow_trade_id = NULL WHERE escrow_trade_id = $1`, tradeID)
+	return err
+}
+
+// ReleaseUserEscrow gives the user's instances held by the trade back.
+func (r *InventoryRepository) ReleaseUserEscrow(tradeID, userID uuid.UUID) error {
+	query := `UPDATE inventory SET escrow_trade_id = NULL WHERE escrow_trade_id = $1 AND user_id = $2`
+	_, err := r.db.Exec(query, tradeID, userID)
+	return err
+}
+
+func (r *InventoryRepository) FindEscrowed(tradeID uuid.UUID) ([]*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE escrow_trade_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	instances := []*domain.Inventory{}
+	if err := r.db.Select(&instances, query, tradeID); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// TransferEscrowed hands the instances the sender put into the trade over to
+// the receiver and returns them.
+func (r *InventoryRepository) TransferEscrowed(tradeID, fromUserID, toUserID uuid.UUID) ([]*domain.Inventory, error) {
+	query := `
+		UPDATE inventory
+		SET user_id = $3, escrow_trade_id = NULL
+		WHERE escrow_trade_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+	`
+
+	instances := []*domain.Inventory{}
+	if err := r.db.Select(&instances, query, tradeID, fromUserID, toUserID); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// FindByIDs returns the instances with the given ids, wherever they are.
+func (r *InventoryRepository) FindByIDs(ids []uuid.UUID) ([]*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked
+		FROM inventory
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	instances := []*domain.Inventory{}
+	if err := r.db.Select(&instances, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// EscrowForAuction takes the user's instance out of the bag for the auction.
+// It has to be unequipped and free, otherwise ErrItemNotInInventory.
+func (r *InventoryRepository) EscrowForAuction(auctionID, userID, id uuid.UUID) error {
+	query := `
+		UPDATE inventory
+		SET escrow_auction_id = $1
+		WHERE id = $3 AND user_id = $2 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+	`
+
+	res, err := r.db.Exec(query, auctionID, userID, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrItemNotInInventory
+	}
+
+	return nil
+}
+
+// ReleaseAuctionEscrow hands the auctioned instance to userID, the buyer or
+// the seller of an unsold auction.
+func (r *InventoryRepository) ReleaseAuctionEscrow(auctionID, userID uuid.UUID) error {
+	query := `UPDATE inventory SET user_id = $2, escrow_auction_id = NULL WHERE escrow_auction_id = $1`
+	_, err := r.db.Exec(query, auctionID, userID)
+	return err
+}
+
+// MailAuctionEscrow attaches the auctioned instance to the user's mail.
+func (r *InventoryRepository) MailAuctionEscrow(auctionID, mailID, userID uuid.UUID) error {
+	query := `UPDATE inventory SET user_id = $3, escrow_auction_id = NULL, escrow_mail_id = $2 WHERE escrow_auction_id = $1`
+	_, err := r.db.Exec(query, auctionID, mailID, userID)
+	return err
+}
+
+// EscrowForMail puts the user's instances into the mail, stacks go whole.
+// Every instance has to be in the user's bag and free, otherwise nothing is
+// held and ErrItemNotInInventory is returned.
+func (r *InventoryRepository) EscrowForMail(mailID, userID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE inventory
+		SET escrow_mail_id = $1
+		WHERE id = ANY($3) AND user_id = $2 AND equipped_slot IS NULL
+			AND escrow_trade_id IS NULL AND escrow_auction_id IS NULL AND escrow_mail_id IS NULL AND NOT banked AND deleted_at IS NULL
+	`
+
+	res, err := r.db.Exec(query, mailID, userID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != int64(len(ids)) {
+		return ErrItemNotInInventory
+	}
+
+	return nil
+}
+
+// FindMailed returns the instances attached to the mails.
+func (r *InventoryRepository) FindMailed(mailIDs []uuid.UUID) ([]*domain.Inventory, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, equipment_item_id, durability,
+			max_durability, bonus_attack, bonus_defense, bonus_hp, enhancement_level, equipped_slot, quantity, banked,
+			escrow_mail_id
+		FROM inventory
+		WHERE escrow_mail_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	instances := []*domain.Inventory{}
+	if err := r.db.Select(&instances, query, pq.Array(mailIDs)); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// ReleaseMailEscrow hands the instances attached to the mail to userID, the
+// recipient collecting them.
+func (r *InventoryRepository) ReleaseMailEscrow(mailID, userID uuid.UUID) error {
+	query := `UPDATE inventory SET user_id = $2, escrow_mail_id = NULL WHERE escrow_mail_id = $1`
+	_, err := r.db.Exec(query, mailID, userID)
+	return err
+}
+
+// MoveMailEscrow attaches the instances of one mail to another.
+func (r *InventoryRepository) MoveMailEscrow(fromMailID, toMailID uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE inventory SET escrow_mail_id = $2 WHERE escrow_mail_id = $1`, fromMailID, toMailID)
+	return err
+}
+
+// DeleteMailed deletes the instances attached to the mail.
+func (r *InventoryRepository) DeleteMailed(mailID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM inventory WHERE escrow_mail_id = $1`, mailID)
+	return err
 }
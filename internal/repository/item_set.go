@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+type ItemSetRepository struct {
+	db ExtHandle
+}
+
+func NewItemSetRepository(db ExtHandle) *ItemSetRepository {
+	return &ItemSetRepository{db: db}
+}
+
+func (r *ItemSetRepository) Create(set *domain.ItemSet) error {
+	query := `
+		INSERT INTO item_sets (name, slug)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query, set.Name, set.Slug).Scan(&set.ID, &set.CreatedAt)
+}
+
+func (r *ItemSetRepository) AddItem(setID, equipmentItemID uuid.UUID) error {
+	query := `
+		INSERT INTO item_set_items (item_set_id, equipment_item_id)
+		VALUES ($1, $2)
+		ON CONFLICT (equipment_item_id) DO UPDATE SET item_set_id = EXCLUDED.item_set_id
+	`
+
+	_, err := r.db.Exec(query, setID, equipmentItemID)
+	return err
+}
+
+func (r *ItemSetRepository) CreateBonus(bonus *domain.ItemSetBonus) error {
+	query := `
+		INSERT INTO item_set_bonuses (item_set_id, pieces, attack, defense, hp)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(query, bonus.ItemSetID, bonus.Pieces, bonus.Attack, bonus.Defense, bonus.Hp).Scan(&bonus.ID)
+}
+
+// FindByEquipmentItemIDs returns the sets any of the items belong to, with
+// all of their items and bonuses.
+func (r *ItemSetRepository) FindByEquipmentItemIDs(ids []uuid.UUID) ([]*domain.ItemSet, error) {
+	if len(ids) == 0 {
+		return []*domain.ItemSet{}, nil
+	}
+
+	query := `
+		SELECT s.id, s.created_at, s.deleted_at, s.name, s.slug
+		FROM item_sets s
+		WHERE s.deleted_at IS NULL
+		  AND EXISTS (
+		      SELECT 1 FROM item_set_items si
+		      WHERE si.item_set_id = s.id AND si.equipment_item_id = ANY($1)
+		  )
+		ORDER BY s.name ASC
+	`
+
+	sets := []*domain.ItemSet{}
+	if err := r.db.Select(&sets, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return sets, nil
+	}
+
+	setIDs := make([]uuid.UUID, len(sets))
+	byID := make(map[uuid.UUID]*domain.ItemSet, len(sets))
+	for i, set := range sets {
+		setIDs[i] = set.ID
+		byID[set.ID] = set
+	}
+
+	var items []struct {
+		ItemSetID       uuid.UUID `db:"item_set_id"`
+		EquipmentItemID uuid.UUID `db:"equipment_item_id"`
+	}
+	itemsQuery := `
+		SELECT item_set_id, equipment_item_id
+		FROM item_set_items
+		WHERE item_set_id = ANY($1)
+	`
+	if err := r.db.Select(&items, itemsQuery, pq.Array(setIDs)); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		byID[item.ItemSetID].ItemIDs = append(byID[item.ItemSetID].ItemIDs, item.EquipmentItemID)
+	}
+
+	var bonuses []*domain.ItemSetBonus
+	bonusesQuery := `
+		SELECT id, item_set_id, pieces, attack, defense, hp
+		FROM item_set_bonuses
+		WHERE item_set_id = ANY($1)
+		ORDER BY pieces ASC
+	`
+	if err := r.db.Select(&bonuses, bonusesQuery, pq.Array(setIDs)); err != nil {
+		return nil, err
+	}
+	for _, bonus := range bonuses {
+		byID[bonus.ItemSetID].Bonuses = append(byID[bonus.ItemSetID].Bonuses, bonus)
+	}
+
+	return sets, nil
+}
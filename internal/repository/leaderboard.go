@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+type UserTotals struct {
+	UserID uuid.UUID `db:"id"`
+	Level  uint      `db:"level"`
+	Exp    uint      `db:"exp"`
+	Gold   uint      `db:"gold"`
+}
+
+type KillCount struct {
+	UserID uuid.UUID `db:"user_id"`
+	Kills  uint      `db:"kills"`
+}
+
+type UserScore struct {
+	UserID uuid.UUID `db:"user_id"`
+	Score  int64     `db:"score"`
+}
+
+// LeaderboardRepository reads the data the Redis leaderboards are rebuilt
+// from.
+type LeaderboardRepository struct {
+	db ExtHandle
+}
+
+func NewLeaderboardRepository(db ExtHandle) *LeaderboardRepository {
+	return &LeaderboardRepository{db: db}
+}
+
+func (r *LeaderboardRepository) FindUserTotals() ([]UserTotals, error) {
+	query := `SELECT id, level, exp, gold FROM users WHERE deleted_at IS NULL`
+
+	totals := []UserTotals{}
+	if err := r.db.Select(&totals, query); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// FindKillCounts counts the bots each user killed in fights started since
+// the given time.
+func (r *LeaderboardRepository) FindKillCounts(since time.Time) ([]KillCount, error) {
+	query := `
+		SELECT f.user_id, COUNT(*) AS kills
+		FROM fights f
+		WHERE f.status = 'FINISHED' AND f.deleted_at IS NULL AND f.created_at >= $1
+		  AND EXISTS (SELECT 1 FROM rounds r WHERE r.fight_id = f.id AND r.bot_hp = 0)
+		GROUP BY f.user_id
+	`
+
+	counts := []KillCount{}
+	if err := r.db.Select(&counts, query, since); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// FindEarnedExp sums the exp each user got from finished fights and claimed
+// quests since the given time.
+func (r *LeaderboardRepository) FindEarnedExp(since time.Time) ([]UserScore, error) {
+	query := `
+		SELECT user_id, SUM(exp) AS score
+		FROM (
+			SELECT user_id, exp FROM fights
+			WHERE status = 'FINISHED' AND deleted_at IS NULL AND created_at >= $1
+			UNION ALL
+			SELECT user_id, reward_exp FROM quest_claims WHERE claimed_at >= $1
+		) earned
+		GROUP BY user_id
+		HAVING SUM(exp) > 0
+	`
+
+	scores := []UserScore{}
+	if err := r.db.Select(&scores, query, since); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// FindEarnedGold sums the reward gold in each user's ledger since the given
+// time, see domain.EarnedGoldReasons.
+func (r *LeaderboardRepository) FindEarnedGold(since time.Time) ([]UserScore, error) {
+	query := `
+		SELECT user_id, SUM(delta) AS score
+		FROM gold_transactions
+		WHERE created_at >= $1 AND reason = ANY($2::gold_transaction_reason[])
+		GROUP BY user_id
+		HAVING SUM(delta) > 0
+	`
+
+	reasons := make([]string, len(domain.EarnedGoldReasons))
+	for i, reason := range domain.EarnedGoldReasons {
+		reasons[i] = string(reason)
+	}
+
+	scores := []UserScore{}
+	if err := r.db.Select(&scores, query, since, pq.Array(reasons)); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrLoadoutNotFound   = errors.New("loadout not found")
+	ErrLoadoutNameExists = errors.New("loadout name already exists")
+)
+
+type LoadoutRepository struct {
+	db ExtHandle
+}
+
+func NewLoadoutRepository(db ExtHandle) *LoadoutRepository {
+	return &LoadoutRepository{db: db}
+}
+
+// Create stores the loadout together with its slots.
+func (r *LoadoutRepository) Create(loadout *domain.Loadout) error {
+	query := `
+		INSERT INTO loadouts (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(query, loadout.UserID, loadout.Name).
+		Scan(&loadout.ID, &loadout.CreatedAt, &loadout.UpdatedAt)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrLoadoutNameExists
+		}
+		return err
+	}
+
+	return r.insertSlots(loadout)
+}
+
+// ReplaceSlots overwrites the saved slots of the loadout.
+func (r *LoadoutRepository) ReplaceSlots(loadout *domain.Loadout) error {
+	if _, err := r.db.Exec(`DELETE FROM loadout_slots WHERE loadout_id = $1`, loadout.ID); err != nil {
+		return err
+	}
+
+	query := `UPDATE loadouts SET updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING updated_at`
+	if err := r.db.QueryRow(query, loadout.ID).Scan(&loadout.UpdatedAt); err != nil {
+		return err
+	}
+
+	return r.insertSlots(loadout)
+}
+
+func (r *LoadoutRepository) insertSlots(loadout *domain.Loadout) error {
+	query := `
+		INSERT INTO loadout_slots (loadout_id, slot, inventory_id, equipment_item_id)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	for _, slot := range loadout.Slots {
+		slot.LoadoutID = loadout.ID
+		if _, err := r.db.Exec(query, slot.LoadoutID, slot.Slot, slot.InventoryID, slot.EquipmentItemID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *LoadoutRepository) CountByUserID(userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM loadouts WHERE user_id = $1`, userID)
+	return count, err
+}
+
+// FindByUserID returns the user's loadouts by name with their slots.
+func (r *LoadoutRepository) FindByUserID(userID uuid.UUID) ([]*domain.Loadout, error) {
+	query := `
+		SELECT id, created_at, updated_at, user_id, name
+		FROM loadouts
+		WHERE user_id = $1
+		ORDER BY name ASC
+	`
+
+	loadouts := []*domain.Loadout{}
+	if err := r.db.Select(&loadouts, query, userID); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadSlots(loadouts); err != nil {
+		return nil, err
+	}
+
+	return loadouts, nil
+}
+
+// FindByIDForUpdate returns the user's loadout with its slots and locks it.
+func (r *LoadoutRepository) FindByIDForUpdate(userID, id uuid.UUID) (*domain.Loadout, error) {
+	query := `
+		SELECT id, created_at, updated_at, user_id, name
+		FROM loadouts
+		WHERE id = $1 AND user_id = $2
+		FOR UPDATE
+	`
+
+	loadout := &domain.Loadout{}
+	if err := r.db.Get(loadout, query, id, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLoadoutNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadSlots([]*domain.Loadout{loadout}); err != nil {
+		return nil, err
+	}
+
+	return loadout, nil
+}
+
+func (r *LoadoutRepository) Delete(userID, id uuid.UUID) error {
+	res, err := r.db.Exec(`DELETE FROM loadouts WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrLoadoutNotFound
+	}
+
+	return nil
+}
+
+func (r *LoadoutRepository) loadSlots(loadouts []*domain.Loadout) error {
+	if len(loadouts) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(loadouts))
+	byID := make(map[uuid.UUID]*domain.Loadout, len(loadouts))
+	for i, loadout := range loadouts {
+		ids[i] = loadout.ID
+		byID[loadout.ID] = loadout
+		loadout.Slots = []*domain.LoadoutSlot{}
+	}
+
+	query := `
+		SELECT loadout_id, slot, inventory_id, equipment_item_id
+		FROM loadout_slots
+		WHERE loadout_id = ANY($1)
+	`
+
+	var slots []*domain.LoadoutSlot
+	if err := r.db.Select(&slots, query, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	for _, slot := range slots {
+		if loadout, ok := byID[slot.LoadoutID]; ok {
+			loadout.Slots = append(loadout.Slots, slot)
+		}
+	}
+	return nil
+}
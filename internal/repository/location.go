@@ -5,7 +5,6 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 
 	"moonshine/internal/domain"
 )
@@ -17,10 +16,10 @@ var (
 )
 
 type LocationRepository struct {
-	db *sqlx.DB
+	db ExtHandle
 }
 
-func NewLocationRepository(db *sqlx.DB) *LocationRepository {
+func NewLocationRepository(db ExtHandle) *LocationRepository {
 	return &LocationRepository{db: db}
 }
 
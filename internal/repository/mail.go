@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var ErrMailNotFound = errors.New("mail not found")
+
+const mailColumns = `m.id, m.created_at, m.sender_id, COALESCE(u.username, '') as sender_name, m.recipient_id,
+	m.subject, m.body, m.gold, m.returned, m.read_at, m.collected_at, m.expires_at`
+
+type MailRepository struct {
+	db ExtHandle
+}
+
+func NewMailRepository(db ExtHandle) *MailRepository {
+	return &MailRepository{db: db}
+}
+
+func (r *MailRepository) Create(mail *domain.Mail) error {
+	query := `
+		INSERT INTO mails (sender_id, recipient_id, subject, body, gold, returned, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		mail.SenderID,
+		mail.RecipientID,
+		mail.Subject,
+		mail.Body,
+		mail.Gold,
+		mail.Returned,
+		mail.ExpiresAt,
+	).Scan(&mail.ID, &mail.CreatedAt)
+}
+
+// FindByRecipientID returns the user's mailbox, newest first. Expired mail
+// the worker has not picked up yet is left out.
+func (r *MailRepository) FindByRecipientID(recipientID uuid.UUID, now time.Time) ([]*domain.Mail, error) {
+	query := `
+		SELECT ` + mailColumns + `
+		FROM mails m
+		LEFT JOIN users u ON u.id = m.sender_id
+		WHERE m.recipient_id = $1 AND m.expires_at > $2
+		ORDER BY m.created_at DESC
+	`
+
+	mails := []*domain.Mail{}
+	if err := r.db.Select(&mails, query, recipientID, now); err != nil {
+		return nil, err
+	}
+
+	return mails, nil
+}
+
+// FindByIDForUpdate returns the recipient's mail and locks it.
+func (r *MailRepository) FindByIDForUpdate(recipientID, id uuid.UUID) (*domain.Mail, error) {
+	query := `
+		SELECT ` + mailColumns + `
+		FROM mails m
+		LEFT JOIN users u ON u.id = m.sender_id
+		WHERE m.id = $1 AND m.recipient_id = $2
+		FOR UPDATE OF m
+	`
+
+	mail := &domain.Mail{}
+	if err := r.db.Get(mail, query, id, recipientID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMailNotFound
+		}
+		return nil, err
+	}
+
+	return mail, nil
+}
+
+// FindExpiredForUpdate locks up to limit mails that expired before now,
+// skipping ones another worker holds.
+func (r *MailRepository) FindExpiredForUpdate(now time.Time, limit int) ([]*domain.Mail, error) {
+	query := `
+		SELECT ` + mailColumns + `
+		FROM mails m
+		LEFT JOIN users u ON u.id = m.sender_id
+		WHERE m.expires_at <= $1
+		ORDER BY m.expires_at ASC
+		LIMIT $2
+		FOR UPDATE OF m SKIP LOCKED
+	`
+
+	mails := []*domain.Mail{}
+	if err := r.db.Select(&mails, query, now, limit); err != nil {
+		return nil, err
+	}
+
+	return mails, nil
+}
+
+// CountUnread returns how many unexpired mails the user hasn't read.
+func (r *MailRepository) CountUnread(recipientID uuid.UUID, now time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM mails WHERE recipient_id = $1 AND read_at IS NULL AND expires_at > $2`
+
+	var count int
+	err := r.db.Get(&count, query, recipientID, now)
+	return count, err
+}
+
+func (r *MailRepository) MarkRead(mail *domain.Mail) error {
+	query := `UPDATE mails SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 RETURNING read_at`
+	return r.db.QueryRow(query, mail.ID).Scan(&mail.ReadAt)
+}
+
+// MarkCollected reads the mail too.
+func (r *MailRepository) MarkCollected(mail *domain.Mail) error {
+	query := `
+		UPDATE mails
+		SET collected_at = NOW(), read_at = COALESCE(read_at, NOW())
+		WHERE id = $1
+		RETURNING collected_at, read_at
+	`
+	return r.db.QueryRow(query, mail.ID).Scan(&mail.CollectedAt, &mail.ReadAt)
+}
+
+func (r *MailRepository) Delete(id uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM mails WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrProgressionNotFound = errors.New("progression config not found")
+)
+
+type ProgressionRepository struct {
+	db ExtHandle
+}
+
+func NewProgressionRepository(db ExtHandle) *ProgressionRepository {
+	return &ProgressionRepository{db: db}
+}
+
+func (r *ProgressionRepository) FindLatest() (*domain.Progression, error) {
+	query := `
+		SELECT config
+		FROM progression_configs
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	var data []byte
+	err := r.db.Get(&data, query)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProgressionNotFound
+		}
+		return nil, err
+	}
+
+	return domain.ParseProgression(data)
+}
+
+func (r *ProgressionRepository) Create(progression *domain.Progression) error {
+	data, err := json.Marshal(progression)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO progression_configs (version, config) VALUES ($1, $2)`
+	_, err = r.db.Exec(query, progression.Version, data)
+	return err
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrQuestNotFound        = errors.New("quest not found")
+	ErrUserQuestNotFound    = errors.New("user quest not found")
+	ErrQuestAlreadyAccepted = errors.New("quest already accepted")
+	ErrQuestNotCompleted    = errors.New("quest not completed")
+	// ErrQuestObjectiveNotTracked is a quest nobody could ever complete.
+	ErrQuestObjectiveNotTracked = errors.New("quest objective is not tracked")
+)
+
+type QuestRepository struct {
+	db ExtHandle
+}
+
+func NewQuestRepository(db ExtHandle) *QuestRepository {
+	return &QuestRepository{db: db}
+}
+
+// Create fails with ErrQuestObjectiveNotTracked for objectives nothing
+// reports progress on.
+func (r *QuestRepository) Create(quest *domain.Quest) error {
+	if !quest.ObjectiveType.Tracked() {
+		return ErrQuestObjectiveNotTracked
+	}
+
+	query := `
+		INSERT INTO quests (name, slug, description, required_level, objective_type, target_slug, target_count,
+			reward_gold, reward_exp, reward_equipment_item_id, daily)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		quest.Name, quest.Slug, quest.Description, quest.RequiredLevel, quest.ObjectiveType, quest.TargetSlug,
+		quest.TargetCount, quest.RewardGold, quest.RewardExp, quest.RewardEquipmentItemID, quest.Daily,
+	).Scan(&quest.ID, &quest.CreatedAt)
+}
+
+func (r *QuestRepository) FindAll() ([]*domain.Quest, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, description, required_level, objective_type,
+			target_slug, target_count, reward_gold, reward_exp, reward_equipment_item_id, daily
+		FROM quests
+		WHERE deleted_at IS NULL
+		ORDER BY daily DESC, required_level ASC, name ASC
+	`
+
+	quests := []*domain.Quest{}
+	if err := r.db.Select(&quests, query); err != nil {
+		return nil, err
+	}
+
+	return quests, nil
+}
+
+func (r *QuestRepository) FindBySlug(slug string) (*domain.Quest, error) {
+	query := `
+		SELECT id, created_at, deleted_at, name, slug, description, required_level, objective_type,
+			target_slug, target_count, reward_gold, reward_exp, reward_equipment_item_id, daily
+		FROM quests
+		WHERE slug = $1 AND deleted_at IS NULL
+	`
+
+	quest := &domain.Quest{}
+	err := r.db.Get(quest, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuestNotFound
+		}
+		return nil, err
+	}
+
+	return quest, nil
+}
+
+func (r *QuestRepository) FindUserQuests(userID uuid.UUID) ([]*domain.UserQuest, error) {
+	query := `
+		SELECT id, created_at, user_id, quest_id, progress, status, completed_at, claimed_at
+		FROM user_quests
+		WHERE user_id = $1
+	`
+
+	userQuests := []*domain.UserQuest{}
+	if err := r.db.Select(&userQuests, query, userID); err != nil {
+		return nil, err
+	}
+
+	return userQuests, nil
+}
+
+func (r *QuestRepository) Accept(userID, questID uuid.UUID) (*domain.UserQuest, error) {
+	query := `
+		INSERT INTO user_quests (user_id, quest_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, quest_id) DO NOTHING
+		RETURNING id, created_at, user_id, quest_id, progress, status, completed_at, claimed_at
+	`
+
+	userQuest := &domain.UserQuest{}
+	err := r.db.Get(userQuest, query, userID, questID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuestAlreadyAccepted
+		}
+		return nil, err
+	}
+
+	return userQuest, nil
+}
+
+// MarkClaimed moves a completed quest to claimed and fails with
+// ErrQuestNotCompleted for any other state, so a reward is paid only once.
+func (r *QuestRepository) MarkClaimed(userID, questID uuid.UUID) error {
+	query := `
+		UPDATE user_quests
+		SET status = 'CLAIMED', claimed_at = NOW()
+		WHERE user_id = $1 AND quest_id = $2 AND status = 'COMPLETED'
+	`
+
+	res, err := r.db.Exec(query, userID, questID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrQuestNotCompleted
+	}
+
+	return nil
+}
+
+// AddProgress advances every accepted quest of the user with a matching
+// objective and completes the ones that reach their target count.
+// RecordClaim keeps the claim and its rewards after the daily reset removes
+// the user's quest.
+func (r *QuestRepository) RecordClaim(userID uuid.UUID, quest *domain.Quest) error {
+	query := `INSERT INTO quest_claims (user_id, quest_id, reward_gold, reward_exp) VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.Exec(query, userID, quest.ID, quest.RewardGold, quest.RewardExp)
+	return err
+}
+
+func (r *QuestRepository) AddProgress(userID uuid.UUID, objective domain.QuestObjectiveType, targetSlug string, amount uint) error {
+	query := `
+		UPDATE user_quests uq
+		SET progress = LEAST(uq.progress + $4, q.target_count),
+		    status = CASE WHEN uq.progress + $4 >= q.target_count THEN 'COMPLETED'::quest_status ELSE uq.status END,
+		    completed_at = CASE WHEN uq.progress + $4 >= q.target_count THEN NOW() ELSE NULL END
+		FROM quests q
+		WHERE uq.quest_id = q.id
+		  AND uq.user_id = $1
+		  AND uq.status = 'IN_PROGRESS'
+		  AND q.objective_type = $2
+		  AND q.target_slug = $3
+		  AND q.deleted_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, userID, objective, targetSlug, amount)
+	return err
+}
+
+// ResetDaily drops daily quests accepted before since so they can be taken
+// again. Completed quests are kept until their reward is claimed. It returns
+// the number of reset quests.
+func (r *QuestRepository) ResetDaily(since time.Time) (int64, error) {
+	query := `
+		DELETE FROM user_quests uq
+		USING quests q
+		WHERE uq.quest_id = q.id AND q.daily AND uq.created_at < $1
+		  AND uq.status <> 'COMPLETED'
+	`
+
+	res, err := r.db.Exec(query, since)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -32,7 +32,10 @@ func (r *RoundRepository) FindByFightID(fightID uuid.UUID) ([]*domain.Round, err
 	query := `
 		SELECT id, created_at, deleted_at, fight_id, player_damage, bot_damage, 
 			status, player_hp, bot_hp, player_attack_point, player_defense_point, 
-			bot_attack_point, bot_defense_point
+			bot_attack_point, bot_defense_point, player_heal, player_consumable_id,
+			player_effect_damage, bot_effect_damage, player_absorbed, bot_absorbed,
+			player_stunned, bot_stunned, player_crit, bot_crit, player_dodged, bot_dodged,
+			player_blocked, bot_blocked
 		FROM rounds 
 		WHERE fight_id = $1 AND deleted_at IS NULL 
 		ORDER BY created_at DESC
@@ -60,7 +63,7 @@ func (r *RoundRepository) FinishRound(id uuid.UUID, botAttackPoint, botDefensePo
 		UPDATE rounds
 		SET bot_attack_point = $1,
 		    bot_defense_point = $2,
-		    player_attack_point = $3,
+		    player_attack_point = NULLIF($3, '')::body_part,
 		    player_defense_point = $4,
 		    player_damage = $5,
 		    bot_damage = $6,
@@ -77,3 +80,45 @@ func (r *RoundRepository) FinishRound(id uuid.UUID, botAttackPoint, botDefensePo
 
 	return nil
 }
+
+func (r *RoundRepository) SetPlayerHeal(id, consumableID uuid.UUID, heal uint) error {
+	query := `UPDATE rounds SET player_heal = $1, player_consumable_id = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, heal, consumableID, id)
+	return err
+}
+
+// SetStatusEffects stores what the fight status effects did during the round.
+func (r *RoundRepository) SetStatusEffects(id uuid.UUID, playerEffectDmg, botEffectDmg, playerAbsorbed, botAbsorbed uint,
+	playerStunned, botStunned bool) error {
+	query := `
+		UPDATE rounds
+		SET player_effect_damage = $1,
+		    bot_effect_damage = $2,
+		    player_absorbed = $3,
+		    bot_absorbed = $4,
+		    player_stunned = $5,
+		    bot_stunned = $6
+		WHERE id = $7
+	`
+
+	_, err := r.db.Exec(query, playerEffectDmg, botEffectDmg, playerAbsorbed, botAbsorbed, playerStunned, botStunned, id)
+	return err
+}
+
+// SetStrikeFlags records crits, dodges and blocks of the round. Player flags
+// describe the player's own strike or defense.
+func (r *RoundRepository) SetStrikeFlags(id uuid.UUID, playerCrit, botCrit, playerDodged, botDodged, playerBlocked, botBlocked bool) error {
+	query := `
+		UPDATE rounds
+		SET player_crit = $1,
+		    bot_crit = $2,
+		    player_dodged = $3,
+		    bot_dodged = $4,
+		    player_blocked = $5,
+		    bot_blocked = $6
+		WHERE id = $7
+	`
+
+	_, err := r.db.Exec(query, playerCrit, botCrit, playerDodged, botDodged, playerBlocked, botBlocked, id)
+	return err
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var ErrShopStockNotFound = errors.New("shop stock not found")
+
+const shopStockColumns = `id, created_at, updated_at, location_id, equipment_item_id, consumable_id, quantity, max_quantity,
+	restock_amount, price, sold_since_restock, restocked_at`
+
+type ShopStockRepository struct {
+	db ExtHandle
+}
+
+func NewShopStockRepository(db ExtHandle) *ShopStockRepository {
+	return &ShopStockRepository{db: db}
+}
+
+// Create adds or replaces the stock of the item or the consumable in the
+// shop.
+func (r *ShopStockRepository) Create(stock *domain.ShopStock) error {
+	equipmentItemID, conflict := &stock.EquipmentItemID, "(location_id, equipment_item_id)"
+	if stock.ConsumableID != nil {
+		equipmentItemID, conflict = nil, "(location_id, consumable_id)"
+	}
+
+	query := `
+		INSERT INTO shop_stock (location_id, equipment_item_id, consumable_id, quantity, max_quantity, restock_amount, price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ` + conflict + ` DO UPDATE
+		SET quantity = EXCLUDED.quantity,
+			max_quantity = EXCLUDED.max_quantity,
+			restock_amount = EXCLUDED.restock_amount,
+			price = EXCLUDED.price,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at, restocked_at
+	`
+
+	return r.db.QueryRow(query,
+		stock.LocationID,
+		equipmentItemID,
+		stock.ConsumableID,
+		stock.Quantity,
+		stock.MaxQuantity,
+		stock.RestockAmount,
+		stock.Price,
+	).Scan(&stock.ID, &stock.CreatedAt, &stock.UpdatedAt, &stock.RestockedAt)
+}
+
+func (r *ShopStockRepository) FindByLocationID(locationID uuid.UUID) ([]*domain.ShopStock, error) {
+	query := `
+		SELECT s.id, s.created_at, s.updated_at, s.location_id, s.equipment_item_id, s.consumable_id, s.quantity,
+			s.max_quantity, s.restock_amount, s.price, s.sold_since_restock, s.restocked_at
+		FROM shop_stock s
+		LEFT JOIN equipment_items ei ON ei.id = s.equipment_item_id
+		LEFT JOIN consumables c ON c.id = s.consumable_id
+		WHERE s.location_id = $1 AND COALESCE(ei.deleted_at, c.deleted_at) IS NULL
+		ORDER BY COALESCE(ei.required_level, c.required_level) ASC, COALESCE(ei.name, c.name) ASC
+	`
+
+	stocks := []*domain.ShopStock{}
+	if err := r.db.Select(&stocks, query, locationID); err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
+
+// FindForUpdate returns the shop's stock of the item and locks it.
+func (r *ShopStockRepository) FindForUpdate(locationID, equipmentItemID uuid.UUID) (*domain.ShopStock, error) {
+	query := `SELECT ` + shopStockColumns + ` FROM shop_stock WHERE location_id = $1 AND equipment_item_id = $2 FOR UPDATE`
+
+	stock := &domain.ShopStock{}
+	if err := r.db.Get(stock, query, locationID, equipmentItemID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShopStockNotFound
+		}
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// FindConsumableForUpdate returns the shop's stock of the consumable and
+// locks it.
+func (r *ShopStockRepository) FindConsumableForUpdate(locationID, consumableID uuid.UUID) (*domain.ShopStock, error) {
+	query := `SELECT ` + shopStockColumns + ` FROM shop_stock WHERE location_id = $1 AND consumable_id = $2 FOR UPDATE`
+
+	stock := &domain.ShopStock{}
+	if err := r.db.Get(stock, query, locationID, consumableID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShopStockNotFound
+		}
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// IsStocked reports whether any shop keeps stock of the item.
+func (r *ShopStockRepository) IsStocked(equipmentItemID uuid.UUID) (bool, error) {
+	exists := false
+	err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM shop_stock WHERE equipment_item_id = $1)`, equipmentItemID)
+	return exists, err
+}
+
+// IsConsumableStocked reports whether any shop keeps stock of the consumable.
+func (r *ShopStockRepository) IsConsumableStocked(consumableID uuid.UUID) (bool, error) {
+	exists := false
+	err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM shop_stock WHERE consumable_id = $1)`, consumableID)
+	return exists, err
+}
+
+// FindStockedItemIDs returns every item some shop keeps stock of.
+func (r *ShopStockRepository) FindStockedItemIDs() ([]uuid.UUID, error) {
+	query := `SELECT DISTINCT equipment_item_id FROM shop_stock WHERE equipment_item_id IS NOT NULL`
+
+	ids := []uuid.UUID{}
+	if err := r.db.Select(&ids, query); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindAllForUpdate locks every stock for a restock.
+func (r *ShopStockRepository) FindAllForUpdate() ([]*domain.ShopStock, error) {
+	query := `SELECT ` + shopStockColumns + ` FROM shop_stock ORDER BY id FOR UPDATE`
+
+	stocks := []*domain.ShopStock{}
+	if err := r.db.Select(&stocks, query); err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
+
+// TakeOne sells one copy, ErrShopStockNotFound when the shop ran out.
+func (r *ShopStockRepository) TakeOne(id uuid.UUID) error {
+	return r.Take(id, 1)
+}
+
+// Take sells quantity copies, ErrShopStockNotFound when the shop has fewer
+// left.
+func (r *ShopStockRepository) Take(id uuid.UUID, quantity uint) error {
+	query := `
+		UPDATE shop_stock
+		SET quantity = quantity - $2, sold_since_restock = sold_since_restock + $2, updated_at = NOW()
+		WHERE id = $1 AND quantity >= $2
+	`
+
+	res, err := r.db.Exec(query, id, quantity)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrShopStockNotFound
+	}
+
+	return nil
+}
+
+// UpdateRestock stores the quantity and price set by a restock.
+func (r *ShopStockRepository) UpdateRestock(stock *domain.ShopStock) error {
+	query := `
+		UPDATE shop_stock
+		SET quantity = $2, price = $3, sold_since_restock = $4, restocked_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		RETURNING restocked_at, updated_at
+	`
+
+	return r.db.QueryRow(query, stock.ID, stock.Quantity, stock.Price, stock.SoldSinceRestock).
+		Scan(&stock.RestockedAt, &stock.UpdatedAt)
+}
+
+func (r *ShopStockRepository) CreatePriceHistory(entry *domain.ShopPriceHistory) error {
+	query := `
+		INSERT INTO shop_price_history (location_id, equipment_item_id, consumable_id, price, quantity, sold)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, recorded_at
+	`
+
+	equipmentItemID := &entry.EquipmentItemID
+	if entry.ConsumableID != nil {
+		equipmentItemID = nil
+	}
+
+	return r.db.QueryRow(query, entry.LocationID, equipmentItemID, entry.ConsumableID, entry.Price, entry.Quantity, entry.Sold).
+		Scan(&entry.ID, &entry.RecordedAt)
+}
+
+// FindPriceHistory returns the latest entries of the item in the shop, newest
+// first.
+func (r *ShopStockRepository) FindPriceHistory(locationID, equipmentItemID uuid.UUID, limit int) ([]*domain.ShopPriceHistory, error) {
+	query := `
+		SELECT id, recorded_at, location_id, equipment_item_id, consumable_id, price, quantity, sold
+		FROM shop_price_history
+		WHERE location_id = $1 AND equipment_item_id = $2
+		ORDER BY recorded_at DESC, id DESC
+		LIMIT $3
+	`
+
+	history := []*domain.ShopPriceHistory{}
+	if err := r.db.Select(&history, query, locationID, equipmentItemID, limit); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+type StatusEffectRepository struct {
+	db ExtHandle
+}
+
+func NewStatusEffectRepository(db ExtHandle) *StatusEffectRepository {
+	return &StatusEffectRepository{db: db}
+}
+
+func (r *StatusEffectRepository) Create(effect *domain.StatusEffect) error {
+	query := `
+		INSERT INTO fight_status_effects (fight_id, round_id, target, effect_type, value, rounds_left)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		effect.FightID, effect.RoundID, effect.Target, effect.EffectType, effect.Value, effect.RoundsLeft,
+	).Scan(&effect.ID, &effect.CreatedAt)
+}
+
+// FindByFightID returns every effect of the fight, expired ones included, in
+// the order they were applied.
+func (r *StatusEffectRepository) FindByFightID(fightID uuid.UUID) ([]*domain.StatusEffect, error) {
+	query := `
+		SELECT id, created_at, deleted_at, fight_id, round_id, target, effect_type, value, rounds_left
+		FROM fight_status_effects
+		WHERE fight_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	effects := []*domain.StatusEffect{}
+	if err := r.db.Select(&effects, query, fightID); err != nil {
+		return nil, err
+	}
+
+	return effects, nil
+}
+
+func (r *StatusEffectRepository) Update(effect *domain.StatusEffect) error {
+	query := `UPDATE fight_status_effects SET value = $1, rounds_left = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, effect.Value, effect.RoundsLeft, effect.ID)
+	return err
+}
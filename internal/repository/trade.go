@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var ErrTradeNotFound = errors.New("trade not found")
+
+type TradeRepository struct {
+	db ExtHandle
+}
+
+func NewTradeRepository(db ExtHandle) *TradeRepository {
+	return &TradeRepository{db: db}
+}
+
+func (r *TradeRepository) Create(trade *domain.Trade) error {
+	query := `
+		INSERT INTO trades (initiator_id, partner_id, location_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at, status
+	`
+
+	return r.db.QueryRow(query, trade.InitiatorID, trade.PartnerID, trade.LocationID).
+		Scan(&trade.ID, &trade.CreatedAt, &trade.UpdatedAt, &trade.Status)
+}
+
+// FindOpenByUserID returns the open trade the user takes part in.
+func (r *TradeRepository) FindOpenByUserID(userID uuid.UUID) (*domain.Trade, error) {
+	query := `
+		SELECT id, created_at, updated_at, initiator_id, partner_id, location_id, status,
+			initiator_gold, partner_gold, initiator_confirmed, partner_confirmed, completed_at
+		FROM trades
+		WHERE (initiator_id = $1 OR partner_id = $1) AND status = 'OPEN'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	trade := &domain.Trade{}
+	if err := r.db.Get(trade, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTradeNotFound
+		}
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// FindByIDForUpdate locks the trade, so offers and confirmations of both
+// sides are applied one at a time.
+func (r *TradeRepository) FindByIDForUpdate(id uuid.UUID) (*domain.Trade, error) {
+	query := `
+		SELECT id, created_at, updated_at, initiator_id, partner_id, location_id, status,
+			initiator_gold, partner_gold, initiator_confirmed, partner_confirmed, completed_at
+		FROM trades
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	trade := &domain.Trade{}
+	if err := r.db.Get(trade, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTradeNotFound
+		}
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// UpdateOffer stores the gold the user offers and resets both confirmations.
+func (r *TradeRepository) UpdateOffer(trade *domain.Trade, userID uuid.UUID, gold uint) error {
+	column := "partner_gold"
+	if trade.InitiatorID == userID {
+		column = "initiator_gold"
+	}
+
+	query := `
+		UPDATE trades
+		SET ` + column + ` = $2,
+			initiator_confirmed = false,
+			partner_confirmed = false,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at, initiator_gold, partner_gold, initiator_confirmed, partner_confirmed
+	`
+
+	return r.db.QueryRow(query, trade.ID, gold).Scan(
+		&trade.UpdatedAt, &trade.InitiatorGold, &trade.PartnerGold, &trade.InitiatorConfirmed, &trade.PartnerConfirmed,
+	)
+}
+
+// Confirm marks the user's side as confirmed.
+func (r *TradeRepository) Confirm(trade *domain.Trade, userID uuid.UUID) error {
+	column := "partner_confirmed"
+	if trade.InitiatorID == userID {
+		column = "initiator_confirmed"
+	}
+
+	query := `
+		UPDATE trades
+		SET ` + column + ` = true, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at, initiator_confirmed, partner_confirmed
+	`
+
+	return r.db.QueryRow(query, trade.ID).Scan(&trade.UpdatedAt, &trade.InitiatorConfirmed, &trade.PartnerConfirmed)
+}
+
+func (r *TradeRepository) UpdateStatus(trade *domain.Trade, status domain.TradeStatus) error {
+	query := `
+		UPDATE trades
+		SET status = $2,
+			updated_at = NOW(),
+			completed_at = CASE WHEN $2 = 'COMPLETED'::trade_status THEN NOW() ELSE completed_at END
+		WHERE id = $1
+		RETURNING status, updated_at, completed_at
+	`
+
+	return r.db.QueryRow(query, trade.ID, status).Scan(&trade.Status, &trade.UpdatedAt, &trade.CompletedAt)
+}
+
+func (r *TradeRepository) CreateLog(entry *domain.TradeLog) error {
+	query := `
+		INSERT INTO trade_logs (trade_id, from_user_id, to_user_id, inventory_id, equipment_item_id, gold)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		entry.TradeID, entry.FromUserID, entry.ToUserID, entry.InventoryID, entry.EquipmentItemID, entry.Gold,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *TradeRepository) FindLogs(tradeID uuid.UUID) ([]*domain.TradeLog, error) {
+	query := `
+		SELECT id, created_at, trade_id, from_user_id, to_user_id, inventory_id, equipment_item_id, gold
+		FROM trade_logs
+		WHERE trade_id = $1
+		ORDER BY created_at ASC
+	`
+
+	logs := []*domain.TradeLog{}
+	if err := r.db.Select(&logs, query, tradeID); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultTxAttempts = 3
+	txRetryBackoff    = 20 * time.Millisecond
+)
+
+// Repositories groups every repository bound to the same handle, either the
+// pool or a single transaction.
+type Repositories struct {
+	Achievements     *AchievementRepository
+	Auctions         *AuctionRepository
+	Avatars          *AvatarRepository
+	Bots             *BotRepository
+	Consumables      *ConsumableRepository
+	EquipmentItems   *EquipmentItemRepository
+	EquipmentSlots   *EquipmentSlotRepository
+	Fights           *FightRepository
+	GoldTransactions *GoldTransactionRepository
+	Guilds           *GuildRepository
+	Inventory        *InventoryRepository
+	ItemSets         *ItemSetRepository
+	Leaderboards     *LeaderboardRepository
+	Loadouts         *LoadoutRepository
+	Locations        *LocationRepository
+	Mails            *MailRepository
+	Progression      *ProgressionRepository
+	Quests           *QuestRepository
+	Rounds           *RoundRepository
+	ShopStock        *ShopStockRepository
+	StatusEffects    *StatusEffectRepository
+	Trades           *TradeRepository
+	Users            *UserRepository
+}
+
+func NewRepositories(h ExtHandle) *Repositories {
+	return &Repositories{
+		Achievements:     NewAchievementRepository(h),
+		Auctions:         NewAuctionRepository(h),
+		Avatars:          NewAvatarRepository(h),
+		Bots:             NewBotRepository(h),
+		Consumables:      NewConsumableRepository(h),
+		EquipmentItems:   NewEquipmentItemRepository(h),
+		EquipmentSlots:   NewEquipmentSlotRepository(h),
+		Fights:           NewFightRepository(h),
+		GoldTransactions: NewGoldTransactionRepository(h),
+		Guilds:           NewGuildRepository(h),
+		Inventory:        NewInventoryRepository(h),
+		ItemSets:         NewItemSetRepository(h),
+		Leaderboards:     NewLeaderboardRepository(h),
+		Loadouts:         NewLoadoutRepository(h),
+		Locations:        NewLocationRepository(h),
+		Mails:            NewMailRepository(h),
+		Progression:      NewProgressionRepository(h),
+		Quests:           NewQuestRepository(h),
+		Rounds:           NewRoundRepository(h),
+		ShopStock:        NewShopStockRepository(h),
+		StatusEffects:    NewStatusEffectRepository(h),
+		Trades:           NewTradeRepository(h),
+		Users:            NewUserRepository(h),
+	}
+}
+
+// UnitOfWork runs a function against repositories that share one transaction.
+type UnitOfWork struct {
+	db       *sqlx.DB
+	attempts int
+}
+
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{db: db, attempts: defaultTxAttempts}
+}
+
+// WithTx runs fn in a read committed transaction, see WithTxOptions.
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(repos *Repositories) error) error {
+	return u.WithTxOptions(ctx, nil, fn)
+}
+
+// WithTxOptions commits when fn returns nil and rolls back otherwise. When
+// Postgres aborts the transaction with a serialization failure or a deadlock
+// the whole function is run again in a fresh transaction, so fn must not have
+// side effects outside the database. Publish events after WithTx returns.
+func (u *UnitOfWork) WithTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(repos *Repositories) error) error {
+	var err error
+	for attempt := 1; attempt <= u.attempts; attempt++ {
+		err = u.run(ctx, opts, fn)
+		if err == nil || !isRetryableTxError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * txRetryBackoff):
+		}
+	}
+
+	return fmt.Errorf("transaction failed after %d attempts: %w", u.attempts, err)
+}
+
+func (u *UnitOfWork) run(ctx context.Context, opts *sql.TxOptions, fn func(repos *Repositories) error) error {
+	tx, err := u.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(NewRepositories(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isRetryableTxError reports whether Postgres gave up on the transaction
+// because of a conflict with another one, so running it again can succeed.
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch pqErr.Code {
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func TestIsRetryableTxError(t *testing.T) {
+	assert.True(t, isRetryableTxError(&pq.Error{Code: "40001"}))
+	assert.True(t, isRetryableTxError(fmt.Errorf("wrapped: %w", &pq.Error{Code: "40P01"})))
+	assert.False(t, isRetryableTxError(&pq.Error{Code: "23505"}))
+	assert.False(t, isRetryableTxError(errors.New("40001")))
+	assert.False(t, isRetryableTxError(nil))
+}
+
+func TestUnitOfWork_WithTx(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	ctx := context.Background()
+	uow := NewUnitOfWork(testDB)
+
+	t.Run("commits on success", func(t *testing.T) {
+		ts := time.Now().UnixNano()
+		location := &domain.Location{Name: fmt.Sprintf("UoW %d", ts), Slug: fmt.Sprintf("uow-%d", ts)}
+
+		err := uow.WithTx(ctx, func(repos *Repositories) error {
+			return repos.Locations.Create(location)
+		})
+		require.NoError(t, err)
+
+		_, err = NewLocationRepository(testDB).FindBySlug(location.Slug)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		ts := time.Now().UnixNano()
+		location := &domain.Location{Name: fmt.Sprintf("UoW %d", ts), Slug: fmt.Sprintf("uow-%d", ts)}
+		errStop := errors.New("stop")
+
+		err := uow.WithTx(ctx, func(repos *Repositories) error {
+			require.NoError(t, repos.Locations.Create(location))
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+
+		_, err = NewLocationRepository(testDB).FindBySlug(location.Slug)
+		assert.Error(t, err)
+	})
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		attempts := 0
+		err := uow.WithTx(ctx, func(repos *Repositories) error {
+			attempts++
+			if attempts < 2 {
+				return &pq.Error{Code: "40001"}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		attempts := 0
+		err := uow.WithTx(ctx, func(repos *Repositories) error {
+			attempts++
+			return &pq.Error{Code: "40P01"}
+		})
+		assert.Error(t, err)
+		assert.Equal(t, defaultTxAttempts, attempts)
+	})
+}
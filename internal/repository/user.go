@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"moonshine/internal/domain"
 )
@@ -14,31 +15,44 @@ import (
 var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrUserExists   = errors.New("user already exists")
+
+	ErrInsufficientFreeStats = errors.New("insufficient free stats")
 )
 
 type UserRepository struct {
-	db *sqlx.DB
+	db ExtHandle
 }
 
-func NewUserRepository(db *sqlx.DB) *UserRepository {
+func NewUserRepository(db ExtHandle) *UserRepository {
 	return &UserRepository{db: db}
 }
 
 func (r *UserRepository) Create(user *domain.User) error {
 	query := `
-		INSERT INTO users (
-			username, email, password, name, avatar_id, location_id,
-			attack, defense, current_hp, exp, free_stats, gold, hp, level
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+		WITH created AS (
+			INSERT INTO users (
+				username, email, password, name, avatar_id, location_id,
+				attack, defense, current_hp, exp, free_stats, gold, hp, level,
+				base_attack, base_defense, base_hp
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+			)
+			RETURNING id, created_at, updated_at, gold, inventory_capacity
+		), ledger AS (
+			INSERT INTO gold_transactions (user_id, delta, reason, balance_after)
+			SELECT id, gold, 'INITIAL', gold FROM created WHERE gold <> 0
 		)
-		RETURNING id, created_at, updated_at
+		SELECT id, created_at, updated_at, inventory_capacity FROM created
 	`
 
+	// New users have nothing equipped, their stats are the base stats.
+	user.BaseAttack, user.BaseDefense, user.BaseHp = user.Attack, user.Defense, user.Hp
+
 	err := r.db.QueryRow(query,
 		user.Username, user.Email, user.Password, user.Name, user.AvatarID, user.LocationID,
 		user.Attack, user.Defense, user.CurrentHp, user.Exp, user.FreeStats, user.Gold, user.Hp, user.Level,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+		user.BaseAttack, user.BaseDefense, user.BaseHp,
+	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt, &user.InventoryCapacity)
 	if err != nil {
 		if isUniqueConstraintError(err) {
 			return ErrUserExists
@@ -52,14 +66,13 @@ func (r *UserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
 	query := `
 		SELECT users.id, users.created_at, users.updated_at, users.deleted_at, users.username, users.email, users.password, users.name, 
 			users.avatar_id, users.location_id, users.attack, users.defense, users.current_hp, users.exp,
-			users.free_stats, users.gold, users.hp, users.level,
-			users.chest_equipment_item_id, users.belt_equipment_item_id, users.head_equipment_item_id,
-			users.neck_equipment_item_id, users.weapon_equipment_item_id, users.shield_equipment_item_id,
-			users.legs_equipment_item_id, users.feet_equipment_item_id, users.arms_equipment_item_id,
-			users.hands_equipment_item_id, users.ring1_equipment_item_id, users.ring2_equipment_item_id,
-			users.ring3_equipment_item_id, users.ring4_equipment_item_id, COALESCE(avatars.image, '') as avatar
+			users.free_stats, users.gold, users.hp, users.level, users.base_attack, users.base_defense, users.base_hp,
+			COALESCE(avatars.image, '') as avatar,
+			users.agility, users.luck, users.title, users.inventory_capacity, guilds.tag as guild_tag
 		FROM users
 		LEFT JOIN avatars ON avatars.id = users.avatar_id
+		LEFT JOIN guild_members ON guild_members.user_id = users.id
+		LEFT JOIN guilds ON guilds.id = guild_members.guild_id
 		WHERE users.id = $1 AND users.deleted_at IS NULL
 	`
 
@@ -72,6 +85,44 @@ func (r *UserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
 		return nil, err
 	}
 
+	if err := r.loadEquipment([]*domain.User{user}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// FindByIDForUpdate reads the user and locks the row until the transaction
+// ends, so concurrent balance checks run one at a time. Call it on a
+// repository bound to a transaction.
+func (r *UserRepository) FindByIDForUpdate(id uuid.UUID) (*domain.User, error) {
+	query := `
+		SELECT users.id, users.created_at, users.updated_at, users.deleted_at, users.username, users.email, users.password, users.name, 
+			users.avatar_id, users.location_id, users.attack, users.defense, users.current_hp, users.exp,
+			users.free_stats, users.gold, users.hp, users.level, users.base_attack, users.base_defense, users.base_hp,
+			COALESCE(avatars.image, '') as avatar,
+			users.agility, users.luck, users.title, users.inventory_capacity, guilds.tag as guild_tag
+		FROM users
+		LEFT JOIN avatars ON avatars.id = users.avatar_id
+		LEFT JOIN guild_members ON guild_members.user_id = users.id
+		LEFT JOIN guilds ON guilds.id = guild_members.guild_id
+		WHERE users.id = $1 AND users.deleted_at IS NULL
+		FOR UPDATE OF users
+	`
+
+	user := &domain.User{}
+	err := r.db.Get(user, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadEquipment([]*domain.User{user}); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -79,14 +130,13 @@ func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
 	query := `
 		SELECT users.id, users.created_at, users.updated_at, users.deleted_at, users.username, users.email, users.password, users.name, 
 			users.avatar_id, users.location_id, users.attack, users.defense, users.current_hp, users.exp,
-			users.free_stats, users.gold, users.hp, users.level,
-			users.chest_equipment_item_id, users.belt_equipment_item_id, users.head_equipment_item_id,
-			users.neck_equipment_item_id, users.weapon_equipment_item_id, users.shield_equipment_item_id,
-			users.legs_equipment_item_id, users.feet_equipment_item_id, users.arms_equipment_item_id,
-			users.hands_equipment_item_id, users.ring1_equipment_item_id, users.ring2_equipment_item_id,
-			users.ring3_equipment_item_id, users.ring4_equipment_item_id, COALESCE(avatars.image, '') as avatar
+			users.free_stats, users.gold, users.hp, users.level, users.base_attack, users.base_defense, users.base_hp,
+			COALESCE(avatars.image, '') as avatar,
+			users.agility, users.luck, users.title, users.inventory_capacity, guilds.tag as guild_tag
 		FROM users
 		LEFT JOIN avatars ON avatars.id = users.avatar_id
+		LEFT JOIN guild_members ON guild_members.user_id = users.id
+		LEFT JOIN guilds ON guilds.id = guild_members.guild_id
 		WHERE users.username = $1 AND users.deleted_at IS NULL
 	`
 
@@ -99,12 +149,68 @@ func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
 		return nil, err
 	}
 
+	if err := r.loadEquipment([]*domain.User{user}); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (r *UserRepository) UpdateGold(userID uuid.UUID, newGold uint) error {
-	query := `UPDATE users SET gold = $1 WHERE id = $2 AND deleted_at IS NULL`
-	_, err := r.db.Exec(query, newGold, userID)
+func (r *UserRepository) FindByIDs(ids []uuid.UUID) ([]*domain.User, error) {
+	query := `
+		SELECT users.id, users.created_at, users.updated_at, users.deleted_at, users.username, users.email, users.password, users.name, 
+			users.avatar_id, users.location_id, users.attack, users.defense, users.current_hp, users.exp,
+			users.free_stats, users.gold, users.hp, users.level, users.base_attack, users.base_defense, users.base_hp,
+			COALESCE(avatars.image, '') as avatar,
+			users.agility, users.luck, users.title, users.inventory_capacity, guilds.tag as guild_tag
+		FROM users
+		LEFT JOIN avatars ON avatars.id = users.avatar_id
+		LEFT JOIN guild_members ON guild_members.user_id = users.id
+		LEFT JOIN guilds ON guilds.id = guild_members.guild_id
+		WHERE users.id = ANY($1) AND users.deleted_at IS NULL
+	`
+
+	users := []*domain.User{}
+	if err := r.db.Select(&users, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadEquipment(users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// loadEquipment fills the equipment of the users from equipped_items.
+func (r *UserRepository) loadEquipment(users []*domain.User) error {
+	ids := make([]uuid.UUID, 0, len(users))
+	byID := make(map[uuid.UUID]*domain.User, len(users))
+	for _, user := range users {
+		user.Equipment = map[string]uuid.UUID{}
+		ids = append(ids, user.ID)
+		byID[user.ID] = user
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `SELECT user_id, slot, equipment_item_id FROM equipped_items WHERE user_id = ANY($1)`
+
+	items := []*domain.EquippedItem{}
+	if err := r.db.Select(&items, query, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		byID[item.UserID].Equipment[item.Slot] = item.EquipmentItemID
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateTitle(userID uuid.UUID, title *string) error {
+	query := `UPDATE users SET title = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, title, userID)
 	return err
 }
 
@@ -161,11 +267,15 @@ func (r *UserRepository) UpdateLocationID(userID uuid.UUID, locationID uuid.UUID
 	return err
 }
 
-func (r *UserRepository) Update(userID uuid.UUID, addedGold, addedExp, newLevel uint, newCurrentHp int) error {
-	return r.UpdateWithExt(r.db, userID, addedGold, addedExp, newLevel, newCurrentHp)
+func (r *UserRepository) Update(userID uuid.UUID, addedGold, addedExp, newLevel uint, newCurrentHp int,
+	reason domain.GoldTransactionReason, referenceID *uuid.UUID) error {
+	return r.UpdateWithExt(r.db, userID, addedGold, addedExp, newLevel, newCurrentHp, reason, referenceID)
 }
 
-func (r *UserRepository) UpdateWithExt(h ExtHandle, userID uuid.UUID, addedGold, addedExp, newLevel uint, newCurrentHp int) error {
+// UpdateWithExt adds gold and exp and sets the level and current hp. Added
+// gold is written to the ledger with the given reason on the same handle.
+func (r *UserRepository) UpdateWithExt(h ExtHandle, userID uuid.UUID, addedGold, addedExp, newLevel uint, newCurrentHp int,
+	reason domain.GoldTransactionReason, referenceID *uuid.UUID) error {
 	if newCurrentHp < 0 {
 		newCurrentHp = 0
 	}
@@ -177,11 +287,64 @@ func (r *UserRepository) UpdateWithExt(h ExtHandle, userID uuid.UUID, addedGold,
 		    level = $3,
 		    current_hp = $4
 		WHERE id = $5 AND deleted_at IS NULL
+		RETURNING gold
 	`
-	_, err := h.Exec(query, addedGold, addedExp, newLevel, newCurrentHp, userID)
+
+	var gold uint
+	if err := h.Get(&gold, query, addedGold, addedExp, newLevel, newCurrentHp, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if addedGold > 0 {
+		if err := NewGoldTransactionRepository(h).Record(userID, int64(addedGold), reason, referenceID, int64(gold)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *UserRepository) AddLevelReward(userID uuid.UUID, reward domain.LevelReward) error {
+	return r.AddLevelRewardWithExt(r.db, userID, reward)
+}
+
+// AddLevelRewardWithExt adds the reward to the free stats and the base hp,
+// recalculate the user's stats afterwards.
+func (r *UserRepository) AddLevelRewardWithExt(h ExtHandle, userID uuid.UUID, reward domain.LevelReward) error {
+	query := `
+		UPDATE users
+		SET free_stats = free_stats + $1,
+		    base_hp = base_hp + $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	_, err := h.Exec(query, reward.FreeStats, reward.Hp, userID)
 	return err
 }
 
+// AllocateStats moves free stats into agility and luck, failing with
+// ErrInsufficientFreeStats when the user has fewer free stats than requested.
+func (r *UserRepository) AllocateStats(userID uuid.UUID, agility, luck uint) error {
+	query := `
+		UPDATE users
+		SET free_stats = free_stats - $1 - $2,
+		    agility = agility + $1,
+		    luck = luck + $2
+		WHERE id = $3 AND deleted_at IS NULL AND free_stats >= $1 + $2
+	`
+
+	res, err := r.db.Exec(query, agility, luck, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInsufficientFreeStats
+	}
+	return nil
+}
+
 func (r *UserRepository) InFight(userID uuid.UUID) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM fights WHERE user_id = $1 AND status = $2 AND deleted_at IS NULL)`
 
@@ -213,3 +376,60 @@ func (r *UserRepository) GetHPForUsers(userIDs []uuid.UUID) ([]HPUpdate, error)
 	}
 	return updates, nil
 }
+
+// UpdateEquipmentSlot puts the item into the slot, nil empties it.
+// Recalculate the user's stats afterwards.
+func (r *UserRepository) UpdateEquipmentSlot(userID uuid.UUID, slot string, itemID *uuid.UUID) error {
+	if itemID == nil {
+		_, err := r.db.Exec(`DELETE FROM equipped_items WHERE user_id = $1 AND slot = $2`, userID, slot)
+		return err
+	}
+
+	query := `
+		INSERT INTO equipped_items (user_id, slot, equipment_item_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, slot) DO UPDATE SET equipment_item_id = EXCLUDED.equipment_item_id
+	`
+
+	_, err := r.db.Exec(query, userID, slot, *itemID)
+	return err
+}
+
+// UpdateStats stores the user's effective stats. Current hp is capped at the
+// new max hp.
+func (r *UserRepository) UpdateStats(userID uuid.UUID, stats domain.Stats) error {
+	query := `
+		UPDATE users
+		SET attack = $2,
+			defense = $3,
+			hp = $4,
+			current_hp = LEAST(current_hp, $4)
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, userID, stats.Attack, stats.Defense, stats.Hp)
+	return err
+}
+
+func (r *UserRepository) UpdateCurrentHp(userID uuid.UUID, currentHp int) error {
+	_, err := r.db.Exec(`UPDATE users SET current_hp = $2 WHERE id = $1 AND deleted_at IS NULL`, userID, currentHp)
+	return err
+}
+
+func (r *UserRepository) FindAllIDs() ([]uuid.UUID, error) {
+	query := `SELECT id FROM users WHERE deleted_at IS NULL ORDER BY created_at ASC`
+
+	ids := []uuid.UUID{}
+	if err := r.db.Select(&ids, query); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// UpdateInventoryCapacity sets how many bag slots the user has.
+func (r *UserRepository) UpdateInventoryCapacity(userID uuid.UUID, capacity uint) error {
+	query := `UPDATE users SET inventory_capacity = $2 WHERE id = $1 AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, userID, capacity)
+	return err
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+)
+
+type AuctionWorker struct {
+	auctionService *services.AuctionService
+	ticker         *time.Ticker
+}
+
+func NewAuctionWorker(db *sqlx.DB, interval time.Duration) *AuctionWorker {
+	return &AuctionWorker{
+		auctionService: services.NewAuctionService(db, ws.GetHub()),
+		ticker:         time.NewTicker(interval),
+	}
+}
+
+func (w *AuctionWorker) StartWorker(ctx context.Context) {
+	defer w.ticker.Stop()
+
+	w.settleExpired(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ticker.C:
+			w.settleExpired(ctx)
+		}
+	}
+}
+
+func (w *AuctionWorker) settleExpired(ctx context.Context) {
+	count, err := w.auctionService.SettleExpired(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("[AuctionWorker] Error settling auctions: %v\n", err)
+		return
+	}
+
+	if count > 0 {
+		log.Printf("[AuctionWorker] Settled %d auctions\n", count)
+	}
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"log"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	goredis "github.com/redis/go-redis/v9"
 
 	"moonshine/internal/domain"
+	"moonshine/internal/events"
 	r "moonshine/internal/redis"
 	"moonshine/internal/repository"
 )
@@ -16,6 +18,7 @@ import (
 type CellsMovingWorker struct {
 	locationRepo *repository.LocationRepository
 	userRepo     *repository.UserRepository
+	questRepo    *repository.QuestRepository
 	userCache    r.Cache[domain.User]
 	interval     time.Duration
 	mu           sync.Mutex
@@ -25,12 +28,14 @@ type CellsMovingWorker struct {
 func NewCellsMovingWorker(
 	locationRepo *repository.LocationRepository,
 	userRepo *repository.UserRepository,
+	questRepo *repository.QuestRepository,
 	rdb *goredis.Client,
 	interval time.Duration,
 ) *CellsMovingWorker {
 	return &CellsMovingWorker{
 		locationRepo: locationRepo,
 		userRepo:     userRepo,
+		questRepo:    questRepo,
 		userCache:    r.NewJSONCache[domain.User](rdb, "user", 5*time.Second),
 		interval:     interval,
 		activeUsers:  make(map[uuid.UUID]context.CancelFunc),
@@ -73,6 +78,12 @@ func (w *CellsMovingWorker) StartMovement(userID uuid.UUID, cellSlugs []string)
 				}
 
 				_ = w.userCache.Delete(context.Background(), userID.String())
+
+				if err := w.questRepo.AddProgress(userID, domain.QuestObjectiveVisitCell, cellSlug, 1); err != nil {
+					log.Printf("[CellsMovingWorker] quest progress for %s: %v", userID, err)
+				}
+
+				events.GetBus().Publish(context.Background(), domain.GameEvent{Type: domain.EventCellVisited, UserID: userID, Slug: cellSlug})
 			}
 		}
 	}()
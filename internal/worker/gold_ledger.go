@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	goredis "github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/services"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+// GoldLedgerWorker follows the gold ledger and keeps the gold boards up to
+// date with every committed change. Entries missed while disconnected are
+// fixed by the next LeaderboardWorker rebuild.
+type GoldLedgerWorker struct {
+	leaderboardService *services.LeaderboardService
+	listener           *repository.GoldTransactionListener
+}
+
+func NewGoldLedgerWorker(db *sqlx.DB, rdb *goredis.Client, dsn string) *GoldLedgerWorker {
+	leaderboardService := services.NewLeaderboardService(
+		repository.NewLeaderboardRepository(db),
+		repository.NewUserRepository(db),
+		r.NewLeaderboard(rdb, "leaderboard"),
+	)
+
+	return &GoldLedgerWorker{
+		leaderboardService: leaderboardService,
+		listener:           repository.NewGoldTransactionListener(dsn),
+	}
+}
+
+func (w *GoldLedgerWorker) StartWorker(ctx context.Context) {
+	defer func() { _ = w.listener.Close() }()
+
+	if err := w.listener.Listen(); err != nil {
+		log.Printf("[GoldLedgerWorker] Error listening to the gold ledger: %v\n", err)
+		return
+	}
+
+	for {
+		transaction, err := w.listener.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[GoldLedgerWorker] Error reading the gold ledger: %v\n", err)
+			continue
+		}
+		if transaction == nil {
+			continue
+		}
+
+		if err := w.leaderboardService.HandleGoldTransaction(ctx, transaction); err != nil {
+			log.Printf("[GoldLedgerWorker] Error updating gold boards for %s: %v\n", transaction.UserID, err)
+		}
+	}
+}
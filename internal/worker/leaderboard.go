@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	goredis "github.com/redis/go-redis/v9"
+
+	"moonshine/internal/api/services"
+	r "moonshine/internal/redis"
+	"moonshine/internal/repository"
+)
+
+// LeaderboardWorker periodically rebuilds the leaderboards from Postgres so
+// scores left behind by rolled back transactions do not stick.
+type LeaderboardWorker struct {
+	leaderboardService *services.LeaderboardService
+	ticker             *time.Ticker
+}
+
+func NewLeaderboardWorker(db *sqlx.DB, rdb *goredis.Client, interval time.Duration) *LeaderboardWorker {
+	leaderboardService := services.NewLeaderboardService(
+		repository.NewLeaderboardRepository(db),
+		repository.NewUserRepository(db),
+		r.NewLeaderboard(rdb, "leaderboard"),
+	)
+
+	return &LeaderboardWorker{
+		leaderboardService: leaderboardService,
+		ticker:             time.NewTicker(interval),
+	}
+}
+
+func (w *LeaderboardWorker) StartWorker(ctx context.Context) {
+	defer w.ticker.Stop()
+
+	w.rebuild(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ticker.C:
+			w.rebuild(ctx)
+		}
+	}
+}
+
+func (w *LeaderboardWorker) rebuild(ctx context.Context) {
+	if err := w.leaderboardService.Rebuild(ctx, time.Now()); err != nil {
+		log.Printf("[LeaderboardWorker] Error rebuilding leaderboards: %v\n", err)
+	}
+}
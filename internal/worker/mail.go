@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+)
+
+type MailWorker struct {
+	mailService *services.MailService
+	ticker      *time.Ticker
+}
+
+func NewMailWorker(db *sqlx.DB, interval time.Duration) *MailWorker {
+	return &MailWorker{
+		mailService: services.NewMailService(db, ws.GetHub()),
+		ticker:      time.NewTicker(interval),
+	}
+}
+
+func (w *MailWorker) StartWorker(ctx context.Context) {
+	defer w.ticker.Stop()
+
+	w.expireMail(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ticker.C:
+			w.expireMail(ctx)
+		}
+	}
+}
+
+func (w *MailWorker) expireMail(ctx context.Context) {
+	count, err := w.mailService.ExpireMail(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("[MailWorker] Error expiring mail: %v\n", err)
+		return
+	}
+
+	if count > 0 {
+		log.Printf("[MailWorker] Expired %d mails\n", count)
+	}
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/repository"
+)
+
+type QuestResetWorker struct {
+	questService *services.QuestService
+	ticker       *time.Ticker
+}
+
+func NewQuestResetWorker(db *sqlx.DB, interval time.Duration) *QuestResetWorker {
+	questService := services.NewQuestService(db, repository.NewQuestRepository(db), repository.NewUserRepository(db), ws.GetHub())
+
+	return &QuestResetWorker{
+		questService: questService,
+		ticker:       time.NewTicker(interval),
+	}
+}
+
+func (w *QuestResetWorker) StartWorker(ctx context.Context) {
+	defer w.ticker.Stop()
+
+	w.resetDailyQuests(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ticker.C:
+			w.resetDailyQuests(ctx)
+		}
+	}
+}
+
+func (w *QuestResetWorker) resetDailyQuests(ctx context.Context) {
+	count, err := w.questService.ResetDailyQuests(ctx, time.Now())
+	if err != nil {
+		log.Printf("[QuestResetWorker] Error resetting daily quests: %v\n", err)
+		return
+	}
+
+	if count > 0 {
+		log.Printf("[QuestResetWorker] Reset %d daily quests\n", count)
+	}
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+)
+
+type ShopRestockWorker struct {
+	shopService *services.ShopService
+	ticker      *time.Ticker
+}
+
+func NewShopRestockWorker(db *sqlx.DB, interval time.Duration) *ShopRestockWorker {
+	return &ShopRestockWorker{
+		shopService: services.NewShopService(db),
+		ticker:      time.NewTicker(interval),
+	}
+}
+
+func (w *ShopRestockWorker) StartWorker(ctx context.Context) {
+	defer w.ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ticker.C:
+			w.restock(ctx)
+		}
+	}
+}
+
+func (w *ShopRestockWorker) restock(ctx context.Context) {
+	count, err := w.shopService.Restock(ctx)
+	if err != nil {
+		log.Printf("[ShopRestockWorker] Error restocking: %v\n", err)
+		return
+	}
+
+	log.Printf("[ShopRestockWorker] Restocked %d items\n", count)
+}
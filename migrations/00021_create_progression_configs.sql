@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE progression_configs (
+    version INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    config JSONB NOT NULL
+);
+
+INSERT INTO progression_configs (version, config) VALUES (1, '{"version":1,"levels":[{"level":1,"exp":0,"reward":{"free_stats":0,"hp":0}},{"level":2,"exp":100,"reward":{"free_stats":0,"hp":0}},{"level":3,"exp":200,"reward":{"free_stats":0,"hp":0}},{"level":4,"exp":400,"reward":{"free_stats":0,"hp":0}},{"level":5,"exp":800,"reward":{"free_stats":0,"hp":0}},{"level":6,"exp":1500,"reward":{"free_stats":0,"hp":0}},{"level":7,"exp":3000,"reward":{"free_stats":0,"hp":0}},{"level":8,"exp":5000,"reward":{"free_stats":0,"hp":0}},{"level":9,"exp":10000,"reward":{"free_stats":0,"hp":0}},{"level":10,"exp":15000,"reward":{"free_stats":0,"hp":0}},{"level":11,"exp":20000,"reward":{"free_stats":0,"hp":0}},{"level":12,"exp":25000,"reward":{"free_stats":0,"hp":0}},{"level":13,"exp":30000,"reward":{"free_stats":0,"hp":0}},{"level":14,"exp":35000,"reward":{"free_stats":0,"hp":0}},{"level":15,"exp":40000,"reward":{"free_stats":0,"hp":0}},{"level":16,"exp":45000,"reward":{"free_stats":0,"hp":0}},{"level":17,"exp":50000,"reward":{"free_stats":0,"hp":0}},{"level":18,"exp":55000,"reward":{"free_stats":0,"hp":0}},{"level":19,"exp":60000,"reward":{"free_stats":0,"hp":0}},{"level":20,"exp":65000,"reward":{"free_stats":0,"hp":0}}],"exp_curve":{"bots_to_level_base":5,"bots_to_level_growth":1.6,"higher_level_bonus":0.25,"lower_level_penalty":0.5}}');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS progression_configs;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE consumable_effect_type AS ENUM ('HEAL');
+
+CREATE TABLE consumables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    image VARCHAR(255),
+    price INTEGER NOT NULL DEFAULT 0,
+    required_level INTEGER NOT NULL DEFAULT 1,
+    effect_type consumable_effect_type NOT NULL,
+    effect_value INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_consumables_slug_unique ON consumables(slug) WHERE deleted_at IS NULL;
+
+CREATE TABLE consumable_inventory (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    consumable_id UUID NOT NULL,
+    quantity INTEGER NOT NULL,
+    CONSTRAINT fk_consumable_inventory_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_consumable_inventory_consumable FOREIGN KEY (consumable_id) REFERENCES consumables(id) ON DELETE CASCADE,
+    CONSTRAINT check_consumable_inventory_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT uq_consumable_inventory_user_consumable UNIQUE (user_id, consumable_id)
+);
+
+ALTER TABLE rounds ADD COLUMN player_heal INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE rounds ADD COLUMN player_consumable_id UUID REFERENCES consumables(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_consumable_id;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_heal;
+DROP TABLE IF EXISTS consumable_inventory;
+DROP TABLE IF EXISTS consumables;
+DROP TYPE IF EXISTS consumable_effect_type;
+-- +goose StatementEnd
@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE status_effect_type AS ENUM ('POISON', 'BLEED', 'STUN', 'SHIELD');
+CREATE TYPE fight_participant AS ENUM ('PLAYER', 'BOT');
+
+CREATE TABLE fight_status_effects (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    fight_id UUID NOT NULL,
+    round_id UUID NOT NULL,
+    target fight_participant NOT NULL,
+    effect_type status_effect_type NOT NULL,
+    value INTEGER NOT NULL DEFAULT 0,
+    rounds_left INTEGER NOT NULL,
+    CONSTRAINT fk_fight_status_effects_fight FOREIGN KEY (fight_id) REFERENCES fights(id) ON DELETE CASCADE,
+    CONSTRAINT fk_fight_status_effects_round FOREIGN KEY (round_id) REFERENCES rounds(id) ON DELETE CASCADE,
+    CONSTRAINT check_fight_status_effects_rounds_left CHECK (rounds_left >= 0)
+);
+
+CREATE INDEX idx_fight_status_effects_fight_id ON fight_status_effects(fight_id);
+
+ALTER TABLE equipment_items ADD COLUMN status_effect_type status_effect_type;
+ALTER TABLE equipment_items ADD COLUMN status_effect_value INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE equipment_items ADD COLUMN status_effect_duration INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE equipment_items ADD COLUMN status_effect_chance INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE bots ADD COLUMN status_effect_type status_effect_type;
+ALTER TABLE bots ADD COLUMN status_effect_value INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN status_effect_duration INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN status_effect_chance INTEGER NOT NULL DEFAULT 0;
+
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_enum WHERE enumlabel = 'STATUS_EFFECT' AND enumtypid = (SELECT oid FROM pg_type WHERE typname = 'consumable_effect_type')) THEN
+        ALTER TYPE consumable_effect_type ADD VALUE 'STATUS_EFFECT';
+    END IF;
+END $$;
+
+ALTER TABLE consumables ADD COLUMN status_effect_type status_effect_type;
+ALTER TABLE consumables ADD COLUMN status_effect_value INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE consumables ADD COLUMN status_effect_duration INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE consumables ADD COLUMN status_effect_chance INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE rounds ADD COLUMN player_effect_damage INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE rounds ADD COLUMN bot_effect_damage INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE rounds ADD COLUMN player_absorbed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE rounds ADD COLUMN bot_absorbed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE rounds ADD COLUMN player_stunned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rounds ADD COLUMN bot_stunned BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_stunned;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_stunned;
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_absorbed;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_absorbed;
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_effect_damage;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_effect_damage;
+
+-- consumable_effect_type keeps the STATUS_EFFECT value, PostgreSQL cannot drop enum values
+ALTER TABLE consumables DROP COLUMN IF EXISTS status_effect_chance;
+ALTER TABLE consumables DROP COLUMN IF EXISTS status_effect_duration;
+ALTER TABLE consumables DROP COLUMN IF EXISTS status_effect_value;
+ALTER TABLE consumables DROP COLUMN IF EXISTS status_effect_type;
+
+ALTER TABLE bots DROP COLUMN IF EXISTS status_effect_chance;
+ALTER TABLE bots DROP COLUMN IF EXISTS status_effect_duration;
+ALTER TABLE bots DROP COLUMN IF EXISTS status_effect_value;
+ALTER TABLE bots DROP COLUMN IF EXISTS status_effect_type;
+
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS status_effect_chance;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS status_effect_duration;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS status_effect_value;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS status_effect_type;
+
+DROP TABLE IF EXISTS fight_status_effects;
+DROP TYPE IF EXISTS fight_participant;
+DROP TYPE IF EXISTS status_effect_type;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE equipment_items ADD COLUMN crit_chance INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE equipment_items ADD COLUMN crit_multiplier INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE equipment_items ADD COLUMN dodge_chance INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE equipment_items ADD COLUMN block_chance INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE users ADD COLUMN agility INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN luck INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE rounds ADD COLUMN player_crit BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rounds ADD COLUMN bot_crit BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rounds ADD COLUMN player_dodged BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rounds ADD COLUMN bot_dodged BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rounds ADD COLUMN player_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rounds ADD COLUMN bot_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_blocked;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_blocked;
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_dodged;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_dodged;
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_crit;
+ALTER TABLE rounds DROP COLUMN IF EXISTS player_crit;
+
+ALTER TABLE users DROP COLUMN IF EXISTS luck;
+ALTER TABLE users DROP COLUMN IF EXISTS agility;
+
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS block_chance;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS dodge_chance;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS crit_multiplier;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS crit_chance;
+-- +goose StatementEnd
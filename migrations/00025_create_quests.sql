@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE quest_objective_type AS ENUM ('KILL_BOT', 'VISIT_CELL', 'BUY_ITEM', 'GATHER_RESOURCE');
+CREATE TYPE quest_status AS ENUM ('IN_PROGRESS', 'COMPLETED', 'CLAIMED');
+
+CREATE TABLE quests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    required_level INTEGER NOT NULL DEFAULT 1,
+    objective_type quest_objective_type NOT NULL,
+    target_slug VARCHAR(255) NOT NULL,
+    target_count INTEGER NOT NULL DEFAULT 1,
+    reward_gold INTEGER NOT NULL DEFAULT 0,
+    reward_exp INTEGER NOT NULL DEFAULT 0,
+    reward_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    daily BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT check_quests_target_count_positive CHECK (target_count > 0)
+);
+
+CREATE UNIQUE INDEX idx_quests_slug_unique ON quests(slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_quests_objective ON quests(objective_type, target_slug);
+
+CREATE TABLE user_quests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    quest_id UUID NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    status quest_status NOT NULL DEFAULT 'IN_PROGRESS',
+    completed_at TIMESTAMP,
+    claimed_at TIMESTAMP,
+    CONSTRAINT fk_user_quests_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_quests_quest FOREIGN KEY (quest_id) REFERENCES quests(id) ON DELETE CASCADE,
+    CONSTRAINT uq_user_quests_user_quest UNIQUE (user_id, quest_id)
+);
+
+CREATE INDEX idx_user_quests_user_status ON user_quests(user_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_quests;
+DROP TABLE IF EXISTS quests;
+DROP TYPE IF EXISTS quest_status;
+DROP TYPE IF EXISTS quest_objective_type;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE achievement_type AS ENUM ('KILL_BOTS', 'REACH_LEVEL', 'OWN_ARTIFACT', 'VISIT_CELLS');
+
+CREATE TABLE achievements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    achievement_type achievement_type NOT NULL,
+    threshold INTEGER NOT NULL DEFAULT 1,
+    title VARCHAR(255),
+    CONSTRAINT check_achievements_threshold_positive CHECK (threshold > 0)
+);
+
+CREATE UNIQUE INDEX idx_achievements_slug_unique ON achievements(slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_achievements_type ON achievements(achievement_type);
+
+CREATE TABLE user_achievements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    unlocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    achievement_id UUID NOT NULL,
+    CONSTRAINT fk_user_achievements_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_achievements_achievement FOREIGN KEY (achievement_id) REFERENCES achievements(id) ON DELETE CASCADE,
+    CONSTRAINT uq_user_achievements_user_achievement UNIQUE (user_id, achievement_id)
+);
+
+CREATE TABLE user_visited_cells (
+    user_id UUID NOT NULL,
+    location_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, location_id),
+    CONSTRAINT fk_user_visited_cells_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_visited_cells_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+ALTER TABLE users ADD COLUMN title VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS title;
+DROP TABLE IF EXISTS user_visited_cells;
+DROP TABLE IF EXISTS user_achievements;
+DROP TABLE IF EXISTS achievements;
+DROP TYPE IF EXISTS achievement_type;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE gold_transaction_reason AS ENUM (
+    'OPENING_BALANCE',
+    'INITIAL',
+    'FIGHT_REWARD',
+    'QUEST_REWARD',
+    'EQUIPMENT_BUY',
+    'EQUIPMENT_SELL',
+    'CONSUMABLE_BUY'
+);
+
+CREATE TABLE gold_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    delta BIGINT NOT NULL,
+    reason gold_transaction_reason NOT NULL,
+    reference_id UUID,
+    balance_after BIGINT NOT NULL,
+    CONSTRAINT fk_gold_transactions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT check_gold_transactions_delta_not_zero CHECK (delta <> 0),
+    CONSTRAINT check_gold_transactions_balance_not_negative CHECK (balance_after >= 0)
+);
+
+CREATE INDEX idx_gold_transactions_user_created ON gold_transactions(user_id, created_at DESC);
+
+-- The ledger is append-only: rows are never changed once written.
+CREATE FUNCTION forbid_gold_transactions_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'gold_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_gold_transactions_append_only
+    BEFORE UPDATE ON gold_transactions
+    FOR EACH ROW EXECUTE FUNCTION forbid_gold_transactions_update();
+
+INSERT INTO gold_transactions (user_id, delta, reason, balance_after)
+SELECT id, gold, 'OPENING_BALANCE', gold
+FROM users
+WHERE gold <> 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS gold_transactions;
+DROP FUNCTION IF EXISTS forbid_gold_transactions_update();
+DROP TYPE IF EXISTS gold_transaction_reason;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE equipment_items
+    ADD COLUMN max_durability INT NOT NULL DEFAULT 100,
+    ADD CONSTRAINT check_equipment_items_max_durability_positive CHECK (max_durability > 0);
+
+-- Every inventory row is an item instance. Equipped instances stay in the
+-- table with equipped_slot set, the users.*_equipment_item_id columns keep
+-- pointing at the template.
+ALTER TABLE inventory
+    ADD COLUMN durability INT NOT NULL DEFAULT 100,
+    ADD COLUMN max_durability INT NOT NULL DEFAULT 100,
+    ADD COLUMN bonus_attack INT NOT NULL DEFAULT 0,
+    ADD COLUMN bonus_defense INT NOT NULL DEFAULT 0,
+    ADD COLUMN bonus_hp INT NOT NULL DEFAULT 0,
+    ADD COLUMN equipped_slot VARCHAR(16),
+    ADD CONSTRAINT check_inventory_durability_range CHECK (durability >= 0 AND durability <= max_durability);
+
+CREATE UNIQUE INDEX idx_inventory_user_equipped_slot ON inventory(user_id, equipped_slot)
+    WHERE equipped_slot IS NOT NULL AND deleted_at IS NULL;
+
+INSERT INTO inventory (user_id, equipment_item_id, equipped_slot)
+SELECT users.id, slots.equipment_item_id, slots.slot
+FROM users
+CROSS JOIN LATERAL (VALUES
+    ('chest', users.chest_equipment_item_id),
+    ('belt', users.belt_equipment_item_id),
+    ('head', users.head_equipment_item_id),
+    ('neck', users.neck_equipment_item_id),
+    ('weapon', users.weapon_equipment_item_id),
+    ('shield', users.shield_equipment_item_id),
+    ('legs', users.legs_equipment_item_id),
+    ('feet', users.feet_equipment_item_id),
+    ('arms', users.arms_equipment_item_id),
+    ('hands', users.hands_equipment_item_id),
+    ('ring1', users.ring1_equipment_item_id),
+    ('ring2', users.ring2_equipment_item_id),
+    ('ring3', users.ring3_equipment_item_id),
+    ('ring4', users.ring4_equipment_item_id)
+) AS slots(slot, equipment_item_id)
+WHERE slots.equipment_item_id IS NOT NULL;
+
+-- Enum values can't be removed again, the down migration leaves it in place.
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'EQUIPMENT_REPAIR';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM inventory WHERE equipped_slot IS NOT NULL;
+DROP INDEX IF EXISTS idx_inventory_user_equipped_slot;
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS check_inventory_durability_range,
+    DROP COLUMN IF EXISTS equipped_slot,
+    DROP COLUMN IF EXISTS bonus_hp,
+    DROP COLUMN IF EXISTS bonus_defense,
+    DROP COLUMN IF EXISTS bonus_attack,
+    DROP COLUMN IF EXISTS max_durability,
+    DROP COLUMN IF EXISTS durability;
+ALTER TABLE equipment_items
+    DROP CONSTRAINT IF EXISTS check_equipment_items_max_durability_positive,
+    DROP COLUMN IF EXISTS max_durability;
+-- +goose StatementEnd
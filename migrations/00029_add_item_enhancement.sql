@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE inventory
+    ADD COLUMN enhancement_level INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT check_inventory_enhancement_level_range CHECK (enhancement_level >= 0 AND enhancement_level <= 10);
+
+-- Materials are consumables that can't be used, they are spent by crafting.
+ALTER TYPE consumable_effect_type ADD VALUE IF NOT EXISTS 'MATERIAL';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'EQUIPMENT_ENHANCE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS check_inventory_enhancement_level_range,
+    DROP COLUMN IF EXISTS enhancement_level;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_sets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_item_sets_slug_unique ON item_sets(slug) WHERE deleted_at IS NULL;
+
+-- An item belongs to at most one set.
+CREATE TABLE item_set_items (
+    item_set_id UUID NOT NULL,
+    equipment_item_id UUID NOT NULL,
+    CONSTRAINT fk_item_set_items_set FOREIGN KEY (item_set_id) REFERENCES item_sets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_item_set_items_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE,
+    CONSTRAINT uq_item_set_items_item UNIQUE (equipment_item_id)
+);
+
+CREATE INDEX idx_item_set_items_set ON item_set_items(item_set_id);
+
+CREATE TABLE item_set_bonuses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_set_id UUID NOT NULL,
+    pieces INTEGER NOT NULL,
+    attack INTEGER NOT NULL DEFAULT 0,
+    defense INTEGER NOT NULL DEFAULT 0,
+    hp INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_item_set_bonuses_set FOREIGN KEY (item_set_id) REFERENCES item_sets(id) ON DELETE CASCADE,
+    CONSTRAINT uq_item_set_bonuses_set_pieces UNIQUE (item_set_id, pieces),
+    CONSTRAINT check_item_set_bonuses_pieces_positive CHECK (pieces > 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_set_bonuses;
+DROP TABLE IF EXISTS item_set_items;
+DROP TABLE IF EXISTS item_sets;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- users.attack, defense and hp become the effective stats computed from the
+-- base stats, the equipped instances and set bonuses.
+ALTER TABLE users
+    ADD COLUMN base_attack INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN base_defense INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN base_hp INTEGER NOT NULL DEFAULT 20;
+
+WITH equipment AS (
+    SELECT inv.user_id,
+        SUM(ei.attack + (ei.attack * inv.enhancement_level * 10 + 99) / 100 + inv.bonus_attack) AS attack,
+        SUM(ei.defense + (ei.defense * inv.enhancement_level * 10 + 99) / 100 + inv.bonus_defense) AS defense,
+        SUM(ei.hp + (ei.hp * inv.enhancement_level * 10 + 99) / 100 + inv.bonus_hp) AS hp
+    FROM inventory inv
+    JOIN equipment_items ei ON ei.id = inv.equipment_item_id
+    WHERE inv.equipped_slot IS NOT NULL AND inv.deleted_at IS NULL AND inv.durability > 0
+    GROUP BY inv.user_id
+), set_pieces AS (
+    SELECT inv.user_id, si.item_set_id, COUNT(DISTINCT inv.equipment_item_id) AS pieces
+    FROM inventory inv
+    JOIN item_set_items si ON si.equipment_item_id = inv.equipment_item_id
+    JOIN item_sets s ON s.id = si.item_set_id AND s.deleted_at IS NULL
+    WHERE inv.equipped_slot IS NOT NULL AND inv.deleted_at IS NULL
+    GROUP BY inv.user_id, si.item_set_id
+), sets AS (
+    SELECT sp.user_id, SUM(b.attack) AS attack, SUM(b.defense) AS defense, SUM(b.hp) AS hp
+    FROM set_pieces sp
+    JOIN item_set_bonuses b ON b.item_set_id = sp.item_set_id AND b.pieces <= sp.pieces
+    GROUP BY sp.user_id
+), base AS (
+    SELECT u.id,
+        GREATEST(u.attack - COALESCE(e.attack, 0) - COALESCE(s.attack, 0), 0) AS attack,
+        GREATEST(u.defense - COALESCE(e.defense, 0) - COALESCE(s.defense, 0), 0) AS defense,
+        GREATEST(u.hp - COALESCE(e.hp, 0) - COALESCE(s.hp, 0), 1) AS hp
+    FROM users u
+    LEFT JOIN equipment e ON e.user_id = u.id
+    LEFT JOIN sets s ON s.user_id = u.id
+)
+UPDATE users
+SET base_attack = base.attack,
+    base_defense = base.defense,
+    base_hp = base.hp
+FROM base
+WHERE base.id = users.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS base_hp,
+    DROP COLUMN IF EXISTS base_defense,
+    DROP COLUMN IF EXISTS base_attack;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE trade_status AS ENUM ('OPEN', 'COMPLETED', 'CANCELLED');
+
+CREATE TABLE trades (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    initiator_id UUID NOT NULL,
+    partner_id UUID NOT NULL,
+    location_id UUID NOT NULL,
+    status trade_status NOT NULL DEFAULT 'OPEN',
+    initiator_gold INTEGER NOT NULL DEFAULT 0,
+    partner_gold INTEGER NOT NULL DEFAULT 0,
+    initiator_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    partner_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    completed_at TIMESTAMP,
+    CONSTRAINT fk_trades_initiator FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_trades_partner FOREIGN KEY (partner_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_trades_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE,
+    CONSTRAINT check_trades_different_users CHECK (initiator_id <> partner_id),
+    CONSTRAINT check_trades_gold_not_negative CHECK (initiator_gold >= 0 AND partner_gold >= 0)
+);
+
+CREATE INDEX idx_trades_initiator_open ON trades(initiator_id) WHERE status = 'OPEN';
+CREATE INDEX idx_trades_partner_open ON trades(partner_id) WHERE status = 'OPEN';
+
+-- Offered instances are held in escrow by the trade: they can't be sold or
+-- equipped until the trade completes or is cancelled.
+ALTER TABLE inventory
+    ADD COLUMN escrow_trade_id UUID,
+    ADD CONSTRAINT fk_inventory_escrow_trade FOREIGN KEY (escrow_trade_id) REFERENCES trades(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_inventory_escrow_trade ON inventory(escrow_trade_id) WHERE escrow_trade_id IS NOT NULL;
+
+-- Every item and gold amount that changed hands, for moderation.
+CREATE TABLE trade_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    trade_id UUID NOT NULL,
+    from_user_id UUID NOT NULL,
+    to_user_id UUID NOT NULL,
+    inventory_id UUID,
+    equipment_item_id UUID,
+    gold INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_trade_logs_trade FOREIGN KEY (trade_id) REFERENCES trades(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_trade_logs_from_user ON trade_logs(from_user_id, created_at DESC);
+CREATE INDEX idx_trade_logs_to_user ON trade_logs(to_user_id, created_at DESC);
+
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'TRADE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS trade_logs;
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS fk_inventory_escrow_trade,
+    DROP COLUMN IF EXISTS escrow_trade_id;
+DROP TABLE IF EXISTS trades;
+DROP TYPE IF EXISTS trade_status;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE auction_status AS ENUM ('ACTIVE', 'SOLD', 'EXPIRED');
+
+CREATE TABLE auctions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    seller_id UUID NOT NULL,
+    inventory_id UUID NOT NULL,
+    equipment_item_id UUID NOT NULL,
+    start_price INTEGER NOT NULL,
+    buyout_price INTEGER,
+    -- The top bid's gold is already taken from the bidder and refunded when
+    -- someone outbids them.
+    current_bid INTEGER,
+    bidder_id UUID,
+    listing_fee INTEGER NOT NULL,
+    status auction_status NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP NOT NULL,
+    settled_at TIMESTAMP,
+    CONSTRAINT fk_auctions_seller FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_auctions_inventory FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE,
+    CONSTRAINT fk_auctions_equipment_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE,
+    CONSTRAINT fk_auctions_bidder FOREIGN KEY (bidder_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT check_auctions_start_price_positive CHECK (start_price > 0),
+    CONSTRAINT check_auctions_buyout_price CHECK (buyout_price IS NULL OR buyout_price >= start_price),
+    CONSTRAINT check_auctions_current_bid CHECK (current_bid IS NULL OR current_bid >= start_price),
+    CONSTRAINT check_auctions_listing_fee_not_negative CHECK (listing_fee >= 0)
+);
+
+CREATE INDEX idx_auctions_active_expires_at ON auctions(expires_at) WHERE status = 'ACTIVE';
+CREATE INDEX idx_auctions_active_equipment_item ON auctions(equipment_item_id) WHERE status = 'ACTIVE';
+CREATE UNIQUE INDEX idx_auctions_active_inventory ON auctions(inventory_id) WHERE status = 'ACTIVE';
+
+-- Listed instances leave the seller's bag until the auction is settled.
+ALTER TABLE inventory
+    ADD COLUMN escrow_auction_id UUID,
+    ADD CONSTRAINT fk_inventory_escrow_auction FOREIGN KEY (escrow_auction_id) REFERENCES auctions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_inventory_escrow_auction ON inventory(escrow_auction_id) WHERE escrow_auction_id IS NOT NULL;
+
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'AUCTION_BID';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'AUCTION_REFUND';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'AUCTION_SALE';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'AUCTION_FEE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS fk_inventory_escrow_auction,
+    DROP COLUMN IF EXISTS escrow_auction_id;
+DROP TABLE IF EXISTS auctions;
+DROP TYPE IF EXISTS auction_status;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Items with stock rows are only sold in those shops and only while in stock,
+-- items without any are sold everywhere at their base price.
+CREATE TABLE shop_stock (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    location_id UUID NOT NULL,
+    equipment_item_id UUID NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    max_quantity INTEGER NOT NULL,
+    restock_amount INTEGER NOT NULL,
+    price INTEGER NOT NULL,
+    sold_since_restock INTEGER NOT NULL DEFAULT 0,
+    restocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_shop_stock_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop_stock_equipment_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE,
+    CONSTRAINT uq_shop_stock_location_item UNIQUE (location_id, equipment_item_id),
+    CONSTRAINT check_shop_stock_quantity_range CHECK (quantity >= 0 AND quantity <= max_quantity),
+    CONSTRAINT check_shop_stock_restock_amount_positive CHECK (restock_amount > 0),
+    CONSTRAINT check_shop_stock_price_positive CHECK (price > 0)
+);
+
+CREATE INDEX idx_shop_stock_equipment_item ON shop_stock(equipment_item_id);
+
+-- One row per restock of every stock, for analytics.
+CREATE TABLE shop_price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    location_id UUID NOT NULL,
+    equipment_item_id UUID NOT NULL,
+    price INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    sold INTEGER NOT NULL,
+    CONSTRAINT fk_shop_price_history_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop_price_history_equipment_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shop_price_history_item ON shop_price_history(equipment_item_id, recorded_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_price_history;
+DROP TABLE IF EXISTS shop_stock;
+-- +goose StatementEnd
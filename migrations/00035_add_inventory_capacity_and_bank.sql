@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Bag capacity counts unequipped instance rows, a stack takes one slot.
+ALTER TABLE users
+    ADD COLUMN inventory_capacity INTEGER NOT NULL DEFAULT 30,
+    ADD CONSTRAINT check_users_inventory_capacity_positive CHECK (inventory_capacity > 0);
+
+-- Items with max_stack above 1 stack up to that many copies in one row.
+ALTER TABLE equipment_items
+    ADD COLUMN max_stack INTEGER NOT NULL DEFAULT 1,
+    ADD CONSTRAINT check_equipment_items_max_stack_positive CHECK (max_stack > 0);
+
+-- Banked instances are kept in the bank instead of the bag.
+ALTER TABLE inventory
+    ADD COLUMN quantity INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN banked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT check_inventory_quantity_positive CHECK (quantity > 0),
+    ADD CONSTRAINT check_inventory_banked_unequipped CHECK (NOT banked OR equipped_slot IS NULL);
+
+CREATE INDEX idx_inventory_user_banked ON inventory(user_id, banked) WHERE deleted_at IS NULL;
+
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'INVENTORY_UPGRADE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_inventory_user_banked;
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS check_inventory_banked_unequipped,
+    DROP CONSTRAINT IF EXISTS check_inventory_quantity_positive,
+    DROP COLUMN IF EXISTS banked,
+    DROP COLUMN IF EXISTS quantity;
+ALTER TABLE equipment_items
+    DROP CONSTRAINT IF EXISTS check_equipment_items_max_stack_positive,
+    DROP COLUMN IF EXISTS max_stack;
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS check_users_inventory_capacity_positive,
+    DROP COLUMN IF EXISTS inventory_capacity;
+-- +goose StatementEnd
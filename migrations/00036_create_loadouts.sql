@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE loadouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    CONSTRAINT fk_loadouts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_loadouts_user_name UNIQUE (user_id, name)
+);
+
+-- Slots left empty by the loadout have no row. The instance is forgotten when
+-- it is sold, another copy of the item is used then.
+CREATE TABLE loadout_slots (
+    loadout_id UUID NOT NULL,
+    slot VARCHAR(16) NOT NULL,
+    inventory_id UUID,
+    equipment_item_id UUID NOT NULL,
+    PRIMARY KEY (loadout_id, slot),
+    CONSTRAINT fk_loadout_slots_loadout FOREIGN KEY (loadout_id) REFERENCES loadouts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_loadout_slots_inventory FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE SET NULL,
+    CONSTRAINT fk_loadout_slots_equipment_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loadout_slots;
+DROP TABLE IF EXISTS loadouts;
+-- +goose StatementEnd
@@ -0,0 +1,134 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Category types become plain strings, a new kind of equipment needs no
+-- enum change.
+ALTER TABLE equipment_categories ALTER COLUMN type TYPE VARCHAR(32) USING type::text;
+DROP TYPE IF EXISTS equipment_category_type;
+
+-- The slot registry: every slot takes items of one category type, slots
+-- sharing a type (the rings) are filled in position order.
+CREATE TABLE equipment_slots (
+    name VARCHAR(16) PRIMARY KEY,
+    equipment_type VARCHAR(32) NOT NULL,
+    position INT NOT NULL,
+    CONSTRAINT uq_equipment_slots_position UNIQUE (position)
+);
+
+CREATE INDEX idx_equipment_slots_equipment_type ON equipment_slots(equipment_type);
+
+INSERT INTO equipment_slots (name, equipment_type, position) VALUES
+    ('chest', 'chest', 1),
+    ('belt', 'belt', 2),
+    ('head', 'head', 3),
+    ('neck', 'neck', 4),
+    ('weapon', 'weapon', 5),
+    ('shield', 'shield', 6),
+    ('legs', 'legs', 7),
+    ('feet', 'feet', 8),
+    ('arms', 'arms', 9),
+    ('hands', 'hands', 10),
+    ('ring1', 'ring', 11),
+    ('ring2', 'ring', 12),
+    ('ring3', 'ring', 13),
+    ('ring4', 'ring', 14);
+
+-- One row per filled slot, replacing the users.*_equipment_item_id columns.
+CREATE TABLE equipped_items (
+    user_id UUID NOT NULL,
+    slot VARCHAR(16) NOT NULL,
+    equipment_item_id UUID NOT NULL,
+    PRIMARY KEY (user_id, slot),
+    CONSTRAINT fk_equipped_items_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_equipped_items_slot FOREIGN KEY (slot) REFERENCES equipment_slots(name),
+    CONSTRAINT fk_equipped_items_equipment_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE
+);
+
+INSERT INTO equipped_items (user_id, slot, equipment_item_id)
+SELECT users.id, slots.slot, slots.equipment_item_id
+FROM users
+CROSS JOIN LATERAL (VALUES
+    ('chest', users.chest_equipment_item_id),
+    ('belt', users.belt_equipment_item_id),
+    ('head', users.head_equipment_item_id),
+    ('neck', users.neck_equipment_item_id),
+    ('weapon', users.weapon_equipment_item_id),
+    ('shield', users.shield_equipment_item_id),
+    ('legs', users.legs_equipment_item_id),
+    ('feet', users.feet_equipment_item_id),
+    ('arms', users.arms_equipment_item_id),
+    ('hands', users.hands_equipment_item_id),
+    ('ring1', users.ring1_equipment_item_id),
+    ('ring2', users.ring2_equipment_item_id),
+    ('ring3', users.ring3_equipment_item_id),
+    ('ring4', users.ring4_equipment_item_id)
+) AS slots(slot, equipment_item_id)
+WHERE slots.equipment_item_id IS NOT NULL;
+
+ALTER TABLE inventory
+    ADD CONSTRAINT fk_inventory_equipped_slot FOREIGN KEY (equipped_slot) REFERENCES equipment_slots(name);
+ALTER TABLE loadout_slots
+    ADD CONSTRAINT fk_loadout_slots_slot FOREIGN KEY (slot) REFERENCES equipment_slots(name);
+
+ALTER TABLE users
+    DROP COLUMN chest_equipment_item_id,
+    DROP COLUMN belt_equipment_item_id,
+    DROP COLUMN head_equipment_item_id,
+    DROP COLUMN neck_equipment_item_id,
+    DROP COLUMN weapon_equipment_item_id,
+    DROP COLUMN shield_equipment_item_id,
+    DROP COLUMN legs_equipment_item_id,
+    DROP COLUMN feet_equipment_item_id,
+    DROP COLUMN arms_equipment_item_id,
+    DROP COLUMN hands_equipment_item_id,
+    DROP COLUMN ring1_equipment_item_id,
+    DROP COLUMN ring2_equipment_item_id,
+    DROP COLUMN ring3_equipment_item_id,
+    DROP COLUMN ring4_equipment_item_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Slots added after this migration are lost on the way down.
+ALTER TABLE users
+    ADD COLUMN chest_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN belt_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN head_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN neck_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN weapon_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN shield_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN legs_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN feet_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN arms_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN hands_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN ring1_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN ring2_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN ring3_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL,
+    ADD COLUMN ring4_equipment_item_id UUID REFERENCES equipment_items(id) ON DELETE SET NULL;
+
+UPDATE users
+SET chest_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'chest'),
+    belt_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'belt'),
+    head_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'head'),
+    neck_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'neck'),
+    weapon_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'weapon'),
+    shield_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'shield'),
+    legs_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'legs'),
+    feet_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'feet'),
+    arms_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'arms'),
+    hands_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'hands'),
+    ring1_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'ring1'),
+    ring2_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'ring2'),
+    ring3_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'ring3'),
+    ring4_equipment_item_id = (SELECT equipment_item_id FROM equipped_items WHERE user_id = users.id AND slot = 'ring4');
+
+ALTER TABLE loadout_slots DROP CONSTRAINT IF EXISTS fk_loadout_slots_slot;
+ALTER TABLE inventory DROP CONSTRAINT IF EXISTS fk_inventory_equipped_slot;
+DROP TABLE IF EXISTS equipped_items;
+DROP TABLE IF EXISTS equipment_slots;
+
+CREATE TYPE equipment_category_type AS ENUM (
+    'chest', 'belt', 'head', 'neck', 'weapon', 'shield', 'legs', 'feet', 'arms', 'hands', 'ring'
+);
+ALTER TABLE equipment_categories
+    ALTER COLUMN type TYPE equipment_category_type USING type::equipment_category_type;
+-- +goose StatementEnd
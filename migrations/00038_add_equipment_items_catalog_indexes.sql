@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE equipment_items
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX idx_equipment_items_search_vector ON equipment_items USING GIN (search_vector) WHERE deleted_at IS NULL;
+CREATE INDEX idx_equipment_items_category ON equipment_items(equipment_category_id) WHERE deleted_at IS NULL;
+
+-- One index per catalog sort, the id breaks ties for cursor pagination.
+CREATE INDEX idx_equipment_items_level_id ON equipment_items(required_level, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_equipment_items_price_id ON equipment_items(price, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_equipment_items_name_id ON equipment_items(name, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_equipment_items_attack_id ON equipment_items(attack, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_equipment_items_defense_id ON equipment_items(defense, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_equipment_items_hp_id ON equipment_items(hp, id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_equipment_items_hp_id;
+DROP INDEX IF EXISTS idx_equipment_items_defense_id;
+DROP INDEX IF EXISTS idx_equipment_items_attack_id;
+DROP INDEX IF EXISTS idx_equipment_items_name_id;
+DROP INDEX IF EXISTS idx_equipment_items_price_id;
+DROP INDEX IF EXISTS idx_equipment_items_level_id;
+DROP INDEX IF EXISTS idx_equipment_items_category;
+DROP INDEX IF EXISTS idx_equipment_items_search_vector;
+ALTER TABLE equipment_items DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
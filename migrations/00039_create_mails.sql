@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Mail without a sender is system mail. Attached gold is taken from the
+-- sender when the mail is sent and paid out when the recipient collects it.
+CREATE TABLE mails (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sender_id UUID,
+    recipient_id UUID NOT NULL,
+    subject VARCHAR(64) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    gold INTEGER NOT NULL DEFAULT 0,
+    -- Returned mail carries the attachments of an expired mail back to its
+    -- sender and is not returned again.
+    returned BOOLEAN NOT NULL DEFAULT FALSE,
+    read_at TIMESTAMP,
+    collected_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_mails_sender FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_mails_recipient FOREIGN KEY (recipient_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT check_mails_gold_not_negative CHECK (gold >= 0)
+);
+
+CREATE INDEX idx_mails_recipient_created_at ON mails(recipient_id, created_at DESC);
+CREATE INDEX idx_mails_expires_at ON mails(expires_at);
+
+-- Attached instances leave the sender's bag until the mail is collected.
+ALTER TABLE inventory
+    ADD COLUMN escrow_mail_id UUID,
+    ADD CONSTRAINT fk_inventory_escrow_mail FOREIGN KEY (escrow_mail_id) REFERENCES mails(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_inventory_escrow_mail ON inventory(escrow_mail_id) WHERE escrow_mail_id IS NOT NULL;
+
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'MAIL_SEND';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'MAIL_COLLECT';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS fk_inventory_escrow_mail,
+    DROP COLUMN IF EXISTS escrow_mail_id;
+DROP TABLE IF EXISTS mails;
+-- +goose StatementEnd
@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE guilds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name VARCHAR(32) NOT NULL,
+    tag VARCHAR(5) NOT NULL,
+    treasury INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT check_guilds_treasury_not_negative CHECK (treasury >= 0)
+);
+
+CREATE UNIQUE INDEX idx_guilds_name ON guilds(LOWER(name));
+CREATE UNIQUE INDEX idx_guilds_tag ON guilds(UPPER(tag));
+
+-- A user is in at most one guild. Ranks and what they may do are defined in
+-- code, every guild has exactly one leader.
+CREATE TABLE guild_members (
+    user_id UUID PRIMARY KEY,
+    guild_id UUID NOT NULL,
+    rank VARCHAR(16) NOT NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_guild_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guild_members_guild FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE,
+    CONSTRAINT check_guild_members_rank CHECK (rank IN ('leader', 'officer', 'member'))
+);
+
+CREATE INDEX idx_guild_members_guild ON guild_members(guild_id);
+CREATE UNIQUE INDEX idx_guild_members_leader ON guild_members(guild_id) WHERE rank = 'leader';
+
+CREATE TABLE guild_invites (
+    guild_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    inviter_id UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (guild_id, user_id),
+    CONSTRAINT fk_guild_invites_guild FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guild_invites_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guild_invites_inviter FOREIGN KEY (inviter_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_guild_invites_user ON guild_invites(user_id);
+
+-- Ledger of the guild treasury, the member's side is in gold_transactions.
+CREATE TABLE guild_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    guild_id UUID NOT NULL,
+    user_id UUID,
+    delta INTEGER NOT NULL,
+    balance_after INTEGER NOT NULL,
+    CONSTRAINT fk_guild_transactions_guild FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guild_transactions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_guild_transactions_guild_created_at ON guild_transactions(guild_id, created_at DESC);
+
+CREATE TABLE guild_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    guild_id UUID NOT NULL,
+    user_id UUID,
+    text VARCHAR(500) NOT NULL,
+    CONSTRAINT fk_guild_messages_guild FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guild_messages_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_guild_messages_guild_created_at ON guild_messages(guild_id, created_at DESC);
+
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'GUILD_CREATE';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'GUILD_DEPOSIT';
+ALTER TYPE gold_transaction_reason ADD VALUE IF NOT EXISTS 'GUILD_WITHDRAW';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS guild_messages;
+DROP TABLE IF EXISTS guild_transactions;
+DROP TABLE IF EXISTS guild_invites;
+DROP TABLE IF EXISTS guild_members;
+DROP TABLE IF EXISTS guilds;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A stock row keeps either an equipment item or a consumable.
+ALTER TABLE shop_stock
+    ALTER COLUMN equipment_item_id DROP NOT NULL,
+    ADD COLUMN consumable_id UUID,
+    ADD CONSTRAINT fk_shop_stock_consumable FOREIGN KEY (consumable_id) REFERENCES consumables(id) ON DELETE CASCADE,
+    ADD CONSTRAINT uq_shop_stock_location_consumable UNIQUE (location_id, consumable_id),
+    ADD CONSTRAINT check_shop_stock_one_good CHECK ((equipment_item_id IS NULL) <> (consumable_id IS NULL));
+
+CREATE INDEX idx_shop_stock_consumable ON shop_stock(consumable_id);
+
+ALTER TABLE shop_price_history
+    ALTER COLUMN equipment_item_id DROP NOT NULL,
+    ADD COLUMN consumable_id UUID,
+    ADD CONSTRAINT fk_shop_price_history_consumable FOREIGN KEY (consumable_id) REFERENCES consumables(id) ON DELETE CASCADE,
+    ADD CONSTRAINT check_shop_price_history_one_good CHECK ((equipment_item_id IS NULL) <> (consumable_id IS NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM shop_price_history WHERE consumable_id IS NOT NULL;
+ALTER TABLE shop_price_history
+    DROP CONSTRAINT IF EXISTS check_shop_price_history_one_good,
+    DROP CONSTRAINT IF EXISTS fk_shop_price_history_consumable,
+    DROP COLUMN IF EXISTS consumable_id,
+    ALTER COLUMN equipment_item_id SET NOT NULL;
+
+DELETE FROM shop_stock WHERE consumable_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_shop_stock_consumable;
+ALTER TABLE shop_stock
+    DROP CONSTRAINT IF EXISTS check_shop_stock_one_good,
+    DROP CONSTRAINT IF EXISTS uq_shop_stock_location_consumable,
+    DROP CONSTRAINT IF EXISTS fk_shop_stock_consumable,
+    DROP COLUMN IF EXISTS consumable_id,
+    ALTER COLUMN equipment_item_id SET NOT NULL;
+-- +goose StatementEnd
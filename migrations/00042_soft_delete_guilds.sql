@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Disbanded guilds are soft-deleted so their treasury ledger is kept, their
+-- name and tag are free again.
+ALTER TABLE guilds ADD COLUMN deleted_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_guilds_name;
+DROP INDEX IF EXISTS idx_guilds_tag;
+CREATE UNIQUE INDEX idx_guilds_name ON guilds(LOWER(name)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_guilds_tag ON guilds(UPPER(tag)) WHERE deleted_at IS NULL;
+
+ALTER TABLE guild_transactions
+    DROP CONSTRAINT fk_guild_transactions_guild,
+    ADD CONSTRAINT fk_guild_transactions_guild FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE guild_transactions
+    DROP CONSTRAINT fk_guild_transactions_guild,
+    ADD CONSTRAINT fk_guild_transactions_guild FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE;
+
+DELETE FROM guilds WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_guilds_name;
+DROP INDEX IF EXISTS idx_guilds_tag;
+CREATE UNIQUE INDEX idx_guilds_name ON guilds(LOWER(name));
+CREATE UNIQUE INDEX idx_guilds_tag ON guilds(UPPER(tag));
+
+ALTER TABLE guilds DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Claims outlive the daily reset of user_quests, the weekly exp board is
+-- rebuilt from them and finished fights.
+CREATE TABLE quest_claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    claimed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    quest_id UUID NOT NULL,
+    reward_gold INTEGER NOT NULL,
+    reward_exp INTEGER NOT NULL,
+    CONSTRAINT fk_quest_claims_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_quest_claims_quest FOREIGN KEY (quest_id) REFERENCES quests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_quest_claims_claimed_at ON quest_claims(claimed_at);
+
+-- The weekly gold board is rebuilt from the ledger.
+CREATE INDEX idx_gold_transactions_created_at ON gold_transactions(created_at);
+
+-- Every ledger entry is announced on the gold_transactions channel once its
+-- transaction commits, so gold scores follow every change of users.gold.
+CREATE OR REPLACE FUNCTION notify_gold_transaction() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('gold_transactions', json_build_object(
+        'user_id', NEW.user_id,
+        'delta', NEW.delta,
+        'reason', NEW.reason,
+        'balance_after', NEW.balance_after
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_gold_transactions_notify
+    AFTER INSERT ON gold_transactions
+    FOR EACH ROW EXECUTE FUNCTION notify_gold_transaction();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_gold_transactions_notify ON gold_transactions;
+DROP FUNCTION IF EXISTS notify_gold_transaction();
+DROP INDEX IF EXISTS idx_gold_transactions_created_at;
+DROP TABLE IF EXISTS quest_claims;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_enum WHERE enumlabel = 'ATTACK_UP' AND enumtypid = (SELECT oid FROM pg_type WHERE typname = 'status_effect_type')) THEN
+        ALTER TYPE status_effect_type ADD VALUE 'ATTACK_UP';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_enum WHERE enumlabel = 'DEFENSE_UP' AND enumtypid = (SELECT oid FROM pg_type WHERE typname = 'status_effect_type')) THEN
+        ALTER TYPE status_effect_type ADD VALUE 'DEFENSE_UP';
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- status_effect_type keeps the ATTACK_UP and DEFENSE_UP values, PostgreSQL cannot drop enum values
+UPDATE fight_status_effects SET rounds_left = 0 WHERE effect_type IN ('ATTACK_UP', 'DEFENSE_UP');
+-- +goose StatementEnd